@@ -0,0 +1,357 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/log"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/log"
+	"github.com/rookie-ninja/rk-logger"
+	"github.com/rookie-ninja/rk-query"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// accessLogFormats maps format of logging middleware to encoding of rk-query event.
+//
+// event is the multi-line format of rk-query, json logs event as plain JSON line and console logs event as single line.
+var accessLogFormats = map[string]string{
+	"event":   "console",
+	"json":    "json",
+	"console": "flatten",
+}
+
+// isCustomized returns true if format, omitted fields, static fields, lumberjack, syslog, sinks or async is configured.
+func (config *BootMiddlewareLogging) isCustomized() bool {
+	return len(config.Format) > 0 || len(config.OmitFields) > 0 || len(config.Fields) > 0 ||
+		config.Lumberjack != nil || config.Syslog.Enabled || len(config.Sinks) > 0 || config.Async.Enabled
+}
+
+// extensions returns enabled extensions of logging middleware, events of failed requests are always tagged with errorClass.
+//
+// Output files of CLF extension are closed with shutdown hook added into hooks.
+func (config *BootMiddlewareLogging) extensions(entryName string, hooks *pendingHooks) ([]rkginlog.Extension, error) {
+	res := []rkginlog.Extension{rkginlog.NewErrorClassExtension()}
+	details := make([]rkginlog.Extension, 0)
+	if ext := rkginlog.NewBodyExtension(&config.Body); ext != nil {
+		details = append(details, ext)
+	}
+	if ext := rkginlog.NewHeaderExtension(&config.Header); ext != nil {
+		details = append(details, ext)
+	}
+	// payloads and headers are recorded only for sampled traces if trace aware
+	if config.TraceAware {
+		if ext := rkginlog.NewTraceAwareExtension(details...); ext != nil {
+			res = append(res, ext)
+		}
+	} else {
+		res = append(res, details...)
+	}
+	if ext := rkginlog.NewQueryExtension(&config.Query); ext != nil {
+		res = append(res, ext)
+	}
+	// slow extension marks event before sampling which keeps marked events
+	if ext := rkginlog.NewSlowExtension(config.SlowThresholdMs); ext != nil {
+		res = append(res, ext)
+	}
+	if ext := rkginlog.NewSamplingExtension(&config.Sampling); ext != nil {
+		res = append(res, ext)
+	}
+
+	if config.CLF.Enabled {
+		paths := config.CLF.OutputPaths
+		if len(paths) < 1 {
+			paths = []string{"stdout"}
+		}
+
+		writer, closeWriter, err := zap.Open(paths...)
+		if err != nil {
+			return nil, err
+		}
+		hooks.add(fmt.Sprintf("%s-clf", entryName), closeWriter)
+
+		ext, err := rkginlog.NewCLFExtension(&config.CLF, writer)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ext)
+	}
+
+	return res, nil
+}
+
+// newLoggingOptions converts boot config of logging middleware into options.
+//
+// A dedicated event logger is built from config of eventEntry if access log is customized,
+// loki syncer of eventEntry is not attached to it. Shutdown hooks of its writers are added into hooks.
+func newLoggingOptions(config *BootMiddlewareLogging, entryName string,
+	loggerEntry *rkentry.LoggerEntry, eventEntry *rkentry.EventEntry, hooks *pendingHooks) ([]rkmidlog.Option, error) {
+	boot := config.BootConfig
+	boot.Ignore = append(append([]string{}, boot.Ignore...), config.IgnorePrefix...)
+	if !config.isCustomized() {
+		return rkmidlog.ToOptions(&boot, entryName, GinEntryType, loggerEntry, eventEntry), nil
+	}
+
+	if len(config.Format) > 0 {
+		encoding, ok := accessLogFormats[strings.ToLower(config.Format)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported format %s of logging middleware, should be one of event, json or console", config.Format)
+		}
+		boot.EventEncoding = encoding
+	}
+
+	accessLogEntry, err := newAccessLogEventEntry(config, entryName, boot.EventEncoding, eventEntry, hooks)
+	if err != nil {
+		return nil, err
+	}
+
+	// output paths are handled by access log event entry
+	boot.EventOutputPaths = nil
+
+	return rkmidlog.ToOptions(&boot, entryName, GinEntryType, loggerEntry, accessLogEntry), nil
+}
+
+// newAccessLogEventEntry creates event entry whose logger drops omitted fields and events carry static fields.
+//
+// Syslog writer, sinks and async writer are closed with shutdown hooks added into hooks, queued events are sent
+// before closed, hooks are released by caller if error occurs.
+func newAccessLogEventEntry(config *BootMiddlewareLogging, entryName, encoding string,
+	eventEntry *rkentry.EventEntry, hooks *pendingHooks) (*rkentry.EventEntry, error) {
+	loggerConfig := rklogger.NewZapEventConfig()
+	lumberjackConfig := rklogger.NewLumberjackConfigDefault()
+	if eventEntry != nil && eventEntry.LoggerConfig != nil {
+		copied := *eventEntry.LoggerConfig
+		loggerConfig = &copied
+	}
+	if eventEntry != nil && eventEntry.LumberjackConfig != nil {
+		lumberjackConfig = eventEntry.LumberjackConfig
+	}
+
+	if len(config.EventOutputPaths) > 0 {
+		loggerConfig.OutputPaths = config.EventOutputPaths
+	}
+
+	// dedicated file of access log, rotation settings override ones of eventEntry
+	if config.Lumberjack != nil {
+		lumberjackConfig = overrideAccessLogLumberjack(lumberjackConfig, config.Lumberjack)
+		if len(config.Lumberjack.Filename) > 0 {
+			loggerConfig.OutputPaths = []string{config.Lumberjack.Filename}
+		}
+	}
+
+	// events are logged with empty message in json encoding
+	if rkquery.ToEncoding(encoding) == rkquery.JSON {
+		loggerConfig.Encoding = "json"
+		loggerConfig.EncoderConfig.MessageKey = ""
+	}
+
+	omit := make(map[string]bool)
+	for _, v := range config.OmitFields {
+		omit[v] = true
+	}
+
+	syncers := make([]zapcore.WriteSyncer, 0)
+	if config.Syslog.Enabled {
+		writer, err := newSyslogWriter(&config.Syslog)
+		if err != nil {
+			return nil, err
+		}
+		syncers = append(syncers, writer)
+		hooks.add(fmt.Sprintf("%s-syslog", entryName), func() {
+			writer.Close()
+		})
+	}
+
+	for i := range config.Sinks {
+		name := fmt.Sprintf("%s-sink-%s-%d", entryName, config.Sinks[i].Type, i)
+		syncer, err := newSinkSyncer(name, &config.Sinks[i])
+		if err != nil {
+			return nil, err
+		}
+		syncers = append(syncers, syncer)
+		hooks.add(name, syncer.Close)
+	}
+
+	var async *asyncWriter
+	if config.Async.Enabled {
+		name := fmt.Sprintf("%s-async", entryName)
+		async = newAsyncWriter(name, &config.Async)
+		hooks.add(name, async.Close)
+	}
+
+	// files are written by writers kept in accessLogFiles, so that they could be reopened on reload
+	stdConfig := *loggerConfig
+	stdConfig.OutputPaths = make([]string, 0)
+	for i, p := range loggerConfig.OutputPaths {
+		if p == "stdout" || p == "stderr" {
+			stdConfig.OutputPaths = append(stdConfig.OutputPaths, p)
+			continue
+		}
+		writer := &lumberjack.Logger{
+			Filename:   p,
+			MaxSize:    lumberjackConfig.MaxSize,
+			MaxAge:     lumberjackConfig.MaxAge,
+			MaxBackups: lumberjackConfig.MaxBackups,
+			LocalTime:  lumberjackConfig.LocalTime,
+			Compress:   lumberjackConfig.Compress,
+		}
+		name := fmt.Sprintf("%s-access-log-file-%d", entryName, i)
+		accessLogFiles.add(name, writer)
+		hooks.add(name, func() {
+			accessLogFiles.remove(name, writer)
+			writer.Close()
+		})
+		syncers = append(syncers, zapcore.AddSync(writer))
+	}
+
+	logger, err := rklogger.NewZapLoggerWithConfAndSyncer(&stdConfig, lumberjackConfig, syncers, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if len(omit) > 0 {
+			core = &accessLogCore{Core: core, omit: omit}
+		}
+		if async != nil {
+			core = &asyncCore{Core: core, writer: async}
+		}
+		return core
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(config.Fields))
+	for k := range config.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]zap.Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, zap.String(k, config.Fields[k]))
+	}
+
+	factory := rkquery.NewEventFactory(
+		rkquery.WithZapLogger(logger),
+		rkquery.WithEncoding(rkquery.ToEncoding(encoding)),
+		rkquery.WithAppName(rkentry.GlobalAppCtx.GetAppInfoEntry().AppName),
+		rkquery.WithAppVersion(rkentry.GlobalAppCtx.GetAppInfoEntry().Version),
+		rkquery.WithPayloads(fields...))
+
+	return &rkentry.EventEntry{
+		EventFactory:     factory,
+		EventHelper:      rkquery.NewEventHelper(factory),
+		LoggerConfig:     loggerConfig,
+		LumberjackConfig: lumberjackConfig,
+	}, nil
+}
+
+// accessLogFiles writers of access log files, keyed by name of their shutdown hooks.
+var accessLogFiles = &accessLogFileRegistry{files: make(map[string]*lumberjack.Logger)}
+
+// accessLogFileRegistry keeps writers of access log files, so that they could be reopened on reload.
+type accessLogFileRegistry struct {
+	lock  sync.Mutex
+	files map[string]*lumberjack.Logger
+}
+
+// add writer with name, writer registered with same name before is replaced.
+func (r *accessLogFileRegistry) add(name string, writer *lumberjack.Logger) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.files[name] = writer
+}
+
+// remove writer with name if it is not replaced by another writer.
+func (r *accessLogFileRegistry) remove(name string, writer *lumberjack.Logger) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.files[name] == writer {
+		delete(r.files, name)
+	}
+}
+
+// reopenAccessLogFiles closes files of access logs, lumberjack opens file with same name again on next write,
+// so that file moved by logrotate is created again.
+func reopenAccessLogFiles() error {
+	accessLogFiles.lock.Lock()
+	defer accessLogFiles.lock.Unlock()
+
+	var res error
+	for _, writer := range accessLogFiles.files {
+		if err := writer.Close(); err != nil && res == nil {
+			res = err
+		}
+	}
+
+	return res
+}
+
+// overrideAccessLogLumberjack returns rotation settings of origin overridden by non-empty fields of override.
+func overrideAccessLogLumberjack(origin, override *lumberjack.Logger) *lumberjack.Logger {
+	res := &lumberjack.Logger{
+		Filename:   origin.Filename,
+		MaxSize:    origin.MaxSize,
+		MaxAge:     origin.MaxAge,
+		MaxBackups: origin.MaxBackups,
+		LocalTime:  override.LocalTime,
+		Compress:   override.Compress,
+	}
+
+	if len(override.Filename) > 0 {
+		res.Filename = override.Filename
+	}
+	if override.MaxSize > 0 {
+		res.MaxSize = override.MaxSize
+	}
+	if override.MaxAge > 0 {
+		res.MaxAge = override.MaxAge
+	}
+	if override.MaxBackups > 0 {
+		res.MaxBackups = override.MaxBackups
+	}
+
+	return res
+}
+
+// accessLogCore drops fields with omitted keys, which are sections of event in json encoding,
+// like payloads, env, app, ids, pairs, counters, error and timing.
+type accessLogCore struct {
+	zapcore.Core
+	omit map[string]bool
+}
+
+// With drops omitted fields before adding fields into core.
+func (c *accessLogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &accessLogCore{Core: c.Core.With(c.filter(fields)), omit: c.omit}
+}
+
+// Check adds itself into checked entry instead of wrapped core, so Write could filter fields.
+func (c *accessLogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write drops omitted fields before writing.
+func (c *accessLogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.filter(fields))
+}
+
+// filter returns fields without omitted keys.
+func (c *accessLogCore) filter(fields []zapcore.Field) []zapcore.Field {
+	res := make([]zapcore.Field, 0, len(fields))
+	for i := range fields {
+		if !c.omit[fields[i].Key] {
+			res = append(res, fields[i])
+		}
+	}
+	return res
+}
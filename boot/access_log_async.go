@@ -0,0 +1,149 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultAsyncBufferSize = 8192
+	// asyncDropReportInterval is the interval of reporting dropped events.
+	asyncDropReportInterval = 10 * time.Second
+)
+
+// BootAccessLogAsync boot config of writing access events in background.
+//
+// Finished events are queued up to BufferSize, 8192 by default, and written by a background goroutine,
+// events are dropped and counted if queue is full.
+type BootAccessLogAsync struct {
+	Enabled    bool `yaml:"enabled" json:"enabled"`
+	BufferSize int  `yaml:"bufferSize" json:"bufferSize"`
+}
+
+// asyncRecord is an entry waiting to be written with core.
+type asyncRecord struct {
+	core   zapcore.Core
+	ent    zapcore.Entry
+	fields []zapcore.Field
+}
+
+// asyncWriter writes queued records in background.
+type asyncWriter struct {
+	// dropped is the first field to keep 64-bit alignment of atomic operations on 32-bit platforms
+	dropped   uint64
+	name      string
+	queue     chan *asyncRecord
+	closeOnce sync.Once
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// newAsyncWriter creates asyncWriter and starts background goroutine.
+func newAsyncWriter(name string, config *BootAccessLogAsync) *asyncWriter {
+	w := &asyncWriter{
+		name:    name,
+		queue:   make(chan *asyncRecord, positiveOrDefault(config.BufferSize, defaultAsyncBufferSize)),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+// enqueue queues record, record is dropped if queue is full or writer is closed.
+func (w *asyncWriter) enqueue(record *asyncRecord) {
+	select {
+	case <-w.done:
+		atomic.AddUint64(&w.dropped, 1)
+		return
+	default:
+	}
+
+	select {
+	case w.queue <- record:
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+}
+
+// Close stops background goroutine after queued records are written.
+func (w *asyncWriter) Close() {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	<-w.stopped
+}
+
+// run writes queued records and reports dropped events periodically.
+func (w *asyncWriter) run() {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(asyncDropReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case record := <-w.queue:
+			_ = record.core.Write(record.ent, record.fields)
+		case <-ticker.C:
+			w.reportDropped()
+		case <-w.done:
+			for {
+				select {
+				case record := <-w.queue:
+					_ = record.core.Write(record.ent, record.fields)
+				default:
+					w.reportDropped()
+					return
+				}
+			}
+		}
+	}
+}
+
+// reportDropped logs number of events dropped since last report with default logger.
+func (w *asyncWriter) reportDropped() {
+	if dropped := atomic.SwapUint64(&w.dropped, 0); dropped > 0 {
+		rkentry.GlobalAppCtx.GetLoggerEntryDefault().Warn("Access events dropped since buffer is full",
+			zap.String("writer", w.name), zap.Uint64("dropped", dropped))
+	}
+}
+
+// asyncCore queues entries into asyncWriter instead of writing them in caller goroutine.
+//
+// Fields added with With are encoded by wrapped core in caller goroutine, which is how events in json encoding
+// are logged, so records never refer to data of request which may be reused after event finished.
+type asyncCore struct {
+	zapcore.Core
+	writer *asyncWriter
+}
+
+// With adds fields into wrapped core.
+func (c *asyncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &asyncCore{Core: c.Core.With(fields), writer: c.writer}
+}
+
+// Check adds itself into checked entry instead of wrapped core, so Write could queue entry.
+func (c *asyncCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write queues entry.
+func (c *asyncCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.writer.enqueue(&asyncRecord{core: c.Core, ent: ent, fields: fields})
+	return nil
+}
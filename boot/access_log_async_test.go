@@ -0,0 +1,87 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAsyncCore(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	writer := newAsyncWriter("ut-async", &BootAccessLogAsync{Enabled: true})
+	assert.Equal(t, defaultAsyncBufferSize, cap(writer.queue))
+
+	logger := zap.New(&asyncCore{Core: core, writer: writer})
+	logger.With(zap.String("k", "v")).Info("ut-message")
+	logger.Debug("ut-debug")
+
+	// queued entries are written when closed
+	writer.Close()
+	assert.Equal(t, 1, logs.Len())
+	assert.Equal(t, "ut-message", logs.All()[0].Message)
+	assert.Equal(t, "v", logs.All()[0].ContextMap()["k"])
+
+	// entries are dropped after closed
+	logger.Info("ut-closed")
+	assert.Equal(t, 1, logs.Len())
+	assert.Equal(t, uint64(1), writer.dropped)
+}
+
+func TestAsyncWriter_Drop(t *testing.T) {
+	writer := &asyncWriter{queue: make(chan *asyncRecord, 1), done: make(chan struct{})}
+
+	writer.enqueue(&asyncRecord{})
+	writer.enqueue(&asyncRecord{})
+	assert.Equal(t, uint64(1), writer.dropped)
+
+	writer.reportDropped()
+	assert.Equal(t, uint64(0), writer.dropped)
+}
+
+func TestAccessLog_Async(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.log")
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{output},
+			},
+			Format: "json",
+			Async:  BootAccessLogAsync{Enabled: true, BufferSize: 16},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-async"))
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+
+	// writer is closed with shutdown hook
+	hook := rkentry.GlobalAppCtx.GetShutdownHook("ut-access-log-async-async")
+	assert.NotNil(t, hook)
+	hook()
+	rkentry.GlobalAppCtx.RemoveShutdownHook("ut-access-log-async-async")
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	event := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &event))
+	assert.Equal(t, "/ut-path", event["operation"])
+}
@@ -0,0 +1,364 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultSinkBatchSize       = 100
+	defaultSinkFlushIntervalMs = 1000
+	defaultSinkQueueSize       = 10000
+	defaultSinkTimeoutMs       = 5000
+)
+
+var eventSinkFactories = &eventSinkRegistry{
+	factories: map[string]EventSinkFactory{
+		"kafka": newKafkaEventSink,
+		"loki":  newLokiEventSink,
+	},
+}
+
+// EventSink sends batch of finished access events, each of which is an encoded event.
+type EventSink interface {
+	Send(ctx context.Context, events [][]byte) error
+}
+
+// EventSinkFactory creates EventSink from boot config.
+type EventSinkFactory func(config *BootAccessLogSink) (EventSink, error)
+
+// eventSinkRegistry keeps factories of event sinks which could be referenced by type in boot config.
+type eventSinkRegistry struct {
+	lock      sync.RWMutex
+	factories map[string]EventSinkFactory
+}
+
+// BootAccessLogSink boot config of pushing access events to external system in batches.
+//
+// Type could be kafka, loki or type registered with RegisterEventSink.
+// Events of kafka are produced to Topic via Kafka REST Proxy at Addr, events of loki are pushed to Addr with Labels.
+//
+// Events are queued up to QueueSize and sent when BatchSize is reached or every FlushIntervalMs,
+// events are dropped if queue is full and Overflow is drop, or request waits for queue if Overflow is block.
+type BootAccessLogSink struct {
+	Type            string            `yaml:"type" json:"type"`
+	Addr            string            `yaml:"addr" json:"addr"`
+	Topic           string            `yaml:"topic" json:"topic"`
+	Labels          map[string]string `yaml:"labels" json:"labels"`
+	Username        string            `yaml:"username" json:"username"`
+	Password        string            `yaml:"password" json:"password"`
+	BatchSize       int               `yaml:"batchSize" json:"batchSize"`
+	FlushIntervalMs int               `yaml:"flushIntervalMs" json:"flushIntervalMs"`
+	QueueSize       int               `yaml:"queueSize" json:"queueSize"`
+	Overflow        string            `yaml:"overflow" json:"overflow"`
+	TimeoutMs       int               `yaml:"timeoutMs" json:"timeoutMs"`
+}
+
+// RegisterEventSink register factory of event sink with type, so it could be used in sinks of logging middleware.
+//
+// Factory with same type will be replaced, this function should be called before GinEntry registered from boot config.
+//
+// Example:
+//
+//	rkgin.RegisterEventSink("nats", func(config *rkgin.BootAccessLogSink) (rkgin.EventSink, error) {
+//	    return newNatsSink(config.Addr, config.Topic)
+//	})
+func RegisterEventSink(sinkType string, f EventSinkFactory) {
+	if len(sinkType) < 1 || f == nil {
+		return
+	}
+
+	eventSinkFactories.lock.Lock()
+	defer eventSinkFactories.lock.Unlock()
+
+	eventSinkFactories.factories[sinkType] = f
+}
+
+// getEventSinkFactory returns factory registered with type, nil if not exist.
+func getEventSinkFactory(sinkType string) EventSinkFactory {
+	eventSinkFactories.lock.RLock()
+	defer eventSinkFactories.lock.RUnlock()
+
+	return eventSinkFactories.factories[sinkType]
+}
+
+// ***************** Batching *****************
+
+// sinkSyncer is a zapcore.WriteSyncer which queues events and sends them to sink in batches.
+type sinkSyncer struct {
+	// dropped is the first field to keep 64-bit alignment of atomic operations on 32-bit platforms
+	dropped   uint64
+	name      string
+	sink      EventSink
+	queue     chan []byte
+	batchSize int
+	interval  time.Duration
+	timeout   time.Duration
+	block     bool
+	closeOnce sync.Once
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// newSinkSyncer creates sink from config and starts background goroutine which sends events.
+func newSinkSyncer(name string, config *BootAccessLogSink) (*sinkSyncer, error) {
+	factory := getEventSinkFactory(config.Type)
+	if factory == nil {
+		return nil, fmt.Errorf("event sink %s is not registered with RegisterEventSink", config.Type)
+	}
+
+	block := false
+	switch strings.ToLower(config.Overflow) {
+	case "", "drop":
+	case "block":
+		block = true
+	default:
+		return nil, fmt.Errorf("unsupported overflow %s of event sink, should be one of drop or block", config.Overflow)
+	}
+
+	sink, err := factory(config)
+	if err != nil {
+		return nil, err
+	}
+
+	syncer := &sinkSyncer{
+		name:      name,
+		sink:      sink,
+		queue:     make(chan []byte, positiveOrDefault(config.QueueSize, defaultSinkQueueSize)),
+		batchSize: positiveOrDefault(config.BatchSize, defaultSinkBatchSize),
+		interval:  time.Duration(positiveOrDefault(config.FlushIntervalMs, defaultSinkFlushIntervalMs)) * time.Millisecond,
+		timeout:   time.Duration(positiveOrDefault(config.TimeoutMs, defaultSinkTimeoutMs)) * time.Millisecond,
+		block:     block,
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+
+	go syncer.run()
+
+	return syncer, nil
+}
+
+// Write queues a copy of p, p is dropped if queue is full and overflow is not block, error is returned if closed.
+func (s *sinkSyncer) Write(p []byte) (int, error) {
+	event := append([]byte(nil), bytes.TrimRight(p, "\n")...)
+
+	select {
+	case <-s.done:
+		return 0, fmt.Errorf("event sink %s is closed", s.name)
+	default:
+	}
+
+	if s.block {
+		select {
+		case s.queue <- event:
+			return len(p), nil
+		case <-s.done:
+			return 0, fmt.Errorf("event sink %s is closed", s.name)
+		}
+	}
+
+	select {
+	case s.queue <- event:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+
+	return len(p), nil
+}
+
+// Sync does nothing since events are sent in background.
+func (s *sinkSyncer) Sync() error {
+	return nil
+}
+
+// Close stops background goroutine after queued events are sent.
+func (s *sinkSyncer) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	<-s.stopped
+}
+
+// run sends events when batch is full or flush interval is reached, queued events are sent before stopped.
+func (s *sinkSyncer) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, s.batchSize)
+	for {
+		select {
+		case event := <-s.queue:
+			if batch = append(batch, event); len(batch) >= s.batchSize {
+				batch = s.flush(batch)
+			}
+		case <-ticker.C:
+			batch = s.flush(batch)
+		case <-s.done:
+			for {
+				select {
+				case event := <-s.queue:
+					if batch = append(batch, event); len(batch) >= s.batchSize {
+						batch = s.flush(batch)
+					}
+				default:
+					s.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush sends batch and returns emptied batch, failures and drops are reported to default logger.
+func (s *sinkSyncer) flush(batch [][]byte) [][]byte {
+	if dropped := atomic.SwapUint64(&s.dropped, 0); dropped > 0 {
+		rkentry.GlobalAppCtx.GetLoggerEntryDefault().Warn("Events dropped since queue of event sink is full",
+			zap.String("sink", s.name), zap.Uint64("dropped", dropped))
+	}
+
+	if len(batch) < 1 {
+		return batch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if err := s.sink.Send(ctx, batch); err != nil {
+		rkentry.GlobalAppCtx.GetLoggerEntryDefault().Warn("Failed to send events to event sink",
+			zap.String("sink", s.name), zap.Int("events", len(batch)), zap.Error(err))
+	}
+
+	return batch[:0]
+}
+
+// positiveOrDefault returns def if v is not positive.
+func positiveOrDefault(v, def int) int {
+	if v < 1 {
+		return def
+	}
+	return v
+}
+
+// ***************** Built-in sinks *****************
+
+// httpEventSink posts batch of events built with newBody to url.
+type httpEventSink struct {
+	url         string
+	contentType string
+	username    string
+	password    string
+	client      *http.Client
+	newBody     func(events [][]byte) ([]byte, error)
+}
+
+// Send posts events and returns error if response is not 2xx.
+func (s *httpEventSink) Send(ctx context.Context, events [][]byte) error {
+	body, err := s.newBody(events)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", s.contentType)
+	if len(s.username) > 0 {
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d from %s, %s", resp.StatusCode, s.url, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+// newKafkaEventSink creates sink which produces events to topic via Kafka REST Proxy with binary embedded format.
+func newKafkaEventSink(config *BootAccessLogSink) (EventSink, error) {
+	if len(config.Addr) < 1 || len(config.Topic) < 1 {
+		return nil, fmt.Errorf("addr and topic are required by kafka event sink")
+	}
+
+	type record struct {
+		Value string `json:"value"`
+	}
+
+	return &httpEventSink{
+		url:         strings.TrimSuffix(config.Addr, "/") + "/topics/" + url.PathEscape(config.Topic),
+		contentType: "application/vnd.kafka.binary.v2+json",
+		username:    config.Username,
+		password:    config.Password,
+		client:      &http.Client{},
+		newBody: func(events [][]byte) ([]byte, error) {
+			records := make([]record, 0, len(events))
+			for i := range events {
+				records = append(records, record{Value: base64.StdEncoding.EncodeToString(events[i])})
+			}
+			return json.Marshal(map[string]interface{}{"records": records})
+		},
+	}, nil
+}
+
+// newLokiEventSink creates sink which pushes events to loki as a stream with labels,
+// app label is name of application by default.
+func newLokiEventSink(config *BootAccessLogSink) (EventSink, error) {
+	if len(config.Addr) < 1 {
+		return nil, fmt.Errorf("addr is required by loki event sink")
+	}
+
+	labels := map[string]string{
+		"app": rkentry.GlobalAppCtx.GetAppInfoEntry().AppName,
+	}
+	for k, v := range config.Labels {
+		labels[k] = v
+	}
+
+	return &httpEventSink{
+		url:         strings.TrimSuffix(config.Addr, "/") + "/loki/api/v1/push",
+		contentType: "application/json",
+		username:    config.Username,
+		password:    config.Password,
+		client:      &http.Client{},
+		newBody: func(events [][]byte) ([]byte, error) {
+			// timestamps are increased by one nanosecond to keep order of events in stream
+			now := time.Now().UnixNano()
+			values := make([][]string, 0, len(events))
+			for i := range events {
+				values = append(values, []string{strconv.FormatInt(now+int64(i), 10), string(events[i])})
+			}
+			return json.Marshal(map[string]interface{}{
+				"streams": []interface{}{
+					map[string]interface{}{"stream": labels, "values": values},
+				},
+			})
+		},
+	}, nil
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/log"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// utEventSink keeps batches sent to it.
+type utEventSink struct {
+	lock    sync.Mutex
+	batches [][]string
+}
+
+func (s *utEventSink) Send(ctx context.Context, events [][]byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	batch := make([]string, 0, len(events))
+	for i := range events {
+		batch = append(batch, string(events[i]))
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func TestRegisterEventSink(t *testing.T) {
+	defer func() {
+		delete(eventSinkFactories.factories, "ut-sink")
+	}()
+
+	RegisterEventSink("", nil)
+	RegisterEventSink("ut-sink", nil)
+	assert.Nil(t, getEventSinkFactory("ut-sink"))
+
+	RegisterEventSink("ut-sink", func(config *BootAccessLogSink) (EventSink, error) {
+		return &utEventSink{}, nil
+	})
+	assert.NotNil(t, getEventSinkFactory("ut-sink"))
+	assert.NotNil(t, getEventSinkFactory("kafka"))
+	assert.NotNil(t, getEventSinkFactory("loki"))
+}
+
+func TestNewSinkSyncer(t *testing.T) {
+	// not registered
+	syncer, err := newSinkSyncer("ut", &BootAccessLogSink{Type: "invalid"})
+	assert.Nil(t, syncer)
+	assert.NotNil(t, err)
+
+	// invalid overflow
+	syncer, err = newSinkSyncer("ut", &BootAccessLogSink{Type: "loki", Addr: "http://localhost", Overflow: "invalid"})
+	assert.Nil(t, syncer)
+	assert.NotNil(t, err)
+
+	// invalid config of sink
+	syncer, err = newSinkSyncer("ut", &BootAccessLogSink{Type: "kafka", Addr: "http://localhost"})
+	assert.Nil(t, syncer)
+	assert.NotNil(t, err)
+
+	syncer, err = newSinkSyncer("ut", &BootAccessLogSink{Type: "loki", Addr: "http://localhost", Overflow: "block"})
+	assert.Nil(t, err)
+	assert.True(t, syncer.block)
+	assert.Equal(t, defaultSinkBatchSize, syncer.batchSize)
+	assert.Equal(t, defaultSinkQueueSize, cap(syncer.queue))
+	syncer.Close()
+
+	// closed sink rejects events
+	_, err = syncer.Write([]byte("ut-event"))
+	assert.NotNil(t, err)
+}
+
+func TestSinkSyncer_Batch(t *testing.T) {
+	sink := &utEventSink{}
+	RegisterEventSink("ut-sink", func(config *BootAccessLogSink) (EventSink, error) {
+		return sink, nil
+	})
+	defer delete(eventSinkFactories.factories, "ut-sink")
+
+	syncer, err := newSinkSyncer("ut", &BootAccessLogSink{Type: "ut-sink", BatchSize: 2, FlushIntervalMs: 60000})
+	assert.Nil(t, err)
+
+	for _, v := range []string{"a\n", "b\n", "c\n"} {
+		n, err := syncer.Write([]byte(v))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, n)
+	}
+	assert.Nil(t, syncer.Sync())
+
+	// queued events are sent when closed
+	syncer.Close()
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, sink.batches)
+}
+
+func TestSinkSyncer_Drop(t *testing.T) {
+	syncer := &sinkSyncer{queue: make(chan []byte, 1), done: make(chan struct{})}
+
+	_, err := syncer.Write([]byte("a"))
+	assert.Nil(t, err)
+	_, err = syncer.Write([]byte("b"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), syncer.dropped)
+}
+
+func TestKafkaEventSink(t *testing.T) {
+	var path, contentType string
+	body := map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+	}))
+	defer server.Close()
+
+	sink, err := newKafkaEventSink(&BootAccessLogSink{Addr: server.URL + "/", Topic: "ut-topic"})
+	assert.Nil(t, err)
+	assert.Nil(t, sink.Send(context.Background(), [][]byte{[]byte("a"), []byte("b")}))
+
+	assert.Equal(t, "/topics/ut-topic", path)
+	assert.Equal(t, "application/vnd.kafka.binary.v2+json", contentType)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"value": "YQ=="},
+		map[string]interface{}{"value": "Yg=="},
+	}, body["records"])
+}
+
+func TestLokiEventSink(t *testing.T) {
+	var path, user string
+	body := map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		user, _, _ = r.BasicAuth()
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, err := newLokiEventSink(&BootAccessLogSink{})
+	assert.NotNil(t, err)
+
+	sink, err := newLokiEventSink(&BootAccessLogSink{
+		Addr:     server.URL,
+		Labels:   map[string]string{"env": "ut"},
+		Username: "ut-user",
+		Password: "ut-pass",
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, sink.Send(context.Background(), [][]byte{[]byte("a"), []byte("b")}))
+
+	assert.Equal(t, "/loki/api/v1/push", path)
+	assert.Equal(t, "ut-user", user)
+
+	stream := body["streams"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "ut", stream["stream"].(map[string]interface{})["env"])
+	assert.Equal(t, rkentry.GlobalAppCtx.GetAppInfoEntry().AppName, stream["stream"].(map[string]interface{})["app"])
+
+	values := stream["values"].([]interface{})
+	assert.Len(t, values, 2)
+	assert.Equal(t, "a", values[0].([]interface{})[1])
+	assert.Equal(t, "b", values[1].([]interface{})[1])
+}
+
+func TestHttpEventSink_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "ut-error", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink, _ := newLokiEventSink(&BootAccessLogSink{Addr: server.URL})
+	err := sink.Send(context.Background(), [][]byte{[]byte("a")})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "ut-error")
+}
+
+func TestAccessLog_Sinks(t *testing.T) {
+	sink := &utEventSink{}
+	RegisterEventSink("ut-sink", func(config *BootAccessLogSink) (EventSink, error) {
+		return sink, nil
+	})
+	defer delete(eventSinkFactories.factories, "ut-sink")
+
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{"stdout"},
+			},
+			Format: "json",
+			Sinks:  []BootAccessLogSink{{Type: "ut-sink"}},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-sink"))
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+
+	// sink is closed with shutdown hook
+	hook := rkentry.GlobalAppCtx.GetShutdownHook("ut-access-log-sink-sink-ut-sink-0")
+	assert.NotNil(t, hook)
+	hook()
+	rkentry.GlobalAppCtx.RemoveShutdownHook("ut-access-log-sink-sink-ut-sink-0")
+
+	assert.Len(t, sink.batches, 1)
+	event := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(sink.batches[0][0]), &event))
+	assert.Equal(t, "/ut-path", event["operation"])
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// syslogSeverityInfo is the severity of access events.
+	syslogSeverityInfo = 6
+	// syslogDialTimeout is the timeout of connecting to syslog server.
+	syslogDialTimeout = 5 * time.Second
+)
+
+// syslogFacilities maps facility names to codes defined in RFC5424.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogLocalAddrs are unix sockets of local syslog daemon.
+var syslogLocalAddrs = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// BootAccessLogSyslog boot config of sending access events to syslog in RFC5424 format.
+//
+// Network could be udp, tcp or unix, events are sent to local syslog daemon if Network and Addr are empty.
+// Facility is local0 by default and Tag, the APP-NAME of message, is name of application by default.
+type BootAccessLogSyslog struct {
+	Enabled  bool   `yaml:"enabled" json:"enabled"`
+	Network  string `yaml:"network" json:"network"`
+	Addr     string `yaml:"addr" json:"addr"`
+	Facility string `yaml:"facility" json:"facility"`
+	Tag      string `yaml:"tag" json:"tag"`
+}
+
+// syslogWriter is a zapcore.WriteSyncer which sends every write as a syslog message,
+// connection is re-established once if write fails.
+type syslogWriter struct {
+	network  string
+	addr     string
+	priority int
+	hostname string
+	tag      string
+	lock     sync.Mutex
+	conn     net.Conn
+}
+
+// newSyslogWriter creates syslogWriter and connects to syslog server.
+func newSyslogWriter(config *BootAccessLogSyslog) (*syslogWriter, error) {
+	facility := "local0"
+	if len(config.Facility) > 0 {
+		facility = strings.ToLower(config.Facility)
+	}
+	code, ok := syslogFacilities[facility]
+	if !ok {
+		return nil, fmt.Errorf("unsupported syslog facility %s", config.Facility)
+	}
+
+	w := &syslogWriter{
+		network:  config.Network,
+		addr:     config.Addr,
+		priority: code*8 + syslogSeverityInfo,
+		tag:      config.Tag,
+	}
+
+	if len(w.tag) < 1 {
+		w.tag = rkentry.GlobalAppCtx.GetAppInfoEntry().AppName
+	}
+	if w.hostname, _ = os.Hostname(); len(w.hostname) < 1 {
+		w.hostname = "-"
+	}
+
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// connect dials syslog server, local syslog daemon is tried if network and addr are empty.
+func (w *syslogWriter) connect() error {
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+
+	if len(w.network) > 0 || len(w.addr) > 0 {
+		conn, err := net.DialTimeout(w.network, w.addr, syslogDialTimeout)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+		return nil
+	}
+
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, addr := range syslogLocalAddrs {
+			if conn, err := net.DialTimeout(network, addr, syslogDialTimeout); err == nil {
+				w.conn = conn
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("local syslog daemon is unavailable")
+}
+
+// format returns RFC5424 message, messages over tcp are framed with octet counting of RFC6587
+// and messages over unix stream are terminated with newline.
+func (w *syslogWriter) format(p []byte) []byte {
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+		w.priority, time.Now().Format(time.RFC3339Nano), w.hostname, w.tag, os.Getpid(), bytes.TrimRight(p, "\n"))
+
+	switch w.conn.LocalAddr().Network() {
+	case "tcp", "tcp4", "tcp6":
+		return []byte(strconv.Itoa(len(msg)) + " " + msg)
+	case "unix":
+		return []byte(msg + "\n")
+	}
+
+	return []byte(msg)
+}
+
+// Write sends p as a syslog message.
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := w.conn.Write(w.format(p)); err != nil {
+		// reconnect once
+		if err = w.connect(); err != nil {
+			return 0, err
+		}
+		if _, err = w.conn.Write(w.format(p)); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Sync does nothing since messages are not buffered.
+func (w *syslogWriter) Sync() error {
+	return nil
+}
+
+// Close closes connection.
+func (w *syslogWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
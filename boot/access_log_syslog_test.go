@@ -0,0 +1,144 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/log"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSyslogWriter(t *testing.T) {
+	// invalid facility
+	w, err := newSyslogWriter(&BootAccessLogSyslog{Enabled: true, Network: "udp", Addr: "127.0.0.1:514", Facility: "invalid"})
+	assert.Nil(t, w)
+	assert.NotNil(t, err)
+
+	// server is unavailable
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := listener.Addr().String()
+	listener.Close()
+	w, err = newSyslogWriter(&BootAccessLogSyslog{Enabled: true, Network: "tcp", Addr: addr})
+	assert.Nil(t, w)
+	assert.NotNil(t, err)
+}
+
+func TestSyslogWriter_Udp(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	w, err := newSyslogWriter(&BootAccessLogSyslog{
+		Enabled:  true,
+		Network:  "udp",
+		Addr:     conn.LocalAddr().String(),
+		Facility: "LOCAL1",
+		Tag:      "ut-tag",
+	})
+	assert.Nil(t, err)
+	defer w.Close()
+
+	n, err := w.Write([]byte("ut-message\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 11, n)
+	assert.Nil(t, w.Sync())
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err = conn.ReadFrom(buf)
+	assert.Nil(t, err)
+
+	// <local1*8+info>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "<142>1 "))
+	assert.True(t, strings.HasSuffix(msg, fmt.Sprintf(" ut-tag %d - - ut-message", os.Getpid())))
+}
+
+func TestSyslogWriter_Tcp(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// octet counting framing
+		reader := bufio.NewReader(conn)
+		size, _ := reader.ReadString(' ')
+		var n int
+		fmt.Sscanf(size, "%d", &n)
+		msg := make([]byte, n)
+		_, _ = io.ReadFull(reader, msg)
+		received <- string(msg)
+	}()
+
+	w, err := newSyslogWriter(&BootAccessLogSyslog{Enabled: true, Network: "tcp", Addr: listener.Addr().String(), Tag: "ut-tag"})
+	assert.Nil(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("ut-message\n"))
+	assert.Nil(t, err)
+
+	select {
+	case msg := <-received:
+		assert.True(t, strings.HasPrefix(msg, "<134>1 "))
+		assert.True(t, strings.HasSuffix(msg, " - - ut-message"))
+	case <-time.After(time.Second):
+		assert.Fail(t, "message is not received")
+	}
+}
+
+func TestAccessLog_Syslog(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{"stdout"},
+			},
+			Format: "json",
+			Syslog: BootAccessLogSyslog{Enabled: true, Network: "udp", Addr: conn.LocalAddr().String()},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-syslog"))
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.Nil(t, err)
+	assert.Contains(t, string(buf[:n]), `"operation":"/ut-path"`)
+
+	// syslog writer is closed with shutdown hook
+	hook := rkentry.GlobalAppCtx.GetShutdownHook("ut-access-log-syslog-syslog")
+	assert.NotNil(t, hook)
+	hook()
+	rkentry.GlobalAppCtx.RemoveShutdownHook("ut-access-log-syslog-syslog")
+}
@@ -0,0 +1,524 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/log"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/log"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveWithLogging serves a request with logging middleware built from config and returns content of event log.
+func serveWithLogging(t *testing.T, config *BootMiddlewareLogging) string {
+	output := filepath.Join(t.TempDir(), "event.log")
+	config.Enabled = true
+	config.EventOutputPaths = []string{output}
+
+	opts, err := newLoggingOptions(config, "ut-access-log", nil, nil, &pendingHooks{})
+	assert.Nil(t, err)
+
+	router := gin.New()
+	router.Use(rkginlog.Middleware(opts...))
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path?k=v", nil))
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	return string(data)
+}
+
+func TestNewLoggingOptions(t *testing.T) {
+	// not customized
+	opts, err := newLoggingOptions(&BootMiddlewareLogging{BootConfig: rkmidlog.BootConfig{Enabled: true}}, "ut", nil, nil, &pendingHooks{})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, opts)
+
+	// unsupported format
+	_, err = newLoggingOptions(&BootMiddlewareLogging{Format: "xml"}, "ut", nil, nil, &pendingHooks{})
+	assert.NotNil(t, err)
+}
+
+func TestAccessLog_Json(t *testing.T) {
+	out := serveWithLogging(t, &BootMiddlewareLogging{
+		Format:     "json",
+		OmitFields: []string{"env", "timing"},
+		Fields:     map[string]string{"region": "ut-region"},
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Len(t, lines, 1)
+
+	event := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), &event))
+	assert.NotContains(t, event, "msg")
+	assert.NotContains(t, event, "env")
+	assert.NotContains(t, event, "timing")
+	assert.Equal(t, "200", event["resCode"])
+
+	payloads := event["payloads"].(map[string]interface{})
+	assert.Equal(t, "/ut-path", payloads["apiPath"])
+	assert.Equal(t, "ut-region", payloads["region"])
+
+	// drop payloads
+	out = serveWithLogging(t, &BootMiddlewareLogging{Format: "json", OmitFields: []string{"payloads"}})
+	event = map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(strings.TrimSpace(out)), &event))
+	assert.NotContains(t, event, "payloads")
+	assert.Contains(t, event, "resCode")
+}
+
+func TestAccessLog_Console(t *testing.T) {
+	out := serveWithLogging(t, &BootMiddlewareLogging{Format: "console"})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "/ut-path")
+	assert.Contains(t, lines[0], "[200]")
+}
+
+func TestAccessLog_Event(t *testing.T) {
+	out := serveWithLogging(t, &BootMiddlewareLogging{
+		Format: "event",
+		Fields: map[string]string{"region": "ut-region"},
+	})
+
+	assert.Contains(t, out, "------------------------------------------------------------------------")
+	assert.Contains(t, out, `"region":"ut-region"`)
+	assert.Contains(t, out, "resCode=200")
+}
+
+func TestAccessLogCore(t *testing.T) {
+	observed, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(&accessLogCore{Core: observed, omit: map[string]bool{"omitted": true}})
+
+	logger.With(zap.String("omitted", "v"), zap.String("kept", "v")).
+		Info("ut", zap.String("omitted", "v"), zap.String("other", "v"))
+
+	assert.Equal(t, 1, logs.Len())
+	ctx := logs.All()[0].ContextMap()
+	assert.NotContains(t, ctx, "omitted")
+	assert.Contains(t, ctx, "kept")
+	assert.Contains(t, ctx, "other")
+
+	// level is respected
+	logger.Debug("ut")
+	assert.Equal(t, 1, logs.Len())
+}
+
+func TestAccessLog_Ignore(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.log")
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{output},
+			},
+			Format:        "json",
+			IgnorePrefix:  []string{"/metrics"},
+			IgnorePattern: []string{"/healthz", "*.css"},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-ignore"))
+	router.GET("/*any", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	for _, p := range []string{"/metrics", "/metrics/sub", "/healthz", "/static/app.css", "/v1/user"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, p, nil))
+	}
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"apiPath":"/v1/user"`)
+}
+
+func TestAccessLog_Body(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.log")
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{output},
+			},
+			Format: "json",
+			Body:   rkginlog.BodyConfig{Enabled: true},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-body"))
+	router.POST("/ut-path", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"token": "t"})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/ut-path", strings.NewReader(`{"password":"p"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	event := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &event))
+	payloads := event["payloads"].(map[string]interface{})
+	assert.Equal(t, `{"password":"***"}`, payloads["reqBody"])
+	assert.Equal(t, `{"token":"***"}`, payloads["resBody"])
+}
+
+func TestAccessLog_Header(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.log")
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{output},
+			},
+			Format: "json",
+			Header: rkginlog.HeaderConfig{Enabled: true, Request: []string{"Authorization", "X-Req"}},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-header"))
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ut-path", nil)
+	req.Header.Set("Authorization", "Basic secret")
+	req.Header.Set("X-Req", "v")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	event := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &event))
+	payloads := event["payloads"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"Authorization": "Basic ***", "X-Req": "v"}, payloads["reqHeaders"])
+}
+
+func TestAccessLog_TraceAware(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.log")
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{output},
+			},
+			Format:     "json",
+			Header:     rkginlog.HeaderConfig{Enabled: true, Request: []string{"X-Req"}},
+			TraceAware: true,
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-trace-aware"))
+	// starts sampled span just like tracing middleware if X-Sampled is set
+	router.Use(func(ctx *gin.Context) {
+		if len(ctx.GetHeader("X-Sampled")) > 0 {
+			spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    trace.TraceID{1},
+				SpanID:     trace.SpanID{1},
+				TraceFlags: trace.FlagsSampled,
+			})
+			ctx.Request = ctx.Request.WithContext(trace.ContextWithSpanContext(ctx.Request.Context(), spanCtx))
+		}
+	})
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ut-path", nil)
+	req.Header.Set("X-Req", "v")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/ut-path", nil)
+	req.Header.Set("X-Req", "v")
+	req.Header.Set("X-Sampled", "true")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 2)
+
+	// headers are recorded only for sampled trace
+	unsampled, sampled := map[string]interface{}{}, map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), &unsampled))
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &sampled))
+	assert.NotContains(t, unsampled["payloads"], "reqHeaders")
+	assert.Equal(t, map[string]interface{}{"X-Req": "v"}, sampled["payloads"].(map[string]interface{})["reqHeaders"])
+}
+
+func TestAccessLog_Sampling(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.log")
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{output},
+			},
+			Format:   "json",
+			Sampling: rkginlog.SamplingConfig{Enabled: true, Every: 2},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-sampling"))
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.GET("/ut-error", func(ctx *gin.Context) {
+		ctx.Status(http.StatusInternalServerError)
+	})
+
+	for i := 0; i < 4; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+	}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-error", nil))
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[2], "/ut-error")
+}
+
+func TestAccessLog_SlowThreshold(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.log")
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{output},
+			},
+			Format:          "json",
+			SlowThresholdMs: 10,
+			Sampling:        rkginlog.SamplingConfig{Enabled: true},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-slow"))
+	router.GET("/ut-fast", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.GET("/ut-slow", func(ctx *gin.Context) {
+		time.Sleep(20 * time.Millisecond)
+		ctx.Status(http.StatusOK)
+	})
+
+	// fast request is dropped by sampling, slow request is kept
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-fast", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-slow", nil))
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	event := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &event))
+	assert.Equal(t, "/ut-slow", event["operation"])
+	assert.Equal(t, map[string]interface{}{"slow": "true"}, event["pairs"])
+	assert.Equal(t, map[string]interface{}{"slow": float64(1)}, event["counters"])
+}
+
+func TestAccessLog_Lumberjack(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "access.log")
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{"stdout"},
+			},
+			Format:     "json",
+			Lumberjack: &lumberjack.Logger{Filename: output, MaxSize: 10},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-lumberjack"))
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+
+	// events are written into dedicated file instead of event output paths
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	event := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &event))
+	assert.Equal(t, "/ut-path", event["operation"])
+}
+
+func TestReopenAccessLogFiles(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "access.log")
+	config := &BootMiddlewareLogging{
+		BootConfig: rkmidlog.BootConfig{Enabled: true},
+		Format:     "json",
+		Lumberjack: &lumberjack.Logger{Filename: output},
+	}
+
+	hooks := &pendingHooks{}
+	opts, err := newLoggingOptions(config, "ut-access-log-reopen", nil, nil, hooks)
+	assert.Nil(t, err)
+	assert.NotNil(t, accessLogFiles.files["ut-access-log-reopen-access-log-file-0"])
+
+	router := gin.New()
+	router.Use(rkginlog.Middleware(opts...))
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+
+	// file moved by logrotate is created again after reopened
+	assert.Nil(t, os.Rename(output, output+".1"))
+	assert.Nil(t, reopenAccessLogFiles())
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"))
+
+	// writer is removed once closed
+	hooks.release()
+	assert.Nil(t, accessLogFiles.files["ut-access-log-reopen-access-log-file-0"])
+}
+
+func TestOverrideAccessLogLumberjack(t *testing.T) {
+	origin := &lumberjack.Logger{Filename: "origin.log", MaxSize: 1024, MaxAge: 7, MaxBackups: 3, Compress: true}
+
+	res := overrideAccessLogLumberjack(origin, &lumberjack.Logger{MaxAge: 1, LocalTime: true})
+	assert.Equal(t, "origin.log", res.Filename)
+	assert.Equal(t, 1024, res.MaxSize)
+	assert.Equal(t, 1, res.MaxAge)
+	assert.Equal(t, 3, res.MaxBackups)
+	assert.True(t, res.LocalTime)
+	assert.False(t, res.Compress)
+
+	res = overrideAccessLogLumberjack(origin, &lumberjack.Logger{Filename: "access.log", MaxSize: 10, MaxBackups: 5})
+	assert.Equal(t, "access.log", res.Filename)
+	assert.Equal(t, 10, res.MaxSize)
+	assert.Equal(t, 5, res.MaxBackups)
+
+	// origin is not changed
+	assert.Equal(t, "origin.log", origin.Filename)
+}
+
+func TestAccessLog_CLF(t *testing.T) {
+	eventOutput := filepath.Join(t.TempDir(), "event.log")
+	clfOutput := filepath.Join(t.TempDir(), "access.log")
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{eventOutput},
+			},
+			Format: "json",
+			CLF: rkginlog.CLFConfig{
+				Enabled:     true,
+				Format:      "common",
+				OutputPaths: []string{clfOutput},
+				Exclusive:   true,
+			},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-clf"))
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "ut-body")
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+
+	data, err := os.ReadFile(clfOutput)
+	assert.Nil(t, err)
+	assert.Regexp(t, `^192\.0\.2\.1 - - \[.+\] "GET /ut-path HTTP/1\.1" 200 7\n$`, string(data))
+
+	// events are dropped since clf is exclusive
+	data, _ = os.ReadFile(eventOutput)
+	assert.Empty(t, data)
+
+	// output files are closed with shutdown hook
+	hook := rkentry.GlobalAppCtx.GetShutdownHook("ut-access-log-clf-clf")
+	assert.NotNil(t, hook)
+	hook()
+	rkentry.GlobalAppCtx.RemoveShutdownHook("ut-access-log-clf-clf")
+}
+
+func TestAccessLog_Query(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.log")
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{output},
+			},
+			Format: "json",
+			Query:  rkginlog.QueryConfig{Enabled: true, Redact: []string{"sig"}},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-query"))
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, ctx.Query("sig"))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ut-path?id=1&sig=secret", nil))
+	assert.Equal(t, "secret", w.Body.String())
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	event := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &event))
+	assert.Equal(t, "id=1&sig=***", event["payloads"].(map[string]interface{})["apiQuery"])
+}
+
+func TestAccessLog_ErrorClass(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.log")
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{
+			BootConfig: rkmidlog.BootConfig{
+				Enabled:          true,
+				EventOutputPaths: []string{output},
+			},
+			Format: "json",
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "logging", config, "ut-access-log-error-class"))
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusServiceUnavailable)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	event := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &event))
+	assert.Equal(t, map[string]interface{}{"errorClass": "server_error"}, event["pairs"])
+}
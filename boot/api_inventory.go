@@ -0,0 +1,76 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"path"
+	"sort"
+)
+
+// ApiInfo describes a route registered in gin.Engine.
+type ApiInfo struct {
+	Method  string `json:"method" yaml:"method"`
+	Path    string `json:"path" yaml:"path"`
+	Handler string `json:"handler" yaml:"handler"`
+}
+
+// ApisResponse response of apis handler.
+type ApisResponse struct {
+	Entries []*ApiInfo `json:"entries" yaml:"entries"`
+}
+
+// ListApis returns routes registered in Router sorted by path and method.
+func (entry *GinEntry) ListApis() []*ApiInfo {
+	res := make([]*ApiInfo, 0)
+	if entry.Router == nil {
+		return res
+	}
+
+	for _, route := range entry.Router.Routes() {
+		res = append(res, &ApiInfo{
+			Method:  route.Method,
+			Path:    route.Path,
+			Handler: route.Handler,
+		})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Path != res[j].Path {
+			return res[i].Path < res[j].Path
+		}
+		return res[i].Method < res[j].Method
+	})
+
+	return res
+}
+
+// ApisPath returns path of apis handler which sits next to common service paths, /rk/v1/apis by default.
+func (entry *GinEntry) ApisPath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "apis")
+}
+
+// ApisHandler returns routes registered in Router as JSON.
+func (entry *GinEntry) ApisHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, &ApisResponse{
+		Entries: entry.ListApis(),
+	})
+}
+
+// apisForEvent formats routes as "METHOD path handler" for bootstrap event.
+func (entry *GinEntry) apisForEvent() []string {
+	res := make([]string, 0)
+	for _, api := range entry.ListApis() {
+		res = append(res, api.Method+" "+api.Path+" "+api.Handler)
+	}
+
+	return res
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func utApiHandler(ctx *gin.Context) {}
+
+func TestGinEntry_ListApis(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-apis"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Empty(t, entry.ListApis())
+	assert.Empty(t, entry.ApisPath())
+
+	entry.Router.POST("/b", utApiHandler)
+	entry.Router.GET("/b", utApiHandler)
+	entry.Router.GET("/a", utApiHandler)
+
+	apis := entry.ListApis()
+	assert.Len(t, apis, 3)
+	assert.Equal(t, "/a", apis[0].Path)
+	assert.Equal(t, http.MethodGet, apis[1].Method)
+	assert.Equal(t, http.MethodPost, apis[2].Method)
+	assert.Contains(t, apis[0].Handler, "utApiHandler")
+
+	assert.Contains(t, entry.apisForEvent()[0], "GET /a ")
+
+	// nil router
+	assert.Empty(t, (&GinEntry{}).ListApis())
+}
+
+func TestGinEntry_ApisHandler(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-apis"),
+		WithPort(0),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{
+			Enabled: true,
+		})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, "/rk/v1/apis", entry.ApisPath())
+
+	entry.Router.GET("/ut", utApiHandler)
+	entry.Router.GET(entry.ApisPath(), entry.ApisHandler)
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rk/v1/apis", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp := &ApisResponse{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Len(t, resp.Entries, 2)
+	assert.Equal(t, "/rk/v1/apis", resp.Entries[0].Path)
+	assert.Equal(t, "/ut", resp.Entries[1].Path)
+}
+
+func TestGinEntry_Bootstrap_WithApis(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-apis"),
+		WithPort(8080),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{
+			Enabled: true,
+		})))
+
+	entry.Bootstrap(context.TODO())
+	defer entry.Interrupt(context.TODO())
+
+	validateServerIsUp(t, 8080, false)
+
+	found := false
+	for _, api := range entry.ListApis() {
+		if api.Path == "/rk/v1/apis" {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"crypto/x509"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"net/http"
+	"path"
+	"sort"
+	"time"
+)
+
+// CertInfo metadata of a certificate loaded by rkentry.CertEntry.
+//
+// Kind is server for certificate served with TLS, and ca for root CA.
+type CertInfo struct {
+	EntryName    string    `json:"entryName" yaml:"entryName"`
+	Kind         string    `json:"kind" yaml:"kind"`
+	Subject      string    `json:"subject" yaml:"subject"`
+	Issuer       string    `json:"issuer" yaml:"issuer"`
+	SerialNumber string    `json:"serialNumber" yaml:"serialNumber"`
+	DNSNames     []string  `json:"dnsNames" yaml:"dnsNames"`
+	IPAddresses  []string  `json:"ipAddresses" yaml:"ipAddresses"`
+	NotBefore    time.Time `json:"notBefore" yaml:"notBefore"`
+	NotAfter     time.Time `json:"notAfter" yaml:"notAfter"`
+	ExpiresInSec int64     `json:"expiresInSec" yaml:"expiresInSec"`
+}
+
+// CertsResponse response of certs API.
+type CertsResponse struct {
+	Certs []*CertInfo `json:"certs" yaml:"certs"`
+}
+
+// CertsPath returns path of certs API which sits next to common service paths, /rk/v1/certs by default.
+func (entry *GinEntry) CertsPath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "certs")
+}
+
+// CertsHandler returns metadata of certificates in CertEntry of GinEntry and ones registered in rkentry.GlobalAppCtx.
+//
+// Private keys are never exposed.
+func (entry *GinEntry) CertsHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, &CertsResponse{
+		Certs: entry.listCertInfos(time.Now()),
+	})
+}
+
+// listCertInfos returns metadata of certificates sorted by entry name, server certificate comes before CA.
+func (entry *GinEntry) listCertInfos(now time.Time) []*CertInfo {
+	certEntries := make(map[string]*rkentry.CertEntry)
+	for name, e := range rkentry.GlobalAppCtx.ListEntriesByType(rkentry.CertEntryType) {
+		if certEntry, ok := e.(*rkentry.CertEntry); ok {
+			certEntries[name] = certEntry
+		}
+	}
+	if entry.CertEntry != nil {
+		certEntries[entry.CertEntry.GetName()] = entry.CertEntry
+	}
+
+	names := make([]string, 0, len(certEntries))
+	for name := range certEntries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := make([]*CertInfo, 0)
+	for _, name := range names {
+		certEntry := certEntries[name]
+
+		if certEntry.Certificate != nil {
+			leaf := certEntry.Certificate.Leaf
+			if leaf == nil && len(certEntry.Certificate.Certificate) > 0 {
+				leaf, _ = x509.ParseCertificate(certEntry.Certificate.Certificate[0])
+			}
+			if leaf != nil {
+				res = append(res, newCertInfo(name, "server", leaf, now))
+			}
+		}
+
+		if certEntry.RootCA != nil {
+			res = append(res, newCertInfo(name, "ca", certEntry.RootCA, now))
+		}
+	}
+
+	return res
+}
+
+// newCertInfo creates CertInfo from x509.Certificate, ExpiresInSec is negative if already expired.
+func newCertInfo(entryName, kind string, cert *x509.Certificate, now time.Time) *CertInfo {
+	ips := make([]string, 0, len(cert.IPAddresses))
+	for i := range cert.IPAddresses {
+		ips = append(ips, cert.IPAddresses[i].String())
+	}
+
+	return &CertInfo{
+		EntryName:    entryName,
+		Kind:         kind,
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		DNSNames:     append([]string{}, cert.DNSNames...),
+		IPAddresses:  ips,
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		ExpiresInSec: int64(cert.NotAfter.Sub(now).Seconds()),
+	}
+}
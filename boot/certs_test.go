@@ -0,0 +1,101 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newUtCert(t *testing.T, cn string, notAfter time.Time) (*x509.Certificate, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	return cert, der
+}
+
+func TestGinEntry_CertsHandler(t *testing.T) {
+	certEntry := rkentry.RegisterCertEntry(&rkentry.BootCert{
+		Cert: []*rkentry.BootCertE{{Name: "ut-certs"}},
+	})[0]
+	defer rkentry.GlobalAppCtx.RemoveEntry(certEntry)
+
+	_, der := newUtCert(t, "server", time.Now().Add(24*time.Hour))
+	ca, _ := newUtCert(t, "ca", time.Now().Add(-time.Minute))
+	certEntry.Certificate = &tls.Certificate{Certificate: [][]byte{der}}
+	certEntry.RootCA = ca
+
+	entry := RegisterGinEntry(
+		WithName("ut-certs"),
+		WithPort(0),
+		WithCertEntry(certEntry),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{Enabled: true})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, "/rk/v1/certs", entry.CertsPath())
+	entry.Router.GET(entry.CertsPath(), entry.CertsHandler)
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rk/v1/certs", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "PRIVATE")
+
+	res := &CertsResponse{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), res))
+
+	infos := make([]*CertInfo, 0)
+	for _, info := range res.Certs {
+		if info.EntryName == "ut-certs" {
+			infos = append(infos, info)
+		}
+	}
+	assert.Len(t, infos, 2)
+
+	// server certificate parsed from DER
+	assert.Equal(t, "server", infos[0].Kind)
+	assert.Equal(t, "CN=server", infos[0].Subject)
+	assert.Equal(t, "CN=server", infos[0].Issuer)
+	assert.Equal(t, "42", infos[0].SerialNumber)
+	assert.Equal(t, []string{"localhost"}, infos[0].DNSNames)
+	assert.Equal(t, []string{"127.0.0.1"}, infos[0].IPAddresses)
+	assert.True(t, infos[0].ExpiresInSec > 0)
+
+	// expired CA
+	assert.Equal(t, "ca", infos[1].Kind)
+	assert.True(t, infos[1].ExpiresInSec < 0)
+
+	// disabled common service
+	disabled := RegisterGinEntry(WithName("ut-certs-disabled"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(disabled)
+	assert.Empty(t, disabled.CertsPath())
+}
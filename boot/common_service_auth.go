@@ -0,0 +1,95 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/jwt"
+	"net/http"
+)
+
+// BootCommonService boot config of common service.
+//
+// APIs of common service, except readiness and liveness which are probed by orchestrators,
+// could be protected with Auth.
+type BootCommonService struct {
+	rkentry.BootCommonService `mapstructure:",squash" yaml:",inline"`
+	Auth                      BootCommonServiceAuth `yaml:"auth" json:"auth"`
+}
+
+// BootCommonServiceAuth credentials required to access common service, access is not restricted if none is configured.
+//
+// Request is allowed if any of configured methods passes.
+type BootCommonServiceAuth struct {
+	Basic  []string            `yaml:"basic" json:"basic"`
+	ApiKey []string            `yaml:"apiKey" json:"apiKey"`
+	Jwt    rkmidjwt.BootConfig `yaml:"jwt" json:"jwt"`
+}
+
+// WithCommonServiceBasicAuth provide basic auth credentials formed as user:pass required to access common service.
+func WithCommonServiceBasicAuth(cred ...string) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.commonServiceBasicAuth = append(entry.commonServiceBasicAuth, cred...)
+	}
+}
+
+// WithCommonServiceApiKeyAuth provide API keys accepted in X-API-Key header to access common service.
+func WithCommonServiceApiKeyAuth(key ...string) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.commonServiceApiKey = append(entry.commonServiceApiKey, key...)
+	}
+}
+
+// WithCommonServiceJwtAuth accept JWT verified with options to access common service.
+func WithCommonServiceJwtAuth(opts ...rkmidjwt.Option) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.commonServiceJwt = rkmidjwt.NewOptionSet(opts...)
+	}
+}
+
+// isCommonServiceAuthEnabled returns true if any credential is required to access common service.
+func (entry *GinEntry) isCommonServiceAuthEnabled() bool {
+	return len(entry.commonServiceBasicAuth) > 0 || len(entry.commonServiceApiKey) > 0 || entry.commonServiceJwt != nil
+}
+
+// commonServiceAccessHandlers returns handlers which restrict access of common service, empty if not restricted.
+func (entry *GinEntry) commonServiceAccessHandlers() []gin.HandlerFunc {
+	if !entry.isCommonServiceAuthEnabled() {
+		return []gin.HandlerFunc{}
+	}
+
+	return []gin.HandlerFunc{entry.commonServiceAuth()}
+}
+
+// commonServiceAuth returns handler which requires basic auth, API key or JWT.
+func (entry *GinEntry) commonServiceAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if matchBasicOrApiKey(ctx, entry.commonServiceBasicAuth, entry.commonServiceApiKey) {
+			ctx.Next()
+			return
+		}
+
+		if entry.commonServiceJwt != nil {
+			beforeCtx := entry.commonServiceJwt.BeforeCtx(ctx.Request, nil)
+			entry.commonServiceJwt.Before(beforeCtx)
+			if beforeCtx.Output.ErrResp == nil {
+				ctx.Set(rkmid.JwtTokenKey.String(), beforeCtx.Output.JwtToken)
+				ctx.Next()
+				return
+			}
+		}
+
+		if len(entry.commonServiceBasicAuth) > 0 {
+			ctx.Header("WWW-Authenticate", fmt.Sprintf(`Basic realm="%s"`, entry.entryName))
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized,
+			rkmid.GetErrorBuilder().New(http.StatusUnauthorized, "Missing or invalid authorization of common service"))
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/jwt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGinEntry_CommonServiceAccessHandlers(t *testing.T) {
+	// not restricted by default
+	entry := RegisterGinEntry(WithName("ut-cs-auth-none"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.Empty(t, entry.commonServiceAccessHandlers())
+
+	// restricted with basic auth
+	entry = RegisterGinEntry(
+		WithName("ut-cs-auth-basic"),
+		WithCommonServiceBasicAuth("user:pass"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.Len(t, entry.commonServiceAccessHandlers(), 1)
+}
+
+func TestGinEntry_CommonServiceAuth(t *testing.T) {
+	signer := rkentry.RegisterSymmetricJwtSigner("ut-cs-auth-signer", jwt.SigningMethodHS256.Name, []byte("ut-key"))
+	token, err := signer.SignJwt(jwt.MapClaims{"sub": "ut"})
+	assert.Nil(t, err)
+
+	entry := RegisterGinEntry(
+		WithName("ut-cs-auth"),
+		WithPort(0),
+		WithCommonServiceBasicAuth("user:pass"),
+		WithCommonServiceApiKeyAuth("ut-api-key"),
+		WithCommonServiceJwtAuth(rkmidjwt.WithSigner(signer)))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.Router.GET("/ut-cs-auth", append(entry.commonServiceAccessHandlers(), func(ctx *gin.Context) {
+		if _, ok := ctx.Get(rkmid.JwtTokenKey.String()); ok {
+			ctx.String(http.StatusOK, "jwt")
+			return
+		}
+		ctx.String(http.StatusOK, "ok")
+	})...)
+
+	serve := func(modify func(req *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ut-cs-auth", nil)
+		modify(req)
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	// without credentials
+	w := serve(func(req *http.Request) {})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Basic realm="ut-cs-auth"`, w.Header().Get("WWW-Authenticate"))
+
+	// with invalid credentials
+	w = serve(func(req *http.Request) {
+		req.SetBasicAuth("user", "invalid")
+		req.Header.Set(rkmid.HeaderApiKey, "invalid")
+	})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// with basic auth
+	w = serve(func(req *http.Request) {
+		req.SetBasicAuth("user", "pass")
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", w.Body.String())
+
+	// with API key
+	w = serve(func(req *http.Request) {
+		req.Header.Set(rkmid.HeaderApiKey, "ut-api-key")
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// with JWT
+	w = serve(func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "jwt", w.Body.String())
+}
@@ -0,0 +1,484 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// RegisterGinEntriesWithConfig register GinEntry from boot config file.
+//
+// YAML, JSON and TOML files are supported with identical schema, format is detected by extension.
+//
+// Overlay files will be deep-merged into base config in order, so that only deltas need to live in per-env files.
+//
+// Maps are merged recursively, lists of maps with name field, like gin entries, are merged by name,
+// other values in overlay replace values in base.
+//
+//	rkgin.RegisterGinEntriesWithConfig("boot.yaml", "boot-prod.yaml")
+func RegisterGinEntriesWithConfig(configFilePath string, overlayFilePaths ...string) map[string]rkentry.Entry {
+	raw, err := readBootConfigFiles(configFilePath, overlayFilePaths...)
+	if err != nil {
+		rkentry.ShutdownWithError(err)
+	}
+
+	return RegisterGinEntryYAML(raw)
+}
+
+// RegisterGinEntriesWithProfile register GinEntry from boot config file with overlay of profile.
+//
+// Overlay file is located next to config file with profile as suffix, like boot-prod.yaml for boot.yaml,
+// it will be skipped if not exists or profile is empty.
+func RegisterGinEntriesWithProfile(configFilePath, profile string) map[string]rkentry.Entry {
+	overlays := make([]string, 0)
+	if p := ProfileConfigPath(configFilePath, profile); len(p) > 0 {
+		if _, err := os.Stat(p); err == nil {
+			overlays = append(overlays, p)
+		}
+	}
+
+	return RegisterGinEntriesWithConfig(configFilePath, overlays...)
+}
+
+// ProfileConfigPath returns path of overlay file of profile, empty string if profile is empty.
+func ProfileConfigPath(configFilePath, profile string) string {
+	if len(profile) < 1 {
+		return ""
+	}
+
+	ext := filepath.Ext(configFilePath)
+	return strings.TrimSuffix(configFilePath, ext) + "-" + profile + ext
+}
+
+// MergeBootYAML deep-merges overlays into base in order and returns merged YAML.
+func MergeBootYAML(base []byte, overlays ...[]byte) ([]byte, error) {
+	merged, err := unmarshalBootYAMLMap(base)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range overlays {
+		overlay, err := unmarshalBootYAMLMap(overlays[i])
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeBootMap(merged, overlay)
+	}
+
+	return yaml.Marshal(merged)
+}
+
+// preprocessBootYAML applies middlewareDefaults to gin entries and resolves secret references in enabled gin entries.
+//
+// Raw will be returned as it is if nothing changed.
+func preprocessBootYAML(raw []byte) ([]byte, error) {
+	m, err := unmarshalBootYAMLMap(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := applyMiddlewareDefaults(m)
+	if err := resolveGinSecrets(m, &changed); err != nil {
+		return nil, err
+	}
+
+	if !changed {
+		return raw, nil
+	}
+
+	return yaml.Marshal(m)
+}
+
+// preprocessBootConfig applies middlewareDefaults and resolves secret references of deep copy of typed boot config
+// the same way as preprocessBootYAML.
+//
+// Zero values in config are treated as missing, so that middlewareDefaults could fill them,
+// sections listed in skipDefaults of middleware are not filled.
+func preprocessBootConfig(config *BootConfig) (*BootConfig, error) {
+	res := copyBootStruct(reflect.ValueOf(config)).Interface().(*BootConfig)
+
+	defaults := reflect.ValueOf(res.MiddlewareDefaults)
+	if !defaults.IsZero() {
+		for _, element := range res.Gin {
+			if element == nil {
+				continue
+			}
+			mergeBootMiddleware(&element.Middleware, defaults)
+
+			for _, group := range element.Groups {
+				if group == nil || reflect.ValueOf(group.Middleware).IsZero() {
+					continue
+				}
+				mergeBootMiddleware(&group.Middleware, defaults)
+			}
+		}
+	}
+
+	for _, element := range res.Gin {
+		if element == nil || !element.Enabled {
+			continue
+		}
+		if err := resolveSecretsInStruct(reflect.ValueOf(element)); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// mergeBootMiddleware fills zero values of middleware with copy of defaults, except sections in skipDefaults.
+func mergeBootMiddleware(middleware *BootMiddleware, defaults reflect.Value) {
+	dst := reflect.ValueOf(middleware).Elem()
+	for i := 0; i < dst.NumField(); i++ {
+		key := strings.Split(dst.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if key == "skipDefaults" || containsFold(middleware.SkipDefaults, key) {
+			continue
+		}
+		mergeBootStruct(dst.Field(i), defaults.Field(i))
+	}
+}
+
+// mergeBootStruct fills zero values in dst with copy of values in defaults, structs are merged field by field and
+// maps are merged key by key, other values in dst are kept.
+func mergeBootStruct(dst, defaults reflect.Value) {
+	switch dst.Kind() {
+	case reflect.Struct:
+		for i := 0; i < dst.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				mergeBootStruct(dst.Field(i), defaults.Field(i))
+			}
+		}
+		return
+	case reflect.Map:
+		if !dst.IsNil() && !defaults.IsNil() {
+			iter := defaults.MapRange()
+			for iter.Next() {
+				if !dst.MapIndex(iter.Key()).IsValid() {
+					dst.SetMapIndex(iter.Key(), copyBootStruct(iter.Value()))
+				}
+			}
+			return
+		}
+	}
+
+	if dst.IsZero() {
+		dst.Set(copyBootStruct(defaults))
+	}
+}
+
+// copyBootStruct deep copies pointers, structs, slices and maps, other values like functions are shared.
+func copyBootStruct(v reflect.Value) reflect.Value {
+	res := reflect.New(v.Type()).Elem()
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			elem := reflect.New(v.Type().Elem())
+			elem.Elem().Set(copyBootStruct(v.Elem()))
+			res.Set(elem)
+		}
+	case reflect.Struct:
+		// unexported fields are copied shallowly
+		res.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if res.Field(i).CanSet() {
+				res.Field(i).Set(copyBootStruct(v.Field(i)))
+			}
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			res.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+			for i := 0; i < v.Len(); i++ {
+				res.Index(i).Set(copyBootStruct(v.Index(i)))
+			}
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			res.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+			iter := v.MapRange()
+			for iter.Next() {
+				res.SetMapIndex(iter.Key(), copyBootStruct(iter.Value()))
+			}
+		}
+	default:
+		res.Set(v)
+	}
+
+	return res
+}
+
+// containsFold returns true if list contains s, case is ignored.
+func containsFold(list []string, s string) bool {
+	for i := range list {
+		if strings.EqualFold(list[i], s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyMiddlewareDefaults merges top-level middlewareDefaults into middleware of each gin entry and middleware of
+// its groups which declare middleware section, values in entry and group have higher priority.
+// Returns false if middlewareDefaults is missing.
+//
+// Groups without middleware section are skipped, since middlewares of entry are applied to them already.
+func applyMiddlewareDefaults(m map[interface{}]interface{}) bool {
+	defaults, ok := m[findBootMapKey(m, "middlewareDefaults")].(map[interface{}]interface{})
+	if !ok || len(defaults) < 1 {
+		return false
+	}
+
+	list, _ := m[findBootMapKey(m, "gin")].([]interface{})
+	for i := range list {
+		element, ok := list[i].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		mergeMiddlewareDefaults(element, defaults)
+
+		groups, _ := element[findBootMapKey(element, "groups")].([]interface{})
+		for j := range groups {
+			group, ok := groups[j].(map[interface{}]interface{})
+			if !ok || findBootMapKey(group, "middleware") == nil {
+				continue
+			}
+			mergeMiddlewareDefaults(group, defaults)
+		}
+	}
+
+	return true
+}
+
+// mergeMiddlewareDefaults replaces middleware of element with copy of defaults merged with it,
+// sections in skipDefaults of element are not copied.
+func mergeMiddlewareDefaults(element, defaults map[interface{}]interface{}) {
+	merged := copyBootValue(defaults).(map[interface{}]interface{})
+	delete(merged, findBootMapKey(merged, "skipDefaults"))
+	if key := findBootMapKey(element, "middleware"); key != nil {
+		if mid, ok := element[key].(map[interface{}]interface{}); ok {
+			skips, _ := mid[findBootMapKey(mid, "skipDefaults")].([]interface{})
+			for i := range skips {
+				delete(merged, findBootMapKey(merged, fmt.Sprint(skips[i])))
+			}
+			merged = mergeBootMap(merged, mid)
+		}
+		delete(element, key)
+	}
+	element["middleware"] = merged
+}
+
+// copyBootValue deep copies maps and slices.
+func copyBootValue(in interface{}) interface{} {
+	switch v := in.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[interface{}]interface{}, len(v))
+		for k := range v {
+			res[k] = copyBootValue(v[k])
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i := range v {
+			res[i] = copyBootValue(v[i])
+		}
+		return res
+	}
+
+	return in
+}
+
+// readBootConfigFiles reads config file and overlays, returns merged YAML.
+func readBootConfigFiles(configFilePath string, overlayFilePaths ...string) ([]byte, error) {
+	base, err := readBootConfigFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	overlays := make([][]byte, 0, len(overlayFilePaths))
+	for _, p := range overlayFilePaths {
+		overlay, err := readBootConfigFile(p)
+		if err != nil {
+			return nil, err
+		}
+		overlays = append(overlays, overlay)
+	}
+
+	return MergeBootYAML(base, overlays...)
+}
+
+// readBootConfigFile reads boot config file and returns it as YAML.
+//
+// Format is detected by extension, .json and .toml files are converted to YAML with identical schema.
+func readBootConfigFile(filePath string) ([]byte, error) {
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read boot config file %s, %v", filePath, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		raw, err = jsonToBootYAML(raw)
+	case ".toml":
+		raw, err = tomlToBootYAML(raw)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse boot config file %s, %v", filePath, err)
+	}
+
+	return raw, nil
+}
+
+// jsonToBootYAML converts JSON boot config to YAML, integers are kept as integers.
+func jsonToBootYAML(raw []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var m map[string]interface{}
+	if err := decoder.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(convertJSONNumbers(m))
+}
+
+// convertJSONNumbers replaces json.Number with int64 or float64 recursively.
+func convertJSONNumbers(in interface{}) interface{} {
+	switch v := in.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = convertJSONNumbers(v[k])
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = convertJSONNumbers(v[i])
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+
+	return in
+}
+
+// tomlToBootYAML converts TOML boot config to YAML.
+func tomlToBootYAML(raw []byte) ([]byte, error) {
+	var m map[string]interface{}
+	if err := toml.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(m)
+}
+
+// unmarshalBootYAMLMap unmarshal YAML into map, empty input will be treated as empty map.
+func unmarshalBootYAMLMap(raw []byte) (map[interface{}]interface{}, error) {
+	res := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(raw, &res); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal boot config, %v", err)
+	}
+
+	return res, nil
+}
+
+// mergeBootMap merges overlay into base, keys are matched case-insensitively
+// since they are lower-cased in rkentry.UnmarshalBootYAML.
+func mergeBootMap(base, overlay map[interface{}]interface{}) map[interface{}]interface{} {
+	for k, v := range overlay {
+		baseKey := findBootMapKey(base, k)
+		if baseKey == nil {
+			base[k] = v
+			continue
+		}
+
+		base[baseKey] = mergeBootValue(base[baseKey], v)
+	}
+
+	return base
+}
+
+// mergeBootValue merges overlay value into base value.
+func mergeBootValue(base, overlay interface{}) interface{} {
+	switch o := overlay.(type) {
+	case map[interface{}]interface{}:
+		if b, ok := base.(map[interface{}]interface{}); ok {
+			return mergeBootMap(b, o)
+		}
+	case []interface{}:
+		if b, ok := base.([]interface{}); ok && isNamedList(b) && isNamedList(o) {
+			return mergeBootNamedList(b, o)
+		}
+	}
+
+	return overlay
+}
+
+// mergeBootNamedList merges elements with same name, elements only in overlay are appended.
+func mergeBootNamedList(base, overlay []interface{}) []interface{} {
+	for _, o := range overlay {
+		om := o.(map[interface{}]interface{})
+		merged := false
+		for i := range base {
+			bm := base[i].(map[interface{}]interface{})
+			if fmt.Sprint(bm[findBootMapKey(bm, "name")]) == fmt.Sprint(om[findBootMapKey(om, "name")]) {
+				base[i] = mergeBootMap(bm, om)
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			base = append(base, om)
+		}
+	}
+
+	return base
+}
+
+// isNamedList returns true if all elements are maps with name field.
+func isNamedList(list []interface{}) bool {
+	for i := range list {
+		m, ok := list[i].(map[interface{}]interface{})
+		if !ok || findBootMapKey(m, "name") == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findBootMapKey returns key in m which equals to key case-insensitively, nil if not exists.
+func findBootMapKey(m map[interface{}]interface{}, key interface{}) interface{} {
+	if _, ok := m[key]; ok {
+		return key
+	}
+
+	s, ok := key.(string)
+	if !ok {
+		return nil
+	}
+
+	for k := range m {
+		if ks, ok := k.(string); ok && strings.EqualFold(ks, s) {
+			return k
+		}
+	}
+
+	return nil
+}
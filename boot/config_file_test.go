@@ -0,0 +1,319 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeBootConfigFile(t *testing.T, dir, name, content string) string {
+	p := filepath.Join(dir, name)
+	assert.Nil(t, os.WriteFile(p, []byte(content), 0644))
+	return p
+}
+
+func TestMergeBootYAML(t *testing.T) {
+	base := `
+gin:
+  - name: ut-a
+    port: 1949
+    enabled: true
+    commonService:
+      enabled: true
+      pathPrefix: /rk/v1
+    middleware:
+      ignore: ["/a"]
+  - name: ut-b
+    port: 1950
+`
+	overlay := `
+gin:
+  - name: ut-a
+    port: 2949
+    commonservice:
+      enabled: false
+    middleware:
+      ignore: ["/b"]
+  - name: ut-c
+    port: 1951
+`
+	raw, err := MergeBootYAML([]byte(base), []byte(overlay))
+	assert.Nil(t, err)
+
+	res := struct {
+		Gin []*BootGinElement `yaml:"gin"`
+	}{}
+	assert.Nil(t, yaml.Unmarshal(raw, &res))
+
+	assert.Len(t, res.Gin, 3)
+	assert.Equal(t, "ut-a", res.Gin[0].Name)
+	assert.Equal(t, uint64(2949), res.Gin[0].Port)
+	assert.True(t, res.Gin[0].Enabled)
+	assert.False(t, res.Gin[0].CommonService.Enabled)
+	assert.Equal(t, "/rk/v1", res.Gin[0].CommonService.PathPrefix)
+	// lists without name are replaced
+	assert.Equal(t, []string{"/b"}, res.Gin[0].Middleware.Ignore)
+	assert.Equal(t, uint64(1950), res.Gin[1].Port)
+	assert.Equal(t, "ut-c", res.Gin[2].Name)
+
+	// invalid yaml
+	_, err = MergeBootYAML([]byte(base), []byte("invalid"))
+	assert.NotNil(t, err)
+	_, err = MergeBootYAML([]byte("invalid"))
+	assert.NotNil(t, err)
+}
+
+func TestProfileConfigPath(t *testing.T) {
+	assert.Empty(t, ProfileConfigPath("boot.yaml", ""))
+	assert.Equal(t, "boot-prod.yaml", ProfileConfigPath("boot.yaml", "prod"))
+	assert.Equal(t, "conf/boot-prod.json", ProfileConfigPath("conf/boot.json", "prod"))
+}
+
+func TestRegisterGinEntriesWithConfig_WithOverlay(t *testing.T) {
+	dir := t.TempDir()
+	base := writeBootConfigFile(t, dir, "boot.yaml", `
+gin:
+  - name: ut-config-file
+    port: 1949
+    enabled: true
+`)
+	writeBootConfigFile(t, dir, "boot-prod.yaml", `
+gin:
+  - name: ut-config-file
+    port: 2949
+`)
+
+	entries := RegisterGinEntriesWithConfig(base)
+	entry := entries["ut-config-file"].(*GinEntry)
+	assert.Equal(t, uint64(1949), entry.Port)
+	rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entries = RegisterGinEntriesWithProfile(base, "prod")
+	entry = entries["ut-config-file"].(*GinEntry)
+	assert.Equal(t, uint64(2949), entry.Port)
+	rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	// missing profile falls back to base
+	entries = RegisterGinEntriesWithProfile(base, "dev")
+	entry = entries["ut-config-file"].(*GinEntry)
+	assert.Equal(t, uint64(1949), entry.Port)
+	rkentry.GlobalAppCtx.RemoveEntry(entry)
+}
+
+func TestRegisterGinEntriesWithConfig_WithMissingFile(t *testing.T) {
+	defer assertPanic(t)
+
+	RegisterGinEntriesWithConfig(filepath.Join(t.TempDir(), "non-exist.yaml"))
+}
+
+func TestRegisterGinEntriesWithConfig_WithJSONAndTOML(t *testing.T) {
+	dir := t.TempDir()
+	base := writeBootConfigFile(t, dir, "boot.json", `{
+  "gin": [
+    {
+      "name": "ut-config-format",
+      "port": 1949,
+      "enabled": true,
+      "dependsOnTimeoutMs": 1500
+    }
+  ]
+}`)
+	overlay := writeBootConfigFile(t, dir, "boot-prod.TOML", `
+[[gin]]
+name = "ut-config-format"
+port = 2949
+description = "from toml"
+`)
+
+	entries := RegisterGinEntriesWithConfig(base)
+	entry := entries["ut-config-format"].(*GinEntry)
+	assert.Equal(t, uint64(1949), entry.Port)
+	rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entries = RegisterGinEntriesWithConfig(base, overlay)
+	entry = entries["ut-config-format"].(*GinEntry)
+	assert.Equal(t, uint64(2949), entry.Port)
+	assert.Equal(t, "from toml", entry.GetDescription())
+	rkentry.GlobalAppCtx.RemoveEntry(entry)
+}
+
+func TestReadBootConfigFile_WithInvalidFormat(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := readBootConfigFile(writeBootConfigFile(t, dir, "boot.json", "invalid"))
+	assert.NotNil(t, err)
+
+	_, err = readBootConfigFile(writeBootConfigFile(t, dir, "boot.toml", "invalid"))
+	assert.NotNil(t, err)
+}
+
+func TestConvertJSONNumbers(t *testing.T) {
+	raw, err := jsonToBootYAML([]byte(`{"a": 1000000, "b": 1.5, "c": [2]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "a: 1000000\nb: 1.5\nc:\n- 2\n", string(raw))
+}
+
+func TestRegisterGinEntryYAML_WithMiddlewareDefaults(t *testing.T) {
+	bootStr := `
+middlewareDefaults:
+  ignore: ["/ut-ignore"]
+  meta:
+    enabled: true
+    prefix: "ut"
+  cors:
+    enabled: true
+gin:
+  - name: ut-defaults-a
+    port: 1949
+    enabled: true
+  - name: ut-defaults-b
+    port: 1950
+    enabled: true
+    middleware:
+      meta:
+        prefix: "override"
+      cors:
+        enabled: false
+`
+	entries := RegisterGinEntryYAML([]byte(bootStr))
+	a := entries["ut-defaults-a"].(*GinEntry)
+	b := entries["ut-defaults-b"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(a)
+	defer rkentry.GlobalAppCtx.RemoveEntry(b)
+
+	// maintenance, panic, cors and meta
+	assert.Len(t, a.Router.Handlers, 4)
+	// maintenance, panic and meta
+	assert.Len(t, b.Router.Handlers, 3)
+}
+
+func TestPreprocessBootYAML(t *testing.T) {
+	// nothing changed
+	raw := []byte("gin: []\n# comment")
+	res, err := preprocessBootYAML(raw)
+	assert.Nil(t, err)
+	assert.Equal(t, raw, res)
+
+	// defaults not shared between entries
+	res, err = preprocessBootYAML([]byte(`
+middlewareDefaults:
+  ignore: ["/a"]
+gin:
+  - name: a
+  - name: b
+    middleware:
+      ignore: ["/b"]
+    groups:
+      - name: with-middleware
+        middleware:
+          meta:
+            enabled: true
+      - name: without-middleware
+  - name: c
+    middleware:
+      skipDefaults: ["Ignore"]
+`))
+	assert.Nil(t, err)
+
+	config := &BootConfig{}
+	assert.Nil(t, yaml.Unmarshal(res, config))
+	assert.Equal(t, []string{"/a"}, config.Gin[0].Middleware.Ignore)
+	assert.Equal(t, []string{"/b"}, config.Gin[1].Middleware.Ignore)
+
+	// groups with middleware section inherit defaults
+	assert.Equal(t, []string{"/a"}, config.Gin[1].Groups[0].Middleware.Ignore)
+	assert.True(t, config.Gin[1].Groups[0].Middleware.Meta.Enabled)
+	assert.Empty(t, config.Gin[1].Groups[1].Middleware.Ignore)
+
+	// sections in skipDefaults are not inherited
+	assert.Empty(t, config.Gin[2].Middleware.Ignore)
+
+	// invalid
+	_, err = preprocessBootYAML([]byte("invalid"))
+	assert.NotNil(t, err)
+}
+
+func TestPreprocessBootConfig(t *testing.T) {
+	t.Setenv("UT_TYPED_SECRET", "ut-secret")
+
+	reqPerSec := 0
+	config := &BootConfig{
+		Gin: []*BootGinElement{
+			nil,
+			{
+				Enabled:     true,
+				Name:        "a",
+				Description: "env://UT_TYPED_SECRET",
+			},
+			{
+				Enabled: true,
+				Name:    "b",
+				Middleware: BootMiddleware{
+					Ignore: []string{"/b"},
+				},
+			},
+			{
+				Enabled: true,
+				Name:    "c",
+				Middleware: BootMiddleware{
+					SkipDefaults: []string{"cors"},
+				},
+			},
+			{
+				Name:        "disabled",
+				Description: "env://UT_TYPED_SECRET_NOT_EXIST",
+			},
+		},
+	}
+	config.MiddlewareDefaults.Ignore = []string{"/a"}
+	config.MiddlewareDefaults.Meta.Enabled = true
+	config.MiddlewareDefaults.RateLimit.ReqPerSec = &reqPerSec
+	config.MiddlewareDefaults.Cors.Enabled = true
+	config.MiddlewareDefaults.Trace.TailSampling.Decision = func(spans []sdktrace.ReadOnlySpan) bool {
+		return true
+	}
+
+	res, err := preprocessBootConfig(config)
+	assert.Nil(t, err)
+	assert.Len(t, res.Gin, 5)
+	assert.Nil(t, res.Gin[0])
+
+	// secrets resolved in enabled entries
+	assert.Equal(t, "ut-secret", res.Gin[1].Description)
+	assert.Equal(t, "env://UT_TYPED_SECRET_NOT_EXIST", res.Gin[4].Description)
+
+	// defaults merged, values of entry have higher priority
+	assert.Equal(t, []string{"/a"}, res.Gin[1].Middleware.Ignore)
+	assert.True(t, res.Gin[1].Middleware.Meta.Enabled)
+	assert.Equal(t, 0, *res.Gin[1].Middleware.RateLimit.ReqPerSec)
+	assert.Equal(t, []string{"/b"}, res.Gin[2].Middleware.Ignore)
+	assert.True(t, res.Gin[2].Middleware.Meta.Enabled)
+
+	// fields without yaml key are inherited as well
+	assert.True(t, res.Gin[1].Middleware.Cors.Enabled)
+	assert.NotNil(t, res.Gin[1].Middleware.Trace.TailSampling.Decision)
+
+	// sections in skipDefaults keep zero values, so that false overrides defaults
+	assert.False(t, res.Gin[3].Middleware.Cors.Enabled)
+	assert.True(t, res.Gin[3].Middleware.Meta.Enabled)
+	assert.Equal(t, []string{"cors"}, res.Gin[3].Middleware.SkipDefaults)
+	assert.Empty(t, res.Gin[1].Middleware.SkipDefaults)
+
+	// original config is kept
+	assert.Empty(t, config.Gin[1].Middleware.Ignore)
+	assert.False(t, config.Gin[1].Middleware.Cors.Enabled)
+
+	// missing secret
+	config.Gin[4].Enabled = true
+	_, err = preprocessBootConfig(config)
+	assert.NotNil(t, err)
+}
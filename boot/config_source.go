@@ -0,0 +1,429 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultConfigWatchInterval = 10 * time.Second
+
+// ConfigSource provides boot config in YAML from local or remote storage.
+type ConfigSource interface {
+	// Read returns latest boot config.
+	Read(ctx context.Context) ([]byte, error)
+
+	// String returns description of source used in logs.
+	String() string
+}
+
+// FileConfigSource reads boot config from local file.
+//
+// Kubernetes ConfigMap mounted as volume could be watched with FileConfigSource,
+// since kubelet updates mounted files while ConfigMap changes.
+type FileConfigSource struct {
+	Path string
+}
+
+// Read boot config from file.
+func (s *FileConfigSource) Read(context.Context) ([]byte, error) {
+	return readBootConfigFile(s.Path)
+}
+
+// String returns file path.
+func (s *FileConfigSource) String() string {
+	return "file://" + s.Path
+}
+
+// ConsulConfigSource reads boot config from Consul KV with HTTP API.
+type ConsulConfigSource struct {
+	Address string
+	Key     string
+	Token   string
+	Client  *http.Client
+}
+
+// Read boot config from Consul KV.
+func (s *ConsulConfigSource) Read(ctx context.Context) ([]byte, error) {
+	u := fmt.Sprintf("%s/v1/kv/%s?raw", strings.TrimSuffix(s.Address, "/"), strings.TrimPrefix(s.Key, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.Token) > 0 {
+		req.Header.Set("X-Consul-Token", s.Token)
+	}
+
+	return doConfigSourceRequest(s.Client, req)
+}
+
+// String returns address and key.
+func (s *ConsulConfigSource) String() string {
+	return fmt.Sprintf("consul://%s/%s", s.Address, strings.TrimPrefix(s.Key, "/"))
+}
+
+// EtcdConfigSource reads boot config from etcd v3 with gRPC gateway HTTP API.
+type EtcdConfigSource struct {
+	Address string
+	Key     string
+	Client  *http.Client
+}
+
+// Read boot config from etcd.
+func (s *EtcdConfigSource) Read(ctx context.Context) ([]byte, error) {
+	body, _ := json.Marshal(map[string]string{
+		"key": base64.StdEncoding.EncodeToString([]byte(s.Key)),
+	})
+
+	u := strings.TrimSuffix(s.Address, "/") + "/v3/kv/range"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	raw, err := doConfigSourceRequest(s.Client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := struct {
+		Kvs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}{}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Kvs) < 1 {
+		return nil, fmt.Errorf("key %s not found in etcd", s.Key)
+	}
+
+	return base64.StdEncoding.DecodeString(resp.Kvs[0].Value)
+}
+
+// String returns address and key.
+func (s *EtcdConfigSource) String() string {
+	return fmt.Sprintf("etcd://%s/%s", s.Address, strings.TrimPrefix(s.Key, "/"))
+}
+
+// doConfigSourceRequest send request and returns body, status code other than 200 will be treated as error.
+func doConfigSourceRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, (&url.URL{
+			Scheme: req.URL.Scheme,
+			Host:   req.URL.Host,
+			Path:   req.URL.Path,
+		}).String())
+	}
+
+	return body, nil
+}
+
+// ConfigWatcherOption option of ConfigWatcher.
+type ConfigWatcherOption func(*ConfigWatcher)
+
+// WithConfigWatchInterval provide interval of polling ConfigSource, default is 10 seconds.
+func WithConfigWatchInterval(interval time.Duration) ConfigWatcherOption {
+	return func(w *ConfigWatcher) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// WithConfigWatchLoggerEntry provide rkentry.LoggerEntry used to log reload outcome.
+func WithConfigWatchLoggerEntry(logger *rkentry.LoggerEntry) ConfigWatcherOption {
+	return func(w *ConfigWatcher) {
+		if logger != nil {
+			w.logger = logger
+		}
+	}
+}
+
+// WithConfigWatchCallback provide function called after each GinEntry reloaded, err is nil if succeed.
+func WithConfigWatchCallback(f func(name string, err error)) ConfigWatcherOption {
+	return func(w *ConfigWatcher) {
+		w.callback = f
+	}
+}
+
+// ConfigWatcher registers GinEntry from ConfigSource and re-bootstraps entries whose config changed.
+//
+// Entries are compared by name, only changed, added or removed entries will be interrupted or bootstrapped,
+// one by one, other entries keep serving. Changed entry is interrupted only after new one registered,
+// otherwise existing entry keeps serving and it is reloaded again next time.
+//
+//	watcher := rkgin.NewConfigWatcher(&rkgin.ConsulConfigSource{Address: "http://consul:8500", Key: "app/boot.yaml"})
+//	watcher.Register(ctx)
+//	watcher.Start(ctx)
+//	defer watcher.Stop()
+type ConfigWatcher struct {
+	source   ConfigSource
+	interval time.Duration
+	logger   *rkentry.LoggerEntry
+	callback func(name string, err error)
+	lock     sync.Mutex
+	raw      []byte
+	elements map[string][]byte
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewConfigWatcher create ConfigWatcher with source.
+func NewConfigWatcher(source ConfigSource, opts ...ConfigWatcherOption) *ConfigWatcher {
+	w := &ConfigWatcher{
+		source:   source,
+		interval: defaultConfigWatchInterval,
+		logger:   rkentry.NewLoggerEntryStdout(),
+		elements: make(map[string][]byte),
+	}
+
+	for i := range opts {
+		opts[i](w)
+	}
+
+	return w
+}
+
+// Register read config from source and register GinEntry, entries are not bootstrapped.
+func (w *ConfigWatcher) Register(ctx context.Context) (map[string]rkentry.Entry, error) {
+	raw, err := w.source.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := splitGinElements(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := RegisterGinEntryYAMLWithError(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	w.lock.Lock()
+	w.raw = raw
+	w.elements = elements
+	w.lock.Unlock()
+
+	return res, nil
+}
+
+// Start polling source in background until Stop called or ctx done.
+func (w *ConfigWatcher) Start(ctx context.Context) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.stopCh != nil {
+		return
+	}
+
+	stopCh := make(chan struct{})
+	w.stopCh = stopCh
+	w.wg.Add(1)
+
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := w.Reload(ctx); err != nil {
+					w.logger.Warn("Failed to reload boot config.",
+						zap.String("source", w.source.String()), zap.Error(err))
+				}
+			case <-stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop polling source.
+func (w *ConfigWatcher) Stop() {
+	w.lock.Lock()
+	stopCh := w.stopCh
+	w.stopCh = nil
+	w.lock.Unlock()
+
+	if stopCh != nil {
+		close(stopCh)
+		w.wg.Wait()
+	}
+}
+
+// Reload read config from source once and re-bootstrap GinEntry whose config changed.
+func (w *ConfigWatcher) Reload(ctx context.Context) error {
+	raw, err := w.source.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if bytes.Equal(raw, w.raw) {
+		return nil
+	}
+
+	elements, err := splitGinElements(raw)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0)
+	for name := range w.elements {
+		names = append(names, name)
+	}
+	for name := range elements {
+		if _, ok := w.elements[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	// entries failed to reload keep old element, so that they are reloaded again next time
+	reloaded := make(map[string][]byte)
+	for name, element := range w.elements {
+		reloaded[name] = element
+	}
+	failed := false
+
+	for _, name := range names {
+		oldElement, newElement := w.elements[name], elements[name]
+		if bytes.Equal(oldElement, newElement) {
+			continue
+		}
+
+		err := w.reloadEntry(ctx, name, newElement)
+		if err != nil {
+			failed = true
+			w.logger.Warn("Failed to reload GinEntry.", zap.String("entryName", name), zap.Error(err))
+		} else {
+			w.logger.Info("GinEntry reloaded.", zap.String("entryName", name))
+			if newElement == nil {
+				delete(reloaded, name)
+			} else {
+				reloaded[name] = newElement
+			}
+		}
+
+		if w.callback != nil {
+			w.callback(name, err)
+		}
+	}
+
+	if !failed {
+		w.raw = raw
+	}
+	w.elements = reloaded
+
+	return nil
+}
+
+// reloadEntry register GinEntry with element and interrupt existing one before bootstrapping it,
+// element is nil if removed.
+//
+// Existing GinEntry keeps serving if new one failed to register, and resources of its middlewares like log files
+// are closed by shutdown hooks of new one only after it is interrupted.
+func (w *ConfigWatcher) reloadEntry(ctx context.Context, name string, element []byte) error {
+	old := GetGinEntry(name)
+
+	entries := make(map[string]rkentry.Entry)
+	if element != nil {
+		var err error
+		if entries, err = registerGinEntryYAMLWithPendingHooks(append([]byte("gin:\n"), element...)); err != nil {
+			// failed GinEntry replaced existing one in rkentry.GlobalAppCtx before it was removed
+			if old != nil {
+				old.register()
+			}
+			return err
+		}
+	}
+
+	if old != nil {
+		old.Interrupt(ctx)
+	}
+	commitHooks(entries)
+
+	for _, v := range entries {
+		if entry, ok := v.(*GinEntry); ok {
+			if err := entry.BootstrapWithError(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitGinElements returns YAML of each gin element keyed by name.
+//
+// Each value is a YAML sequence with single element, so that it could be registered with "gin:" prefix.
+// middlewareDefaults is applied to each element, so that change of it is treated as change of every element.
+func splitGinElements(raw []byte) (map[string][]byte, error) {
+	m, err := unmarshalBootYAMLMap(raw)
+	if err != nil {
+		return nil, err
+	}
+	applyMiddlewareDefaults(m)
+
+	res := make(map[string][]byte)
+	list, _ := m[findBootMapKey(m, "gin")].([]interface{})
+	for i := range list {
+		element, ok := list[i].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		name := fmt.Sprint(element[findBootMapKey(element, "name")])
+		bytes, err := yaml.Marshal([]interface{}{element})
+		if err != nil {
+			return nil, err
+		}
+		res[name] = bytes
+	}
+
+	return res, nil
+}
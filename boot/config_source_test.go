@@ -0,0 +1,371 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileConfigSource(t *testing.T) {
+	p := writeBootConfigFile(t, t.TempDir(), "boot.yaml", "gin: []")
+	source := &FileConfigSource{Path: p}
+
+	raw, err := source.Read(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "gin: []", string(raw))
+	assert.Contains(t, source.String(), "file://")
+
+	_, err = (&FileConfigSource{Path: filepath.Join(t.TempDir(), "non-exist")}).Read(context.TODO())
+	assert.NotNil(t, err)
+}
+
+func TestConsulConfigSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/app/boot.yaml" || r.Header.Get("X-Consul-Token") != "ut-token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("gin: []"))
+	}))
+	defer server.Close()
+
+	source := &ConsulConfigSource{Address: server.URL + "/", Key: "/app/boot.yaml", Token: "ut-token"}
+	raw, err := source.Read(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "gin: []", string(raw))
+	assert.Contains(t, source.String(), "consul://")
+
+	// not found
+	source.Token = ""
+	_, err = source.Read(context.TODO())
+	assert.NotNil(t, err)
+}
+
+func TestEtcdConfigSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/kv/range" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"kvs":[{"value":"%s"}]}`, base64.StdEncoding.EncodeToString([]byte("gin: []")))
+	}))
+	defer server.Close()
+
+	source := &EtcdConfigSource{Address: server.URL, Key: "app/boot.yaml"}
+	raw, err := source.Read(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "gin: []", string(raw))
+	assert.Contains(t, source.String(), "etcd://")
+
+	// key not found
+	empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer empty.Close()
+	_, err = (&EtcdConfigSource{Address: empty.URL, Key: "ut"}).Read(context.TODO())
+	assert.NotNil(t, err)
+}
+
+type fakeConfigSource struct {
+	lock sync.Mutex
+	raw  string
+}
+
+func (s *fakeConfigSource) Read(context.Context) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return []byte(s.raw), nil
+}
+
+func (s *fakeConfigSource) String() string {
+	return "fake"
+}
+
+func (s *fakeConfigSource) set(raw string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.raw = raw
+}
+
+func TestConfigWatcher(t *testing.T) {
+	source := &fakeConfigSource{raw: `
+gin:
+  - name: ut-watch-a
+    port: 1949
+    enabled: true
+  - name: ut-watch-b
+    port: 1950
+    enabled: true
+`}
+
+	reloaded := make([]string, 0)
+	watcher := NewConfigWatcher(source,
+		WithConfigWatchInterval(10*time.Millisecond),
+		WithConfigWatchLoggerEntry(nil),
+		WithConfigWatchCallback(func(name string, err error) {
+			assert.Nil(t, err)
+			reloaded = append(reloaded, name)
+		}))
+
+	entries, err := watcher.Register(context.TODO())
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	b := GetGinEntry("ut-watch-b")
+
+	// nothing changed
+	assert.Nil(t, watcher.Reload(context.TODO()))
+	assert.Empty(t, reloaded)
+
+	// a changed, b untouched, c added
+	source.set(`
+gin:
+  - name: ut-watch-a
+    port: 1951
+    enabled: true
+  - name: ut-watch-b
+    port: 1950
+    enabled: true
+  - name: ut-watch-c
+    port: 1952
+    enabled: true
+`)
+	assert.Nil(t, watcher.Reload(context.TODO()))
+	assert.Equal(t, []string{"ut-watch-a", "ut-watch-c"}, reloaded)
+	assert.Equal(t, uint64(1951), GetGinEntry("ut-watch-a").Port)
+	assert.True(t, GetGinEntry("ut-watch-a").IsReady())
+	assert.Equal(t, b, GetGinEntry("ut-watch-b"))
+
+	// a removed
+	source.set(`
+gin:
+  - name: ut-watch-b
+    port: 1950
+    enabled: true
+  - name: ut-watch-c
+    port: 1952
+    enabled: true
+`)
+	assert.Nil(t, watcher.Reload(context.TODO()))
+	assert.Nil(t, GetGinEntry("ut-watch-a"))
+
+	// invalid config
+	source.set("invalid")
+	assert.NotNil(t, watcher.Reload(context.TODO()))
+
+	GetGinEntry("ut-watch-c").Interrupt(context.TODO())
+	b.Interrupt(context.TODO())
+}
+
+func TestSplitGinElements(t *testing.T) {
+	raw := `
+middlewareDefaults:
+  meta:
+    enabled: true
+gin:
+  - name: ut-a
+    port: 1949
+  - name: ut-b
+    port: 1950
+    middleware:
+      meta:
+        prefix: ut
+`
+	elements, err := splitGinElements([]byte(raw))
+	assert.Nil(t, err)
+	assert.Len(t, elements, 2)
+
+	// defaults applied to each element
+	config := &BootConfig{}
+	assert.Nil(t, yaml.Unmarshal(append([]byte("gin:\n"), elements["ut-a"]...), config))
+	assert.True(t, config.Gin[0].Middleware.Meta.Enabled)
+
+	config = &BootConfig{}
+	assert.Nil(t, yaml.Unmarshal(append([]byte("gin:\n"), elements["ut-b"]...), config))
+	assert.True(t, config.Gin[0].Middleware.Meta.Enabled)
+	assert.Equal(t, "ut", config.Gin[0].Middleware.Meta.Prefix)
+
+	// change of defaults changes every element
+	changed, err := splitGinElements([]byte(strings.Replace(raw, "enabled: true", "enabled: false", 1)))
+	assert.Nil(t, err)
+	assert.NotEqual(t, elements["ut-a"], changed["ut-a"])
+	assert.NotEqual(t, elements["ut-b"], changed["ut-b"])
+
+	// invalid
+	_, err = splitGinElements([]byte("invalid"))
+	assert.NotNil(t, err)
+}
+
+func TestConfigWatcher_FailedReload(t *testing.T) {
+	source := &fakeConfigSource{raw: `
+gin:
+  - name: ut-watch-failed
+    port: 1954
+    enabled: true
+    commonService:
+      enabled: true
+    middleware:
+      prom:
+        enabled: true
+`}
+
+	errs := make([]error, 0)
+	watcher := NewConfigWatcher(source,
+		WithConfigWatchLoggerEntry(nil),
+		WithConfigWatchCallback(func(name string, err error) {
+			errs = append(errs, err)
+		}))
+
+	_, err := watcher.Register(context.TODO())
+	assert.Nil(t, err)
+	old := GetGinEntry("ut-watch-failed")
+	assert.Nil(t, old.BootstrapWithError(context.TODO()))
+	defer func() {
+		GetGinEntry("ut-watch-failed").Interrupt(context.TODO())
+	}()
+
+	// existing entry keeps serving
+	invalid := `
+gin:
+  - name: ut-watch-failed
+    port: 1955
+    enabled: true
+    middleware:
+      prom:
+        enabled: true
+      logging:
+        enabled: true
+        format: bogus
+`
+	source.set(invalid)
+	assert.Nil(t, watcher.Reload(context.TODO()))
+	assert.Len(t, errs, 1)
+	assert.NotNil(t, errs[0])
+	assert.Equal(t, old, GetGinEntry("ut-watch-failed"))
+	assert.True(t, old.IsReady())
+	assert.Equal(t, http.StatusOK, serveTest(old, http.MethodGet, "/rk/v1/ready", "", nil).Code)
+
+	// failed entry is reloaded again
+	assert.Nil(t, watcher.Reload(context.TODO()))
+	assert.Len(t, errs, 2)
+
+	// fixed
+	source.set(`
+gin:
+  - name: ut-watch-failed
+    port: 1955
+    enabled: true
+    middleware:
+      prom:
+        enabled: true
+`)
+	assert.Nil(t, watcher.Reload(context.TODO()))
+	assert.Len(t, errs, 3)
+	assert.Nil(t, errs[2])
+	assert.False(t, old.IsReady())
+	assert.Equal(t, uint64(1955), GetGinEntry("ut-watch-failed").Port)
+	assert.True(t, GetGinEntry("ut-watch-failed").IsReady())
+}
+
+func TestConfigWatcher_ReloadClosesWritersAfterInterrupt(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-watch-hooks
+    port: %d
+    enabled: true
+    middleware:
+      logging:
+        enabled: true
+        clf:
+          enabled: true
+          outputPaths: ["%s"]
+`
+	clfOutput := filepath.Join(t.TempDir(), "access.log")
+	source := &fakeConfigSource{raw: fmt.Sprintf(bootStr, 1956, clfOutput)}
+	watcher := NewConfigWatcher(source, WithConfigWatchLoggerEntry(nil))
+
+	_, err := watcher.Register(context.TODO())
+	assert.Nil(t, err)
+	old := GetGinEntry("ut-watch-hooks")
+	assert.Nil(t, old.BootstrapWithError(context.TODO()))
+
+	// record when writer of existing entry is closed
+	closed := false
+	closeWriter := rkentry.GlobalAppCtx.GetShutdownHook("ut-watch-hooks-clf")
+	assert.NotNil(t, closeWriter)
+	rkentry.GlobalAppCtx.AddShutdownHook("ut-watch-hooks-clf", func() {
+		closed = true
+		closeWriter()
+	})
+	closedWhileInterrupted := true
+	old.AddShutdownHook("ut-check-writer", func(context.Context) error {
+		closedWhileInterrupted = closed
+		return nil
+	})
+
+	// writer of existing entry is open until it is interrupted, and closed by new one
+	source.set(fmt.Sprintf(bootStr, 1957, clfOutput))
+	assert.Nil(t, watcher.Reload(context.TODO()))
+	assert.False(t, closedWhileInterrupted)
+	assert.True(t, closed)
+
+	entry := GetGinEntry("ut-watch-hooks")
+	assert.NotEqual(t, old, entry)
+	assert.Nil(t, entry.pendingHooks)
+	entry.Interrupt(context.TODO())
+	rkentry.GlobalAppCtx.GetShutdownHook("ut-watch-hooks-clf")()
+	rkentry.GlobalAppCtx.RemoveShutdownHook("ut-watch-hooks-clf")
+}
+
+func TestConfigWatcher_StartAndStop(t *testing.T) {
+	source := &fakeConfigSource{raw: "gin: []"}
+
+	reloaded := make(chan string, 1)
+	watcher := NewConfigWatcher(source,
+		WithConfigWatchInterval(10*time.Millisecond),
+		WithConfigWatchCallback(func(name string, err error) {
+			if err == nil {
+				reloaded <- name
+			}
+		}))
+	_, err := watcher.Register(context.TODO())
+	assert.Nil(t, err)
+
+	watcher.Start(context.TODO())
+	// start twice
+	watcher.Start(context.TODO())
+
+	source.set(`
+gin:
+  - name: ut-watch-start
+    port: 1953
+    enabled: true
+`)
+	select {
+	case name := <-reloaded:
+		assert.Equal(t, "ut-watch-start", name)
+	case <-time.After(time.Second):
+		assert.Fail(t, "config was not reloaded")
+	}
+
+	watcher.Stop()
+	// stop twice
+	watcher.Stop()
+
+	assert.NotNil(t, GetGinEntry("ut-watch-start"))
+	GetGinEntry("ut-watch-start").Interrupt(context.TODO())
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDependsOnTimeout  = 30 * time.Second
+	dependsOnPollingInterval = 100 * time.Millisecond
+)
+
+// ReadyReporter should be implemented by entries which GinEntry depends on.
+//
+// Registration of entry is not treated as readiness, since entries are registered while parsing boot config,
+// dependency without IsReady() fails bootstrap of GinEntry.
+type ReadyReporter interface {
+	IsReady() bool
+}
+
+// findDependency search entry referenced as <name> or <entryType>/<name> in rkentry.GlobalAppCtx.
+//
+// Nil is returned if entry is missing, error is returned if name is shared by entries of different types.
+func findDependency(ref string) (rkentry.Entry, error) {
+	if tokens := strings.SplitN(ref, "/", 2); len(tokens) == 2 {
+		return rkentry.GlobalAppCtx.GetEntry(tokens[0], tokens[1]), nil
+	}
+
+	var res rkentry.Entry
+	for entryType, entries := range rkentry.GlobalAppCtx.ListEntries() {
+		e, ok := entries[ref]
+		if !ok {
+			continue
+		}
+
+		if res != nil {
+			return nil, fmt.Errorf("dependency %s is ambiguous between %s and %s, use <entryType>/<name> instead",
+				ref, res.GetType(), entryType)
+		}
+		res = e
+	}
+
+	return res, nil
+}
+
+// resolveDependencies returns registered dependencies keyed by reference, missing ones are omitted.
+//
+// Error is returned if any dependency is ambiguous or could not report readiness.
+func (entry *GinEntry) resolveDependencies() (map[string]ReadyReporter, error) {
+	res := make(map[string]ReadyReporter)
+
+	for _, ref := range entry.dependsOn {
+		e, err := findDependency(ref)
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			continue
+		}
+
+		reporter, ok := e.(ReadyReporter)
+		if !ok {
+			return nil, fmt.Errorf("dependency %s of type %s could not report readiness, IsReady() is required",
+				ref, e.GetType())
+		}
+		res[ref] = reporter
+	}
+
+	return res, nil
+}
+
+// notReadyDependencies returns references of dependencies which are missing or not ready yet.
+func (entry *GinEntry) notReadyDependencies() ([]string, error) {
+	deps, err := entry.resolveDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0)
+	for _, ref := range entry.dependsOn {
+		if reporter, ok := deps[ref]; !ok || !reporter.IsReady() {
+			res = append(res, ref)
+		}
+	}
+
+	return res, nil
+}
+
+// checkDependencyCycle returns error if GinEntry depends on itself through GinEntry dependencies.
+func (entry *GinEntry) checkDependencyCycle(path []string) error {
+	for i := range path {
+		if path[i] == entry.entryName {
+			return fmt.Errorf("dependency cycle [%s]", strings.Join(append(path, entry.entryName), " -> "))
+		}
+	}
+	path = append(path, entry.entryName)
+
+	deps, err := entry.resolveDependencies()
+	if err != nil {
+		return err
+	}
+
+	for _, v := range deps {
+		if dep, ok := v.(*GinEntry); ok {
+			if err := dep.checkDependencyCycle(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// bootstrapDependencies bootstrap GinEntry dependencies which are not bootstrapped yet,
+// so that GinEntries are bootstrapped in order of dependencies regardless of order of bootstrap calls.
+//
+// Bootstrap of GinEntry called again later, like in rk-boot, is skipped.
+func (entry *GinEntry) bootstrapDependencies(ctx context.Context) error {
+	if err := entry.checkDependencyCycle(nil); err != nil {
+		return err
+	}
+
+	deps, err := entry.resolveDependencies()
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range entry.dependsOn {
+		dep, ok := deps[ref].(*GinEntry)
+		if !ok || dep.isBootstrapStarted() {
+			continue
+		}
+
+		if err := dep.BootstrapWithError(ctx); err != nil {
+			return fmt.Errorf("failed to bootstrap dependency %s, %v", ref, err)
+		}
+	}
+
+	return nil
+}
+
+// waitForDependencies blocks until all dependencies are ready, timed out or context canceled.
+func (entry *GinEntry) waitForDependencies(ctx context.Context) error {
+	if len(entry.dependsOn) < 1 {
+		return nil
+	}
+
+	timeout := entry.dependsOnTimeout
+	if timeout <= 0 {
+		timeout = defaultDependsOnTimeout
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(dependsOnPollingInterval)
+	defer ticker.Stop()
+
+	if err := entry.bootstrapDependencies(ctx); err != nil {
+		return err
+	}
+
+	for {
+		notReady, err := entry.notReadyDependencies()
+		if err != nil {
+			return err
+		}
+		if len(notReady) < 1 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context canceled while waiting for dependencies [%s], %v",
+				strings.Join(notReady, ","), ctx.Err())
+		case <-timer.C:
+			return fmt.Errorf("timed out after %s while waiting for dependencies [%s]",
+				timeout, strings.Join(notReady, ","))
+		case <-ticker.C:
+		}
+	}
+}
+
+// WithDependsOn provide references of entries which should be ready before GinEntry bootstraps.
+//
+// Entry is referenced as <name>, or <entryType>/<name> if name is shared by entries of different types,
+// and should implement ReadyReporter. GinEntry dependencies are bootstrapped first if not bootstrapped yet.
+func WithDependsOn(names ...string) GinEntryOption {
+	return func(entry *GinEntry) {
+		for i := range names {
+			if len(names[i]) > 0 {
+				entry.dependsOn = append(entry.dependsOn, names[i])
+			}
+		}
+	}
+}
+
+// WithDependsOnTimeout provide timeout of waiting for dependencies, default is 30 seconds.
+func WithDependsOnTimeout(timeout time.Duration) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.dependsOnTimeout = timeout
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeDependencyEntry struct {
+	name  string
+	ready int32
+}
+
+func (f *fakeDependencyEntry) Bootstrap(context.Context) {}
+
+func (f *fakeDependencyEntry) Interrupt(context.Context) {}
+
+func (f *fakeDependencyEntry) GetName() string {
+	return f.name
+}
+
+func (f *fakeDependencyEntry) GetType() string {
+	return "FakeDependencyEntry"
+}
+
+func (f *fakeDependencyEntry) GetDescription() string {
+	return ""
+}
+
+func (f *fakeDependencyEntry) String() string {
+	return ""
+}
+
+func (f *fakeDependencyEntry) IsReady() bool {
+	return atomic.LoadInt32(&f.ready) == 1
+}
+
+func TestGinEntry_waitForDependencies(t *testing.T) {
+	// without dependencies
+	entry := RegisterGinEntry(WithName("ut-deps"), WithPort(0))
+	assert.Nil(t, entry.waitForDependencies(context.TODO()))
+	rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	// missing dependency
+	entry = RegisterGinEntry(
+		WithName("ut-deps"),
+		WithPort(0),
+		WithDependsOn("ut-db", ""),
+		WithDependsOnTimeout(300*time.Millisecond))
+	assert.Equal(t, []string{"ut-db"}, entry.dependsOn)
+	err := entry.waitForDependencies(context.TODO())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "ut-db")
+
+	// dependency registered but not ready
+	dep := &fakeDependencyEntry{name: "ut-db"}
+	rkentry.GlobalAppCtx.AddEntry(dep)
+	defer rkentry.GlobalAppCtx.RemoveEntry(dep)
+	assert.NotNil(t, entry.waitForDependencies(context.TODO()))
+
+	// context canceled
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	assert.NotNil(t, entry.waitForDependencies(ctx))
+
+	// dependency becomes ready later
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		atomic.StoreInt32(&dep.ready, 1)
+	}()
+	assert.Nil(t, entry.waitForDependencies(context.TODO()))
+
+	rkentry.GlobalAppCtx.RemoveEntry(entry)
+}
+
+func TestGinEntry_BootstrapWithError_DependsOn(t *testing.T) {
+	defer assertNotPanic(t)
+
+	entry := RegisterGinEntry(
+		WithName("ut-deps"),
+		WithPort(0),
+		WithDependsOn("ut-missing"),
+		WithDependsOnTimeout(100*time.Millisecond))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.NotNil(t, entry.BootstrapWithError(context.TODO()))
+	assert.False(t, entry.IsReady())
+}
+
+type fakeEntryWithoutReady struct {
+	name string
+}
+
+func (f *fakeEntryWithoutReady) Bootstrap(context.Context) {}
+
+func (f *fakeEntryWithoutReady) Interrupt(context.Context) {}
+
+func (f *fakeEntryWithoutReady) GetName() string {
+	return f.name
+}
+
+func (f *fakeEntryWithoutReady) GetType() string {
+	return "FakeEntryWithoutReady"
+}
+
+func (f *fakeEntryWithoutReady) GetDescription() string {
+	return ""
+}
+
+func (f *fakeEntryWithoutReady) String() string {
+	return ""
+}
+
+func TestFindDependency(t *testing.T) {
+	// missing
+	e, err := findDependency("ut-find")
+	assert.Nil(t, e)
+	assert.Nil(t, err)
+
+	dep := &fakeDependencyEntry{name: "ut-find"}
+	rkentry.GlobalAppCtx.AddEntry(dep)
+	defer rkentry.GlobalAppCtx.RemoveEntry(dep)
+
+	// with name
+	e, err = findDependency("ut-find")
+	assert.Equal(t, dep, e)
+	assert.Nil(t, err)
+
+	// with type and name
+	e, err = findDependency("FakeDependencyEntry/ut-find")
+	assert.Equal(t, dep, e)
+	assert.Nil(t, err)
+	e, err = findDependency("GinEntry/ut-find")
+	assert.Nil(t, e)
+	assert.Nil(t, err)
+
+	// name shared by entries of different types
+	other := &fakeEntryWithoutReady{name: "ut-find"}
+	rkentry.GlobalAppCtx.AddEntry(other)
+	defer rkentry.GlobalAppCtx.RemoveEntry(other)
+	_, err = findDependency("ut-find")
+	assert.NotNil(t, err)
+}
+
+func TestGinEntry_waitForDependencies_WithoutReady(t *testing.T) {
+	dep := &fakeEntryWithoutReady{name: "ut-no-ready"}
+	rkentry.GlobalAppCtx.AddEntry(dep)
+	defer rkentry.GlobalAppCtx.RemoveEntry(dep)
+
+	entry := RegisterGinEntry(
+		WithName("ut-deps"),
+		WithPort(0),
+		WithDependsOn("ut-no-ready"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	// fails without waiting for timeout
+	start := time.Now()
+	err := entry.waitForDependencies(context.TODO())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "IsReady()")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestGinEntry_BootstrapWithError_GinEntryDependency(t *testing.T) {
+	defer assertNotPanic(t)
+
+	dep := RegisterGinEntry(WithName("ut-deps-db"), WithPort(0))
+	entry := RegisterGinEntry(
+		WithName("ut-deps-api"),
+		WithPort(0),
+		WithDependsOn("GinEntry/ut-deps-db"))
+
+	// dependency is bootstrapped first
+	assert.Nil(t, entry.BootstrapWithError(context.TODO()))
+	assert.True(t, dep.IsReady())
+	assert.True(t, entry.IsReady())
+
+	// bootstrap again is skipped
+	assert.Nil(t, dep.BootstrapWithError(context.TODO()))
+	assert.True(t, dep.IsReady())
+
+	entry.Interrupt(context.TODO())
+	dep.Interrupt(context.TODO())
+	assert.False(t, dep.isBootstrapStarted())
+}
+
+func TestGinEntry_BootstrapWithError_DependencyCycle(t *testing.T) {
+	defer assertNotPanic(t)
+
+	first := RegisterGinEntry(WithName("ut-deps-first"), WithPort(0), WithDependsOn("ut-deps-second"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(first)
+	second := RegisterGinEntry(WithName("ut-deps-second"), WithPort(0), WithDependsOn("ut-deps-first"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(second)
+
+	err := first.BootstrapWithError(context.TODO())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+	assert.False(t, first.IsReady())
+	assert.False(t, second.IsReady())
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"net/http"
+	"path"
+	"runtime/debug"
+)
+
+// DepInfo module compiled into binary, Replace is set if module is replaced in go.mod.
+type DepInfo struct {
+	Path    string   `json:"path" yaml:"path"`
+	Version string   `json:"version" yaml:"version"`
+	Sum     string   `json:"sum,omitempty" yaml:"sum,omitempty"`
+	Replace *DepInfo `json:"replace,omitempty" yaml:"replace,omitempty"`
+}
+
+// DepsResponse response of deps API.
+type DepsResponse struct {
+	GoVersion string     `json:"goVersion" yaml:"goVersion"`
+	Main      *DepInfo   `json:"main" yaml:"main"`
+	Deps      []*DepInfo `json:"deps" yaml:"deps"`
+}
+
+// readBuildInfo could be replaced in unit test.
+var readBuildInfo = debug.ReadBuildInfo
+
+// DepsPath returns path of deps API which sits next to common service paths, /rk/v1/deps by default.
+func (entry *GinEntry) DepsPath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "deps")
+}
+
+// DepsHandler returns modules compiled into binary read from debug.ReadBuildInfo, 404 if binary is built without module support.
+func (entry *GinEntry) DepsHandler(ctx *gin.Context) {
+	info, ok := readBuildInfo()
+	if !ok {
+		ctx.JSON(http.StatusNotFound, rkmid.GetErrorBuilder().New(http.StatusNotFound, "Build info not available"))
+		return
+	}
+
+	res := &DepsResponse{
+		GoVersion: info.GoVersion,
+		Main:      newDepInfo(&info.Main),
+		Deps:      make([]*DepInfo, 0, len(info.Deps)),
+	}
+	for i := range info.Deps {
+		res.Deps = append(res.Deps, newDepInfo(info.Deps[i]))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// newDepInfo converts debug.Module into DepInfo.
+func newDepInfo(module *debug.Module) *DepInfo {
+	if module == nil {
+		return nil
+	}
+
+	return &DepInfo{
+		Path:    module.Path,
+		Version: module.Version,
+		Sum:     module.Sum,
+		Replace: newDepInfo(module.Replace),
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"testing"
+)
+
+func TestGinEntry_DepsHandler(t *testing.T) {
+	defer func() {
+		readBuildInfo = debug.ReadBuildInfo
+	}()
+
+	entry := RegisterGinEntry(
+		WithName("ut-deps"),
+		WithPort(0),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{Enabled: true})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, "/rk/v1/deps", entry.DepsPath())
+	entry.Router.GET(entry.DepsPath(), entry.DepsHandler)
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.18",
+			Main:      debug.Module{Path: "example.com/app", Version: "(devel)"},
+			Deps: []*debug.Module{
+				{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", Sum: "h1:gin"},
+				{Path: "example.com/lib", Version: "v1.0.0", Replace: &debug.Module{Path: "../lib", Version: "(devel)"}},
+			},
+		}, true
+	}
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rk/v1/deps", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	res := &DepsResponse{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, "go1.18", res.GoVersion)
+	assert.Equal(t, "example.com/app", res.Main.Path)
+	assert.Len(t, res.Deps, 2)
+	assert.Equal(t, &DepInfo{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", Sum: "h1:gin"}, res.Deps[0])
+	assert.Equal(t, "../lib", res.Deps[1].Replace.Path)
+
+	// without build info
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return nil, false
+	}
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rk/v1/deps", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// disabled common service
+	disabled := RegisterGinEntry(WithName("ut-deps-disabled"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(disabled)
+	assert.Empty(t, disabled.DepsPath())
+}
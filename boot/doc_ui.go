@@ -0,0 +1,122 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// specFileSuffixes suffixes of spec files served by swagger UI and documentation UIs like ReDoc and RapiDoc.
+var specFileSuffixes = []string{".json", ".yaml", ".yml"}
+
+// parseDocHeaders parses headers formed as key:value, invalid ones are ignored.
+func parseDocHeaders(raw []string) map[string]string {
+	headers := make(map[string]string, 0)
+	for i := range raw {
+		tokens := strings.Split(raw[i], ":")
+		if len(tokens) == 2 {
+			headers[tokens[0]] = tokens[1]
+		}
+	}
+
+	return headers
+}
+
+// loadDocSpecs reads spec files in dirs, or default directories of docs, api/gen/v1 and api/gen, into store.
+//
+// Spec files are keyed with <entryName>-<file name> and served under basePath.
+func loadDocSpecs(store *swSpecStore, fsys fs.FS, entryName, basePath string, dirs []string) {
+	if len(dirs) < 1 {
+		dirs = []string{"docs", "api/gen/v1", "api/gen"}
+	}
+
+	for _, dir := range dirs {
+		for _, file := range listSpecFiles(fsys, dir, specFileSuffixes) {
+			var content []byte
+			var err error
+			if fsys != nil {
+				content, err = fs.ReadFile(fsys, file)
+			} else {
+				content, err = os.ReadFile(file)
+			}
+			if err != nil {
+				continue
+			}
+
+			contentType := "application/yaml"
+			if strings.HasSuffix(strings.ToLower(file), ".json") {
+				contentType = "application/json"
+			}
+
+			key := entryName + "-" + path.Base(filepath.ToSlash(file))
+			store.add(key, path.Join(basePath, key), &swSpec{
+				content:     content,
+				contentType: contentType,
+			})
+		}
+	}
+}
+
+// serveDocUI serves index page rendered with tmpl at basePath, and spec files in store under basePath.
+//
+// Specs and Current are added into data before rendering, Current is url of spec selected by query parameter spec,
+// or the first one if not selected.
+func serveDocUI(writer http.ResponseWriter, request *http.Request,
+	basePath string, headers map[string]string, store *swSpecStore, tmpl *template.Template, data map[string]interface{}) {
+	name := strings.Trim(strings.TrimPrefix(request.URL.Path, strings.TrimSuffix(basePath, "/")), "/")
+
+	writer.Header().Set("cache-control", "no-cache")
+	for k, v := range headers {
+		writer.Header().Set(k, v)
+	}
+
+	// spec files
+	if len(name) > 0 {
+		spec := store.get(name)
+		if spec == nil {
+			http.NotFound(writer, request)
+			return
+		}
+
+		writer.Header().Set("Content-Type", spec.contentType)
+		http.ServeContent(writer, request, name, time.Time{}, bytes.NewReader(spec.content))
+		return
+	}
+
+	// index page
+	specs := store.listUrls()
+
+	current := ""
+	selected := request.URL.Query().Get("spec")
+	for i := range specs {
+		if len(current) < 1 || specs[i].Name == selected {
+			current = specs[i].Url
+		}
+		if specs[i].Name == selected {
+			break
+		}
+	}
+
+	data["Specs"] = specs
+	data["Current"] = current
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	http.ServeContent(writer, request, "index.html", time.Time{}, bytes.NewReader(buf.Bytes()))
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseDocHeaders(t *testing.T) {
+	assert.Empty(t, parseDocHeaders(nil))
+	assert.Equal(t, map[string]string{"a": "b"}, parseDocHeaders([]string{"a:b", "invalid", "c:d:e"}))
+}
+
+func TestLoadDocSpecs(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.JSON"), []byte(`{}`), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte(utOpenApi3Yaml), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("ut"), 0644))
+
+	store := newSwSpecStore()
+	loadDocSpecs(store, nil, "ut", "/doc/", []string{dir, filepath.Join(dir, "non-exist")})
+
+	urls := store.listUrls()
+	assert.Len(t, urls, 2)
+	assert.Equal(t, "/doc/ut-a.JSON", urls[0].Url)
+	assert.Equal(t, "application/json", store.get("ut-a.JSON").contentType)
+	assert.Equal(t, "application/yaml", store.get("ut-b.yaml").contentType)
+}
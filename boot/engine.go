@@ -0,0 +1,84 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strings"
+)
+
+// BootEngine boot config of gin.Engine behavior.
+//
+// Fields left empty keep defaults of gin.New(), except Mode which defaults to release.
+// Mode is global in gin, entries configured with different modes will override each other.
+type BootEngine struct {
+	Mode                   string   `yaml:"mode" json:"mode"`
+	RedirectTrailingSlash  *bool    `yaml:"redirectTrailingSlash" json:"redirectTrailingSlash"`
+	RedirectFixedPath      *bool    `yaml:"redirectFixedPath" json:"redirectFixedPath"`
+	HandleMethodNotAllowed *bool    `yaml:"handleMethodNotAllowed" json:"handleMethodNotAllowed"`
+	RemoveExtraSlash       *bool    `yaml:"removeExtraSlash" json:"removeExtraSlash"`
+	TrustedProxies         []string `yaml:"trustedProxies" json:"trustedProxies"`
+}
+
+// ginMode returns gin mode of config, release if missing.
+func (config *BootEngine) ginMode() string {
+	switch strings.ToLower(config.Mode) {
+	case "debug":
+		return gin.DebugMode
+	case "test":
+		return gin.TestMode
+	default:
+		return gin.ReleaseMode
+	}
+}
+
+// validate returns error if trusted proxies are invalid.
+func (config *BootEngine) validate() error {
+	if len(config.TrustedProxies) > 0 {
+		if err := gin.New().SetTrustedProxies(config.TrustedProxies); err != nil {
+			return fmt.Errorf("invalid trusted proxies, %v", err)
+		}
+	}
+
+	return nil
+}
+
+// apply settings into gin.Engine, error is returned if trusted proxies are invalid.
+func (config *BootEngine) apply(engine *gin.Engine) error {
+	if config.RedirectTrailingSlash != nil {
+		engine.RedirectTrailingSlash = *config.RedirectTrailingSlash
+	}
+
+	if config.RedirectFixedPath != nil {
+		engine.RedirectFixedPath = *config.RedirectFixedPath
+	}
+
+	if config.HandleMethodNotAllowed != nil {
+		engine.HandleMethodNotAllowed = *config.HandleMethodNotAllowed
+	}
+
+	if config.RemoveExtraSlash != nil {
+		engine.RemoveExtraSlash = *config.RemoveExtraSlash
+	}
+
+	if len(config.TrustedProxies) > 0 {
+		if err := engine.SetTrustedProxies(config.TrustedProxies); err != nil {
+			return fmt.Errorf("invalid trusted proxies, %v", err)
+		}
+	}
+
+	return nil
+}
+
+// WithEngine provide gin.Engine settings.
+//
+// Invalid settings are returned by BootstrapWithError.
+func WithEngine(config *BootEngine) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.engineConfig = config
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBootEngine_ginMode(t *testing.T) {
+	assert.Equal(t, gin.ReleaseMode, (&BootEngine{}).ginMode())
+	assert.Equal(t, gin.DebugMode, (&BootEngine{Mode: "Debug"}).ginMode())
+	assert.Equal(t, gin.TestMode, (&BootEngine{Mode: "test"}).ginMode())
+}
+
+func TestBootEngine_apply(t *testing.T) {
+	f, tr := false, true
+	engine := gin.New()
+	config := &BootEngine{
+		RedirectTrailingSlash:  &f,
+		RedirectFixedPath:      &tr,
+		HandleMethodNotAllowed: &tr,
+		RemoveExtraSlash:       &tr,
+		TrustedProxies:         []string{"10.0.0.0/8"},
+	}
+	assert.Nil(t, config.apply(engine))
+
+	assert.False(t, engine.RedirectTrailingSlash)
+	assert.True(t, engine.RedirectFixedPath)
+	assert.True(t, engine.HandleMethodNotAllowed)
+	assert.True(t, engine.RemoveExtraSlash)
+
+	// invalid trusted proxies
+	config = &BootEngine{TrustedProxies: []string{"invalid"}}
+	assert.NotNil(t, config.apply(engine))
+}
+
+func TestRegisterGinEntry_WithInvalidEngine(t *testing.T) {
+	defer assertNotPanic(t)
+
+	entry := RegisterGinEntry(
+		WithName("ut-engine-invalid"),
+		WithEngine(&BootEngine{TrustedProxies: []string{"invalid"}}))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	err := entry.BootstrapWithError(context.TODO())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid trusted proxies")
+	assert.False(t, entry.IsReady())
+}
+
+func TestRegisterGinEntryYAML_WithEngine(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-engine
+    port: 1949
+    enabled: true
+    errorHandler:
+      enabled: true
+    engine:
+      mode: release
+      redirectTrailingSlash: false
+      handleMethodNotAllowed: false
+`
+	entries := RegisterGinEntryYAML([]byte(bootStr))
+	entry := entries["ut-engine"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.False(t, entry.Router.RedirectTrailingSlash)
+	// keep default
+	assert.False(t, entry.Router.RedirectFixedPath)
+	// explicit config wins over errorHandler
+	assert.False(t, entry.Router.HandleMethodNotAllowed)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"net/http"
+	"path"
+	"sort"
+)
+
+// EntryInfo entry registered in rkentry.GlobalAppCtx with its state marshaled with json.Marshal.
+//
+// Error is set instead of EntryMeta if entry failed to marshal.
+type EntryInfo struct {
+	EntryName        string          `json:"entryName" yaml:"entryName"`
+	EntryType        string          `json:"entryType" yaml:"entryType"`
+	EntryDescription string          `json:"entryDescription" yaml:"entryDescription"`
+	EntryMeta        json.RawMessage `json:"entryMeta,omitempty" yaml:"entryMeta,omitempty"`
+	Error            string          `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// EntriesResponse response of entries API, entries are grouped by type and sorted by name.
+type EntriesResponse struct {
+	Entries map[string][]*EntryInfo `json:"entries" yaml:"entries"`
+}
+
+// EntriesPath returns path of entries API which sits next to common service paths, /rk/v1/entries by default.
+func (entry *GinEntry) EntriesPath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "entries")
+}
+
+// EntriesHandler returns all entries registered in rkentry.GlobalAppCtx, entries of a type could be selected with query parameter type.
+func (entry *GinEntry) EntriesHandler(ctx *gin.Context) {
+	entryType := ctx.Query("type")
+
+	res := &EntriesResponse{
+		Entries: make(map[string][]*EntryInfo),
+	}
+
+	for t, entries := range rkentry.GlobalAppCtx.ListEntries() {
+		if len(entryType) > 0 && t != entryType {
+			continue
+		}
+
+		infos := make([]*EntryInfo, 0, len(entries))
+		for _, e := range entries {
+			info := &EntryInfo{
+				EntryName:        e.GetName(),
+				EntryType:        e.GetType(),
+				EntryDescription: e.GetDescription(),
+			}
+
+			if meta, err := json.Marshal(e); err != nil {
+				info.Error = err.Error()
+			} else {
+				info.EntryMeta = meta
+			}
+
+			infos = append(infos, info)
+		}
+
+		sort.Slice(infos, func(i, j int) bool {
+			return infos[i].EntryName < infos[j].EntryName
+		})
+		res.Entries[t] = infos
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
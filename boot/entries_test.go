@@ -0,0 +1,83 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// utFailEntry entry fails to marshal.
+type utFailEntry struct{}
+
+func (e *utFailEntry) Bootstrap(context.Context)    {}
+func (e *utFailEntry) Interrupt(context.Context)    {}
+func (e *utFailEntry) GetName() string              { return "ut-fail" }
+func (e *utFailEntry) GetType() string              { return "UtFailEntry" }
+func (e *utFailEntry) GetDescription() string       { return "fail" }
+func (e *utFailEntry) String() string               { return "" }
+func (e *utFailEntry) MarshalJSON() ([]byte, error) { return nil, errors.New("ut-error") }
+func (e *utFailEntry) UnmarshalJSON([]byte) error   { return nil }
+
+func TestGinEntry_EntriesHandler(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-entries"),
+		WithPort(0),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{Enabled: true})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	fail := &utFailEntry{}
+	rkentry.GlobalAppCtx.AddEntry(fail)
+	defer rkentry.GlobalAppCtx.RemoveEntry(fail)
+
+	assert.Equal(t, "/rk/v1/entries", entry.EntriesPath())
+	entry.Router.GET(entry.EntriesPath(), entry.EntriesHandler)
+
+	serve := func(p string) *EntriesResponse {
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, p, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		res := &EntriesResponse{}
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), res))
+		return res
+	}
+
+	// all entries
+	res := serve("/rk/v1/entries")
+
+	var gin *EntryInfo
+	for _, info := range res.Entries[GinEntryType] {
+		if info.EntryName == "ut-entries" {
+			gin = info
+		}
+	}
+	assert.NotNil(t, gin)
+	meta := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(gin.EntryMeta, &meta))
+	assert.Equal(t, "ut-entries", meta["name"])
+
+	// failed to marshal
+	assert.Equal(t, "fail", res.Entries["UtFailEntry"][0].EntryDescription)
+	assert.Contains(t, res.Entries["UtFailEntry"][0].Error, "ut-error")
+	assert.Empty(t, res.Entries["UtFailEntry"][0].EntryMeta)
+
+	// selected by type
+	res = serve("/rk/v1/entries?type=" + GinEntryType)
+	assert.Len(t, res.Entries, 1)
+	assert.NotEmpty(t, res.Entries[GinEntryType])
+
+	// disabled common service
+	disabled := RegisterGinEntry(WithName("ut-entries-disabled"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(disabled)
+	assert.Empty(t, disabled.EntriesPath())
+}
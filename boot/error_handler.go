@@ -0,0 +1,62 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"net/http"
+)
+
+// BootErrorHandler boot config of 404 and 405 handlers.
+//
+// Once enabled, gin's plain text responses will be replaced with JSON body built with rkmid.GetErrorBuilder(),
+// and 405 will be returned instead of 404 if path exists with another method.
+type BootErrorHandler struct {
+	Enabled     bool   `yaml:"enabled" json:"enabled"`
+	NoRouteMsg  string `yaml:"noRouteMsg" json:"noRouteMsg"`
+	NoMethodMsg string `yaml:"noMethodMsg" json:"noMethodMsg"`
+}
+
+// NoRouteHandler returns handler which responds 404 with JSON error body consistent with rkerror.
+func NoRouteHandler(msg string) gin.HandlerFunc {
+	if len(msg) < 1 {
+		msg = http.StatusText(http.StatusNotFound)
+	}
+
+	return func(ctx *gin.Context) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound,
+			rkmid.GetErrorBuilder().New(http.StatusNotFound, msg, ctx.Request.Method+" "+ctx.Request.URL.Path))
+	}
+}
+
+// NoMethodHandler returns handler which responds 405 with JSON error body consistent with rkerror.
+func NoMethodHandler(msg string) gin.HandlerFunc {
+	if len(msg) < 1 {
+		msg = http.StatusText(http.StatusMethodNotAllowed)
+	}
+
+	return func(ctx *gin.Context) {
+		ctx.AbortWithStatusJSON(http.StatusMethodNotAllowed,
+			rkmid.GetErrorBuilder().New(http.StatusMethodNotAllowed, msg, ctx.Request.Method+" "+ctx.Request.URL.Path))
+	}
+}
+
+// WithNoRouteHandler provide handlers for requests without matched route.
+func WithNoRouteHandler(handlers ...gin.HandlerFunc) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.noRouteHandlers = append(entry.noRouteHandlers, handlers...)
+	}
+}
+
+// WithNoMethodHandler provide handlers for requests with path matched but method not allowed.
+//
+// gin.Engine.HandleMethodNotAllowed will be enabled, otherwise handlers will never be called.
+func WithNoMethodHandler(handlers ...gin.HandlerFunc) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.noMethodHandlers = append(entry.noMethodHandlers, handlers...)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNoRouteHandler(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-no-route"),
+		WithPort(0),
+		WithNoRouteHandler(NoRouteHandler("")),
+		WithNoMethodHandler(NoMethodHandler("ut-no-method")))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.Router.GET("/ut", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	// 404
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/not-exist", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.Contains(t, w.Body.String(), http.StatusText(http.StatusNotFound))
+	assert.Contains(t, w.Body.String(), "/not-exist")
+
+	// 405
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ut", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Contains(t, w.Body.String(), "ut-no-method")
+}
+
+func TestRegisterGinEntryYAML_WithErrorHandler(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-error-handler
+    port: 1949
+    enabled: true
+    errorHandler:
+      enabled: true
+      noRouteMsg: "ut-no-route"
+`
+	entries := RegisterGinEntryYAML([]byte(bootStr))
+	entry := entries["ut-error-handler"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.True(t, entry.Router.HandleMethodNotAllowed)
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/not-exist", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "ut-no-route")
+}
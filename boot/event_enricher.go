@@ -0,0 +1,37 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/log"
+	"github.com/rookie-ninja/rk-query"
+)
+
+// WithEventEnricher provide enricher which appends domain fields like tenantId into every event of logging middleware.
+func WithEventEnricher(f func(*gin.Context, rkquery.Event)) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.AddEventEnricher(f)
+	}
+}
+
+// AddEventEnricher adds enricher which is called before event of logging middleware is finished.
+// This function should be called before Bootstrap() called.
+func (entry *GinEntry) AddEventEnricher(f func(*gin.Context, rkquery.Event)) {
+	if f != nil {
+		entry.eventEnrichers = append(entry.eventEnrichers, f)
+	}
+}
+
+// eventEnricherExtension returns extension of logging middleware which calls enrichers of entry,
+// enrichers added after middlewares are built are called too.
+func (entry *GinEntry) eventEnricherExtension() rkginlog.Extension {
+	return rkginlog.EventEnricher(func(ctx *gin.Context, event rkquery.Event) {
+		for _, f := range entry.eventEnrichers {
+			f(ctx, event)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-query"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithEventEnricher(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-enricher-option"),
+		WithEventEnricher(func(ctx *gin.Context, event rkquery.Event) {}),
+		WithEventEnricher(nil))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Len(t, entry.eventEnrichers, 1)
+}
+
+func TestGinEntry_AddEventEnricher(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.log")
+	bootStr := fmt.Sprintf(`
+gin:
+  - name: ut-enricher
+    port: 1949
+    enabled: true
+    middleware:
+      logging:
+        enabled: true
+        format: json
+        eventOutputPaths: ["%s"]
+`, output)
+
+	entry := RegisterGinEntryYAML([]byte(bootStr))["ut-enricher"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	// enricher added after middlewares are built from config
+	entry.AddEventEnricher(func(ctx *gin.Context, event rkquery.Event) {
+		event.AddPair("tenantId", ctx.GetString("tenantId"))
+	})
+	entry.Router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Set("tenantId", "ut-tenant")
+		ctx.Status(http.StatusOK)
+	})
+	entry.Router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	event := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &event))
+	assert.Equal(t, map[string]interface{}{"tenantId": "ut-tenant"}, event["pairs"])
+}
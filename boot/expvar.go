@@ -0,0 +1,84 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"expvar"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"path"
+	"sync"
+	"sync/atomic"
+)
+
+// publishExpvarOnce makes sure rk variable is published once, expvar.Publish panics on duplicated name.
+var publishExpvarOnce sync.Once
+
+// BootExpvar config of expvar variables served by GinEntry.
+type BootExpvar struct {
+	Enabled bool   `yaml:"enabled" json:"enabled"`
+	Path    string `yaml:"path" json:"path"`
+}
+
+// ExpvarStats variables of GinEntry published under rk in expvar.
+type ExpvarStats struct {
+	Ready      bool   `json:"ready"`
+	Port       uint64 `json:"port"`
+	Requests   uint64 `json:"requests"`
+	ErrorCount uint64 `json:"errorCount"`
+}
+
+// WithExpvar serve expvar variables at path, /debug/vars by default.
+//
+// Variables published with expvar package are served, including cmdline, memstats and rk which contains stats of GinEntry.
+func WithExpvar(p string) GinEntryOption {
+	return func(entry *GinEntry) {
+		if len(p) < 1 {
+			p = "/debug/vars"
+		}
+		entry.expvarPath = path.Join("/", p)
+	}
+}
+
+// IsExpvarEnabled is expvar enabled?
+func (entry *GinEntry) IsExpvarEnabled() bool {
+	return len(entry.expvarPath) > 0
+}
+
+// ExpvarPath returns path of expvar variables, empty if not enabled.
+func (entry *GinEntry) ExpvarPath() string {
+	return entry.expvarPath
+}
+
+// publishExpvar publishes rk variable which reports ExpvarStats of GinEntry in GlobalAppCtx by name.
+func publishExpvar() {
+	publishExpvarOnce.Do(func() {
+		expvar.Publish("rk", expvar.Func(func() interface{} {
+			res := make(map[string]*ExpvarStats)
+			for name, v := range rkentry.GlobalAppCtx.ListEntriesByType(GinEntryType) {
+				if entry, ok := v.(*GinEntry); ok {
+					res[name] = newExpvarStats(entry)
+				}
+			}
+
+			return res
+		}))
+	})
+}
+
+// newExpvarStats sums request stats recorded by prom middleware.
+func newExpvarStats(entry *GinEntry) *ExpvarStats {
+	res := &ExpvarStats{
+		Ready: atomic.LoadInt32(&entry.ready) == 1,
+		Port:  entry.Port,
+	}
+
+	for _, metric := range listReqMetrics(entry.GetMetricsSet()) {
+		res.Requests += metric.Count
+		res.ErrorCount += metric.ErrorCount
+	}
+
+	return res
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"expvar"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithExpvar(t *testing.T) {
+	// default path
+	entry := &GinEntry{}
+	assert.False(t, entry.IsExpvarEnabled())
+	WithExpvar("")(entry)
+	assert.True(t, entry.IsExpvarEnabled())
+	assert.Equal(t, "/debug/vars", entry.ExpvarPath())
+
+	// custom path
+	WithExpvar("ut/vars")(entry)
+	assert.Equal(t, "/ut/vars", entry.ExpvarPath())
+}
+
+func TestGinEntry_Expvar(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-expvar"),
+		WithPort(8080),
+		WithExpvar(""))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	publishExpvar()
+	// publish twice should not panic
+	publishExpvar()
+	atomic.StoreInt32(&entry.ready, 1)
+
+	entry.Router.GET(entry.ExpvarPath(), gin.WrapH(expvar.Handler()))
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	res := map[string]json.RawMessage{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Contains(t, res, "memstats")
+	assert.Contains(t, res, "cmdline")
+
+	rk := map[string]*ExpvarStats{}
+	assert.Nil(t, json.Unmarshal(res["rk"], &rk))
+	assert.Equal(t, &ExpvarStats{Ready: true, Port: 8080}, rk["ut-expvar"])
+}
+
+func TestGinEntry_Expvar_WithCommonServiceAuth(t *testing.T) {
+	entry := newBootstrappedTestEntry(t, `
+gin:
+  - name: ut-expvar-auth
+    port: 0
+    enabled: true
+    commonService:
+      enabled: true
+      auth:
+        basic: ["admin:secret"]
+    expvar:
+      enabled: true
+`)
+
+	assert.Equal(t, http.StatusUnauthorized, serveTest(entry, http.MethodGet, "/debug/vars", "", nil).Code)
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/debug/vars", "", utCommonServiceAuth).Code)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/os"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+)
+
+// GcMemStats memory stats reported by GC API, fields of rkos.MemInfo are kept for compatibility.
+type GcMemStats struct {
+	rkos.MemInfo     `yaml:",inline"`
+	HeapAllocByte    uint64 `json:"heapAllocByte" yaml:"heapAllocByte"`
+	HeapSysByte      uint64 `json:"heapSysByte" yaml:"heapSysByte"`
+	HeapIdleByte     uint64 `json:"heapIdleByte" yaml:"heapIdleByte"`
+	HeapInuseByte    uint64 `json:"heapInuseByte" yaml:"heapInuseByte"`
+	HeapReleasedByte uint64 `json:"heapReleasedByte" yaml:"heapReleasedByte"`
+	HeapObjects      uint64 `json:"heapObjects" yaml:"heapObjects"`
+	PauseTotalNs     uint64 `json:"pauseTotalNs" yaml:"pauseTotalNs"`
+}
+
+// GcResponse response of GC API.
+type GcResponse struct {
+	MemStatBeforeGc *GcMemStats `json:"memStatBeforeGc" yaml:"memStatBeforeGc"`
+	MemStatAfterGc  *GcMemStats `json:"memStatAfterGc" yaml:"memStatAfterGc"`
+}
+
+// newGcMemStats reads memory stats of current process.
+func newGcMemStats() *GcMemStats {
+	stats := runtime.MemStats{}
+	runtime.ReadMemStats(&stats)
+
+	return &GcMemStats{
+		MemInfo:          *rkos.NewMemInfo(),
+		HeapAllocByte:    stats.HeapAlloc,
+		HeapSysByte:      stats.HeapSys,
+		HeapIdleByte:     stats.HeapIdle,
+		HeapInuseByte:    stats.HeapInuse,
+		HeapReleasedByte: stats.HeapReleased,
+		HeapObjects:      stats.HeapObjects,
+		PauseTotalNs:     stats.PauseTotalNs,
+	}
+}
+
+// GcHandler runs GC and returns as much memory to OS as possible, memory stats before and after are returned.
+//
+// It replaces handler of CommonServiceEntry at the same path, which runs GC only, and is protected
+// by auth middlewares like other common service APIs.
+func (entry *GinEntry) GcHandler(ctx *gin.Context) {
+	before := newGcMemStats()
+	runtime.GC()
+	debug.FreeOSMemory()
+	after := newGcMemStats()
+
+	ctx.JSON(http.StatusOK, &GcResponse{
+		MemStatBeforeGc: before,
+		MemStatAfterGc:  after,
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/auth"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGinEntry_GcHandler(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-gc"),
+		WithPort(0),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{Enabled: true})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.Router.Use(rkginauth.Middleware(rkmidauth.WithBasicAuth("", "user:pass")))
+	entry.Router.GET(entry.CommonServiceEntry.GcPath, entry.GcHandler)
+
+	// protected by auth
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rk/v1/gc", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/rk/v1/gc", nil)
+	req.SetBasicAuth("user", "pass")
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	res := &GcResponse{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.NotZero(t, res.MemStatBeforeGc.HeapSysByte)
+	assert.True(t, res.MemStatAfterGc.GcCount > res.MemStatBeforeGc.GcCount)
+
+	// fields of rkos.MemInfo kept
+	raw := make(map[string]map[string]interface{})
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &raw))
+	assert.Contains(t, raw["memStatAfterGc"], "memAllocByte")
+	assert.Contains(t, raw["memStatAfterGc"], "heapReleasedByte")
+}
@@ -10,46 +10,33 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"expvar"
 	"fmt"
 	"github.com/gin-contrib/pprof"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	rkentry "github.com/rookie-ninja/rk-entry/v2/entry"
 	rkerror "github.com/rookie-ninja/rk-entry/v2/error"
 	rkmid "github.com/rookie-ninja/rk-entry/v2/middleware"
-	rkmidauth "github.com/rookie-ninja/rk-entry/v2/middleware/auth"
-	rkmidcors "github.com/rookie-ninja/rk-entry/v2/middleware/cors"
-	rkmidcsrf "github.com/rookie-ninja/rk-entry/v2/middleware/csrf"
 	rkmidjwt "github.com/rookie-ninja/rk-entry/v2/middleware/jwt"
-	rkmidlog "github.com/rookie-ninja/rk-entry/v2/middleware/log"
-	rkmidmeta "github.com/rookie-ninja/rk-entry/v2/middleware/meta"
-	rkmidpanic "github.com/rookie-ninja/rk-entry/v2/middleware/panic"
-	rkmidprom "github.com/rookie-ninja/rk-entry/v2/middleware/prom"
-	rkmidlimit "github.com/rookie-ninja/rk-entry/v2/middleware/ratelimit"
-	rkmidsec "github.com/rookie-ninja/rk-entry/v2/middleware/secure"
-	rkmidtimeout "github.com/rookie-ninja/rk-entry/v2/middleware/timeout"
-	rkmidtrace "github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
-	"github.com/rookie-ninja/rk-gin/v2/middleware/auth"
-	"github.com/rookie-ninja/rk-gin/v2/middleware/cors"
-	"github.com/rookie-ninja/rk-gin/v2/middleware/csrf"
-	"github.com/rookie-ninja/rk-gin/v2/middleware/gzip"
-	"github.com/rookie-ninja/rk-gin/v2/middleware/jwt"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
 	"github.com/rookie-ninja/rk-gin/v2/middleware/log"
-	"github.com/rookie-ninja/rk-gin/v2/middleware/meta"
 	"github.com/rookie-ninja/rk-gin/v2/middleware/panic"
 	"github.com/rookie-ninja/rk-gin/v2/middleware/prom"
-	"github.com/rookie-ninja/rk-gin/v2/middleware/ratelimit"
-	"github.com/rookie-ninja/rk-gin/v2/middleware/secure"
-	"github.com/rookie-ninja/rk-gin/v2/middleware/timeout"
 	"github.com/rookie-ninja/rk-gin/v2/middleware/tracing"
 	"github.com/rookie-ninja/rk-query"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/zap"
+	"io/fs"
+	"net"
 	"net/http"
 	"path"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -65,64 +52,123 @@ func init() {
 }
 
 // BootGin boot config which is for gin entry.
+//
+// MiddlewareDefaults is merged into middleware of each gin entry and its groups which declare middleware section.
 type BootGin struct {
-	Gin []*BootGinElement `yaml:"gin" json:"gin"`
+	Gin                []*BootGinElement `yaml:"gin" json:"gin"`
+	MiddlewareDefaults BootMiddleware    `yaml:"middlewareDefaults" json:"middlewareDefaults"`
 }
 
+// BootConfig typed boot config of gin entries, same schema as boot.yaml.
+type BootConfig = BootGin
+
 type BootGinElement struct {
-	Enabled       bool                          `yaml:"enabled" json:"enabled"`
-	Name          string                        `yaml:"name" json:"name"`
-	Port          uint64                        `yaml:"port" json:"port"`
-	Description   string                        `yaml:"description" json:"description"`
-	SW            rkentry.BootSW                `yaml:"sw" json:"sw"`
-	Docs          rkentry.BootDocs              `yaml:"docs" json:"docs"`
-	CommonService rkentry.BootCommonService     `yaml:"commonService" json:"commonService"`
-	Prom          rkentry.BootProm              `yaml:"prom" json:"prom"`
-	CertEntry     string                        `yaml:"certEntry" json:"certEntry"`
-	LoggerEntry   string                        `yaml:"loggerEntry" json:"loggerEntry"`
-	EventEntry    string                        `yaml:"eventEntry" json:"eventEntry"`
-	Static        rkentry.BootStaticFileHandler `yaml:"static" json:"static"`
-	PProf         rkentry.BootPProf             `yaml:"pprof" json:"pprof"`
-	Middleware    struct {
-		Ignore     []string                `yaml:"ignore" json:"ignore"`
-		ErrorModel string                  `yaml:"errorModel" json:"errorModel"`
-		Logging    rkmidlog.BootConfig     `yaml:"logging" json:"logging"`
-		Prom       rkmidprom.BootConfig    `yaml:"prom" json:"prom"`
-		Auth       rkmidauth.BootConfig    `yaml:"auth" json:"auth"`
-		Cors       rkmidcors.BootConfig    `yaml:"cors" json:"cors"`
-		Meta       rkmidmeta.BootConfig    `yaml:"meta" json:"meta"`
-		Jwt        rkmidjwt.BootConfig     `yaml:"jwt" json:"jwt"`
-		Secure     rkmidsec.BootConfig     `yaml:"secure" json:"secure"`
-		RateLimit  rkmidlimit.BootConfig   `yaml:"rateLimit" json:"rateLimit"`
-		Csrf       rkmidcsrf.BootConfig    `yaml:"csrf" yaml:"csrf"`
-		Timeout    rkmidtimeout.BootConfig `yaml:"timeout" json:"timeout"`
-		Trace      rkmidtrace.BootConfig   `yaml:"trace" json:"trace"`
-		Gzip       struct {
-			Enabled bool     `yaml:"enabled" json:"enabled"`
-			Ignore  []string `yaml:"ignore" json:"ignore"`
-			Level   string   `yaml:"level" json:"level"`
-		} `yaml:"gzip" json:"gzip"`
-	} `yaml:"middleware" json:"middleware"`
+	Enabled            bool                  `yaml:"enabled" json:"enabled"`
+	Name               string                `yaml:"name" json:"name"`
+	Locale             string                `yaml:"locale" json:"locale"`
+	Port               uint64                `yaml:"port" json:"port"`
+	Description        string                `yaml:"description" json:"description"`
+	SW                 BootSW                `yaml:"sw" json:"sw"`
+	Docs               rkentry.BootDocs      `yaml:"docs" json:"docs"`
+	Redoc              BootRedoc             `yaml:"redoc" json:"redoc"`
+	RapiDoc            BootRapiDoc           `yaml:"rapiDoc" json:"rapiDoc"`
+	TV                 BootTV                `yaml:"tv" json:"tv"`
+	CommonService      BootCommonService     `yaml:"commonService" json:"commonService"`
+	Prom               BootProm              `yaml:"prom" json:"prom"`
+	CertEntry          string                `yaml:"certEntry" json:"certEntry"`
+	LoggerEntry        string                `yaml:"loggerEntry" json:"loggerEntry"`
+	EventEntry         string                `yaml:"eventEntry" json:"eventEntry"`
+	Static             BootStaticFileHandler `yaml:"static" json:"static"`
+	PProf              rkentry.BootPProf     `yaml:"pprof" json:"pprof"`
+	Expvar             BootExpvar            `yaml:"expvar" json:"expvar"`
+	Gops               BootGops              `yaml:"gops" json:"gops"`
+	Engine             BootEngine            `yaml:"engine" json:"engine"`
+	ErrorHandler       BootErrorHandler      `yaml:"errorHandler" json:"errorHandler"`
+	Signal             BootSignal            `yaml:"signal" json:"signal"`
+	Maintenance        BootMaintenance       `yaml:"maintenance" json:"maintenance"`
+	Warmup             BootWarmup            `yaml:"warmup" json:"warmup"`
+	HealthCheck        BootHealthCheck       `yaml:"healthCheck" json:"healthCheck"`
+	Groups             []*BootGinGroup       `yaml:"groups" json:"groups"`
+	Routes             []*BootRoute          `yaml:"routes" json:"routes"`
+	DependsOn          []string              `yaml:"dependsOn" json:"dependsOn"`
+	DependsOnTimeoutMs int                   `yaml:"dependsOnTimeoutMs" json:"dependsOnTimeoutMs"`
+	Middleware         BootMiddleware        `yaml:"middleware" json:"middleware"`
 }
 
 // GinEntry implements rkentry.Entry interface.
 type GinEntry struct {
-	entryName          string                          `json:"-" yaml:"-"`
-	entryType          string                          `json:"-" yaml:"-"`
-	entryDescription   string                          `json:"-" yaml:"-"`
-	Router             *gin.Engine                     `json:"-" yaml:"-"`
-	Server             *http.Server                    `json:"-" yaml:"-"`
-	Port               uint64                          `json:"-" yaml:"-"`
-	LoggerEntry        *rkentry.LoggerEntry            `json:"-" yaml:"-"`
-	EventEntry         *rkentry.EventEntry             `json:"-" yaml:"-"`
-	SwEntry            *rkentry.SWEntry                `json:"-" yaml:"-"`
-	DocsEntry          *rkentry.DocsEntry              `json:"-" yaml:"-"`
-	CommonServiceEntry *rkentry.CommonServiceEntry     `json:"-" yaml:"-"`
-	PromEntry          *rkentry.PromEntry              `json:"-" yaml:"-"`
-	StaticFileEntry    *rkentry.StaticFileHandlerEntry `json:"-" yaml:"-"`
-	CertEntry          *rkentry.CertEntry              `json:"-" yaml:"-"`
-	PProfEntry         *rkentry.PProfEntry             `json:"-" yaml:"-"`
-	bootstrapLogOnce   sync.Once                       `json:"-" yaml:"-"`
+	entryName              string                          `json:"-" yaml:"-"`
+	entryType              string                          `json:"-" yaml:"-"`
+	entryDescription       string                          `json:"-" yaml:"-"`
+	Router                 *gin.Engine                     `json:"-" yaml:"-"`
+	Server                 *http.Server                    `json:"-" yaml:"-"`
+	Port                   uint64                          `json:"-" yaml:"-"`
+	LoggerEntry            *rkentry.LoggerEntry            `json:"-" yaml:"-"`
+	EventEntry             *rkentry.EventEntry             `json:"-" yaml:"-"`
+	SwEntry                *rkentry.SWEntry                `json:"-" yaml:"-"`
+	DocsEntry              *rkentry.DocsEntry              `json:"-" yaml:"-"`
+	RedocEntry             *RedocEntry                     `json:"-" yaml:"-"`
+	RapiDocEntry           *RapiDocEntry                   `json:"-" yaml:"-"`
+	TvEntry                *TvEntry                        `json:"-" yaml:"-"`
+	CommonServiceEntry     *rkentry.CommonServiceEntry     `json:"-" yaml:"-"`
+	PromEntry              *rkentry.PromEntry              `json:"-" yaml:"-"`
+	StaticFileEntry        *rkentry.StaticFileHandlerEntry `json:"-" yaml:"-"`
+	CertEntry              *rkentry.CertEntry              `json:"-" yaml:"-"`
+	PProfEntry             *rkentry.PProfEntry             `json:"-" yaml:"-"`
+	bootstrapLogOnce       sync.Once                       `json:"-" yaml:"-"`
+	signalRegistry         *signalRegistry                 `json:"-" yaml:"-"`
+	errCh                  chan error                      `json:"-" yaml:"-"`
+	dependsOn              []string                        `json:"-" yaml:"-"`
+	dependsOnTimeout       time.Duration                   `json:"-" yaml:"-"`
+	ready                  int32                           `json:"-" yaml:"-"`
+	bootstrapStarted       int32                           `json:"-" yaml:"-"`
+	routesRegistered       int32                           `json:"-" yaml:"-"`
+	shutdownHookRegistry   *shutdownHookRegistry           `json:"-" yaml:"-"`
+	noRouteHandlers        []gin.HandlerFunc               `json:"-" yaml:"-"`
+	noMethodHandlers       []gin.HandlerFunc               `json:"-" yaml:"-"`
+	routes                 []*BootRoute                    `json:"-" yaml:"-"`
+	engineConfig           *BootEngine                     `json:"-" yaml:"-"`
+	engineErr              error                           `json:"-" yaml:"-"`
+	pendingHooks           *pendingHooks                   `json:"-" yaml:"-"`
+	groups                 []*GinGroupEntry                `json:"-" yaml:"-"`
+	warmupPaths            []string                        `json:"-" yaml:"-"`
+	warmupTimeout          time.Duration                   `json:"-" yaml:"-"`
+	traceFlushTimeout      time.Duration                   `json:"-" yaml:"-"`
+	warmupFuncs            []*warmupFunc                   `json:"-" yaml:"-"`
+	maintenance            *maintenance                    `json:"-" yaml:"-"`
+	middlewareRegistry     *middlewareRegistry             `json:"-" yaml:"-"`
+	metricsSet             *rkmidprom.MetricsSet           `json:"-" yaml:"-"`
+	traceProviders         []*sdktrace.TracerProvider      `json:"-" yaml:"-"`
+	meterProviders         []*sdkmetric.MeterProvider      `json:"-" yaml:"-"`
+	assetsFS               fs.FS                           `json:"-" yaml:"-"`
+	swSpecStore            *swSpecStore                    `json:"-" yaml:"-"`
+	swJsonUrls             []string                        `json:"-" yaml:"-"`
+	swJsonUrlsTtl          time.Duration                   `json:"-" yaml:"-"`
+	swBasicAuth            []string                        `json:"-" yaml:"-"`
+	swApiKey               []string                        `json:"-" yaml:"-"`
+	swAllowedIps           []string                        `json:"-" yaml:"-"`
+	swGenerateSpec         bool                            `json:"-" yaml:"-"`
+	routeDocs              *routeDocRegistry               `json:"-" yaml:"-"`
+	swMerge                bool                            `json:"-" yaml:"-"`
+	swWatch                *swWatcher                      `json:"-" yaml:"-"`
+	swMock                 *swMocker                       `json:"-" yaml:"-"`
+	swFilter               *BootSWFilter                   `json:"-" yaml:"-"`
+	healthChecks           *healthCheckRegistry            `json:"-" yaml:"-"`
+	cpuSampler             *cpuSampler                     `json:"-" yaml:"-"`
+	commonServiceBasicAuth []string                        `json:"-" yaml:"-"`
+	commonServiceApiKey    []string                        `json:"-" yaml:"-"`
+	commonServiceJwt       rkmidjwt.OptionSetInterface     `json:"-" yaml:"-"`
+	staticFS               fs.FS                           `json:"-" yaml:"-"`
+	staticListing          *BootStaticListing              `json:"-" yaml:"-"`
+	staticCacheControl     []BootStaticCacheControl        `json:"-" yaml:"-"`
+	expvarPath             string                          `json:"-" yaml:"-"`
+	gops                   *BootGops                       `json:"-" yaml:"-"`
+	eventEnrichers         []rkginlog.EventEnricher        `json:"-" yaml:"-"`
+	recoveryHandler        rkginpanic.RecoveryHandler      `json:"-" yaml:"-"`
+	promPort               uint64                          `json:"-" yaml:"-"`
+	promCertEntry          *rkentry.CertEntry              `json:"-" yaml:"-"`
+	promBasicAuth          []string                        `json:"-" yaml:"-"`
+	promServer             *http.Server                    `json:"-" yaml:"-"`
 }
 
 // RegisterGinEntryYAML register gin entries with provided config file (Must YAML file).
@@ -144,182 +190,342 @@ type GinEntry struct {
 // Example of nested map:   ./binary_file --rkset "outer.inner.key=val"
 // Example of slice:        ./binary_file --rkset "outer[0].key=val"
 func RegisterGinEntryYAML(raw []byte) map[string]rkentry.Entry {
-	res := make(map[string]rkentry.Entry)
+	res, err := RegisterGinEntryYAMLWithError(raw)
+	if err != nil {
+		rkentry.ShutdownWithError(err)
+	}
 
-	// 1: Decode config map into boot config struct
-	config := &BootGin{}
-	rkentry.UnmarshalBootYAML(raw, config)
+	return res
+}
+
+// RegisterGinEntriesWithBootConfig register GinEntry with typed boot config built in code.
+//
+// MiddlewareDefaults and secret references are applied the same way as RegisterGinEntryYAML, zero values are treated
+// as missing, list sections in Middleware.SkipDefaults to keep them zero. ENV and --rkset overrides are only applied
+// to YAML in RegisterGinEntryYAML.
+//
+//	rkgin.RegisterGinEntriesWithBootConfig(&rkgin.BootConfig{
+//		Gin: []*rkgin.BootGinElement{
+//			{Enabled: true, Name: "greeter", Port: *port},
+//		},
+//	})
+func RegisterGinEntriesWithBootConfig(config *BootConfig) map[string]rkentry.Entry {
+	res, err := registerGinEntriesWithBootConfig(config)
+	if err != nil {
+		rkentry.ShutdownWithError(err)
+	}
+
+	return res
+}
+
+// registerGinEntriesWithBootConfig preprocess typed boot config and register GinEntry with it.
+func registerGinEntriesWithBootConfig(config *BootConfig) (map[string]rkentry.Entry, error) {
+	if config == nil {
+		return map[string]rkentry.Entry{}, nil
+	}
+
+	config, err := preprocessBootConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return registerGinEntries(config)
+}
+
+// RegisterGinEntryYAMLWithError same as RegisterGinEntryYAML, but returns error instead of shutting down process.
+//
+// GinEntry registered before error occurs will be removed from rkentry.GlobalAppCtx.
+func RegisterGinEntryYAMLWithError(raw []byte) (map[string]rkentry.Entry, error) {
+	res, err := registerGinEntryYAMLWithPendingHooks(raw)
+	if err != nil {
+		return nil, err
+	}
+	commitHooks(res)
+
+	return res, nil
+}
+
+// registerGinEntryYAMLWithPendingHooks register GinEntry with raw YAML like RegisterGinEntryYAMLWithError,
+// shutdown hooks of middlewares are kept pending until committed with commitHooks.
+func registerGinEntryYAMLWithPendingHooks(raw []byte) (map[string]rkentry.Entry, error) {
+	// 1: Apply middleware defaults, resolve secret references and decode config map into boot config struct
+	raw, err := preprocessBootYAML(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := unmarshalBootConfig(raw)
+	if err != nil {
+		return nil, err
+	}
 
 	// 2: Init gin entries with boot config
+	return registerGinEntriesWithPendingHooks(config)
+}
+
+// unmarshalBootConfig decode raw YAML into boot config with ENV and --rkset overrides.
+func unmarshalBootConfig(raw []byte) (config *BootConfig, err error) {
+	// rkentry.UnmarshalBootYAML shuts down process with invalid YAML
+	defer recoverShutdownError(&err)
+
+	config = &BootConfig{}
+	rkentry.UnmarshalBootYAML(raw, config)
+
+	return config, nil
+}
+
+// registerGinEntries register GinEntry with boot config.
+//
+// If error occurs, GinEntry registered by this call are removed together with metrics and tracer providers of them.
+func registerGinEntries(config *BootConfig) (map[string]rkentry.Entry, error) {
+	res, err := registerGinEntriesWithPendingHooks(config)
+	if err != nil {
+		return nil, err
+	}
+	commitHooks(res)
+
+	return res, nil
+}
+
+// registerGinEntriesWithPendingHooks register GinEntry with boot config like registerGinEntries,
+// shutdown hooks of middlewares are kept pending until committed with commitHooks.
+func registerGinEntriesWithPendingHooks(config *BootConfig) (map[string]rkentry.Entry, error) {
+	res := make(map[string]rkentry.Entry)
+	if config == nil {
+		return res, nil
+	}
+
 	for i := range config.Gin {
 		element := config.Gin[i]
-		if !element.Enabled {
+		if element == nil || !element.Enabled || !IsLocaleValid(element.Locale) {
 			continue
 		}
 
-		name := element.Name
-
-		// logger entry
-		loggerEntry := rkentry.GlobalAppCtx.GetLoggerEntry(element.LoggerEntry)
-		if loggerEntry == nil {
-			loggerEntry = rkentry.LoggerEntryStdout
+		entry, err := newGinEntryFromConfig(element)
+		if err != nil {
+			for _, v := range res {
+				v.(*GinEntry).unregister()
+			}
+			return nil, fmt.Errorf("failed to register GinEntry %s, %v", element.Name, err)
 		}
 
-		// event entry
-		eventEntry := rkentry.GlobalAppCtx.GetEventEntry(element.EventEntry)
-		if eventEntry == nil {
-			eventEntry = rkentry.EventEntryStdout
-		}
+		res[entry.entryName] = entry
+	}
 
-		// cert entry
-		certEntry := rkentry.GlobalAppCtx.GetCertEntry(element.CertEntry)
+	return res, nil
+}
 
-		// Register swagger entry
-		swEntry := rkentry.RegisterSWEntry(&element.SW, rkentry.WithNameSWEntry(element.Name))
+// newGinEntryFromConfig register GinEntry with boot config of element, GinEntry is removed if error occurs.
+//
+// Shutdown hooks of middlewares are kept pending in GinEntry, since committing them closes resources of
+// GinEntry with the same name which may be still serving while reloading.
+//
+// Sub entries of rk-entry shut down process with invalid config, which is returned as error instead.
+func newGinEntryFromConfig(element *BootGinElement) (res *GinEntry, err error) {
+	defer recoverShutdownError(&err)
 
-		// Register docs entry
-		docsEntry := rkentry.RegisterDocsEntry(&element.Docs, rkentry.WithNameDocsEntry(element.Name))
+	if err := element.Engine.validate(); err != nil {
+		return nil, err
+	}
 
-		// Register prometheus entry
-		promRegistry := prometheus.NewRegistry()
-		promEntry := rkentry.RegisterPromEntry(&element.Prom, rkentry.WithRegistryPromEntry(promRegistry))
+	name := element.Name
 
-		// Register common service entry
-		commonServiceEntry := rkentry.RegisterCommonServiceEntry(&element.CommonService)
+	// logger entry
+	loggerEntry := rkentry.GlobalAppCtx.GetLoggerEntry(element.LoggerEntry)
+	if loggerEntry == nil {
+		loggerEntry = rkentry.LoggerEntryStdout
+	}
 
-		// Register static file handler
-		staticEntry := rkentry.RegisterStaticFileHandlerEntry(&element.Static, rkentry.WithNameStaticFileHandlerEntry(element.Name))
+	// event entry
+	eventEntry := rkentry.GlobalAppCtx.GetEventEntry(element.EventEntry)
+	if eventEntry == nil {
+		eventEntry = rkentry.EventEntryStdout
+	}
 
-		// Register pprof entry
-		pprofEntry := rkentry.RegisterPProfEntry(&element.PProf, rkentry.WithNamePProfEntry(element.Name))
+	// cert entry
+	certEntry := rkentry.GlobalAppCtx.GetCertEntry(element.CertEntry)
 
-		inters := make([]gin.HandlerFunc, 0)
+	// Register swagger entry
+	swEntry := rkentry.RegisterSWEntry(&element.SW.BootSW, rkentry.WithNameSWEntry(element.Name))
 
-		// add global path ignorance
-		rkmid.AddPathToIgnoreGlobal(element.Middleware.Ignore...)
+	// Register docs entry
+	docsEntry := rkentry.RegisterDocsEntry(&element.Docs, rkentry.WithNameDocsEntry(element.Name))
 
-		// set error builder based on error builder
-		switch strings.ToLower(element.Middleware.ErrorModel) {
-		case "", "google":
-			rkmid.SetErrorBuilder(rkerror.NewErrorBuilderGoogle())
-		case "amazon":
-			rkmid.SetErrorBuilder(rkerror.NewErrorBuilderAMZN())
-		}
+	// Register redoc entry, spec files are read from the same paths as swagger by default
+	redocEntry := RegisterRedocEntry(&element.Redoc,
+		WithNameRedocEntry(element.Name),
+		WithJsonPathsRedocEntry(element.SW.JsonPaths...))
 
-		// logging middlewares
-		if element.Middleware.Logging.Enabled {
-			inters = append(inters, rkginlog.Middleware(
-				rkmidlog.ToOptions(&element.Middleware.Logging, element.Name, GinEntryType,
-					loggerEntry, eventEntry)...))
-		}
+	// Register rapidoc entry, spec files are read from the same paths as swagger by default
+	rapiDocEntry := RegisterRapiDocEntry(&element.RapiDoc,
+		WithNameRapiDocEntry(element.Name),
+		WithJsonPathsRapiDocEntry(element.SW.JsonPaths...))
 
-		// Default interceptor should be placed after logging middleware, we should make sure interceptors never panic
-		// insert panic interceptor
-		inters = append(inters, rkginpanic.Middleware(
-			rkmidpanic.WithEntryNameAndType(element.Name, GinEntryType)))
+	// Register prometheus entry
+	promRegistry := prometheus.NewRegistry()
+	promEntry := rkentry.RegisterPromEntry(&element.Prom.BootProm, rkentry.WithRegistryPromEntry(promRegistry))
+	element.Prom.Collectors.register(promEntry, element.Name)
 
-		// metrics middleware
-		if element.Middleware.Prom.Enabled {
-			inters = append(inters, rkginprom.Middleware(
-				rkmidprom.ToOptions(&element.Middleware.Prom, element.Name, GinEntryType,
-					promRegistry, rkmidprom.LabelerTypeHttp)...))
-		}
+	// Register common service entry
+	commonServiceEntry := rkentry.RegisterCommonServiceEntry(&element.CommonService.BootCommonService)
 
-		// tracing middleware
-		if element.Middleware.Trace.Enabled {
-			inters = append(inters, rkgintrace.Middleware(
-				rkmidtrace.ToOptions(&element.Middleware.Trace, element.Name, GinEntryType)...))
-		}
+	// Register tv entry, served under the same path prefix as common service
+	tvEntry := RegisterTvEntry(&element.TV,
+		WithNameTvEntry(element.Name),
+		WithPathPrefixTvEntry(element.CommonService.PathPrefix))
 
-		// cors middleware
-		if element.Middleware.Cors.Enabled {
-			inters = append(inters, rkgincors.Middleware(
-				rkmidcors.ToOptions(&element.Middleware.Cors, element.Name, GinEntryType)...))
+	// Register static file handler
+	staticEntry := rkentry.RegisterStaticFileHandlerEntry(&element.Static.BootStaticFileHandler, rkentry.WithNameStaticFileHandlerEntry(element.Name))
+	var staticFS fs.FS
+	if staticEntry != nil {
+		if staticFS, err = newStaticFS(element.Name, &element.Static); err != nil {
+			return nil, err
 		}
+	}
 
-		// jwt middleware
-		if element.Middleware.Jwt.Enabled {
-			inters = append(inters, rkginjwt.Middleware(
-				rkmidjwt.ToOptions(&element.Middleware.Jwt, element.Name, GinEntryType)...))
-		}
+	// Register pprof entry
+	pprofEntry := rkentry.RegisterPProfEntry(&element.PProf, rkentry.WithNamePProfEntry(element.Name))
 
-		// secure middleware
-		if element.Middleware.Secure.Enabled {
-			inters = append(inters, rkginsec.Middleware(
-				rkmidsec.ToOptions(&element.Middleware.Secure, element.Name, GinEntryType)...))
-		}
+	// add global path ignorance
+	rkmid.AddPathToIgnoreGlobal(element.Middleware.Ignore...)
 
-		// csrf middleware
-		if element.Middleware.Csrf.Enabled {
-			inters = append(inters, rkgincsrf.Middleware(
-				rkmidcsrf.ToOptions(&element.Middleware.Csrf, element.Name, GinEntryType)...))
-		}
+	// set error builder based on error builder
+	switch strings.ToLower(element.Middleware.ErrorModel) {
+	case "", "google":
+		rkmid.SetErrorBuilder(rkerror.NewErrorBuilderGoogle())
+	case "amazon":
+		rkmid.SetErrorBuilder(rkerror.NewErrorBuilderAMZN())
+	}
 
-		// gzip middleware
-		if element.Middleware.Gzip.Enabled {
-			opts := []rkgingzip.Option{
-				rkgingzip.WithEntryNameAndType(element.Name, GinEntryType),
-				rkgingzip.WithLevel(element.Middleware.Gzip.Level),
-				rkgingzip.WithPathToIgnore(element.Middleware.Gzip.Ignore...),
-			}
+	opts := []GinEntryOption{
+		WithLoggerEntry(loggerEntry),
+		WithEventEntry(eventEntry),
+		WithName(name),
+		WithDescription(element.Description),
+		WithPort(element.Port),
+		WithSwEntry(swEntry),
+		WithDocsEntry(docsEntry),
+		WithRedocEntry(redocEntry),
+		WithRapiDocEntry(rapiDocEntry),
+		WithTvEntry(tvEntry),
+		WithPromEntry(promEntry),
+		WithCommonServiceEntry(commonServiceEntry),
+		WithCertEntry(certEntry),
+		WithPProfEntry(pprofEntry),
+		WithStaticFileHandlerEntry(staticEntry),
+		WithStaticFS(staticFS),
+		WithStaticListing(&element.Static.Listing),
+		WithStaticCacheControl(element.Static.CacheControl),
+		WithGops(&element.Gops),
+		WithSignalEnabled(element.Signal.Enabled),
+		WithSignalInterrupt(element.Signal.Interrupt),
+		WithDependsOn(element.DependsOn...),
+		WithDependsOnTimeout(time.Duration(element.DependsOnTimeoutMs) * time.Millisecond),
+		WithRoutes(element.Routes...),
+		WithEngine(&element.Engine),
+		WithMaintenance(&element.Maintenance),
+		withMaintenanceInMiddlewares(),
+		WithSwJsonUrls(time.Duration(element.SW.JsonUrlsTtlMs)*time.Millisecond, element.SW.JsonUrls...),
+		WithSwBasicAuth(element.SW.Auth.Basic...),
+		WithSwApiKeyAuth(element.SW.Auth.ApiKey...),
+		WithSwAllowedIps(element.SW.AllowedIps...),
+		WithSwGenerateSpec(element.SW.GenerateSpec),
+		WithSwMerge(element.SW.Merge),
+		WithSwMock(element.SW.Mock),
+		WithSwFilter(&element.SW.Filter),
+		WithHealthCheckTimeout(time.Duration(element.HealthCheck.TimeoutMs) * time.Millisecond),
+		WithCommonServiceBasicAuth(element.CommonService.Auth.Basic...),
+		WithCommonServiceApiKeyAuth(element.CommonService.Auth.ApiKey...),
+		WithPromPort(element.Prom.Port),
+		WithPromCertEntry(rkentry.GlobalAppCtx.GetCertEntry(element.Prom.CertEntry)),
+		WithPromBasicAuth(element.Prom.Auth.Basic...),
+		WithTraceFlushTimeout(time.Duration(element.Middleware.Trace.FlushTimeoutMs) * time.Millisecond),
+	}
 
-			inters = append(inters, rkgingzip.Middleware(opts...))
-		}
+	// expvar variables
+	if element.Expvar.Enabled {
+		opts = append(opts, WithExpvar(element.Expvar.Path))
+	}
 
-		// meta middleware
-		if element.Middleware.Meta.Enabled {
-			inters = append(inters, rkginmeta.Middleware(
-				rkmidmeta.ToOptions(&element.Middleware.Meta, element.Name, GinEntryType)...))
-		}
+	// warmup paths
+	if element.Warmup.Enabled {
+		opts = append(opts,
+			WithWarmupPaths(element.Warmup.Paths...),
+			WithWarmupTimeout(time.Duration(element.Warmup.TimeoutMs)*time.Millisecond))
+	}
 
-		// auth middlewares
-		if element.Middleware.Auth.Enabled {
-			inters = append(inters, rkginauth.Middleware(
-				rkmidauth.ToOptions(&element.Middleware.Auth, element.Name, GinEntryType)...))
-		}
+	// jwt of common service
+	if element.CommonService.Auth.Jwt.Enabled {
+		opts = append(opts, WithCommonServiceJwtAuth(
+			rkmidjwt.ToOptions(&element.CommonService.Auth.Jwt, element.Name, GinEntryType)...))
+	}
 
-		// timeout middlewares
-		if element.Middleware.Timeout.Enabled {
-			inters = append(inters, rkgintout.Middleware(
-				rkmidtimeout.ToOptions(&element.Middleware.Timeout, element.Name, GinEntryType)...))
-		}
+	// watch swagger spec files
+	if element.SW.Watch {
+		opts = append(opts, WithSwWatch(time.Duration(element.SW.WatchIntervalMs)*time.Millisecond))
+	}
 
-		// rate limit middleware
-		if element.Middleware.RateLimit.Enabled {
-			inters = append(inters, rkginlimit.Middleware(
-				rkmidlimit.ToOptions(&element.Middleware.RateLimit, element.Name, GinEntryType)...))
-		}
+	// 404 and 405 handlers
+	if element.ErrorHandler.Enabled {
+		opts = append(opts,
+			WithNoRouteHandler(NoRouteHandler(element.ErrorHandler.NoRouteMsg)),
+			WithNoMethodHandler(NoMethodHandler(element.ErrorHandler.NoMethodMsg)))
+	}
 
-		entry := RegisterGinEntry(
-			WithLoggerEntry(loggerEntry),
-			WithEventEntry(eventEntry),
-			WithName(name),
-			WithDescription(element.Description),
-			WithPort(element.Port),
-			WithSwEntry(swEntry),
-			WithDocsEntry(docsEntry),
-			WithPromEntry(promEntry),
-			WithCommonServiceEntry(commonServiceEntry),
-			WithCertEntry(certEntry),
-			WithPProfEntry(pprofEntry),
-			WithStaticFileHandlerEntry(staticEntry))
+	registered := RegisterGinEntry(opts...)
+
+	// remove GinEntry and close writers of middlewares if middlewares failed to build
+	hooks := &pendingHooks{}
+	defer func() {
+		if err != nil {
+			hooks.release()
+			registered.flushTraces(context.Background(), registered.LoggerEntry.Logger)
+			registered.shutdownMeterProviders(context.Background(), registered.LoggerEntry.Logger)
+			registered.unregister()
+			return
+		}
+		registered.pendingHooks = hooks
+		registered.takeTraceProviders()
+	}()
 
-		entry.AddMiddleware(inters...)
+	mids, err := registered.newMiddlewaresFromConfig(&element.Middleware, promRegistry, hooks)
+	if err != nil {
+		return nil, err
+	}
+	registered.AddMiddleware(mids...)
 
-		res[name] = entry
+	// router groups with their own middlewares
+	for j := range element.Groups {
+		if !IsLocaleValid(element.Groups[j].Locale) {
+			continue
+		}
+		if _, err := registered.addGroupFromConfig(element.Groups[j], promRegistry, hooks); err != nil {
+			return nil, err
+		}
 	}
 
-	return res
+	return registered, nil
 }
 
 // RegisterGinEntry register GinEntry with options.
 func RegisterGinEntry(opts ...GinEntryOption) *GinEntry {
 	entry := &GinEntry{
-		entryType:        GinEntryType,
-		entryDescription: "Internal RK entry which helps to bootstrap with Gin framework.",
-		LoggerEntry:      rkentry.NewLoggerEntryStdout(),
-		EventEntry:       rkentry.NewEventEntryStdout(),
-		Port:             80,
+		entryType:            GinEntryType,
+		entryDescription:     "Internal RK entry which helps to bootstrap with Gin framework.",
+		LoggerEntry:          rkentry.NewLoggerEntryStdout(),
+		EventEntry:           rkentry.NewEventEntryStdout(),
+		Port:                 80,
+		signalRegistry:       newSignalRegistry(),
+		errCh:                make(chan error, 1),
+		shutdownHookRegistry: newShutdownHookRegistry(),
+		maintenance:          newMaintenance(),
+		swSpecStore:          newSwSpecStore(),
+		routeDocs:            newRouteDocRegistry(),
+		healthChecks:         newHealthCheckRegistry(),
+		cpuSampler:           &cpuSampler{},
+		middlewareRegistry: &middlewareRegistry{
+			handlers: make(map[string]*swappableHandler),
+		},
 	}
 
 	for i := range opts {
@@ -330,11 +536,33 @@ func RegisterGinEntry(opts ...GinEntryOption) *GinEntry {
 		entry.entryName = "gin-" + strconv.FormatUint(entry.Port, 10)
 	}
 
+	if entry.engineConfig == nil {
+		entry.engineConfig = &BootEngine{}
+	}
+
 	if entry.Router == nil {
-		gin.SetMode(gin.ReleaseMode)
+		gin.SetMode(entry.engineConfig.ginMode())
 		entry.Router = gin.New()
 	}
 
+	if len(entry.noRouteHandlers) > 0 {
+		entry.Router.NoRoute(entry.noRouteHandlers...)
+	}
+
+	if len(entry.noMethodHandlers) > 0 {
+		entry.Router.HandleMethodNotAllowed = true
+		entry.Router.NoMethod(entry.noMethodHandlers...)
+	}
+
+	// settings in config have higher priority, invalid ones are returned while bootstrapping
+	entry.engineErr = entry.engineConfig.apply(entry.Router)
+
+	// maintenance check comes before middlewares added with AddMiddleware,
+	// entry built from boot config places it after logging, panic, prom and trace middlewares
+	if !entry.maintenance.inMiddlewares {
+		entry.Router.Use(entry.maintenanceMiddleware())
+	}
+
 	if entry.Port != 0 {
 		entry.Server = &http.Server{
 			Addr:    "0.0.0.0:" + strconv.FormatUint(entry.Port, 10),
@@ -367,16 +595,67 @@ func (entry *GinEntry) GetDescription() string {
 }
 
 // Bootstrap GinEntry.
+//
+// Process will shutdown with rkentry.ShutdownWithError if any error occurs,
+// use BootstrapWithError instead if caller would like to handle errors by itself.
 func (entry *GinEntry) Bootstrap(ctx context.Context) {
+	if err := entry.BootstrapWithError(ctx); err != nil {
+		rkentry.ShutdownWithError(err)
+	}
+}
+
+// BootstrapWithError bootstrap GinEntry and return error instead of shutting down process.
+//
+// Listener is bound before returning, so errors like invalid port or address in use will be returned.
+// Errors occur while serving after bootstrap will be sent to channel returned by Errors().
+//
+// Bootstrap is skipped if GinEntry was bootstrapped already, like by GinEntry depends on it, until it is interrupted.
+func (entry *GinEntry) BootstrapWithError(ctx context.Context) (err error) {
+	if !atomic.CompareAndSwapInt32(&entry.bootstrapStarted, 0, 1) {
+		return nil
+	}
+
 	event, logger := entry.logBasicInfo("Bootstrap", ctx)
 
+	defer func() {
+		if err != nil {
+			atomic.StoreInt32(&entry.bootstrapStarted, 0)
+
+			// servers and workers started before error occurs are stopped
+			entry.stopBootstrapped(ctx, logger)
+
+			event.AddErr(err)
+			logger.Error("Error occurs while bootstrapping GinEntry.", zap.Error(err))
+			entry.bootstrapLogOnce.Do(func() {
+				entry.EventEntry.FinishWithCond(event, false)
+			})
+		}
+	}()
+
+	// sub entries of rk-entry shut down process with invalid config, which is returned as error instead
+	defer recoverShutdownError(&err)
+
+	// Invalid settings of gin.Engine provided with WithEngine
+	if entry.engineErr != nil {
+		return entry.engineErr
+	}
+
+	// Wait for dependencies
+	if err := entry.waitForDependencies(ctx); err != nil {
+		return err
+	}
+
+	// Register routes only once, since gin panics with routes registered already while bootstrapping again
+	if err := entry.registerRoutes(); err != nil {
+		return err
+	}
+
 	// Is common service enabled?
 	if entry.IsCommonServiceEnabled() {
-		// Register common service path into Router.
-		entry.Router.GET(entry.CommonServiceEntry.ReadyPath, gin.WrapF(entry.CommonServiceEntry.Ready))
-		entry.Router.GET(entry.CommonServiceEntry.AlivePath, gin.WrapF(entry.CommonServiceEntry.Alive))
-		entry.Router.GET(entry.CommonServiceEntry.GcPath, gin.WrapF(entry.CommonServiceEntry.Gc))
-		entry.Router.GET(entry.CommonServiceEntry.InfoPath, gin.WrapF(entry.CommonServiceEntry.Info))
+		// Is tv enabled?
+		if entry.IsTvEnabled() {
+			entry.TvEntry.Bootstrap(ctx)
+		}
 
 		// Bootstrap common service entry.
 		entry.CommonServiceEntry.Bootstrap(ctx)
@@ -384,36 +663,74 @@ func (entry *GinEntry) Bootstrap(ctx context.Context) {
 
 	// Is swagger enabled?
 	if entry.IsSwEnabled() {
-		entry.Router.GET(path.Join(entry.SwEntry.Path, "*any"), gin.WrapF(entry.SwEntry.ConfigFileHandler()))
 		entry.SwEntry.Bootstrap(ctx)
+		entry.reloadSwSpecs()
+		entry.initSwRemoteSpecs()
+		entry.initSwGeneratedSpec()
+		entry.startSwWatcher()
+		entry.initSwMock()
 	}
 
 	// Is docs enabled?
 	if entry.IsDocsEnabled() {
-		entry.Router.GET(path.Join(entry.DocsEntry.Path, "*any"), gin.WrapF(entry.DocsEntry.ConfigFileHandler()))
 		entry.DocsEntry.Bootstrap(ctx)
 	}
 
+	// Is redoc enabled?
+	if entry.IsRedocEnabled() {
+		entry.RedocEntry.Bootstrap(ctx)
+	}
+
+	// Is rapidoc enabled?
+	if entry.IsRapiDocEnabled() {
+		entry.RapiDocEntry.Bootstrap(ctx)
+	}
+
 	// Is static file handler enabled?
 	if entry.IsStaticFileHandlerEnabled() {
-		entry.Router.GET(path.Join(entry.StaticFileEntry.Path, "*any"), gin.WrapF(entry.StaticFileEntry.GetFileHandler()))
 		entry.StaticFileEntry.Bootstrap(ctx)
 	}
 
 	// Is prometheus enabled?
 	if entry.IsPromEnabled() {
-		// Register prom path into Router.
-		entry.Router.GET(entry.PromEntry.Path, gin.WrapH(promhttp.HandlerFor(entry.PromEntry.Gatherer, promhttp.HandlerOpts{})))
+		// Serve prom path on admin port if configured.
+		if entry.promPort > 0 {
+			if err := entry.startPromServer(logger); err != nil {
+				return err
+			}
+		}
 		entry.PromEntry.Bootstrap(ctx)
 	}
 
-	// Is pprof enabled?
-	if entry.IsPProfEnabled() {
-		pprof.Register(entry.Router, entry.PProfEntry.Path)
+	// Start gops agent
+	if entry.IsGopsEnabled() {
+		gopsAddr, err := startGopsAgent(entry.entryName, entry.gops)
+		if err != nil {
+			return err
+		}
+		event.AddPayloads(zap.String("gopsAddr", gopsAddr))
+	}
+
+	// Record all registered routes
+	event.AddPayloads(zap.Strings("apis", entry.apisForEvent()))
+
+	// Bind listener and start gin server
+	var addr net.Addr
+	if entry.Server != nil {
+		listener, err := net.Listen("tcp", entry.Server.Addr)
+		if err != nil {
+			return err
+		}
+		addr = listener.Addr()
+
+		go entry.startServer(listener, event, logger)
 	}
 
-	// Start gin server
-	go entry.startServer(event, logger)
+	// Warmup before turning ready
+	entry.runWarmup(ctx, addr, event, logger)
+
+	// Start listening on signals if enabled
+	entry.startSignalListener()
 
 	entry.bootstrapLogOnce.Do(func() {
 		// Print link and logging message
@@ -428,8 +745,24 @@ func (entry *GinEntry) Bootstrap(ctx context.Context) {
 		if entry.IsDocsEnabled() {
 			entry.LoggerEntry.Info(fmt.Sprintf("DocsEntry: %s://localhost:%d%s", scheme, entry.Port, entry.DocsEntry.Path))
 		}
+		if entry.IsRedocEnabled() {
+			entry.LoggerEntry.Info(fmt.Sprintf("RedocEntry: %s://localhost:%d%s", scheme, entry.Port, entry.RedocEntry.Path))
+		}
+		if entry.IsRapiDocEnabled() {
+			entry.LoggerEntry.Info(fmt.Sprintf("RapiDocEntry: %s://localhost:%d%s", scheme, entry.Port, entry.RapiDocEntry.Path))
+		}
+		if entry.IsTvEnabled() {
+			entry.LoggerEntry.Info(fmt.Sprintf("TvEntry: %s://localhost:%d%s", scheme, entry.Port, entry.TvEntry.Path))
+		}
 		if entry.IsPromEnabled() {
-			entry.LoggerEntry.Info(fmt.Sprintf("PromEntry: %s://localhost:%d%s", scheme, entry.Port, entry.PromEntry.Path))
+			promScheme, promPort := "http", entry.Port
+			if entry.isPromTlsEnabled() {
+				promScheme = "https"
+			}
+			if entry.promPort > 0 {
+				promPort = entry.promPort
+			}
+			entry.LoggerEntry.Info(fmt.Sprintf("PromEntry: %s://localhost:%d%s", promScheme, promPort, entry.PromEntry.Path))
 		}
 		if entry.IsStaticFileHandlerEnabled() {
 			entry.LoggerEntry.Info(fmt.Sprintf("StaticFileHandlerEntry: %s://localhost:%d%s", scheme, entry.Port, entry.StaticFileEntry.Path))
@@ -439,6 +772,7 @@ func (entry *GinEntry) Bootstrap(ctx context.Context) {
 				fmt.Sprintf("%s://localhost:%d%s", scheme, entry.Port, entry.CommonServiceEntry.ReadyPath),
 				fmt.Sprintf("%s://localhost:%d%s", scheme, entry.Port, entry.CommonServiceEntry.AlivePath),
 				fmt.Sprintf("%s://localhost:%d%s", scheme, entry.Port, entry.CommonServiceEntry.InfoPath),
+				fmt.Sprintf("%s://localhost:%d%s", scheme, entry.Port, entry.ApisPath()),
 			}
 
 			entry.LoggerEntry.Info(fmt.Sprintf("CommonSreviceEntry: %s", strings.Join(handlers, ", ")))
@@ -446,14 +780,124 @@ func (entry *GinEntry) Bootstrap(ctx context.Context) {
 		if entry.IsPProfEnabled() {
 			entry.LoggerEntry.Info(fmt.Sprintf("PProfEntry: %s://localhost:%d%s", scheme, entry.Port, entry.PProfEntry.Path))
 		}
+		if entry.IsExpvarEnabled() {
+			entry.LoggerEntry.Info(fmt.Sprintf("Expvar: %s://localhost:%d%s", scheme, entry.Port, entry.ExpvarPath()))
+		}
 		entry.EventEntry.Finish(event)
 	})
+
+	atomic.StoreInt32(&entry.ready, 1)
+
+	return nil
+}
+
+// registerRoutes registers routes declared in boot config and paths of sub entries into Router.
+//
+// Routes are registered only once, so that BootstrapWithError could be retried after failure like address in use.
+func (entry *GinEntry) registerRoutes() error {
+	if atomic.LoadInt32(&entry.routesRegistered) == 1 {
+		return nil
+	}
+
+	// Register routes declared in boot config
+	if err := entry.AddRoutes(entry.routes...); err != nil {
+		return err
+	}
+
+	// Is common service enabled?
+	if entry.IsCommonServiceEnabled() {
+		// Register common service path into Router, readiness and liveness are not restricted since probed by orchestrators.
+		auth := entry.commonServiceAccessHandlers()
+		entry.Router.GET(entry.CommonServiceEntry.ReadyPath, entry.ReadyHandler)
+		entry.Router.GET(entry.CommonServiceEntry.AlivePath, gin.WrapF(entry.CommonServiceEntry.Alive))
+		entry.Router.GET(entry.CommonServiceEntry.GcPath, append(auth, entry.GcHandler)...)
+		entry.Router.GET(entry.CommonServiceEntry.InfoPath, append(auth, gin.WrapF(entry.CommonServiceEntry.Info))...)
+		entry.Router.GET(entry.ApisPath(), append(auth, entry.ApisHandler)...)
+		entry.Router.GET(entry.MaintenancePath(), append(auth, entry.MaintenanceHandler)...)
+		entry.Router.GET(entry.MiddlewarePath(), append(auth, entry.MiddlewareHandler)...)
+		entry.Router.GET(path.Join(entry.MiddlewarePath(), ":name"), append(auth, entry.MiddlewareHandler)...)
+		// maintenance mode and middlewares like auth could be switched with PUT, only allowed with credentials
+		if entry.isCommonServiceAuthEnabled() {
+			entry.Router.PUT(entry.MaintenancePath(), append(auth, entry.MaintenanceHandler)...)
+			entry.Router.PUT(path.Join(entry.MiddlewarePath(), ":name"), append(auth, entry.MiddlewareHandler)...)
+		}
+		entry.Router.GET(entry.OpenApiPath()+".json", append(auth, entry.OpenApiHandler)...)
+		entry.Router.GET(entry.OpenApiPath()+".yaml", append(auth, entry.OpenApiHandler)...)
+		entry.Router.GET(entry.HealthyPath(), append(auth, entry.HealthyHandler)...)
+		entry.Router.GET(entry.CertsPath(), append(auth, entry.CertsHandler)...)
+		entry.Router.GET(entry.SysPath(), append(auth, entry.SysHandler)...)
+		entry.Router.GET(entry.ReqPath(), append(auth, entry.ReqHandler)...)
+		entry.Router.GET(entry.EntriesPath(), append(auth, entry.EntriesHandler)...)
+		entry.Router.GET(entry.DepsPath(), append(auth, entry.DepsHandler)...)
+		entry.Router.GET(entry.GitPath(), append(auth, entry.GitHandler)...)
+		entry.Router.GET(entry.ProfilePath(), append(auth, entry.ProfileHandler)...)
+
+		// Is tv enabled?
+		if entry.IsTvEnabled() {
+			entry.Router.GET(path.Join(entry.TvEntry.Path, "*any"), append(auth, gin.WrapF(entry.TvEntry.ConfigFileHandler()))...)
+		}
+	}
+
+	// Is swagger enabled?
+	if entry.IsSwEnabled() {
+		entry.Router.GET(path.Join(entry.SwEntry.Path, "*any"), append(entry.swAccessHandlers(), entry.swHandler())...)
+	}
+
+	// Is docs enabled?
+	if entry.IsDocsEnabled() {
+		entry.Router.GET(path.Join(entry.DocsEntry.Path, "*any"), gin.WrapF(entry.DocsEntry.ConfigFileHandler()))
+	}
+
+	// Is redoc enabled?
+	if entry.IsRedocEnabled() {
+		entry.Router.GET(path.Join(entry.RedocEntry.Path, "*any"), gin.WrapF(entry.RedocEntry.ConfigFileHandler()))
+	}
+
+	// Is rapidoc enabled?
+	if entry.IsRapiDocEnabled() {
+		entry.Router.GET(path.Join(entry.RapiDocEntry.Path, "*any"), gin.WrapF(entry.RapiDocEntry.ConfigFileHandler()))
+	}
+
+	// Is static file handler enabled?
+	if entry.IsStaticFileHandlerEnabled() {
+		if entry.staticFS != nil {
+			entry.Router.GET(path.Join(entry.StaticFileEntry.Path, "*any"), entry.staticHandler())
+		} else {
+			entry.Router.GET(path.Join(entry.StaticFileEntry.Path, "*any"), gin.WrapF(entry.StaticFileEntry.GetFileHandler()))
+		}
+	}
+
+	// Register prom path into Router unless served on admin port
+	if entry.IsPromEnabled() && entry.promPort < 1 {
+		entry.Router.GET(entry.PromEntry.Path, entry.promHandlers()...)
+	}
+
+	// Is pprof enabled?
+	if entry.IsPProfEnabled() {
+		pprof.Register(entry.Router, entry.PProfEntry.Path)
+	}
+
+	// Is expvar enabled?
+	if entry.IsExpvarEnabled() {
+		publishExpvar()
+		entry.Router.GET(entry.ExpvarPath(), append(entry.commonServiceAccessHandlers(), gin.WrapH(expvar.Handler()))...)
+	}
+
+	atomic.StoreInt32(&entry.routesRegistered, 1)
+
+	return nil
 }
 
 // Interrupt GinEntry.
 func (entry *GinEntry) Interrupt(ctx context.Context) {
 	event, logger := entry.logBasicInfo("Interrupt", ctx)
 
+	atomic.StoreInt32(&entry.ready, 0)
+	defer atomic.StoreInt32(&entry.bootstrapStarted, 0)
+
+	// Stop listening on signals
+	entry.stopSignalListener()
+
 	if entry.IsStaticFileHandlerEnabled() {
 		// Interrupt entry
 		entry.StaticFileEntry.Interrupt(ctx)
@@ -461,6 +905,7 @@ func (entry *GinEntry) Interrupt(ctx context.Context) {
 
 	if entry.IsSwEnabled() {
 		// Interrupt swagger entry
+		entry.stopSwWatcher()
 		entry.SwEntry.Interrupt(ctx)
 	}
 
@@ -469,6 +914,14 @@ func (entry *GinEntry) Interrupt(ctx context.Context) {
 		entry.PromEntry.Interrupt(ctx)
 	}
 
+	if entry.promServer != nil {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		if err := entry.promServer.Shutdown(ctx); err != nil {
+			logger.Warn("Error occurs while stopping metrics server.", zap.Error(err))
+		}
+		cancel()
+	}
+
 	if entry.IsCommonServiceEnabled() {
 		// Interrupt common service entry
 		entry.CommonServiceEntry.Interrupt(ctx)
@@ -478,10 +931,26 @@ func (entry *GinEntry) Interrupt(ctx context.Context) {
 		entry.DocsEntry.Interrupt(ctx)
 	}
 
+	if entry.IsRedocEnabled() {
+		entry.RedocEntry.Interrupt(ctx)
+	}
+
+	if entry.IsRapiDocEnabled() {
+		entry.RapiDocEntry.Interrupt(ctx)
+	}
+
+	if entry.IsTvEnabled() {
+		entry.TvEntry.Interrupt(ctx)
+	}
+
 	if entry.IsPProfEnabled() {
 		entry.PProfEntry.Interrupt(ctx)
 	}
 
+	if entry.IsGopsEnabled() {
+		stopGopsAgent(entry.entryName)
+	}
+
 	if entry.Router != nil && entry.Server != nil {
 		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		defer cancel()
@@ -492,9 +961,72 @@ func (entry *GinEntry) Interrupt(ctx context.Context) {
 		}
 	}
 
+	// Flush spans of tracing middlewares and metrics of prom middlewares after server stopped accepting requests
+	entry.flushTraces(ctx, logger)
+	entry.shutdownMeterProviders(ctx, logger)
+
+	// Run shutdown hooks after server stopped accepting requests
+	entry.runShutdownHooks(ctx, event, logger)
+
 	entry.EventEntry.Finish(event)
 
-	rkentry.GlobalAppCtx.RemoveEntry(entry)
+	// Unregister metrics of prom middleware, so entry with same name could be registered again
+	entry.unregister()
+}
+
+// unregister removes GinEntry and its groups from rkentry.GlobalAppCtx with metrics of their prom middlewares,
+// tracer providers of their tracing middlewares are removed without flushing if not shut down already.
+//
+// Names taken over by another GinEntry registered while reloading are left to it.
+func (entry *GinEntry) unregister() {
+	entry.releaseHooks()
+
+	for i := range entry.groups {
+		rkginprom.Unregister(entry.groups[i].metricsSet)
+		if entry.groups[i].isRegistered() {
+			rkgintrace.Deregister(entry.groups[i].entryName)
+			rkentry.GlobalAppCtx.RemoveEntry(entry.groups[i])
+		}
+	}
+
+	rkginprom.Unregister(entry.metricsSet)
+	if entry.isRegistered() {
+		rkgintrace.Deregister(entry.entryName)
+		rkentry.GlobalAppCtx.RemoveEntry(entry)
+	}
+}
+
+// register adds GinEntry and its groups back into rkentry.GlobalAppCtx, used if GinEntry reloaded with the same
+// name failed to register.
+func (entry *GinEntry) register() {
+	rkentry.GlobalAppCtx.AddEntry(entry)
+	for i := range entry.groups {
+		rkentry.GlobalAppCtx.AddEntry(entry.groups[i])
+	}
+}
+
+// commitHooks registers pending shutdown hooks of middlewares of GinEntry in entries,
+// which closes resources of previous GinEntry with the same name.
+func commitHooks(entries map[string]rkentry.Entry) {
+	for _, v := range entries {
+		if entry, ok := v.(*GinEntry); ok && entry.pendingHooks != nil {
+			entry.pendingHooks.commit()
+			entry.pendingHooks = nil
+		}
+	}
+}
+
+// releaseHooks closes resources of middlewares whose shutdown hooks are not committed yet.
+func (entry *GinEntry) releaseHooks() {
+	if entry.pendingHooks != nil {
+		entry.pendingHooks.release()
+		entry.pendingHooks = nil
+	}
+}
+
+// isRegistered returns true if entry is the one registered in rkentry.GlobalAppCtx with its name.
+func (entry *GinEntry) isRegistered() bool {
+	return rkentry.GlobalAppCtx.GetEntry(GinEntryType, entry.entryName) == rkentry.Entry(entry)
 }
 
 // String Stringfy gin entry.
@@ -503,6 +1035,21 @@ func (entry *GinEntry) String() string {
 	return string(bytes)
 }
 
+// isBootstrapStarted returns true once bootstrap of GinEntry started and before it failed or GinEntry was interrupted.
+func (entry *GinEntry) isBootstrapStarted() bool {
+	return atomic.LoadInt32(&entry.bootstrapStarted) == 1
+}
+
+// IsReady returns true once GinEntry bootstrapped successfully and before it was interrupted.
+func (entry *GinEntry) IsReady() bool {
+	return atomic.LoadInt32(&entry.ready) == 1
+}
+
+// Errors returns channel of errors occur while serving after bootstrap.
+func (entry *GinEntry) Errors() <-chan error {
+	return entry.errCh
+}
+
 // SetReadinessCheck set readiness check into rkentry.GlobalAppCtx
 func (entry *GinEntry) SetReadinessCheck(f rkentry.ReadinessCheck) {
 	rkentry.GlobalAppCtx.SetReadinessCheck(f)
@@ -524,6 +1071,9 @@ func (entry *GinEntry) MarshalJSON() ([]byte, error) {
 		"port":                   entry.Port,
 		"swEntry":                entry.SwEntry,
 		"docsEntry":              entry.DocsEntry,
+		"redocEntry":             entry.RedocEntry,
+		"rapiDocEntry":           entry.RapiDocEntry,
+		"tvEntry":                entry.TvEntry,
 		"commonServiceEntry":     entry.CommonServiceEntry,
 		"promEntry":              entry.PromEntry,
 		"staticFileHandlerEntry": entry.StaticFileEntry,
@@ -571,6 +1121,21 @@ func (entry *GinEntry) IsDocsEnabled() bool {
 	return entry.DocsEntry != nil
 }
 
+// IsRedocEnabled Is redoc entry enabled?
+func (entry *GinEntry) IsRedocEnabled() bool {
+	return entry.RedocEntry != nil
+}
+
+// IsRapiDocEnabled Is rapidoc entry enabled?
+func (entry *GinEntry) IsRapiDocEnabled() bool {
+	return entry.RapiDocEntry != nil
+}
+
+// IsTvEnabled Is tv entry enabled? Common service is required since TV is rendered from common service APIs.
+func (entry *GinEntry) IsTvEnabled() bool {
+	return entry.TvEntry != nil && entry.IsCommonServiceEnabled()
+}
+
 // IsStaticFileHandlerEnabled Is static file handler entry enabled?
 func (entry *GinEntry) IsStaticFileHandlerEnabled() bool {
 	return entry.StaticFileEntry != nil
@@ -641,11 +1206,36 @@ func (entry *GinEntry) logBasicInfo(operation string, ctx context.Context) (rkqu
 			zap.String("docsPath", entry.DocsEntry.Path))
 	}
 
+	// add RedocEntry info
+	if entry.IsRedocEnabled() {
+		event.AddPayloads(
+			zap.Bool("redocEnabled", true),
+			zap.String("redocPath", entry.RedocEntry.Path))
+	}
+
+	// add RapiDocEntry info
+	if entry.IsRapiDocEnabled() {
+		event.AddPayloads(
+			zap.Bool("rapiDocEnabled", true),
+			zap.String("rapiDocPath", entry.RapiDocEntry.Path))
+	}
+
+	// add TvEntry info
+	if entry.IsTvEnabled() {
+		event.AddPayloads(
+			zap.Bool("tvEnabled", true),
+			zap.String("tvPath", entry.TvEntry.Path))
+	}
+
 	// add PromEntry info
 	if entry.IsPromEnabled() {
+		promPort := entry.Port
+		if entry.promPort > 0 {
+			promPort = entry.promPort
+		}
 		event.AddPayloads(
 			zap.Bool("promEnabled", true),
-			zap.Uint64("promPort", entry.Port),
+			zap.Uint64("promPort", promPort),
 			zap.String("promPath", entry.PromEntry.Path))
 	}
 
@@ -663,6 +1253,31 @@ func (entry *GinEntry) logBasicInfo(operation string, ctx context.Context) (rkqu
 			zap.String("pprofPath", entry.PProfEntry.Path))
 	}
 
+	// add expvar info
+	if entry.IsExpvarEnabled() {
+		event.AddPayloads(
+			zap.Bool("expvarEnabled", true),
+			zap.String("expvarPath", entry.ExpvarPath()))
+	}
+
+	// add gops info
+	if entry.IsGopsEnabled() {
+		event.AddPayloads(
+			zap.Bool("gopsEnabled", true))
+	}
+
+	// add dependency info
+	if len(entry.dependsOn) > 0 {
+		event.AddPayloads(
+			zap.Strings("dependsOn", entry.dependsOn))
+	}
+
+	// add signal info
+	if entry.IsSignalEnabled() {
+		event.AddPayloads(
+			zap.Bool("signalEnabled", true))
+	}
+
 	// add tls info
 	if entry.IsTlsEnabled() {
 		event.AddPayloads(
@@ -674,29 +1289,61 @@ func (entry *GinEntry) logBasicInfo(operation string, ctx context.Context) (rkqu
 	return event, logger
 }
 
-// Start server
+// stopBootstrapped stops metrics server, swagger watcher and gops agent started by BootstrapWithError.
+func (entry *GinEntry) stopBootstrapped(ctx context.Context, logger *zap.Logger) {
+	entry.stopSwWatcher()
+
+	if entry.IsGopsEnabled() {
+		stopGopsAgent(entry.entryName)
+	}
+
+	if entry.promServer != nil {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		if err := entry.promServer.Shutdown(ctx); err != nil {
+			logger.Warn("Error occurs while stopping metrics server.", zap.Error(err))
+		}
+		cancel()
+		entry.promServer = nil
+	}
+}
+
+// recoverShutdownError converts panic of rkentry.ShutdownWithError, which is called by rk-entry with invalid config,
+// into err. Runtime errors and panics other than error are raised again.
+func recoverShutdownError(err *error) {
+	recv := recover()
+	if recv == nil {
+		return
+	}
+
+	if e, ok := recv.(error); ok {
+		if _, isRuntime := e.(runtime.Error); !isRuntime {
+			*err = e
+			return
+		}
+	}
+
+	panic(recv)
+}
+
+// Start server with bound listener, errors will be sent to channel returned by Errors().
 // We move the code here for testability
-func (entry *GinEntry) startServer(event rkquery.Event, logger *zap.Logger) {
-	if entry.Server != nil {
-		// If TLS was enabled, we need to load server certificate and key and start http server with ListenAndServeTLS()
-		if entry.IsTlsEnabled() {
-			entry.Server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{*entry.CertEntry.Certificate}}
-
-			if err := entry.Server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
-				logger.Error("Error occurs while serving gin-listener-tls.", event.ListPayloads()...)
-				entry.bootstrapLogOnce.Do(func() {
-					entry.EventEntry.FinishWithCond(event, false)
-				})
-				rkentry.ShutdownWithError(err)
-			}
-		} else {
-			if err := entry.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-				logger.Error("Error occurs while serving gin-listener.", event.ListPayloads()...)
-				entry.bootstrapLogOnce.Do(func() {
-					entry.EventEntry.FinishWithCond(event, false)
-				})
-				rkentry.ShutdownWithError(err)
-			}
+func (entry *GinEntry) startServer(listener net.Listener, event rkquery.Event, logger *zap.Logger) {
+	var err error
+	// If TLS was enabled, we need to load server certificate and key and start http server with ServeTLS()
+	if entry.IsTlsEnabled() {
+		entry.Server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{*entry.CertEntry.Certificate}}
+		err = entry.Server.ServeTLS(listener, "", "")
+	} else {
+		err = entry.Server.Serve(listener)
+	}
+
+	if err != nil && err != http.ErrServerClosed {
+		logger.Error("Error occurs while serving gin-listener.", zap.Error(err))
+
+		// drop error if nobody is reading and channel is full
+		select {
+		case entry.errCh <- err:
+		default:
 		}
 	}
 }
@@ -759,6 +1406,27 @@ func WithDocsEntry(docs *rkentry.DocsEntry) GinEntryOption {
 	}
 }
 
+// WithRedocEntry provide RedocEntry.
+func WithRedocEntry(redoc *RedocEntry) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.RedocEntry = redoc
+	}
+}
+
+// WithRapiDocEntry provide RapiDocEntry.
+func WithRapiDocEntry(rapiDoc *RapiDocEntry) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.RapiDocEntry = rapiDoc
+	}
+}
+
+// WithTvEntry provide TvEntry.
+func WithTvEntry(tv *TvEntry) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.TvEntry = tv
+	}
+}
+
 func WithPProfEntry(p *rkentry.PProfEntry) GinEntryOption {
 	return func(entry *GinEntry) {
 		entry.PProfEntry = p
@@ -10,22 +10,17 @@ package rkgin
 
 import (
 	"context"
-	"crypto/rand"
-	"crypto/rsa"
 	"crypto/tls"
-	"crypto/x509"
-	"crypto/x509/pkix"
-	"encoding/pem"
-	"github.com/gin-gonic/gin"
+	"encoding/json"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rookie-ninja/rk-entry/v2/entry"
 	"github.com/rookie-ninja/rk-gin/v2/middleware/meta"
 	"github.com/stretchr/testify/assert"
-	"math/big"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
-	"strconv"
 	"testing"
-	"time"
 )
 
 const (
@@ -179,8 +174,10 @@ func TestGinEntry_Bootstrap(t *testing.T) {
 	entry.Bootstrap(context.TODO())
 	validateServerIsUp(t, 8080, entry.IsTlsEnabled())
 	assert.Empty(t, entry.Router.Routes())
+	assert.True(t, entry.IsReady())
 
 	entry.Interrupt(context.TODO())
+	assert.False(t, entry.IsReady())
 
 	// with enable sw, static, prom, common, tv, tls
 	entry = RegisterGinEntry(
@@ -211,7 +208,32 @@ func TestGinEntry_Bootstrap(t *testing.T) {
 	entry.Interrupt(context.TODO())
 }
 
-func TestGinEntry_startServer_TlsServerFail(t *testing.T) {
+func TestGinEntry_Bootstrap_WithInfo(t *testing.T) {
+	assert.Nil(t, os.Setenv("REGION", "ut-region"))
+	defer os.Unsetenv("REGION")
+
+	entry := RegisterGinEntry(
+		WithName("ut-info"),
+		WithPort(0),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{
+			Enabled: true,
+		})))
+	entry.Bootstrap(context.TODO())
+	defer entry.Interrupt(context.TODO())
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rk/v1/info", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	info := &rkentry.ProcessInfo{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), info))
+	assert.Equal(t, rkentry.GlobalAppCtx.GetAppInfoEntry().AppName, info.AppName)
+	assert.Equal(t, rkentry.GlobalAppCtx.GetAppInfoEntry().Version, info.Version)
+	assert.NotEmpty(t, info.StartTime)
+	assert.Equal(t, "ut-region", info.Region)
+}
+
+func TestGinEntry_Bootstrap_TlsServerFail(t *testing.T) {
 	defer assertPanic(t)
 
 	certEntry := rkentry.RegisterCertEntry(&rkentry.BootCert{
@@ -229,119 +251,228 @@ func TestGinEntry_startServer_TlsServerFail(t *testing.T) {
 		WithPort(808080),
 		WithCertEntry(certEntry))
 
-	event := rkentry.EventEntryNoop.EventFactory.CreateEventNoop()
-	logger := rkentry.LoggerEntryNoop.Logger
-
-	entry.startServer(event, logger)
+	entry.Bootstrap(context.TODO())
 }
 
-func TestGinEntry_startServer_ServerFail(t *testing.T) {
+func TestGinEntry_Bootstrap_ServerFail(t *testing.T) {
 	defer assertPanic(t)
 
 	// let's give an invalid port
 	entry := RegisterGinEntry(
 		WithPort(808080))
 
-	event := rkentry.EventEntryNoop.EventFactory.CreateEventNoop()
-	logger := rkentry.LoggerEntryNoop.Logger
-
-	entry.startServer(event, logger)
+	entry.Bootstrap(context.TODO())
 }
 
-func TestRegisterGinEntriesWithConfig(t *testing.T) {
-	assertNotPanic(t)
+func TestGinEntry_BootstrapWithError(t *testing.T) {
+	defer assertNotPanic(t)
 
-	// write config file in unit test temp directory
-	entries := RegisterGinEntryYAML([]byte(defaultBootConfigStr))
-	assert.NotNil(t, entries)
-	assert.Len(t, entries, 2)
+	// invalid port
+	entry := RegisterGinEntry(
+		WithPort(808080))
+	assert.NotNil(t, entry.BootstrapWithError(context.TODO()))
+	rkentry.GlobalAppCtx.RemoveEntry(entry)
 
-	// validate entry element based on boot.yaml config defined in defaultBootConfigStr
-	greeter := entries["greeter"].(*GinEntry)
-	assert.NotNil(t, greeter)
+	// port already in use
+	entry = RegisterGinEntry(WithPort(8080))
+	assert.Nil(t, entry.BootstrapWithError(context.TODO()))
+	validateServerIsUp(t, 8080, entry.IsTlsEnabled())
 
-	greeter2 := entries["greeter2"].(*GinEntry)
-	assert.NotNil(t, greeter2)
+	another := RegisterGinEntry(WithName("ut-another"), WithPort(8080))
+	assert.NotNil(t, another.BootstrapWithError(context.TODO()))
+	rkentry.GlobalAppCtx.RemoveEntry(another)
 
-	greeter3 := entries["greeter3"]
-	assert.Nil(t, greeter3)
-}
+	entry.Interrupt(context.TODO())
 
-func generateCerts() ([]byte, []byte) {
-	// Create certs and return as []byte
-	ca := &x509.Certificate{
-		Subject: pkix.Name{
-			Organization: []string{"Fake cert."},
-		},
-		SerialNumber:          big.NewInt(42),
-		NotAfter:              time.Now().Add(2 * time.Hour),
-		IsCA:                  true,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
-		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
-		BasicConstraintsValid: true,
+	// no errors while serving after interrupt
+	select {
+	case err := <-entry.Errors():
+		assert.Nil(t, err)
+	default:
 	}
+}
 
-	// Create a Private Key
-	key, _ := rsa.GenerateKey(rand.Reader, 4096)
+func TestGinEntry_BootstrapWithError_StopsPromServer(t *testing.T) {
+	defer assertNotPanic(t)
 
-	// Use CA Cert to sign a CSR and create a Public Cert
-	csr := &key.PublicKey
-	cert, _ := x509.CreateCertificate(rand.Reader, ca, ca, csr, key)
+	// gin port is in use after metrics server started
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
 
-	// Convert keys into pem.Block
-	c := &pem.Block{Type: "CERTIFICATE", Bytes: cert}
-	k := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	promListener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	promPort := uint64(promListener.Addr().(*net.TCPAddr).Port)
+	assert.Nil(t, promListener.Close())
 
-	return pem.EncodeToMemory(c), pem.EncodeToMemory(k)
+	entry := RegisterGinEntry(
+		WithName("ut-bootstrap-fail-prom"),
+		WithPromEntry(rkentry.RegisterPromEntry(&rkentry.BootProm{Enabled: true},
+			rkentry.WithRegistryPromEntry(prometheus.NewRegistry()))),
+		WithPromPort(promPort))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	entry.Server.Addr = listener.Addr().String()
+
+	assert.NotNil(t, entry.BootstrapWithError(context.TODO()))
+	assert.Nil(t, entry.promServer)
 }
 
-func validateServerIsUp(t *testing.T, port uint64, isTls bool) {
-	// sleep for 2 seconds waiting server startup
-	time.Sleep(2 * time.Second)
+func TestGinEntry_BootstrapWithError_Retry(t *testing.T) {
+	defer assertNotPanic(t)
+
+	// occupy port of gin server
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
 
-	if !isTls {
-		conn, err := net.DialTimeout("tcp", net.JoinHostPort("0.0.0.0", strconv.FormatUint(port, 10)), time.Second)
-		assert.Nil(t, err)
-		assert.NotNil(t, conn)
-		if conn != nil {
-			assert.Nil(t, conn.Close())
+	entry := RegisterGinEntry(
+		WithName("ut-bootstrap-retry"),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{Enabled: true})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	entry.Server.Addr = listener.Addr().String()
+
+	assert.NotNil(t, entry.BootstrapWithError(context.TODO()))
+	routes := len(entry.Router.Routes())
+	assert.NotZero(t, routes)
+
+	// bootstrapped after port is freed without registering routes again
+	assert.Nil(t, listener.Close())
+	assert.Nil(t, entry.BootstrapWithError(context.TODO()))
+	assert.True(t, entry.IsReady())
+	assert.Len(t, entry.Router.Routes(), routes)
+
+	resp := httptest.NewRecorder()
+	entry.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, entry.CommonServiceEntry.ReadyPath, nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	entry.Interrupt(context.TODO())
+}
+
+func TestGinEntry_LogBasicInfo_WithPromPort(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-log-prom-port"),
+		WithPort(8080),
+		WithPromEntry(rkentry.RegisterPromEntry(&rkentry.BootProm{Enabled: true},
+			rkentry.WithRegistryPromEntry(prometheus.NewRegistry()))),
+		WithPromPort(9090))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	event, _ := entry.logBasicInfo("ut", context.TODO())
+	for _, field := range event.ListPayloads() {
+		if field.Key == "promPort" {
+			assert.Equal(t, int64(9090), field.Integer)
+			return
 		}
-		return
 	}
+	assert.Fail(t, "promPort not logged")
+}
 
-	tlsConf := &tls.Config{
-		InsecureSkipVerify: true,
-	}
+func TestRegisterGinEntryYAMLWithError_Rollback(t *testing.T) {
+	defer assertNotPanic(t)
 
-	tlsConn, err := tls.Dial("tcp", net.JoinHostPort("0.0.0.0", strconv.FormatUint(port, 10)), tlsConf)
-	assert.Nil(t, err)
-	assert.NotNil(t, tlsConn)
-	if tlsConn != nil {
-		assert.Nil(t, tlsConn.Close())
-	}
+	bootStr := `
+gin:
+  - name: ut-rollback-valid
+    port: 1949
+    enabled: true
+    middleware:
+      prom:
+        enabled: true
+  - name: ut-rollback-invalid
+    port: 1950
+    enabled: true
+    engine:
+      trustedProxies: ["invalid"]
+`
+	entries, err := RegisterGinEntryYAMLWithError([]byte(bootStr))
+	assert.NotNil(t, err)
+	assert.Nil(t, entries)
+	assert.Nil(t, GetGinEntry("ut-rollback-valid"))
+	assert.Nil(t, GetGinEntry("ut-rollback-invalid"))
+
+	// invalid middleware of entry
+	entries, err = RegisterGinEntryYAMLWithError([]byte(`
+gin:
+  - name: ut-rollback-logging
+    port: 1951
+    enabled: true
+    middleware:
+      logging:
+        enabled: true
+        format: bogus
+`))
+	assert.NotNil(t, err)
+	assert.Nil(t, entries)
+	assert.Nil(t, GetGinEntry("ut-rollback-logging"))
 }
 
-func assertNotPanic(t *testing.T) {
-	if r := recover(); r != nil {
-		// Expect panic to be called with non nil error
-		assert.True(t, false)
-	} else {
-		// This should never be called in case of a bug
-		assert.True(t, true)
+func TestRegisterGinEntryYAMLWithError(t *testing.T) {
+	defer assertNotPanic(t)
+
+	// invalid yaml
+	entries, err := RegisterGinEntryYAMLWithError([]byte("gin: [invalid"))
+	assert.NotNil(t, err)
+	assert.Nil(t, entries)
+
+	// happy case
+	entries, err = RegisterGinEntryYAMLWithError([]byte(defaultBootConfigStr))
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	for _, v := range entries {
+		rkentry.GlobalAppCtx.RemoveEntry(v)
 	}
 }
 
-func assertPanic(t *testing.T) {
-	if r := recover(); r != nil {
-		// Expect panic to be called with non nil error
-		assert.True(t, true)
-	} else {
-		// This should never be called in case of a bug
-		assert.True(t, false)
-	}
+func TestRegisterGinEntriesWithConfig(t *testing.T) {
+	assertNotPanic(t)
+
+	// write config file in unit test temp directory
+	entries := RegisterGinEntryYAML([]byte(defaultBootConfigStr))
+	assert.NotNil(t, entries)
+	assert.Len(t, entries, 2)
+
+	// validate entry element based on boot.yaml config defined in defaultBootConfigStr
+	greeter := entries["greeter"].(*GinEntry)
+	assert.NotNil(t, greeter)
+
+	greeter2 := entries["greeter2"].(*GinEntry)
+	assert.NotNil(t, greeter2)
+
+	greeter3 := entries["greeter3"]
+	assert.Nil(t, greeter3)
 }
 
-func TestMain(m *testing.M) {
-	gin.SetMode(gin.ReleaseMode)
-	os.Exit(m.Run())
+func TestRegisterGinEntriesWithBootConfig(t *testing.T) {
+	// nil config
+	assert.Empty(t, RegisterGinEntriesWithBootConfig(nil))
+
+	entries := RegisterGinEntriesWithBootConfig(&BootConfig{
+		Gin: []*BootGinElement{
+			nil,
+			{
+				Enabled: true,
+				Name:    "ut-typed",
+				Port:    1949,
+				CommonService: BootCommonService{
+					BootCommonService: rkentry.BootCommonService{
+						Enabled: true,
+					},
+				},
+				Routes: []*BootRoute{
+					{Path: "/ut", Body: "ut"},
+				},
+			},
+			{
+				Enabled: false,
+				Name:    "ut-typed-disabled",
+			},
+		},
+	})
+	assert.Len(t, entries, 1)
+
+	entry := entries["ut-typed"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, uint64(1949), entry.Port)
+	assert.True(t, entry.IsCommonServiceEnabled())
+	assert.Len(t, entry.routes, 1)
 }
@@ -0,0 +1,189 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/prom"
+	"path"
+	"regexp"
+)
+
+const (
+	// GinGroupEntryType type of entry
+	GinGroupEntryType = "GinGroupEntry"
+)
+
+var invalidMetricsPrefixChars = regexp.MustCompile("[^a-zA-Z0-9_]")
+
+// BootGinGroup boot config of router group mounted under GinEntry.
+//
+// Middlewares of group run after middlewares of GinEntry, metrics of group will be prefixed with group name.
+type BootGinGroup struct {
+	Name        string         `yaml:"name" json:"name"`
+	Description string         `yaml:"description" json:"description"`
+	Prefix      string         `yaml:"prefix" json:"prefix"`
+	Locale      string         `yaml:"locale" json:"locale"`
+	Middleware  BootMiddleware `yaml:"middleware" json:"middleware"`
+}
+
+// GinGroupEntry implements rkentry.Entry interface.
+//
+// GinGroupEntry is a router group of GinEntry with its own middlewares,
+// so that APIs with different policies could be served with same port.
+type GinGroupEntry struct {
+	entryName        string                `json:"-" yaml:"-"`
+	entryType        string                `json:"-" yaml:"-"`
+	entryDescription string                `json:"-" yaml:"-"`
+	Prefix           string                `json:"-" yaml:"-"`
+	Group            *gin.RouterGroup      `json:"-" yaml:"-"`
+	parent           *GinEntry             `json:"-" yaml:"-"`
+	metricsSet       *rkmidprom.MetricsSet `json:"-" yaml:"-"`
+}
+
+// AddGroup creates GinGroupEntry mounted at prefix with middlewares and register it into rkentry.GlobalAppCtx.
+//
+// Middlewares added by AddMiddleware() after this call will not apply to the group,
+// so this function should be called after AddMiddleware() and before Bootstrap().
+func (entry *GinEntry) AddGroup(name, prefix string, mids ...gin.HandlerFunc) *GinGroupEntry {
+	prefix = path.Join("/", prefix)
+
+	group := &GinGroupEntry{
+		entryName:        name,
+		entryType:        GinGroupEntryType,
+		entryDescription: "Internal RK entry which mounts router group under GinEntry.",
+		Prefix:           prefix,
+		Group:            entry.Router.Group(prefix, mids...),
+		parent:           entry,
+	}
+
+	if len(group.entryName) < 1 {
+		group.entryName = entry.entryName + "-" + prefix
+	}
+
+	entry.groups = append(entry.groups, group)
+	rkentry.GlobalAppCtx.AddEntry(group)
+
+	return group
+}
+
+// GetGroup returns GinGroupEntry added with AddGroup, nil if not exist.
+func (entry *GinEntry) GetGroup(name string) *GinGroupEntry {
+	for i := range entry.groups {
+		if entry.groups[i].entryName == name {
+			return entry.groups[i]
+		}
+	}
+
+	return nil
+}
+
+// ListGroups returns all GinGroupEntry added with AddGroup.
+func (entry *GinEntry) ListGroups() []*GinGroupEntry {
+	return entry.groups
+}
+
+// addGroupFromConfig creates GinGroupEntry with middlewares built from boot config.
+func (entry *GinEntry) addGroupFromConfig(config *BootGinGroup, promRegistry *prometheus.Registry,
+	hooks *pendingHooks) (*GinGroupEntry, error) {
+	metricsPrefix := invalidMetricsPrefixChars.ReplaceAllString(config.Name, "_") + "_"
+	metricsSet, err := config.Middleware.Prom.newMetricsSet(prometheus.WrapRegistererWithPrefix(metricsPrefix, promRegistry))
+	if err != nil {
+		return nil, err
+	}
+
+	meterProvider, err := entry.newMeterProvider(&config.Middleware.Prom, config.Name)
+	if err != nil {
+		rkginprom.Unregister(metricsSet)
+		return nil, err
+	}
+
+	mids, err := newMiddlewareChain(&config.Middleware, config.Name, entry.LoggerEntry, entry.EventEntry,
+		metricsSet, meterProvider, hooks, entry.panicRecoveryHandler(), entry.eventEnricherExtension())
+	if err != nil {
+		rkginprom.Unregister(metricsSet)
+		return nil, err
+	}
+
+	group := entry.AddGroup(config.Name, config.Prefix, mids...)
+	group.metricsSet = metricsSet
+	if len(config.Description) > 0 {
+		group.entryDescription = config.Description
+	}
+
+	return group, nil
+}
+
+// isRegistered returns true if group is the one registered in rkentry.GlobalAppCtx with its name.
+func (group *GinGroupEntry) isRegistered() bool {
+	return rkentry.GlobalAppCtx.GetEntry(GinGroupEntryType, group.entryName) == rkentry.Entry(group)
+}
+
+// GetName Get entry name.
+func (group *GinGroupEntry) GetName() string {
+	return group.entryName
+}
+
+// GetType Get entry type.
+func (group *GinGroupEntry) GetType() string {
+	return group.entryType
+}
+
+// GetDescription Get description of entry.
+func (group *GinGroupEntry) GetDescription() string {
+	return group.entryDescription
+}
+
+// GetGinEntry returns GinEntry which group mounted on.
+func (group *GinGroupEntry) GetGinEntry() *GinEntry {
+	return group.parent
+}
+
+// Bootstrap noop, routes of group will be served by GinEntry.
+func (group *GinGroupEntry) Bootstrap(context.Context) {}
+
+// Interrupt noop, GinGroupEntry will be removed while GinEntry interrupted.
+func (group *GinGroupEntry) Interrupt(context.Context) {}
+
+// String Stringfy entry.
+func (group *GinGroupEntry) String() string {
+	bytes, _ := json.Marshal(group)
+	return string(bytes)
+}
+
+// MarshalJSON Marshal entry.
+func (group *GinGroupEntry) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{
+		"name":        group.entryName,
+		"type":        group.entryType,
+		"description": group.entryDescription,
+		"prefix":      group.Prefix,
+		"ginEntry":    group.parent.GetName(),
+	}
+
+	return json.Marshal(&m)
+}
+
+// UnmarshalJSON Not supported.
+func (group *GinGroupEntry) UnmarshalJSON([]byte) error {
+	return nil
+}
+
+// GetGinGroupEntry Get GinGroupEntry from rkentry.GlobalAppCtx.
+func GetGinGroupEntry(name string) *GinGroupEntry {
+	entryRaw := rkentry.GlobalAppCtx.GetEntry(GinGroupEntryType, name)
+	if entryRaw == nil {
+		return nil
+	}
+
+	entry, _ := entryRaw.(*GinGroupEntry)
+	return entry
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGinEntry_AddGroup(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-group"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.AddMiddleware(func(ctx *gin.Context) {
+		ctx.Header("X-Entry", "true")
+	})
+
+	group := entry.AddGroup("ut-admin", "admin", func(ctx *gin.Context) {
+		ctx.Header("X-Group", "true")
+	})
+	defer rkentry.GlobalAppCtx.RemoveEntry(group)
+
+	group.Group.GET("/ut", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	entry.Router.GET("/ut", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, "ut-admin", group.GetName())
+	assert.Equal(t, GinGroupEntryType, group.GetType())
+	assert.NotEmpty(t, group.GetDescription())
+	assert.Equal(t, "/admin", group.Prefix)
+	assert.Equal(t, entry, group.GetGinEntry())
+	assert.Equal(t, group, entry.GetGroup("ut-admin"))
+	assert.Nil(t, entry.GetGroup("non-exist"))
+	assert.Len(t, entry.ListGroups(), 1)
+	assert.Equal(t, group, GetGinGroupEntry("ut-admin"))
+
+	// group runs entry middlewares and its own
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/ut", nil))
+	assert.Equal(t, "true", w.Header().Get("X-Entry"))
+	assert.Equal(t, "true", w.Header().Get("X-Group"))
+
+	// routes outside of group
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ut", nil))
+	assert.Equal(t, "true", w.Header().Get("X-Entry"))
+	assert.Empty(t, w.Header().Get("X-Group"))
+
+	// noop
+	group.Bootstrap(context.TODO())
+	group.Interrupt(context.TODO())
+
+	m := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(group.String()), &m))
+	assert.Equal(t, "/admin", m["prefix"])
+	assert.Equal(t, "ut-group", m["ginEntry"])
+	assert.Nil(t, group.UnmarshalJSON(nil))
+}
+
+func TestGinEntry_AddGroup_WithoutName(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-group"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	group := entry.AddGroup("", "/internal/")
+	defer rkentry.GlobalAppCtx.RemoveEntry(group)
+
+	assert.Equal(t, "ut-group-/internal", group.GetName())
+}
+
+func TestGetGinGroupEntry(t *testing.T) {
+	assert.Nil(t, GetGinGroupEntry("non-exist"))
+}
+
+func TestRegisterGinEntryYAML_WithGroups(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-groups
+    port: 1949
+    enabled: true
+    prom:
+      enabled: true
+    middleware:
+      prom:
+        enabled: true
+    groups:
+      - name: ut-admin
+        prefix: /admin
+        description: admin APIs
+        middleware:
+          prom:
+            enabled: true
+          auth:
+            enabled: true
+            basic: ["user:pass"]
+      - name: ut-public
+        prefix: /public
+`
+	entries := RegisterGinEntryYAML([]byte(bootStr))
+	entry := entries["ut-groups"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Len(t, entry.ListGroups(), 2)
+	admin := GetGinGroupEntry("ut-admin")
+	assert.NotNil(t, admin)
+	assert.Equal(t, "admin APIs", admin.GetDescription())
+
+	admin.Group.GET("/ut", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	entry.GetGroup("ut-public").Group.GET("/ut", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	// auth of admin group
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/ut", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/ut", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// groups removed while interrupted
+	entry.Interrupt(context.TODO())
+	assert.Nil(t, GetGinGroupEntry("ut-admin"))
+	assert.Nil(t, GetGinGroupEntry("ut-public"))
+}
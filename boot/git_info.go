@@ -0,0 +1,89 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"path"
+)
+
+// Build metadata injected at link time, served by git API.
+//
+//	go build -ldflags "\
+//	  -X github.com/rookie-ninja/rk-gin/v2/boot.GitCommit=$(git rev-parse HEAD) \
+//	  -X github.com/rookie-ninja/rk-gin/v2/boot.GitBranch=$(git rev-parse --abbrev-ref HEAD) \
+//	  -X github.com/rookie-ninja/rk-gin/v2/boot.GitTag=$(git describe --tags --always) \
+//	  -X github.com/rookie-ninja/rk-gin/v2/boot.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ) \
+//	  -X github.com/rookie-ninja/rk-gin/v2/boot.BuildBy=$(whoami)"
+//
+// GitCommit and BuildTime fall back to vcs.revision and vcs.time stamped by go build if not injected.
+var (
+	GitCommit string
+	GitBranch string
+	GitTag    string
+	BuildTime string
+	BuildBy   string
+)
+
+// GitResponse response of git API.
+//
+// Modified is true if binary is built from source tree with uncommitted changes, known only if stamped by go build.
+type GitResponse struct {
+	Commit    string `json:"commit" yaml:"commit"`
+	Branch    string `json:"branch" yaml:"branch"`
+	Tag       string `json:"tag" yaml:"tag"`
+	BuildTime string `json:"buildTime" yaml:"buildTime"`
+	BuildBy   string `json:"buildBy" yaml:"buildBy"`
+	Modified  bool   `json:"modified" yaml:"modified"`
+}
+
+// GitPath returns path of git API which sits next to common service paths, /rk/v1/git by default.
+func (entry *GinEntry) GitPath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "git")
+}
+
+// GitHandler returns build metadata injected at link time, see GitCommit.
+func (entry *GinEntry) GitHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, newGitResponse())
+}
+
+// newGitResponse returns build metadata injected at link time, with vcs settings stamped by go build as fallback.
+func newGitResponse() *GitResponse {
+	res := &GitResponse{
+		Commit:    GitCommit,
+		Branch:    GitBranch,
+		Tag:       GitTag,
+		BuildTime: BuildTime,
+		BuildBy:   BuildBy,
+	}
+
+	info, ok := readBuildInfo()
+	if !ok {
+		return res
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if len(res.Commit) < 1 {
+				res.Commit = setting.Value
+			}
+		case "vcs.time":
+			if len(res.BuildTime) < 1 {
+				res.BuildTime = setting.Value
+			}
+		case "vcs.modified":
+			res.Modified = setting.Value == "true"
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"testing"
+)
+
+func TestGinEntry_GitHandler(t *testing.T) {
+	defer func() {
+		readBuildInfo = debug.ReadBuildInfo
+		GitCommit, GitBranch, GitTag, BuildTime, BuildBy = "", "", "", "", ""
+	}()
+
+	entry := RegisterGinEntry(
+		WithName("ut-git"),
+		WithPort(0),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{Enabled: true})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, "/rk/v1/git", entry.GitPath())
+	entry.Router.GET(entry.GitPath(), entry.GitHandler)
+
+	serve := func() *GitResponse {
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rk/v1/git", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		res := &GitResponse{}
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), res))
+		return res
+	}
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "stamped"},
+				{Key: "vcs.time", Value: "2022-01-01T00:00:00Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+
+	// stamped by go build
+	assert.Equal(t, &GitResponse{
+		Commit:    "stamped",
+		BuildTime: "2022-01-01T00:00:00Z",
+		Modified:  true,
+	}, serve())
+
+	// injected at link time
+	GitCommit, GitBranch, GitTag, BuildTime, BuildBy = "abc", "main", "v1.0.0", "2022-02-02T00:00:00Z", "ci"
+	assert.Equal(t, &GitResponse{
+		Commit:    "abc",
+		Branch:    "main",
+		Tag:       "v1.0.0",
+		BuildTime: "2022-02-02T00:00:00Z",
+		BuildBy:   "ci",
+		Modified:  true,
+	}, serve())
+
+	// without build info
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return nil, false
+	}
+	assert.False(t, serve().Modified)
+
+	// disabled common service
+	disabled := RegisterGinEntry(WithName("ut-git-disabled"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(disabled)
+	assert.Empty(t, disabled.GitPath())
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"fmt"
+	"github.com/google/gops/agent"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const defaultGopsAddr = "127.0.0.1:0"
+
+var (
+	// gopsLock guards gopsOwner and gopsAddr, only one agent could run in a process.
+	gopsLock  sync.Mutex
+	gopsOwner string
+	gopsAddr  string
+)
+
+// BootGops config of github.com/google/gops agent.
+//
+// Agent listens on Addr, 127.0.0.1:0 by default, and writes port into file named after pid in ConfigDir,
+// where gops command finds it. ConfigDir is $GOPS_CONFIG_DIR or gops in user config directory by default.
+//
+// Agent is not protected by credentials, so Addr must be a loopback address.
+type BootGops struct {
+	Enabled   bool   `yaml:"enabled" json:"enabled"`
+	Addr      string `yaml:"addr" json:"addr"`
+	ConfigDir string `yaml:"configDir" json:"configDir"`
+}
+
+// WithGops start gops agent while bootstrapping.
+func WithGops(gops *BootGops) GinEntryOption {
+	return func(entry *GinEntry) {
+		if gops != nil && gops.Enabled {
+			entry.gops = gops
+		}
+	}
+}
+
+// IsGopsEnabled is gops agent enabled?
+func (entry *GinEntry) IsGopsEnabled() bool {
+	return entry.gops != nil
+}
+
+// startGopsAgent starts agent owned by entry and returns address of it,
+// nothing happens if an agent is already running.
+func startGopsAgent(owner string, boot *BootGops) (string, error) {
+	gopsLock.Lock()
+	defer gopsLock.Unlock()
+
+	if len(gopsOwner) > 0 {
+		return gopsAddr, nil
+	}
+
+	addr := boot.Addr
+	if len(addr) < 1 {
+		addr = defaultGopsAddr
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if !isLoopbackHost(host) {
+		return "", fmt.Errorf("gops agent must listen on loopback address, got %s", addr)
+	}
+
+	configDir, err := gopsConfigDir(boot)
+	if err != nil {
+		return "", err
+	}
+
+	if err := agent.Listen(agent.Options{Addr: addr, ConfigDir: configDir}); err != nil {
+		return "", err
+	}
+
+	// agent does not expose listener, port is read from port file written by it
+	port, err := os.ReadFile(filepath.Join(configDir, strconv.Itoa(os.Getpid())))
+	if err != nil {
+		agent.Close()
+		return "", err
+	}
+
+	gopsOwner = owner
+	gopsAddr = net.JoinHostPort(host, strings.TrimSpace(string(port)))
+
+	return gopsAddr, nil
+}
+
+// stopGopsAgent stops agent if it is owned by entry, port file is removed by agent.
+func stopGopsAgent(owner string) {
+	gopsLock.Lock()
+	defer gopsLock.Unlock()
+
+	if len(gopsOwner) < 1 || gopsOwner != owner {
+		return
+	}
+
+	agent.Close()
+	gopsOwner, gopsAddr = "", ""
+}
+
+// isLoopbackHost returns true if host is localhost or loopback IP.
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// gopsConfigDir returns directory of port files, same as gops command.
+func gopsConfigDir(boot *BootGops) (string, error) {
+	if len(boot.ConfigDir) > 0 {
+		return boot.ConfigDir, nil
+	}
+
+	if dir := os.Getenv("GOPS_CONFIG_DIR"); len(dir) > 0 {
+		return dir, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "gops"), nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithGops(t *testing.T) {
+	entry := &GinEntry{}
+
+	// disabled
+	WithGops(&BootGops{})(entry)
+	assert.False(t, entry.IsGopsEnabled())
+	WithGops(nil)(entry)
+	assert.False(t, entry.IsGopsEnabled())
+
+	// enabled
+	WithGops(&BootGops{Enabled: true})(entry)
+	assert.True(t, entry.IsGopsEnabled())
+}
+
+func TestGopsConfigDir(t *testing.T) {
+	dir, err := gopsConfigDir(&BootGops{ConfigDir: "ut-dir"})
+	assert.Nil(t, err)
+	assert.Equal(t, "ut-dir", dir)
+
+	t.Setenv("GOPS_CONFIG_DIR", "ut-env-dir")
+	dir, err = gopsConfigDir(&BootGops{})
+	assert.Nil(t, err)
+	assert.Equal(t, "ut-env-dir", dir)
+}
+
+func TestStartGopsAgent(t *testing.T) {
+	configDir := t.TempDir()
+	boot := &BootGops{Enabled: true, ConfigDir: configDir}
+
+	addr, err := startGopsAgent("ut-gops", boot)
+	assert.Nil(t, err)
+	defer stopGopsAgent("ut-gops")
+
+	// port file
+	portFile := filepath.Join(configDir, strconv.Itoa(os.Getpid()))
+	port, err := os.ReadFile(portFile)
+	assert.Nil(t, err)
+	assert.Equal(t, net.JoinHostPort("127.0.0.1", string(port)), addr)
+
+	// agent is shared in process
+	another, err := startGopsAgent("ut-gops-another", boot)
+	assert.Nil(t, err)
+	assert.Equal(t, addr, another)
+
+	// stopped by owner only
+	stopGopsAgent("ut-gops-another")
+	assert.Equal(t, "ut-gops", gopsOwner)
+
+	// version signal of gops protocol
+	conn, err := net.Dial("tcp", addr)
+	assert.Nil(t, err)
+	_, err = conn.Write([]byte{0x4})
+	assert.Nil(t, err)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	data, err := io.ReadAll(conn)
+	assert.Nil(t, err)
+	assert.Equal(t, runtime.Version(), strings.TrimSpace(string(data)))
+	conn.Close()
+
+	stopGopsAgent("ut-gops")
+	assert.Empty(t, gopsOwner)
+	_, err = os.Stat(portFile)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStartGopsAgent_WithNonLoopbackAddr(t *testing.T) {
+	for _, addr := range []string{":0", "0.0.0.0:0", "192.0.2.1:0", "invalid"} {
+		_, err := startGopsAgent("ut-gops", &BootGops{Enabled: true, Addr: addr, ConfigDir: t.TempDir()})
+		assert.NotNil(t, err, addr)
+		assert.Empty(t, gopsOwner)
+	}
+
+	assert.True(t, isLoopbackHost("localhost"))
+	assert.True(t, isLoopbackHost("::1"))
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultHealthCheckTimeout = 5 * time.Second
+
+// BootHealthCheck boot config of health check API.
+type BootHealthCheck struct {
+	TimeoutMs int `yaml:"timeoutMs" json:"timeoutMs"`
+}
+
+// HealthCheckFunc checks health of a dependency like database, returns error if unhealthy.
+//
+// Context is canceled once timed out.
+type HealthCheckFunc func(ctx context.Context) error
+
+// HealthCheckResult result of a health check.
+type HealthCheckResult struct {
+	Name      string  `json:"name" yaml:"name"`
+	Critical  bool    `json:"critical" yaml:"critical"`
+	Healthy   bool    `json:"healthy" yaml:"healthy"`
+	Error     string  `json:"error,omitempty" yaml:"error,omitempty"`
+	LatencyMs float64 `json:"latencyMs" yaml:"latencyMs"`
+}
+
+// HealthResponse response of health check API.
+//
+// Healthy is false if any critical check failed, failures of non-critical checks are reported only.
+type HealthResponse struct {
+	Healthy bool                 `json:"healthy" yaml:"healthy"`
+	Checks  []*HealthCheckResult `json:"checks" yaml:"checks"`
+}
+
+// healthCheck named HealthCheckFunc.
+type healthCheck struct {
+	name     string
+	critical bool
+	f        HealthCheckFunc
+}
+
+// healthCheckRegistry keeps health checks of a GinEntry.
+type healthCheckRegistry struct {
+	lock    sync.RWMutex
+	timeout time.Duration
+	checks  []*healthCheck
+}
+
+func newHealthCheckRegistry() *healthCheckRegistry {
+	return &healthCheckRegistry{
+		timeout: defaultHealthCheckTimeout,
+		checks:  make([]*healthCheck, 0),
+	}
+}
+
+// AddHealthCheck register health check called by health check API, GinEntry is unhealthy if critical check failed.
+//
+// Check with same name will be replaced.
+func (entry *GinEntry) AddHealthCheck(name string, critical bool, f HealthCheckFunc) {
+	if f == nil {
+		return
+	}
+
+	r := entry.healthChecks
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for i := range r.checks {
+		if r.checks[i].name == name {
+			r.checks[i] = &healthCheck{name: name, critical: critical, f: f}
+			return
+		}
+	}
+
+	r.checks = append(r.checks, &healthCheck{name: name, critical: critical, f: f})
+}
+
+// CheckHealth runs health checks concurrently and returns aggregated results sorted by name.
+func (entry *GinEntry) CheckHealth(ctx context.Context) *HealthResponse {
+	r := entry.healthChecks
+	r.lock.RLock()
+	checks := make([]*healthCheck, len(r.checks))
+	copy(checks, r.checks)
+	timeout := r.timeout
+	r.lock.RUnlock()
+
+	res := &HealthResponse{
+		Healthy: true,
+		Checks:  make([]*HealthCheckResult, len(checks)),
+	}
+
+	wg := sync.WaitGroup{}
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res.Checks[i] = runHealthCheck(ctx, checks[i], timeout)
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Slice(res.Checks, func(i, j int) bool {
+		return res.Checks[i].Name < res.Checks[j].Name
+	})
+
+	for _, check := range res.Checks {
+		if check.Critical && !check.Healthy {
+			res.Healthy = false
+		}
+	}
+
+	return res
+}
+
+// runHealthCheck calls check with timeout, panic is treated as failure.
+func runHealthCheck(ctx context.Context, check *healthCheck, timeout time.Duration) *HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recv := recover(); recv != nil {
+				errCh <- fmt.Errorf("panic: %v", recv)
+			}
+		}()
+		errCh <- check.f(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := &HealthCheckResult{
+		Name:      check.name,
+		Critical:  check.critical,
+		Healthy:   err == nil,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+// HealthyPath returns path of health check API which sits next to common service paths, /rk/v1/healthy by default.
+func (entry *GinEntry) HealthyPath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "healthy")
+}
+
+// HealthyHandler runs health checks and responds 503 if any critical check failed.
+func (entry *GinEntry) HealthyHandler(ctx *gin.Context) {
+	res := entry.CheckHealth(ctx.Request.Context())
+	if !res.Healthy {
+		ctx.JSON(http.StatusServiceUnavailable, res)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ReadyHandler responds 503 before GinEntry finished warmup and after it was interrupted,
+// otherwise readiness is reported by CommonServiceEntry with check set by SetReadinessCheck.
+func (entry *GinEntry) ReadyHandler(ctx *gin.Context) {
+	if !entry.IsReady() {
+		ctx.JSON(http.StatusServiceUnavailable, map[string]bool{"ready": false})
+		return
+	}
+
+	entry.CommonServiceEntry.Ready(ctx.Writer, ctx.Request)
+}
+
+// WithHealthCheck provide HealthCheckFunc.
+func WithHealthCheck(name string, critical bool, f HealthCheckFunc) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.AddHealthCheck(name, critical, f)
+	}
+}
+
+// WithHealthCheckTimeout provide timeout of each health check, default is 5 seconds.
+func WithHealthCheckTimeout(timeout time.Duration) GinEntryOption {
+	return func(entry *GinEntry) {
+		if timeout > 0 {
+			entry.healthChecks.timeout = timeout
+		}
+	}
+}
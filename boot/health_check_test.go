@@ -0,0 +1,101 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGinEntry_AddHealthCheck(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithHealthCheck("db", true, func(context.Context) error { return nil }),
+		WithHealthCheckTimeout(time.Second))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, time.Second, entry.healthChecks.timeout)
+
+	// replaced with same name
+	entry.AddHealthCheck("db", false, func(context.Context) error { return nil })
+	entry.AddHealthCheck("nil", false, nil)
+	assert.Len(t, entry.healthChecks.checks, 1)
+	assert.False(t, entry.healthChecks.checks[0].critical)
+
+	// default timeout kept
+	WithHealthCheckTimeout(0)(entry)
+	assert.Equal(t, time.Second, entry.healthChecks.timeout)
+}
+
+func TestGinEntry_CheckHealth(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithHealthCheckTimeout(50*time.Millisecond),
+		WithHealthCheck("queue", false, func(context.Context) error { return errors.New("too deep") }),
+		WithHealthCheck("db", true, func(context.Context) error { return nil }))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	// non-critical failure
+	res := entry.CheckHealth(context.TODO())
+	assert.True(t, res.Healthy)
+	assert.Len(t, res.Checks, 2)
+	assert.Equal(t, "db", res.Checks[0].Name)
+	assert.True(t, res.Checks[0].Healthy)
+	assert.Equal(t, "too deep", res.Checks[1].Error)
+
+	// critical timeout and panic
+	entry.AddHealthCheck("slow", true, func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	entry.AddHealthCheck("panic", true, func(context.Context) error { panic("boom") })
+
+	res = entry.CheckHealth(context.TODO())
+	assert.False(t, res.Healthy)
+	assert.Equal(t, "panic", res.Checks[1].Name)
+	assert.Contains(t, res.Checks[1].Error, "boom")
+	assert.Equal(t, "slow", res.Checks[3].Name)
+	assert.Equal(t, context.DeadlineExceeded.Error(), res.Checks[3].Error)
+	assert.True(t, res.Checks[3].LatencyMs >= 50)
+}
+
+func TestGinEntry_HealthyHandler(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-healthy"),
+		WithPort(0),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{Enabled: true})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, "/rk/v1/healthy", entry.HealthyPath())
+	entry.Router.GET(entry.HealthyPath(), entry.HealthyHandler)
+
+	// without checks
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rk/v1/healthy", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// critical failure
+	entry.AddHealthCheck("db", true, func(context.Context) error { return errors.New("refused") })
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rk/v1/healthy", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	res := &HealthResponse{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.False(t, res.Healthy)
+	assert.Equal(t, "refused", res.Checks[0].Error)
+
+	// disabled common service
+	disabled := RegisterGinEntry(WithName("ut-healthy-disabled"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(disabled)
+	assert.Empty(t, disabled.HealthyPath())
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func generateCerts() ([]byte, []byte) {
+	// Create certs and return as []byte
+	ca := &x509.Certificate{
+		Subject: pkix.Name{
+			Organization: []string{"Fake cert."},
+		},
+		SerialNumber:          big.NewInt(42),
+		NotAfter:              time.Now().Add(2 * time.Hour),
+		IsCA:                  true,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	// Create a Private Key
+	key, _ := rsa.GenerateKey(rand.Reader, 4096)
+
+	// Use CA Cert to sign a CSR and create a Public Cert
+	csr := &key.PublicKey
+	cert, _ := x509.CreateCertificate(rand.Reader, ca, ca, csr, key)
+
+	// Convert keys into pem.Block
+	c := &pem.Block{Type: "CERTIFICATE", Bytes: cert}
+	k := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+
+	return pem.EncodeToMemory(c), pem.EncodeToMemory(k)
+}
+
+func validateServerIsUp(t *testing.T, port uint64, isTls bool) {
+	// sleep for 2 seconds waiting server startup
+	time.Sleep(2 * time.Second)
+
+	if !isTls {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort("0.0.0.0", strconv.FormatUint(port, 10)), time.Second)
+		assert.Nil(t, err)
+		assert.NotNil(t, conn)
+		if conn != nil {
+			assert.Nil(t, conn.Close())
+		}
+		return
+	}
+
+	tlsConf := &tls.Config{
+		InsecureSkipVerify: true,
+	}
+
+	tlsConn, err := tls.Dial("tcp", net.JoinHostPort("0.0.0.0", strconv.FormatUint(port, 10)), tlsConf)
+	assert.Nil(t, err)
+	assert.NotNil(t, tlsConn)
+	if tlsConn != nil {
+		assert.Nil(t, tlsConn.Close())
+	}
+}
+
+// newBootstrappedTestEntry register GinEntry from boot config and bootstrap it, which is interrupted after test.
+//
+// GET /ut and /ut-allow are served with 200.
+func newBootstrappedTestEntry(t *testing.T, bootStr string) *GinEntry {
+	entries, err := RegisterGinEntryYAMLWithError([]byte(bootStr))
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+
+	var entry *GinEntry
+	for _, v := range entries {
+		entry = v.(*GinEntry)
+	}
+
+	entry.Router.GET("/ut", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	entry.Router.GET("/ut-allow", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	assert.Nil(t, entry.BootstrapWithError(context.TODO()))
+	t.Cleanup(func() {
+		entry.Interrupt(context.TODO())
+	})
+
+	return entry
+}
+
+// utCommonServiceAuth header of credentials admin:secret which are configured as basic auth of common service.
+var utCommonServiceAuth = map[string]string{"Authorization": "Basic YWRtaW46c2VjcmV0"}
+
+// serveTest serve request with Router of GinEntry.
+func serveTest(entry *GinEntry, method, p, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, p, strings.NewReader(body))
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, req)
+	return w
+}
+
+func assertNotPanic(t *testing.T) {
+	if r := recover(); r != nil {
+		// Expect panic to be called with non nil error
+		assert.True(t, false)
+	} else {
+		// This should never be called in case of a bug
+		assert.True(t, true)
+	}
+}
+
+func assertPanic(t *testing.T) {
+	if r := recover(); r != nil {
+		// Expect panic to be called with non nil error
+		assert.True(t, true)
+	} else {
+		// This should never be called in case of a bug
+		assert.True(t, false)
+	}
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.ReleaseMode)
+	os.Exit(m.Run())
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"os"
+	"strings"
+)
+
+// IsLocaleValid returns true if locale matches current environment.
+//
+// Locale is in format of realm::region::az::domain, each part is compared with
+// environment variable of REALM, REGION, AZ and DOMAIN. Part could be * which matches any value,
+// or comma separated values. Missing parts are treated as *, empty locale matches any environment.
+//
+//	*::*::*::dev        // enabled only if DOMAIN=dev
+//	rk::us-east-1::*::* // enabled only if REALM=rk and REGION=us-east-1
+func IsLocaleValid(locale string) bool {
+	if len(strings.TrimSpace(locale)) < 1 {
+		return true
+	}
+
+	parts := strings.Split(locale, "::")
+	if len(parts) > 4 {
+		return false
+	}
+
+	envs := []string{
+		os.Getenv("REALM"),
+		os.Getenv("REGION"),
+		os.Getenv("AZ"),
+		os.Getenv("DOMAIN"),
+	}
+
+	for i := range parts {
+		if !matchLocalePart(strings.TrimSpace(parts[i]), envs[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchLocalePart returns true if part is empty, * or contains value.
+func matchLocalePart(part, value string) bool {
+	if len(part) < 1 || part == "*" {
+		return true
+	}
+
+	for _, v := range strings.Split(part, ",") {
+		if strings.TrimSpace(v) == value {
+			return true
+		}
+	}
+
+	return false
+}
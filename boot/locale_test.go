@@ -0,0 +1,68 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIsLocaleValid(t *testing.T) {
+	t.Setenv("REALM", "rk")
+	t.Setenv("REGION", "us-east-1")
+	t.Setenv("AZ", "")
+	t.Setenv("DOMAIN", "prod")
+
+	assert.True(t, IsLocaleValid(""))
+	assert.True(t, IsLocaleValid("*::*::*::*"))
+	assert.True(t, IsLocaleValid("*::*::*::prod"))
+	assert.True(t, IsLocaleValid("rk::us-east-1"))
+	assert.True(t, IsLocaleValid("*::*::*::dev, prod"))
+	assert.False(t, IsLocaleValid("*::*::*::dev"))
+	assert.False(t, IsLocaleValid("rk::eu-west-1::*::*"))
+	assert.False(t, IsLocaleValid("*::*::az-1::*"))
+	assert.False(t, IsLocaleValid("*::*::*::*::*"))
+}
+
+func TestRegisterGinEntryYAML_WithLocale(t *testing.T) {
+	t.Setenv("DOMAIN", "prod")
+
+	bootStr := `
+gin:
+  - name: ut-locale
+    port: 1949
+    enabled: true
+    locale: "*::*::*::dev"
+    description: dev
+  - name: ut-locale
+    port: 1949
+    enabled: true
+    locale: "*::*::*::prod"
+    description: prod
+    middleware:
+      logging:
+        enabled: true
+        locale: "*::*::*::dev"
+      meta:
+        enabled: true
+        locale: "*::*::*::prod"
+    groups:
+      - name: ut-locale-dev
+        prefix: /dev
+        locale: "*::*::*::dev"
+`
+	entries := RegisterGinEntryYAML([]byte(bootStr))
+	assert.Len(t, entries, 1)
+	entry := entries["ut-locale"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, "prod", entry.GetDescription())
+	assert.Empty(t, entry.ListGroups())
+
+	// maintenance, panic and meta middlewares, logging skipped
+	assert.Len(t, entry.Router.Handlers, 3)
+}
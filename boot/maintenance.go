@@ -0,0 +1,173 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// BootMaintenance boot config of maintenance mode.
+//
+// While in maintenance, requests will be responded with 503 except paths in Allow,
+// readiness, liveness, health check and maintenance APIs of common service are always allowed.
+type BootMaintenance struct {
+	Enabled       bool                `yaml:"enabled" json:"enabled"`
+	Message       string              `yaml:"message" json:"message"`
+	RetryAfterSec int                 `yaml:"retryAfterSec" json:"retryAfterSec"`
+	Allow         BootMiddlewareScope `yaml:"allow" json:"allow"`
+}
+
+// MaintenanceRequest request body of maintenance API.
+type MaintenanceRequest struct {
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// MaintenanceResponse response of maintenance API.
+type MaintenanceResponse struct {
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// maintenance keeps maintenance state of a GinEntry.
+type maintenance struct {
+	enabled       int32
+	lock          sync.RWMutex
+	message       string
+	retryAfter    int
+	allow         BootMiddlewareScope
+	inMiddlewares bool
+}
+
+func newMaintenance() *maintenance {
+	return &maintenance{
+		message: http.StatusText(http.StatusServiceUnavailable),
+	}
+}
+
+// EnableMaintenance turns GinEntry into maintenance mode, default message will be used if msg is empty.
+func (entry *GinEntry) EnableMaintenance(msg string) {
+	m := entry.maintenance
+	if len(msg) > 0 {
+		m.lock.Lock()
+		m.message = msg
+		m.lock.Unlock()
+	}
+
+	atomic.StoreInt32(&m.enabled, 1)
+}
+
+// DisableMaintenance turns GinEntry back to serve requests.
+func (entry *GinEntry) DisableMaintenance() {
+	atomic.StoreInt32(&entry.maintenance.enabled, 0)
+}
+
+// IsInMaintenance returns true if GinEntry is in maintenance mode.
+func (entry *GinEntry) IsInMaintenance() bool {
+	return atomic.LoadInt32(&entry.maintenance.enabled) == 1
+}
+
+// MaintenancePath returns path of maintenance API which sits next to common service paths, /rk/v1/maintenance by default.
+func (entry *GinEntry) MaintenancePath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "maintenance")
+}
+
+// MaintenanceHandler returns maintenance state with GET and switches it with PUT.
+func (entry *GinEntry) MaintenanceHandler(ctx *gin.Context) {
+	if ctx.Request.Method == http.MethodPut {
+		req := &MaintenanceRequest{}
+		if err := ctx.ShouldBindJSON(req); err != nil {
+			ctx.JSON(http.StatusBadRequest, rkmid.GetErrorBuilder().New(http.StatusBadRequest, "Invalid request body", err))
+			return
+		}
+
+		if req.Enabled {
+			entry.EnableMaintenance(req.Message)
+		} else {
+			entry.DisableMaintenance()
+		}
+	}
+
+	entry.maintenance.lock.RLock()
+	msg := entry.maintenance.message
+	entry.maintenance.lock.RUnlock()
+
+	ctx.JSON(http.StatusOK, &MaintenanceResponse{
+		Enabled: entry.IsInMaintenance(),
+		Message: msg,
+	})
+}
+
+// maintenanceMiddleware responds 503 while GinEntry is in maintenance mode.
+func (entry *GinEntry) maintenanceMiddleware() gin.HandlerFunc {
+	m := entry.maintenance
+
+	return func(ctx *gin.Context) {
+		if atomic.LoadInt32(&m.enabled) != 1 || entry.isMaintenanceAllowed(ctx.Request.URL.Path) {
+			return
+		}
+
+		m.lock.RLock()
+		msg := m.message
+		m.lock.RUnlock()
+
+		if m.retryAfter > 0 {
+			ctx.Header("Retry-After", strconv.Itoa(m.retryAfter))
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusServiceUnavailable,
+			rkmid.GetErrorBuilder().New(http.StatusServiceUnavailable, msg, ctx.Request.Method+" "+ctx.Request.URL.Path))
+	}
+}
+
+// withMaintenanceInMiddlewares place maintenance check into middlewares built from boot config
+// instead of in front of all middlewares.
+func withMaintenanceInMiddlewares() GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.maintenance.inMiddlewares = true
+	}
+}
+
+// isMaintenanceAllowed returns true if path should be served in maintenance mode.
+func (entry *GinEntry) isMaintenanceAllowed(urlPath string) bool {
+	if entry.IsCommonServiceEnabled() {
+		switch urlPath {
+		case entry.CommonServiceEntry.ReadyPath, entry.CommonServiceEntry.AlivePath, entry.MaintenancePath(), entry.HealthyPath():
+			return true
+		}
+	}
+
+	return !entry.maintenance.allow.IsEmpty() && entry.maintenance.allow.Match(urlPath)
+}
+
+// WithMaintenance provide maintenance config.
+func WithMaintenance(config *BootMaintenance) GinEntryOption {
+	return func(entry *GinEntry) {
+		if config == nil {
+			return
+		}
+
+		if len(config.Message) > 0 {
+			entry.maintenance.message = config.Message
+		}
+		entry.maintenance.retryAfter = config.RetryAfterSec
+		entry.maintenance.allow = config.Allow
+
+		if config.Enabled {
+			entry.maintenance.enabled = 1
+		}
+	}
+}
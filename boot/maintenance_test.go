@@ -0,0 +1,143 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+const utMaintenanceBootStr = `
+gin:
+  - name: ut-maintenance
+    port: 0
+    enabled: true
+    commonService:
+      enabled: true
+      auth:
+        basic: ["admin:secret"]
+    middleware:
+      prom:
+        enabled: true
+      meta:
+        enabled: true
+`
+
+func TestGinEntry_Maintenance(t *testing.T) {
+	entry := newBootstrappedTestEntry(t, utMaintenanceBootStr)
+
+	assert.Equal(t, "/rk/v1/maintenance", entry.MaintenancePath())
+	assert.False(t, entry.IsInMaintenance())
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/ut", "", nil).Code)
+
+	entry.EnableMaintenance("upgrading")
+	assert.True(t, entry.IsInMaintenance())
+
+	w := serveTest(entry, http.MethodGet, "/ut", "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "upgrading")
+	assert.Empty(t, w.Header().Get("Retry-After"))
+
+	// rejected before meta middleware
+	assert.Empty(t, w.Header().Get("X-Rk-App-Name"))
+
+	// common service is always allowed
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/rk/v1/ready", "", nil).Code)
+
+	entry.DisableMaintenance()
+	assert.False(t, entry.IsInMaintenance())
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/ut", "", nil).Code)
+
+	// rejected request is measured by prom middleware
+	stats := serveTest(entry, http.MethodGet, entry.ReqPath(), "", utCommonServiceAuth)
+	assert.Contains(t, stats.Body.String(), `"resCode":"503"`)
+}
+
+func TestGinEntry_MaintenanceHandler(t *testing.T) {
+	entry := newBootstrappedTestEntry(t, utMaintenanceBootStr)
+
+	// credentials required
+	w := serveTest(entry, http.MethodPut, "/rk/v1/maintenance", `{"enabled":true}`, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.False(t, entry.IsInMaintenance())
+
+	// turn on
+	w = serveTest(entry, http.MethodPut, "/rk/v1/maintenance", `{"enabled":true,"message":"ut"}`, utCommonServiceAuth)
+	assert.Equal(t, http.StatusOK, w.Code)
+	resp := &MaintenanceResponse{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.True(t, resp.Enabled)
+	assert.Equal(t, "ut", resp.Message)
+	assert.Equal(t, http.StatusServiceUnavailable, serveTest(entry, http.MethodGet, "/ut", "", nil).Code)
+
+	// get
+	w = serveTest(entry, http.MethodGet, "/rk/v1/maintenance", "", utCommonServiceAuth)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"enabled":true`)
+
+	// invalid body
+	w = serveTest(entry, http.MethodPut, "/rk/v1/maintenance", `invalid`, utCommonServiceAuth)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// turn off
+	w = serveTest(entry, http.MethodPut, "/rk/v1/maintenance", `{"enabled":false}`, utCommonServiceAuth)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/ut", "", nil).Code)
+}
+
+func TestGinEntry_MaintenanceHandler_WithoutCommonServiceAuth(t *testing.T) {
+	entry := newBootstrappedTestEntry(t, `
+gin:
+  - name: ut-maintenance
+    port: 0
+    enabled: true
+    commonService:
+      enabled: true
+`)
+
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/rk/v1/maintenance", "", nil).Code)
+	assert.NotEqual(t, http.StatusOK, serveTest(entry, http.MethodPut, "/rk/v1/maintenance", `{"enabled":true}`, nil).Code)
+	assert.False(t, entry.IsInMaintenance())
+}
+
+func TestWithMaintenance(t *testing.T) {
+	entry := newBootstrappedTestEntry(t, `
+gin:
+  - name: ut-maintenance
+    port: 0
+    enabled: true
+    commonService:
+      enabled: true
+    maintenance:
+      enabled: true
+      message: ut-msg
+      retryAfterSec: 60
+      allow:
+        paths: ["/ut-allow"]
+`)
+
+	assert.True(t, entry.IsInMaintenance())
+
+	w := serveTest(entry, http.MethodGet, "/ut", "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "ut-msg")
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/ut-allow", "", nil).Code)
+
+	// nil config
+	another := RegisterGinEntry(WithName("ut-maintenance-nil"), WithPort(0), WithMaintenance(nil))
+	defer rkentry.GlobalAppCtx.RemoveEntry(another)
+	assert.False(t, another.IsInMaintenance())
+	assert.Empty(t, another.MaintenancePath())
+
+	// maintenance check comes first in entry registered with options
+	another.EnableMaintenance("")
+	assert.Equal(t, http.StatusServiceUnavailable, serveTest(another, http.MethodGet, "/ut", "", nil).Code)
+}
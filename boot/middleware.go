@@ -0,0 +1,572 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/auth"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/cors"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/csrf"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/jwt"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/log"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/meta"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/panic"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/ratelimit"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/secure"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/timeout"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/auth"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/cors"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/csrf"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/gzip"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/jwt"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/log"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/meta"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/otelmetric"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/panic"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/prom"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/ratelimit"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/secure"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/timeout"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/tracing"
+	"go.opentelemetry.io/otel/metric"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"path"
+	"strings"
+)
+
+// BootMiddleware boot config of middlewares in GinEntry.
+//
+// SkipDefaults lists sections, like cors, which are not inherited from middlewareDefaults,
+// so that middleware enabled in middlewareDefaults could be disabled in typed config where false is zero value.
+type BootMiddleware struct {
+	Ignore       []string               `yaml:"ignore" json:"ignore"`
+	ErrorModel   string                 `yaml:"errorModel" json:"errorModel"`
+	Logging      BootMiddlewareLogging  `yaml:"logging" json:"logging"`
+	Panic        BootMiddlewarePanic    `yaml:"panic" json:"panic"`
+	Prom         BootMiddlewareProm     `yaml:"prom" json:"prom"`
+	Auth         BootMiddlewareAuth     `yaml:"auth" json:"auth"`
+	Cors         BootMiddlewareCors     `yaml:"cors" json:"cors"`
+	Meta         BootMiddlewareMeta     `yaml:"meta" json:"meta"`
+	Jwt          BootMiddlewareJwt      `yaml:"jwt" json:"jwt"`
+	Secure       BootMiddlewareSecure   `yaml:"secure" json:"secure"`
+	RateLimit    BootMiddlewareLimit    `yaml:"rateLimit" json:"rateLimit"`
+	Csrf         BootMiddlewareCsrf     `yaml:"csrf" json:"csrf"`
+	Timeout      BootMiddlewareTimeout  `yaml:"timeout" json:"timeout"`
+	Trace        BootMiddlewareTrace    `yaml:"trace" json:"trace"`
+	Gzip         BootMiddlewareGzip     `yaml:"gzip" json:"gzip"`
+	Custom       []BootMiddlewareCustom `yaml:"custom" json:"custom"`
+	Order        []string               `yaml:"order" json:"order"`
+	SkipDefaults []string               `yaml:"skipDefaults" json:"skipDefaults"`
+}
+
+// BootMiddlewareScope limits middleware to requests matching Paths or PathPrefix.
+//
+// Middleware applies to all requests if both of them are empty.
+// Elements in PathPrefix match on path segment boundary, /v1 matches /v1 and /v1/user but not /v10.
+// Elements in Paths could be exact path or pattern supported by path.Match, like /v1/user/*.
+type BootMiddlewareScope struct {
+	Paths      []string `yaml:"paths" json:"paths"`
+	PathPrefix []string `yaml:"pathPrefix" json:"pathPrefix"`
+}
+
+// IsEmpty returns true if no path or prefix configured.
+func (scope *BootMiddlewareScope) IsEmpty() bool {
+	return len(scope.Paths) < 1 && len(scope.PathPrefix) < 1
+}
+
+// Match returns true if middleware should apply to urlPath.
+func (scope *BootMiddlewareScope) Match(urlPath string) bool {
+	if scope.IsEmpty() {
+		return true
+	}
+
+	for _, prefix := range scope.PathPrefix {
+		if urlPath == prefix || strings.HasPrefix(urlPath, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+
+	for _, p := range scope.Paths {
+		if p == urlPath {
+			return true
+		}
+
+		if matched, _ := path.Match(p, urlPath); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Wrap returns middleware which only calls handler on matched requests.
+//
+// gin will move on to next handler if handler skipped, no need to call ctx.Next().
+func (scope *BootMiddlewareScope) Wrap(handler gin.HandlerFunc) gin.HandlerFunc {
+	if scope.IsEmpty() {
+		return handler
+	}
+
+	return func(ctx *gin.Context) {
+		if scope.Match(ctx.Request.URL.Path) {
+			handler(ctx)
+		}
+	}
+}
+
+// wrapIgnorePattern returns handler which skips requests whose path matches any of patterns.
+//
+// Pattern follows path.Match, pattern without slash is matched against base name of path, like *.js.
+func wrapIgnorePattern(patterns []string, handler gin.HandlerFunc) gin.HandlerFunc {
+	if len(patterns) < 1 {
+		return handler
+	}
+
+	return func(ctx *gin.Context) {
+		if !matchIgnorePattern(patterns, ctx.Request.URL.Path) {
+			handler(ctx)
+		}
+	}
+}
+
+// matchIgnorePattern returns true if urlPath matches any of patterns.
+func matchIgnorePattern(patterns []string, urlPath string) bool {
+	for _, pattern := range patterns {
+		target := urlPath
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(urlPath)
+		}
+
+		if matched, _ := path.Match(pattern, target); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BootMiddlewareLogging boot config of logging middleware.
+//
+// Format could be event, json or console and overrides EventEncoding, OmitFields are sections of event
+// dropped in json format, like payloads or env, Fields are static payloads added into every event.
+//
+// Requests whose path starts with IgnorePrefix, same as Ignore, or matches IgnorePattern are not logged.
+// Events of failed requests are tagged with errorClass pair of client_error, server_error, timeout or canceled.
+// Body records request and response bodies with redaction, Header records allowed headers with masking.
+// Query redacts and truncates query string recorded as apiQuery.
+// Sampling keeps part of events of successful requests, events of errors and slow requests are always kept.
+// Requests slower than SlowThresholdMs are marked with slow=true in event and logged at WARN level.
+// Lumberjack writes events into dedicated file of entry with rotation, separated from application logs.
+// CLF writes Common or Combined Log Format lines in addition to or instead of events.
+// Syslog sends events to local or remote syslog server in RFC5424 format besides event output paths.
+// Sinks push events to kafka, loki or sinks registered with RegisterEventSink in batches.
+// Async writes events with a background goroutine, events are dropped if its buffer is full.
+type BootMiddlewareLogging struct {
+	rkmidlog.BootConfig `mapstructure:",squash" yaml:",inline"`
+	IgnorePrefix        []string                `yaml:"ignorePrefix" json:"ignorePrefix"`
+	IgnorePattern       []string                `yaml:"ignorePattern" json:"ignorePattern"`
+	Body                rkginlog.BodyConfig     `yaml:"body" json:"body"`
+	Header              rkginlog.HeaderConfig   `yaml:"header" json:"header"`
+	Query               rkginlog.QueryConfig    `yaml:"query" json:"query"`
+	TraceAware          bool                    `yaml:"traceAware" json:"traceAware"`
+	Sampling            rkginlog.SamplingConfig `yaml:"sampling" json:"sampling"`
+	SlowThresholdMs     int64                   `yaml:"slowThresholdMs" json:"slowThresholdMs"`
+	Format              string                  `yaml:"format" json:"format"`
+	OmitFields          []string                `yaml:"omitFields" json:"omitFields"`
+	Fields              map[string]string       `yaml:"fields" json:"fields"`
+	Lumberjack          *lumberjack.Logger      `yaml:"lumberjack" json:"lumberjack"`
+	CLF                 rkginlog.CLFConfig      `yaml:"clf" json:"clf"`
+	Syslog              BootAccessLogSyslog     `yaml:"syslog" json:"syslog"`
+	Sinks               []BootAccessLogSink     `yaml:"sinks" json:"sinks"`
+	Async               BootAccessLogAsync      `yaml:"async" json:"async"`
+	Scope               BootMiddlewareScope     `yaml:"scope" json:"scope"`
+	Locale              string                  `yaml:"locale" json:"locale"`
+}
+
+// BootMiddlewareProm boot config of prometheus middleware.
+type BootMiddlewareProm struct {
+	rkmidprom.BootConfig `mapstructure:",squash" yaml:",inline"`
+	Backend              string                             `yaml:"backend" json:"backend"`
+	Otlp                 rkginotelmetric.OtlpExporterConfig `yaml:"otlp" json:"otlp"`
+	IgnoreRegex          []string                           `yaml:"ignoreRegex" json:"ignoreRegex"`
+	Histogram            rkginprom.HistogramConfig          `yaml:"histogram" json:"histogram"`
+	SLO                  rkginprom.SLOConfig                `yaml:"slo" json:"slo"`
+	Tenant               rkginprom.TenantConfig             `yaml:"tenant" json:"tenant"`
+	Scope                BootMiddlewareScope                `yaml:"scope" json:"scope"`
+	Locale               string                             `yaml:"locale" json:"locale"`
+}
+
+// BootMiddlewareAuth boot config of auth middleware.
+type BootMiddlewareAuth struct {
+	rkmidauth.BootConfig `mapstructure:",squash" yaml:",inline"`
+	Scope                BootMiddlewareScope `yaml:"scope" json:"scope"`
+	Locale               string              `yaml:"locale" json:"locale"`
+}
+
+// BootMiddlewareCors boot config of cors middleware.
+type BootMiddlewareCors struct {
+	rkmidcors.BootConfig `mapstructure:",squash" yaml:",inline"`
+	Scope                BootMiddlewareScope `yaml:"scope" json:"scope"`
+	Locale               string              `yaml:"locale" json:"locale"`
+}
+
+// BootMiddlewarePanic boot config of panic middleware, which is always enabled.
+type BootMiddlewarePanic struct {
+	Format string `yaml:"format" json:"format"`
+}
+
+// validate format of panic middleware.
+func (config *BootMiddlewarePanic) validate() error {
+	switch strings.ToLower(config.Format) {
+	case "", rkginpanic.FormatJson, rkginpanic.FormatProblem:
+		return nil
+	}
+
+	return fmt.Errorf("invalid format %s of panic middleware", config.Format)
+}
+
+// BootMiddlewareMeta boot config of meta middleware.
+type BootMiddlewareMeta struct {
+	rkmidmeta.BootConfig   `mapstructure:",squash" yaml:",inline"`
+	RequestIdHeader        string              `yaml:"requestIdHeader" json:"requestIdHeader"`
+	DisableRequestIdHeader bool                `yaml:"disableRequestIdHeader" json:"disableRequestIdHeader"`
+	Scope                  BootMiddlewareScope `yaml:"scope" json:"scope"`
+	Locale                 string              `yaml:"locale" json:"locale"`
+}
+
+// BootMiddlewareJwt boot config of jwt middleware.
+type BootMiddlewareJwt struct {
+	rkmidjwt.BootConfig `mapstructure:",squash" yaml:",inline"`
+	Scope               BootMiddlewareScope `yaml:"scope" json:"scope"`
+	Locale              string              `yaml:"locale" json:"locale"`
+}
+
+// BootMiddlewareSecure boot config of secure middleware.
+type BootMiddlewareSecure struct {
+	rkmidsec.BootConfig `mapstructure:",squash" yaml:",inline"`
+	Scope               BootMiddlewareScope `yaml:"scope" json:"scope"`
+	Locale              string              `yaml:"locale" json:"locale"`
+}
+
+// BootMiddlewareLimit boot config of rate limit middleware.
+type BootMiddlewareLimit struct {
+	rkmidlimit.BootConfig `mapstructure:",squash" yaml:",inline"`
+	Scope                 BootMiddlewareScope `yaml:"scope" json:"scope"`
+	Locale                string              `yaml:"locale" json:"locale"`
+}
+
+// BootMiddlewareCsrf boot config of csrf middleware.
+type BootMiddlewareCsrf struct {
+	rkmidcsrf.BootConfig `mapstructure:",squash" yaml:",inline"`
+	Scope                BootMiddlewareScope `yaml:"scope" json:"scope"`
+	Locale               string              `yaml:"locale" json:"locale"`
+}
+
+// BootMiddlewareTimeout boot config of timeout middleware.
+type BootMiddlewareTimeout struct {
+	rkmidtimeout.BootConfig `mapstructure:",squash" yaml:",inline"`
+	Scope                   BootMiddlewareScope `yaml:"scope" json:"scope"`
+	Locale                  string              `yaml:"locale" json:"locale"`
+}
+
+// BootMiddlewareTrace boot config of tracing middleware.
+//
+// Fields of rkmidtrace.BootConfig are declared with Exporter extended by BootTraceExporter.
+// JaegerAgent replaces Exporter if enabled, spans are sent to jaeger agent with UDP.
+// FanOut exports spans with all enabled exporters above, each with its own processor, instead of the first one.
+// Sampler replaces sampler of tracer provider which always samples by default.
+// Propagators replaces tracecontext and baggage with propagators of names, like b3, b3multi, jaeger or xray.
+// Body records redacted request and response bodies of sampled spans as span events.
+// Spans batched by tracing middlewares of entry and its groups are flushed within FlushTimeoutMs while interrupting.
+//
+// Requests whose path starts with IgnorePrefix, same as Ignore, or matches IgnorePattern are not traced.
+type BootMiddlewareTrace struct {
+	Enabled              bool                                 `yaml:"enabled" json:"enabled"`
+	Ignore               []string                             `yaml:"ignore" json:"ignore"`
+	Exporter             BootTraceExporter                    `yaml:"exporter" json:"exporter"`
+	IgnorePrefix         []string                             `yaml:"ignorePrefix" json:"ignorePrefix"`
+	IgnorePattern        []string                             `yaml:"ignorePattern" json:"ignorePattern"`
+	JaegerAgent          rkgintrace.JaegerAgentExporterConfig `yaml:"jaegerAgent" json:"jaegerAgent"`
+	FanOut               bool                                 `yaml:"fanOut" json:"fanOut"`
+	Sampler              rkgintrace.SamplerConfig             `yaml:"sampler" json:"sampler"`
+	TailSampling         rkgintrace.TailSamplingConfig        `yaml:"tailSampling" json:"tailSampling"`
+	Resource             rkgintrace.ResourceConfig            `yaml:"resource" json:"resource"`
+	Propagators          []string                             `yaml:"propagators" json:"propagators"`
+	FallbackHeaders      []string                             `yaml:"fallbackHeaders" json:"fallbackHeaders"`
+	Body                 rkginlog.BodyConfig                  `yaml:"body" json:"body"`
+	TraceIdHeader        string                               `yaml:"traceIdHeader" json:"traceIdHeader"`
+	DisableTraceIdHeader bool                                 `yaml:"disableTraceIdHeader" json:"disableTraceIdHeader"`
+	FlushTimeoutMs       int                                  `yaml:"flushTimeoutMs" json:"flushTimeoutMs"`
+	Scope                BootMiddlewareScope                  `yaml:"scope" json:"scope"`
+	Locale               string                               `yaml:"locale" json:"locale"`
+}
+
+// BootMiddlewareGzip boot config of gzip middleware.
+type BootMiddlewareGzip struct {
+	Enabled bool                `yaml:"enabled" json:"enabled"`
+	Ignore  []string            `yaml:"ignore" json:"ignore"`
+	Level   string              `yaml:"level" json:"level"`
+	Scope   BootMiddlewareScope `yaml:"scope" json:"scope"`
+	Locale  string              `yaml:"locale" json:"locale"`
+}
+
+// builtInMiddlewareOrder default order of built-in middlewares.
+var builtInMiddlewareOrder = []string{
+	"logging", "panic", "prom", "trace", "cors", "jwt", "secure", "csrf", "gzip", "meta", "auth", "timeout", "rateLimit",
+}
+
+// newMiddlewareChain build middlewares from boot config.
+//
+// Middlewares listed in config.Order come first in the listed order, the rest follow default order of:
+// logging, panic, prom, trace, cors, jwt, secure, csrf, gzip, meta, auth, timeout, rateLimit, custom middlewares
+func newMiddlewareChain(config *BootMiddleware, entryName string,
+	loggerEntry *rkentry.LoggerEntry, eventEntry *rkentry.EventEntry,
+	metricsSet *rkmidprom.MetricsSet, meterProvider metric.MeterProvider, hooks *pendingHooks,
+	recoveryHandler rkginpanic.RecoveryHandler, logExtensions ...rkginlog.Extension) ([]gin.HandlerFunc, error) {
+	inters, err := newNamedMiddlewares(config, entryName, loggerEntry, eventEntry, metricsSet, meterProvider, hooks,
+		recoveryHandler, logExtensions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return orderMiddlewares(inters, config.Order), nil
+}
+
+// newNamedMiddlewares build middlewares from boot config in default order.
+func newNamedMiddlewares(config *BootMiddleware, entryName string,
+	loggerEntry *rkentry.LoggerEntry, eventEntry *rkentry.EventEntry,
+	metricsSet *rkmidprom.MetricsSet, meterProvider metric.MeterProvider, hooks *pendingHooks,
+	recoveryHandler rkginpanic.RecoveryHandler, logExtensions ...rkginlog.Extension) ([]*namedHandler, error) {
+	inters := make([]*namedHandler, 0)
+
+	// built-in middlewares, panic middleware is always enabled and placed after logging middleware,
+	// we should make sure interceptors never panic
+	for _, name := range builtInMiddlewareOrder {
+		handler, err := newBuiltInMiddleware(name, config, entryName, loggerEntry, eventEntry, metricsSet, meterProvider,
+			hooks, recoveryHandler, logExtensions...)
+		if err != nil {
+			return nil, err
+		}
+		if handler != nil {
+			inters = append(inters, &namedHandler{name: name, handler: handler})
+		}
+	}
+
+	// custom middlewares, each name could be enabled once since middlewares are ordered by name
+	enabled := make(map[string]bool)
+	for i := range config.Custom {
+		custom := config.Custom[i]
+		if !custom.Enabled || !IsLocaleValid(custom.Locale) {
+			continue
+		}
+		if enabled[custom.Name] {
+			return nil, fmt.Errorf("middleware %s is enabled more than once in custom middlewares", custom.Name)
+		}
+		enabled[custom.Name] = true
+
+		mid := GetNamedMiddleware(custom.Name)
+		if mid == nil {
+			return nil, fmt.Errorf("middleware %s is not registered with RegisterNamedMiddleware", custom.Name)
+		}
+
+		inters = append(inters, &namedHandler{name: custom.Name, handler: custom.Scope.Wrap(mid)})
+	}
+
+	return inters, nil
+}
+
+// newBuiltInMiddleware build built-in middleware with name, nil if disabled,
+// logExtensions are appended to extensions of logging middleware built from config,
+// error recovered by panic middleware is handled by recoveryHandler if not nil,
+// metrics of prom middleware with otel backend are recorded with meterProvider, global MeterProvider if nil.
+// Shutdown hooks of resources created for middleware are added into hooks, which are committed by caller.
+//
+// Options of rk-entry shut down process with invalid config, which is returned as error instead.
+func newBuiltInMiddleware(name string, config *BootMiddleware, entryName string,
+	loggerEntry *rkentry.LoggerEntry, eventEntry *rkentry.EventEntry,
+	metricsSet *rkmidprom.MetricsSet, meterProvider metric.MeterProvider, hooks *pendingHooks,
+	recoveryHandler rkginpanic.RecoveryHandler, logExtensions ...rkginlog.Extension) (handler gin.HandlerFunc, err error) {
+	defer recoverShutdownError(&err)
+
+	switch name {
+	case "logging":
+		if config.Logging.Enabled && IsLocaleValid(config.Logging.Locale) {
+			extensions, err := config.Logging.extensions(entryName, hooks)
+			if err != nil {
+				return nil, err
+			}
+			opts, err := newLoggingOptions(&config.Logging, entryName, loggerEntry, eventEntry, hooks)
+			if err != nil {
+				return nil, err
+			}
+			return config.Logging.Scope.Wrap(wrapIgnorePattern(config.Logging.IgnorePattern,
+				rkginlog.MiddlewareWithExtensions(append(extensions, logExtensions...), opts...))), nil
+		}
+	case "panic":
+		if err := config.Panic.validate(); err != nil {
+			return nil, err
+		}
+		return rkginpanic.MiddlewareWithConfig(rkginpanic.NewConfig(
+			rkginpanic.WithFormat(config.Panic.Format), rkginpanic.WithRecoveryHandler(recoveryHandler)),
+			rkmidpanic.WithEntryNameAndType(entryName, GinEntryType)), nil
+	case "prom":
+		if config.Prom.Enabled && IsLocaleValid(config.Prom.Locale) {
+			handler, err := config.Prom.newHandler(entryName, metricsSet, meterProvider)
+			if err != nil {
+				return nil, err
+			}
+
+			return config.Prom.Scope.Wrap(handler), nil
+		}
+	case "trace":
+		if config.Trace.Enabled && IsLocaleValid(config.Trace.Locale) {
+			trace := config.Trace
+			// bodies are redacted with the same fields as logging middleware if not configured
+			if len(trace.Body.Redact) < 1 {
+				trace.Body.Redact = config.Logging.Body.Redact
+			}
+
+			handler, err := trace.newHandler(entryName)
+			if err != nil {
+				return nil, err
+			}
+
+			return trace.Scope.Wrap(handler), nil
+		}
+	case "cors":
+		if config.Cors.Enabled && IsLocaleValid(config.Cors.Locale) {
+			return config.Cors.Scope.Wrap(rkgincors.Middleware(
+				rkmidcors.ToOptions(&config.Cors.BootConfig, entryName, GinEntryType)...)), nil
+		}
+	case "jwt":
+		if config.Jwt.Enabled && IsLocaleValid(config.Jwt.Locale) {
+			return config.Jwt.Scope.Wrap(rkginjwt.Middleware(
+				rkmidjwt.ToOptions(&config.Jwt.BootConfig, entryName, GinEntryType)...)), nil
+		}
+	case "secure":
+		if config.Secure.Enabled && IsLocaleValid(config.Secure.Locale) {
+			return config.Secure.Scope.Wrap(rkginsec.Middleware(
+				rkmidsec.ToOptions(&config.Secure.BootConfig, entryName, GinEntryType)...)), nil
+		}
+	case "csrf":
+		if config.Csrf.Enabled && IsLocaleValid(config.Csrf.Locale) {
+			return config.Csrf.Scope.Wrap(rkgincsrf.Middleware(
+				rkmidcsrf.ToOptions(&config.Csrf.BootConfig, entryName, GinEntryType)...)), nil
+		}
+	case "gzip":
+		if config.Gzip.Enabled && IsLocaleValid(config.Gzip.Locale) {
+			opts := []rkgingzip.Option{
+				rkgingzip.WithEntryNameAndType(entryName, GinEntryType),
+				rkgingzip.WithLevel(config.Gzip.Level),
+				rkgingzip.WithPathToIgnore(config.Gzip.Ignore...),
+			}
+
+			return config.Gzip.Scope.Wrap(rkgingzip.Middleware(opts...)), nil
+		}
+	case "meta":
+		if config.Meta.Enabled && IsLocaleValid(config.Meta.Locale) {
+			return config.Meta.Scope.Wrap(rkginmeta.MiddlewareWithConfig(&rkginmeta.Config{
+				RequestIdHeader:        config.Meta.RequestIdHeader,
+				DisableRequestIdHeader: config.Meta.DisableRequestIdHeader,
+			}, rkmidmeta.ToOptions(&config.Meta.BootConfig, entryName, GinEntryType)...)), nil
+		}
+	case "auth":
+		if config.Auth.Enabled && IsLocaleValid(config.Auth.Locale) {
+			return config.Auth.Scope.Wrap(rkginauth.Middleware(
+				rkmidauth.ToOptions(&config.Auth.BootConfig, entryName, GinEntryType)...)), nil
+		}
+	case "timeout":
+		if config.Timeout.Enabled && IsLocaleValid(config.Timeout.Locale) {
+			return config.Timeout.Scope.Wrap(rkgintout.Middleware(
+				rkmidtimeout.ToOptions(&config.Timeout.BootConfig, entryName, GinEntryType)...)), nil
+		}
+	case "rateLimit":
+		if config.RateLimit.Enabled && IsLocaleValid(config.RateLimit.Locale) {
+			return config.RateLimit.Scope.Wrap(rkginlimit.Middleware(
+				rkmidlimit.ToOptions(&config.RateLimit.BootConfig, entryName, GinEntryType)...)), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// pendingHooks shutdown hooks of resources created while building middlewares.
+//
+// Hooks are registered into rkentry.GlobalAppCtx with commit after middlewares are built and used,
+// which closes resources of previous middlewares with same names, or closed with release if build failed,
+// so that resources of running middlewares are never closed by a failed build.
+type pendingHooks struct {
+	names []string
+	hooks []rkentry.ShutdownHook
+}
+
+// add hook of resource with name.
+func (p *pendingHooks) add(name string, hook rkentry.ShutdownHook) {
+	p.names = append(p.names, name)
+	p.hooks = append(p.hooks, hook)
+}
+
+// commit closes resources registered with same names before and registers hooks.
+func (p *pendingHooks) commit() {
+	for i := range p.names {
+		if hook := rkentry.GlobalAppCtx.GetShutdownHook(p.names[i]); hook != nil {
+			hook()
+		}
+		rkentry.GlobalAppCtx.AddShutdownHook(p.names[i], p.hooks[i])
+	}
+	p.names, p.hooks = nil, nil
+}
+
+// release closes resources without registering hooks.
+func (p *pendingHooks) release() {
+	for i := range p.hooks {
+		p.hooks[i]()
+	}
+	p.names, p.hooks = nil, nil
+}
+
+// namedHandler middleware with name which could be referenced in boot config.
+type namedHandler struct {
+	name    string
+	handler gin.HandlerFunc
+}
+
+// orderMiddlewares sort middlewares by names in order, middlewares not in order keep their relative positions at the end.
+func orderMiddlewares(inters []*namedHandler, order []string) []gin.HandlerFunc {
+	res := make([]gin.HandlerFunc, 0, len(inters))
+	used := make(map[string]bool)
+
+	for _, name := range order {
+		for i := range inters {
+			if inters[i].name == name && !used[name] {
+				res = append(res, inters[i].handler)
+				used[name] = true
+			}
+		}
+	}
+
+	for i := range inters {
+		if !used[inters[i].name] {
+			res = append(res, inters[i].handler)
+		}
+	}
+
+	return res
+}
+
+// isBuiltInMiddleware returns true if name is reserved by built-in middlewares.
+func isBuiltInMiddleware(name string) bool {
+	for i := range builtInMiddlewareOrder {
+		if builtInMiddlewareOrder[i] == name {
+			return true
+		}
+	}
+
+	return false
+}
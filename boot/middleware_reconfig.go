@@ -0,0 +1,367 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// redactedValue replaces credentials in middleware config returned by MiddlewareHandler and recorded in event log.
+const redactedValue = "******"
+
+// swappableHandler middleware whose handler could be replaced at runtime.
+type swappableHandler struct {
+	value atomic.Value
+}
+
+func newSwappableHandler(handler gin.HandlerFunc) *swappableHandler {
+	h := &swappableHandler{}
+	h.store(handler)
+	return h
+}
+
+// store replaces handler, disabled middleware is stored as noop handler.
+func (h *swappableHandler) store(handler gin.HandlerFunc) {
+	if handler == nil {
+		handler = func(*gin.Context) {}
+	}
+	h.value.Store(handler)
+}
+
+func (h *swappableHandler) handle(ctx *gin.Context) {
+	h.value.Load().(gin.HandlerFunc)(ctx)
+}
+
+// middlewareRegistry keeps middleware config of GinEntry and middlewares which could be reconfigured.
+type middlewareRegistry struct {
+	lock          sync.Mutex
+	config        *BootMiddleware
+	metricsSet    *rkmidprom.MetricsSet
+	meterProvider metric.MeterProvider
+	handlers      map[string]*swappableHandler
+}
+
+// isReconfigurableMiddleware returns true if middleware could be rebuilt at runtime.
+//
+// prom and trace middlewares are excluded since they register metrics and exporters globally.
+func isReconfigurableMiddleware(name string) bool {
+	switch name {
+	case "prom", "trace":
+		return false
+	}
+
+	return isBuiltInMiddleware(name)
+}
+
+// newMiddlewaresFromConfig build middlewares from boot config, metrics of prom middleware are registered into
+// promRegisterer and unregistered with entry.
+// Built-in middlewares other than prom and trace could be reconfigured with ReconfigureMiddleware.
+func (entry *GinEntry) newMiddlewaresFromConfig(config *BootMiddleware, promRegisterer prometheus.Registerer,
+	hooks *pendingHooks) ([]gin.HandlerFunc, error) {
+	metricsSet, err := config.Prom.newMetricsSet(promRegisterer)
+	if err != nil {
+		return nil, err
+	}
+	entry.metricsSet = metricsSet
+	meterProvider, err := entry.newMeterProvider(&config.Prom, entry.entryName)
+	if err != nil {
+		return nil, err
+	}
+
+	inters, err := newNamedMiddlewares(config, entry.entryName, entry.LoggerEntry, entry.EventEntry, metricsSet,
+		meterProvider, hooks, entry.panicRecoveryHandler(), entry.eventEnricherExtension())
+	if err != nil {
+		return nil, err
+	}
+
+	reg := entry.middlewareRegistry
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	reg.config = config
+	reg.metricsSet = metricsSet
+	reg.meterProvider = meterProvider
+
+	// requests rejected in maintenance mode are still logged and measured
+	pos := 0
+	for i := range inters {
+		if isReconfigurableMiddleware(inters[i].name) {
+			h := newSwappableHandler(inters[i].handler)
+			reg.handlers[inters[i].name] = h
+			inters[i].handler = h.handle
+		}
+
+		switch inters[i].name {
+		case "logging", "panic", "prom", "trace":
+			pos = i + 1
+		}
+	}
+
+	if entry.maintenance.inMiddlewares {
+		inters = append(inters[:pos], append([]*namedHandler{{
+			name:    "maintenance",
+			handler: entry.maintenanceMiddleware(),
+		}}, inters[pos:]...)...)
+	}
+
+	return orderMiddlewares(inters, config.Order), nil
+}
+
+// GetMiddlewareConfig returns copy of middleware config built from boot config, nil if not exist.
+func (entry *GinEntry) GetMiddlewareConfig() *BootMiddleware {
+	reg := entry.middlewareRegistry
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	if reg.config == nil {
+		return nil
+	}
+
+	return copyMiddlewareConfig(reg.config)
+}
+
+// ListReconfigurableMiddlewares returns names of middlewares which could be reconfigured.
+func (entry *GinEntry) ListReconfigurableMiddlewares() []string {
+	reg := entry.middlewareRegistry
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	res := make([]string, 0)
+	for _, name := range builtInMiddlewareOrder {
+		if _, ok := reg.handlers[name]; ok {
+			res = append(res, name)
+		}
+	}
+
+	return res
+}
+
+// ReconfigureMiddleware rebuild middleware with name from config and replace it without restarting.
+//
+// Only section of config with name is used, start with GetMiddlewareConfig() to keep other settings.
+// Middleware disabled in new config will be skipped until enabled again.
+// Running middleware and its writers are kept if new config is invalid.
+// Only built-in middlewares enabled in boot config, except prom and trace, could be reconfigured.
+// Changes are recorded in event log.
+//
+//	config := entry.GetMiddlewareConfig()
+//	config.RateLimit.ReqPerSec = &reqPerSec
+//	entry.ReconfigureMiddleware("rateLimit", config)
+func (entry *GinEntry) ReconfigureMiddleware(name string, config *BootMiddleware) error {
+	if config == nil {
+		return fmt.Errorf("nil config of middleware %s", name)
+	}
+
+	reg := entry.middlewareRegistry
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	h, ok := reg.handlers[name]
+	if !ok {
+		return fmt.Errorf("middleware %s is not reconfigurable", name)
+	}
+
+	event, logger := entry.logBasicInfo("ReconfigureMiddleware", context.Background())
+	defer entry.EventEntry.Finish(event)
+
+	// copy section with name into current config
+	newConfig := copyMiddlewareConfig(reg.config)
+	if err := copyMiddlewareSection(name, config, newConfig); err != nil {
+		event.AddErr(err)
+		return err
+	}
+
+	hooks := &pendingHooks{}
+	handler, err := newBuiltInMiddleware(name, newConfig, entry.entryName, entry.LoggerEntry, entry.EventEntry,
+		reg.metricsSet, reg.meterProvider, hooks, entry.panicRecoveryHandler(), entry.eventEnricherExtension())
+	if err != nil {
+		hooks.release()
+		event.AddErr(err)
+		return err
+	}
+
+	// writers of previous middleware are closed after it is replaced
+	h.store(handler)
+	hooks.commit()
+	reg.config = newConfig
+
+	section, _ := getMiddlewareSection(name, redactMiddlewareConfig(newConfig))
+	event.AddPayloads(
+		zap.String("middleware", name),
+		zap.Any("config", section))
+	logger.Info("Middleware reconfigured.", zap.String("middleware", name))
+
+	return nil
+}
+
+// MiddlewarePath returns path of middleware API which sits next to common service paths, /rk/v1/middleware by default.
+func (entry *GinEntry) MiddlewarePath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "middleware")
+}
+
+// MiddlewareHandler returns middleware config with GET, credentials in config are masked.
+//
+// PUT /rk/v1/middleware/:name with JSON body of middleware config will update fields in body and rebuild middleware,
+// fields missing in body keep current values. PUT is served only if access of common service is restricted.
+func (entry *GinEntry) MiddlewareHandler(ctx *gin.Context) {
+	name := ctx.Param("name")
+
+	if ctx.Request.Method == http.MethodPut {
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, rkmid.GetErrorBuilder().New(http.StatusBadRequest, "Invalid request body", err))
+			return
+		}
+
+		config := entry.GetMiddlewareConfig()
+		if config == nil {
+			ctx.JSON(http.StatusNotFound, rkmid.GetErrorBuilder().New(http.StatusNotFound, "Middleware not configured"))
+			return
+		}
+
+		section, err := getMiddlewareSection(name, config)
+		if err == nil {
+			err = json.Unmarshal(body, section)
+		}
+		if err == nil {
+			err = entry.ReconfigureMiddleware(name, config)
+		}
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, rkmid.GetErrorBuilder().New(http.StatusBadRequest, "Failed to reconfigure middleware", err))
+			return
+		}
+	}
+
+	config := redactMiddlewareConfig(entry.GetMiddlewareConfig())
+	if len(name) < 1 {
+		ctx.JSON(http.StatusOK, config)
+		return
+	}
+
+	section, err := getMiddlewareSection(name, config)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, rkmid.GetErrorBuilder().New(http.StatusNotFound, "Middleware not found", err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, section)
+}
+
+// getMiddlewareSection returns pointer of config section of middleware with name.
+func getMiddlewareSection(name string, config *BootMiddleware) (interface{}, error) {
+	if config == nil {
+		return nil, fmt.Errorf("middleware %s not configured", name)
+	}
+
+	switch name {
+	case "logging":
+		return &config.Logging, nil
+	case "panic":
+		return &config.Panic, nil
+	case "cors":
+		return &config.Cors, nil
+	case "jwt":
+		return &config.Jwt, nil
+	case "secure":
+		return &config.Secure, nil
+	case "csrf":
+		return &config.Csrf, nil
+	case "gzip":
+		return &config.Gzip, nil
+	case "meta":
+		return &config.Meta, nil
+	case "auth":
+		return &config.Auth, nil
+	case "timeout":
+		return &config.Timeout, nil
+	case "rateLimit":
+		return &config.RateLimit, nil
+	}
+
+	return nil, fmt.Errorf("middleware %s is not reconfigurable", name)
+}
+
+// copyMiddlewareSection copy section of middleware with name from src to dst.
+func copyMiddlewareSection(name string, src, dst *BootMiddleware) error {
+	from, err := getMiddlewareSection(name, src)
+	if err != nil {
+		return err
+	}
+
+	to, _ := getMiddlewareSection(name, dst)
+	bytes, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	// reset section, so that fields missing in src won't be kept
+	v := reflect.ValueOf(to).Elem()
+	v.Set(reflect.Zero(v.Type()))
+
+	return json.Unmarshal(bytes, to)
+}
+
+// redactMiddlewareConfig returns copy of config whose credentials are replaced with redactedValue,
+// which are basic auth passwords, API keys, jwt token and private key, passwords of event sinks and OTLP headers.
+func redactMiddlewareConfig(config *BootMiddleware) *BootMiddleware {
+	if config == nil {
+		return nil
+	}
+
+	res := copyMiddlewareConfig(config)
+	for i := range res.Auth.Basic {
+		res.Auth.Basic[i] = strings.SplitN(res.Auth.Basic[i], ":", 2)[0] + ":" + redactedValue
+	}
+	for i := range res.Auth.ApiKey {
+		res.Auth.ApiKey[i] = redactedValue
+	}
+	if res.Jwt.Symmetric != nil && len(res.Jwt.Symmetric.Token) > 0 {
+		res.Jwt.Symmetric.Token = redactedValue
+	}
+	if res.Jwt.Asymmetric != nil && len(res.Jwt.Asymmetric.PrivateKey) > 0 {
+		res.Jwt.Asymmetric.PrivateKey = redactedValue
+	}
+	for i := range res.Logging.Sinks {
+		if len(res.Logging.Sinks[i].Password) > 0 {
+			res.Logging.Sinks[i].Password = redactedValue
+		}
+	}
+	for k := range res.Trace.Exporter.Otlp.Headers {
+		res.Trace.Exporter.Otlp.Headers[k] = redactedValue
+	}
+	for k := range res.Prom.Otlp.Headers {
+		res.Prom.Otlp.Headers[k] = redactedValue
+	}
+
+	return res
+}
+
+// copyMiddlewareConfig deep copy config.
+func copyMiddlewareConfig(config *BootMiddleware) *BootMiddleware {
+	res := &BootMiddleware{}
+	bytes, _ := json.Marshal(config)
+	json.Unmarshal(bytes, res)
+	return res
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/jwt"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/panic"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const utReconfigBootStr = `
+gin:
+  - name: ut-reconfig
+    port: 0
+    enabled: true
+    commonService:
+      enabled: true
+      auth:
+        basic: ["admin:secret"]
+    middleware:
+      logging:
+        enabled: true
+      prom:
+        enabled: true
+      meta:
+        enabled: true
+        prefix: "ut"
+      auth:
+        enabled: true
+        basic: ["user:pass"]
+        ignore: ["/rk/v1"]
+`
+
+func TestGinEntry_ReconfigureMiddleware(t *testing.T) {
+	entry := newBootstrappedTestEntry(t, utReconfigBootStr)
+
+	assert.Equal(t, []string{"logging", "panic", "meta", "auth"}, entry.ListReconfigurableMiddlewares())
+
+	w := serveTest(entry, http.MethodGet, "/ut", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotEmpty(t, w.Header().Get("X-Ut-App-Name"))
+
+	// disable auth
+	config := entry.GetMiddlewareConfig()
+	config.Auth.Enabled = false
+	assert.Nil(t, entry.ReconfigureMiddleware("auth", config))
+	assert.False(t, entry.GetMiddlewareConfig().Auth.Enabled)
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/ut", "", nil).Code)
+
+	// change meta prefix
+	config = entry.GetMiddlewareConfig()
+	config.Meta.Prefix = "new"
+	assert.Nil(t, entry.ReconfigureMiddleware("meta", config))
+	w = serveTest(entry, http.MethodGet, "/ut", "", nil)
+	assert.NotEmpty(t, w.Header().Get("X-New-App-Name"))
+
+	// change panic format
+	entry.Router.GET("/ut-panic", func(*gin.Context) {
+		panic("ut")
+	})
+	config = entry.GetMiddlewareConfig()
+	config.Panic.Format = rkginpanic.FormatProblem
+	assert.Nil(t, entry.ReconfigureMiddleware("panic", config))
+	w = serveTest(entry, http.MethodGet, "/ut-panic", "", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, rkginpanic.MIMEProblemJSON, w.Header().Get("Content-Type"))
+
+	config.Panic.Format = "bogus"
+	assert.NotNil(t, entry.ReconfigureMiddleware("panic", config))
+	assert.Equal(t, rkginpanic.FormatProblem, entry.GetMiddlewareConfig().Panic.Format)
+
+	// invalid
+	assert.NotNil(t, entry.ReconfigureMiddleware("meta", nil))
+	assert.NotNil(t, entry.ReconfigureMiddleware("prom", config))
+	assert.NotNil(t, entry.ReconfigureMiddleware("cors", config))
+}
+
+func TestGinEntry_MiddlewareHandler(t *testing.T) {
+	entry := newBootstrappedTestEntry(t, utReconfigBootStr)
+
+	assert.Equal(t, "/rk/v1/middleware", entry.MiddlewarePath())
+
+	// credentials of common service required
+	w := serveTest(entry, http.MethodGet, "/rk/v1/middleware", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// list
+	w = serveTest(entry, http.MethodGet, "/rk/v1/middleware", "", utCommonServiceAuth)
+	assert.Equal(t, http.StatusOK, w.Code)
+	config := &BootMiddleware{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), config))
+	assert.True(t, config.Auth.Enabled)
+
+	// get
+	w = serveTest(entry, http.MethodGet, "/rk/v1/middleware/meta", "", utCommonServiceAuth)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"prefix":"ut"`)
+
+	w = serveTest(entry, http.MethodGet, "/rk/v1/middleware/prom", "", utCommonServiceAuth)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// partial update
+	w = serveTest(entry, http.MethodPut, "/rk/v1/middleware/auth", `{"enabled":false}`, utCommonServiceAuth)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"basic":["user:******"]`)
+	assert.Equal(t, []string{"user:pass"}, entry.GetMiddlewareConfig().Auth.Basic)
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/ut", "", nil).Code)
+
+	// invalid
+	w = serveTest(entry, http.MethodPut, "/rk/v1/middleware/auth", `invalid`, utCommonServiceAuth)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serveTest(entry, http.MethodPut, "/rk/v1/middleware/cors", `{"enabled":true}`, utCommonServiceAuth)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// middleware which failed to build is kept
+	w = serveTest(entry, http.MethodPut, "/rk/v1/middleware/logging", `{"format":"bogus"}`, utCommonServiceAuth)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, entry.GetMiddlewareConfig().Logging.Format)
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/ut", "", nil).Code)
+}
+
+func TestGinEntry_MiddlewareHandler_WithoutCommonServiceAuth(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-reconfig
+    port: 0
+    enabled: true
+    commonService:
+      enabled: true
+    middleware:
+      meta:
+        enabled: true
+`
+	entry := newBootstrappedTestEntry(t, bootStr)
+
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/rk/v1/middleware/meta", "", nil).Code)
+	assert.NotEqual(t, http.StatusOK, serveTest(entry, http.MethodPut, "/rk/v1/middleware/meta", `{}`, nil).Code)
+}
+
+func TestRedactMiddlewareConfig(t *testing.T) {
+	assert.Nil(t, redactMiddlewareConfig(nil))
+
+	config := &BootMiddleware{}
+	config.Auth.Basic = []string{"user:pass"}
+	config.Auth.ApiKey = []string{"key"}
+	config.Jwt.Symmetric = &rkmidjwt.SymmetricConfig{Token: "token"}
+	config.Logging.Sinks = []BootAccessLogSink{{Password: "pass"}}
+
+	res := redactMiddlewareConfig(config)
+	assert.Equal(t, []string{"user:" + redactedValue}, res.Auth.Basic)
+	assert.Equal(t, []string{redactedValue}, res.Auth.ApiKey)
+	assert.Equal(t, redactedValue, res.Jwt.Symmetric.Token)
+	assert.Equal(t, redactedValue, res.Logging.Sinks[0].Password)
+
+	// origin config is untouched
+	assert.Equal(t, "token", config.Jwt.Symmetric.Token)
+	assert.Equal(t, "pass", config.Logging.Sinks[0].Password)
+}
+
+func TestGinEntry_MiddlewareHandler_WithoutConfig(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-reconfig"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Nil(t, entry.GetMiddlewareConfig())
+	assert.Empty(t, entry.ListReconfigurableMiddlewares())
+	assert.Empty(t, entry.MiddlewarePath())
+
+	entry.Router.PUT("/middleware/:name", entry.MiddlewareHandler)
+	w := serveTest(entry, http.MethodPut, "/middleware/auth", `{}`, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSwappableHandler(t *testing.T) {
+	h := newSwappableHandler(nil)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	h.handle(ctx)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/jwt"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/panic"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestMiddleware builds built-in middleware with name from config and registers shutdown hooks of it,
+// test fails if error occurs.
+func newTestMiddleware(t *testing.T, name string, config *BootMiddleware, entryName string) gin.HandlerFunc {
+	hooks := &pendingHooks{}
+	handler, err := newBuiltInMiddleware(name, config, entryName, nil, nil, nil, nil, hooks, nil)
+	assert.Nil(t, err)
+	hooks.commit()
+	return handler
+}
+
+func TestBootMiddlewareScope_Match(t *testing.T) {
+	// empty scope matches everything
+	scope := &BootMiddlewareScope{}
+	assert.True(t, scope.IsEmpty())
+	assert.True(t, scope.Match("/any"))
+
+	scope = &BootMiddlewareScope{
+		Paths:      []string{"/login", "/v1/user/*"},
+		PathPrefix: []string{"/api/"},
+	}
+	assert.False(t, scope.IsEmpty())
+	assert.True(t, scope.Match("/login"))
+	assert.True(t, scope.Match("/v1/user/123"))
+	assert.True(t, scope.Match("/api/v1/orders"))
+	assert.False(t, scope.Match("/public/index.html"))
+	assert.False(t, scope.Match("/v1/user/123/orders"))
+
+	// prefix matches on path segment boundary
+	scope = &BootMiddlewareScope{
+		PathPrefix: []string{"/v1"},
+	}
+	assert.True(t, scope.Match("/v1"))
+	assert.True(t, scope.Match("/v1/user"))
+	assert.False(t, scope.Match("/v10/user"))
+}
+
+func TestBootMiddlewareScope_Wrap(t *testing.T) {
+	called := false
+	handler := func(ctx *gin.Context) {
+		called = true
+	}
+
+	router := gin.New()
+	scope := &BootMiddlewareScope{PathPrefix: []string{"/api/"}}
+	router.Use(scope.Wrap(handler))
+	router.GET("/api/ut", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	router.GET("/public/ut", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	// skipped, but next handler still called
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/ut", nil))
+	assert.False(t, called)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// matched
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ut", nil))
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestNewBuiltInMiddleware_Panic(t *testing.T) {
+	config := &BootMiddleware{Panic: BootMiddlewarePanic{Format: "problem"}}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "panic", config, "ut-panic"))
+	router.GET("/ut", func(ctx *gin.Context) {
+		panic("ut panic")
+	})
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ut", nil))
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, rkginpanic.MIMEProblemJSON, resp.Header().Get("Content-Type"))
+
+	// reconfigurable
+	section, err := getMiddlewareSection("panic", config)
+	assert.Nil(t, err)
+	assert.Equal(t, &config.Panic, section)
+}
+
+func TestMatchIgnorePattern(t *testing.T) {
+	patterns := []string{"/rk/v1/*", "*.js"}
+
+	assert.True(t, matchIgnorePattern(patterns, "/rk/v1/ready"))
+	assert.True(t, matchIgnorePattern(patterns, "/static/js/app.js"))
+	assert.False(t, matchIgnorePattern(patterns, "/rk/v1/sub/path"))
+	assert.False(t, matchIgnorePattern(patterns, "/v1/user"))
+}
+
+func TestRegisterGinEntryYAML_WithMiddlewareScope(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-scope
+    port: 1949
+    enabled: true
+    middleware:
+      auth:
+        enabled: true
+        basic: ["user:pass"]
+        scope:
+          pathPrefix: ["/api/"]
+      timeout:
+        enabled: true
+        paths:
+          - path: "/api/slow"
+            timeoutMs: 1000
+        scope:
+          paths: ["/api/slow"]
+`
+	config := &BootGin{}
+	rkentry.UnmarshalBootYAML([]byte(bootStr), config)
+	assert.True(t, config.Gin[0].Middleware.Auth.Enabled)
+	assert.Equal(t, []string{"user:pass"}, config.Gin[0].Middleware.Auth.Basic)
+	assert.Equal(t, []string{"/api/"}, config.Gin[0].Middleware.Auth.Scope.PathPrefix)
+	assert.Len(t, config.Gin[0].Middleware.Timeout.Paths, 1)
+	assert.Equal(t, []string{"/api/slow"}, config.Gin[0].Middleware.Timeout.Scope.Paths)
+
+	entries := RegisterGinEntryYAML([]byte(bootStr))
+	entry := entries["ut-scope"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.Router.GET("/api/ut", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	entry.Router.GET("/public/ut", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	// auth only applies to /api/
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ut", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/ut", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestNewBuiltInMiddleware_WithError(t *testing.T) {
+	defer assertNotPanic(t)
+
+	// invalid format of logging middleware
+	config := &BootMiddleware{}
+	config.Logging.Enabled = true
+	config.Logging.Format = "xml"
+	handler, err := newBuiltInMiddleware("logging", config, "ut-invalid-logging", nil, nil, nil, nil, &pendingHooks{}, nil)
+	assert.NotNil(t, err)
+	assert.Nil(t, handler)
+
+	// rk-entry shuts down process with invalid jwt config
+	config = &BootMiddleware{}
+	config.Jwt.Enabled = true
+	config.Jwt.Symmetric = &rkmidjwt.SymmetricConfig{TokenPath: "ut-missing-token"}
+	handler, err = newBuiltInMiddleware("jwt", config, "ut-invalid-jwt", nil, nil, nil, nil, &pendingHooks{}, nil)
+	assert.NotNil(t, err)
+	assert.Nil(t, handler)
+
+	// missing custom middleware
+	config = &BootMiddleware{Custom: []BootMiddlewareCustom{{Name: "ut-missing", Enabled: true}}}
+	_, err = newMiddlewareChain(config, "ut-missing-custom", nil, nil, nil, nil, &pendingHooks{}, nil)
+	assert.NotNil(t, err)
+}
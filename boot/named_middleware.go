@@ -0,0 +1,85 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"sync"
+)
+
+var namedMiddlewares = &namedMiddlewareRegistry{
+	middlewares: make(map[string]gin.HandlerFunc),
+}
+
+// namedMiddlewareRegistry keeps user defined middlewares which could be referenced by name in boot config.
+type namedMiddlewareRegistry struct {
+	lock        sync.RWMutex
+	middlewares map[string]gin.HandlerFunc
+}
+
+// BootMiddlewareCustom boot config of middleware registered with RegisterNamedMiddleware.
+type BootMiddlewareCustom struct {
+	Name    string              `yaml:"name" json:"name"`
+	Enabled bool                `yaml:"enabled" json:"enabled"`
+	Scope   BootMiddlewareScope `yaml:"scope" json:"scope"`
+	Locale  string              `yaml:"locale" json:"locale"`
+}
+
+// RegisterNamedMiddleware register middleware with name, so it could be enabled and ordered in boot config.
+//
+// Middleware with same name will be replaced, names of built-in middlewares and maintenance are reserved.
+// This function should be called before GinEntry registered from boot config.
+//
+// Example:
+//
+//	rkgin.RegisterNamedMiddleware("tenant", tenantMiddleware)
+//
+//	gin:
+//	  - name: greeter
+//	    middleware:
+//	      custom:
+//	        - name: tenant
+//	          enabled: true
+//	      order: ["logging", "tenant", "prom"]
+func RegisterNamedMiddleware(name string, mid gin.HandlerFunc) {
+	if len(name) < 1 || mid == nil || isBuiltInMiddleware(name) || name == "maintenance" {
+		return
+	}
+
+	namedMiddlewares.lock.Lock()
+	defer namedMiddlewares.lock.Unlock()
+
+	namedMiddlewares.middlewares[name] = mid
+}
+
+// GetNamedMiddleware returns middleware registered with RegisterNamedMiddleware, nil if not exist.
+func GetNamedMiddleware(name string) gin.HandlerFunc {
+	namedMiddlewares.lock.RLock()
+	defer namedMiddlewares.lock.RUnlock()
+
+	return namedMiddlewares.middlewares[name]
+}
+
+// RemoveNamedMiddleware remove middleware registered with RegisterNamedMiddleware.
+func RemoveNamedMiddleware(name string) {
+	namedMiddlewares.lock.Lock()
+	defer namedMiddlewares.lock.Unlock()
+
+	delete(namedMiddlewares.middlewares, name)
+}
+
+// ListNamedMiddlewares returns names of middlewares registered with RegisterNamedMiddleware.
+func ListNamedMiddlewares() []string {
+	namedMiddlewares.lock.RLock()
+	defer namedMiddlewares.lock.RUnlock()
+
+	res := make([]string, 0, len(namedMiddlewares.middlewares))
+	for k := range namedMiddlewares.middlewares {
+		res = append(res, k)
+	}
+
+	return res
+}
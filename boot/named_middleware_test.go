@@ -0,0 +1,137 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterNamedMiddleware(t *testing.T) {
+	mid := func(ctx *gin.Context) {}
+
+	// invalid inputs
+	RegisterNamedMiddleware("", mid)
+	RegisterNamedMiddleware("ut-nil", nil)
+	RegisterNamedMiddleware("logging", mid)
+	assert.Nil(t, GetNamedMiddleware("ut-nil"))
+	assert.Nil(t, GetNamedMiddleware("logging"))
+
+	// happy case
+	RegisterNamedMiddleware("ut-mid", mid)
+	assert.NotNil(t, GetNamedMiddleware("ut-mid"))
+	assert.Contains(t, ListNamedMiddlewares(), "ut-mid")
+
+	RemoveNamedMiddleware("ut-mid")
+	assert.Nil(t, GetNamedMiddleware("ut-mid"))
+}
+
+func TestOrderMiddlewares(t *testing.T) {
+	res := make([]string, 0)
+	newHandler := func(name string) *namedHandler {
+		return &namedHandler{
+			name: name,
+			handler: func(*gin.Context) {
+				res = append(res, name)
+			},
+		}
+	}
+
+	inters := []*namedHandler{newHandler("logging"), newHandler("panic"), newHandler("prom"), newHandler("ut-mid")}
+
+	for _, h := range orderMiddlewares(inters, []string{"ut-mid", "logging", "not-exist"}) {
+		h(nil)
+	}
+
+	assert.Equal(t, []string{"ut-mid", "logging", "panic", "prom"}, res)
+}
+
+func TestRegisterGinEntryYAML_WithCustomMiddleware(t *testing.T) {
+	defer assertNotPanic(t)
+
+	order := make([]string, 0)
+	RegisterNamedMiddleware("ut-first", func(ctx *gin.Context) {
+		order = append(order, "ut-first")
+	})
+	RegisterNamedMiddleware("ut-second", func(ctx *gin.Context) {
+		order = append(order, "ut-second")
+	})
+	RegisterNamedMiddleware("ut-disabled", func(ctx *gin.Context) {
+		order = append(order, "ut-disabled")
+	})
+	defer RemoveNamedMiddleware("ut-first")
+	defer RemoveNamedMiddleware("ut-second")
+	defer RemoveNamedMiddleware("ut-disabled")
+
+	bootStr := `
+gin:
+  - name: ut-custom
+    port: 1949
+    enabled: true
+    middleware:
+      custom:
+        - name: ut-second
+          enabled: true
+        - name: ut-first
+          enabled: true
+        - name: ut-disabled
+          enabled: false
+      order: ["ut-first"]
+`
+	entries := RegisterGinEntryYAML([]byte(bootStr))
+	entry := entries["ut-custom"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.Router.GET("/ut", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ut", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"ut-first", "ut-second"}, order)
+}
+
+func TestRegisterGinEntryYAML_WithMissingCustomMiddleware(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-custom-missing
+    port: 1949
+    enabled: true
+    middleware:
+      custom:
+        - name: ut-missing
+          enabled: true
+`
+	entries, err := RegisterGinEntryYAMLWithError([]byte(bootStr))
+	assert.NotNil(t, err)
+	assert.Nil(t, entries)
+	assert.Nil(t, GetGinEntry("ut-custom-missing"))
+}
+
+func TestRegisterGinEntryYAML_WithDuplicateCustomMiddleware(t *testing.T) {
+	RegisterNamedMiddleware("ut-duplicate", func(ctx *gin.Context) {})
+	defer RemoveNamedMiddleware("ut-duplicate")
+
+	bootStr := `
+gin:
+  - name: ut-custom-duplicate
+    port: 1949
+    enabled: true
+    middleware:
+      custom:
+        - name: ut-duplicate
+          enabled: true
+        - name: ut-duplicate
+          enabled: true
+`
+	entries, err := RegisterGinEntryYAMLWithError([]byte(bootStr))
+	assert.NotNil(t, err)
+	assert.Nil(t, entries)
+	assert.Nil(t, GetGinEntry("ut-custom-duplicate"))
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"gopkg.in/yaml.v2"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// OpenApiPath returns path of raw spec handler which sits next to common service paths, /rk/v1/openapi by default.
+//
+// Spec is served at <path>.json and <path>.yaml.
+func (entry *GinEntry) OpenApiPath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "openapi")
+}
+
+// OpenApiHandler serves spec as JSON or YAML depending on suffix of request path, with ETag for conditional requests.
+//
+// Spec could be selected by name listed in swagger-config.json with query parameter name,
+// otherwise merged spec, generated spec or the first spec is served in order.
+func (entry *GinEntry) OpenApiHandler(ctx *gin.Context) {
+	spec := entry.selectOpenApiSpec(ctx.Query("name"))
+	if spec == nil {
+		ctx.JSON(http.StatusNotFound, rkmid.GetErrorBuilder().New(http.StatusNotFound, "Spec not found"))
+		return
+	}
+
+	content, contentType, err := entry.loadSwSpec(spec)
+	if err != nil {
+		ctx.JSON(http.StatusBadGateway, rkmid.GetErrorBuilder().New(http.StatusBadGateway, "Failed to load spec", err))
+		return
+	}
+
+	asYaml := strings.HasSuffix(ctx.Request.URL.Path, ".yaml")
+	if content, err = convertOpenApiSpec(content, strings.Contains(contentType, "json"), asYaml); err != nil {
+		ctx.JSON(http.StatusInternalServerError, rkmid.GetErrorBuilder().New(http.StatusInternalServerError, "Failed to convert spec", err))
+		return
+	}
+
+	sum := sha256.Sum256(content)
+	ctx.Header("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	ctx.Header("cache-control", "no-cache")
+	if asYaml {
+		ctx.Header("Content-Type", "application/yaml")
+	} else {
+		ctx.Header("Content-Type", "application/json")
+	}
+
+	// If-None-Match is handled with ETag
+	http.ServeContent(ctx.Writer, ctx.Request, "", time.Time{}, bytes.NewReader(content))
+}
+
+// selectOpenApiSpec returns spec with name, or merged, generated and the first one in order if name is empty.
+func (entry *GinEntry) selectOpenApiSpec(name string) *swSpec {
+	if !entry.IsSwEnabled() {
+		return nil
+	}
+
+	if len(name) > 0 {
+		return entry.swSpecStore.get(name)
+	}
+
+	for _, key := range []string{
+		entry.SwEntry.GetName() + "-merged.json",
+		entry.SwEntry.GetName() + "-generated.openapi.json",
+	} {
+		if spec := entry.swSpecStore.get(key); spec != nil {
+			return spec
+		}
+	}
+
+	if urls := entry.swSpecStore.listUrls(); len(urls) > 0 {
+		return entry.swSpecStore.get(urls[0].Name)
+	}
+
+	return nil
+}
+
+// convertOpenApiSpec converts spec between JSON and YAML, content is returned as it is if already in expected format.
+//
+// Order of keys is kept while converting JSON to YAML.
+func convertOpenApiSpec(content []byte, isJson, toYaml bool) ([]byte, error) {
+	if isJson != toYaml {
+		return content, nil
+	}
+
+	if toYaml {
+		doc := yaml.MapSlice{}
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(doc)
+	}
+
+	doc := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(toJsonCompatible(doc))
+}
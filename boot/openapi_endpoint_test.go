@@ -0,0 +1,91 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+// newOpenApiTestEntry bootstrap GinEntry with common service and swagger enabled, sw section appended into boot config.
+func newOpenApiTestEntry(t *testing.T, name, sw string) *GinEntry {
+	return newBootstrappedTestEntry(t, `
+gin:
+  - name: `+name+`
+    port: 0
+    enabled: true
+    commonService:
+      enabled: true
+    sw:
+      enabled: true
+`+sw)
+}
+
+func TestGinEntry_OpenApiPath(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-openapi-path"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.Empty(t, entry.OpenApiPath())
+
+	entry = newOpenApiTestEntry(t, "ut-openapi-path", "")
+	assert.Equal(t, "/rk/v1/openapi", entry.OpenApiPath())
+}
+
+func TestGinEntry_OpenApiHandler(t *testing.T) {
+	entry := newOpenApiTestEntry(t, "ut-openapi-handler", "")
+
+	// without spec
+	assert.Equal(t, http.StatusNotFound, serveTest(entry, http.MethodGet, "/rk/v1/openapi.json", "", nil).Code)
+
+	entry.swSpecStore.add("a.yaml", "/sw/a.yaml", &swSpec{content: []byte(utOpenApi3Yaml), contentType: "application/yaml"})
+	entry.swSpecStore.add("b.json", "/sw/b.json", &swSpec{content: []byte(`{"swagger":"2.0","info":{"title":"b"}}`), contentType: "application/json"})
+
+	// first spec converted into JSON
+	w := serveTest(entry, http.MethodGet, "/rk/v1/openapi.json", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"openapi":"3.0.0","info":{"title":"ut","version":"1.0.0"},"paths":{}}`, w.Body.String())
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// conditional request
+	w = serveTest(entry, http.MethodGet, "/rk/v1/openapi.json", "", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// first spec as it is
+	w = serveTest(entry, http.MethodGet, "/rk/v1/openapi.yaml", "", nil)
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.Equal(t, utOpenApi3Yaml, w.Body.String())
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	// selected spec converted into YAML with order of keys kept
+	w = serveTest(entry, http.MethodGet, "/rk/v1/openapi.yaml?name=b.json", "", nil)
+	assert.Equal(t, "swagger: \"2.0\"\ninfo:\n  title: b\n", w.Body.String())
+
+	// not exist
+	assert.Equal(t, http.StatusNotFound, serveTest(entry, http.MethodGet, "/rk/v1/openapi.json?name=c.json", "", nil).Code)
+
+	// invalid spec
+	entry.swSpecStore.add("d.json", "/sw/d.json", &swSpec{content: []byte(`{`), contentType: "application/json"})
+	assert.Equal(t, http.StatusInternalServerError, serveTest(entry, http.MethodGet, "/rk/v1/openapi.yaml?name=d.json", "", nil).Code)
+
+	// merged spec takes precedence
+	entry.swSpecStore.add("ut-openapi-handler-merged.json", "/sw/ut-openapi-handler-merged.json",
+		&swSpec{content: []byte(`{"swagger":"2.0"}`), contentType: "application/json"})
+	assert.Equal(t, `{"swagger":"2.0"}`, serveTest(entry, http.MethodGet, "/rk/v1/openapi.json", "", nil).Body.String())
+}
+
+func TestGinEntry_selectOpenApiSpec(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-openapi-select"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.Nil(t, entry.selectOpenApiSpec(""))
+
+	entry = newOpenApiTestEntry(t, "ut-openapi-select", `
+      generateSpec: true
+`)
+	assert.NotNil(t, entry.selectOpenApiSpec("").generate)
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RouteDoc annotation of route used while generating OpenAPI spec.
+type RouteDoc struct {
+	Summary     string           `json:"summary" yaml:"summary"`
+	Description string           `json:"description" yaml:"description"`
+	Tags        []string         `json:"tags" yaml:"tags"`
+	Deprecated  bool             `json:"deprecated" yaml:"deprecated"`
+	Params      []*RouteDocParam `json:"params" yaml:"params"`
+	Responses   map[int]string   `json:"responses" yaml:"responses"`
+}
+
+// RouteDocParam parameter of route, path parameters are generated from route automatically.
+type RouteDocParam struct {
+	Name        string `json:"name" yaml:"name"`
+	In          string `json:"in" yaml:"in"`
+	Description string `json:"description" yaml:"description"`
+	Required    bool   `json:"required" yaml:"required"`
+	Type        string `json:"type" yaml:"type"`
+}
+
+// routeDocRegistry keeps RouteDoc keyed with "METHOD path".
+type routeDocRegistry struct {
+	lock sync.RWMutex
+	docs map[string]*RouteDoc
+}
+
+func newRouteDocRegistry() *routeDocRegistry {
+	return &routeDocRegistry{
+		docs: make(map[string]*RouteDoc),
+	}
+}
+
+// AddRouteDoc annotate route with method and path, path should be the same as registered in Router, like /v1/user/:id.
+//
+// Annotation could be added before or after route registered, since spec is generated on every request.
+//
+//	entry.Router.GET("/v1/user/:id", getUser)
+//	entry.AddRouteDoc(http.MethodGet, "/v1/user/:id", &rkgin.RouteDoc{
+//	  Summary: "Get user",
+//	  Tags:    []string{"user"},
+//	})
+func (entry *GinEntry) AddRouteDoc(method, routePath string, doc *RouteDoc) {
+	if doc == nil {
+		return
+	}
+
+	entry.routeDocs.lock.Lock()
+	defer entry.routeDocs.lock.Unlock()
+
+	entry.routeDocs.docs[strings.ToUpper(method)+" "+routePath] = doc
+}
+
+// GetRouteDoc returns RouteDoc added with AddRouteDoc, nil if not exist.
+func (entry *GinEntry) GetRouteDoc(method, routePath string) *RouteDoc {
+	entry.routeDocs.lock.RLock()
+	defer entry.routeDocs.lock.RUnlock()
+
+	return entry.routeDocs.docs[strings.ToUpper(method)+" "+routePath]
+}
+
+// WithSwGenerateSpec generate OpenAPI spec from routes registered in Router, and serve it with SwEntry.
+func WithSwGenerateSpec(enabled bool) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.swGenerateSpec = enabled
+	}
+}
+
+// GenerateOpenApiSpec generates OpenAPI 3 document in JSON from routes registered in Router and RouteDoc.
+//
+// Routes of swagger, docs, static file, prometheus and pprof are excluded.
+func (entry *GinEntry) GenerateOpenApiSpec() ([]byte, error) {
+	version := "local"
+	if appInfo := rkentry.GlobalAppCtx.GetAppInfoEntry(); appInfo != nil && len(appInfo.Version) > 0 {
+		version = appInfo.Version
+	}
+
+	paths := make(map[string]map[string]interface{})
+	excluded := entry.internalPathPrefixes()
+
+	for _, api := range entry.ListApis() {
+		if hasAnyPrefix(api.Path, excluded) {
+			continue
+		}
+
+		specPath, params := toOpenApiPath(api.Path)
+		if _, ok := paths[specPath]; !ok {
+			paths[specPath] = make(map[string]interface{})
+		}
+
+		paths[specPath][strings.ToLower(api.Method)] = newOpenApiOperation(api, params, entry.GetRouteDoc(api.Method, api.Path))
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       entry.entryName,
+			"description": entry.entryDescription,
+			"version":     version,
+		},
+		"paths": paths,
+	})
+}
+
+// initSwGeneratedSpec adds generated spec into swSpecStore if enabled.
+func (entry *GinEntry) initSwGeneratedSpec() {
+	if !entry.swGenerateSpec {
+		return
+	}
+
+	key := entry.SwEntry.GetName() + "-generated.openapi.json"
+	entry.swSpecStore.add(key, path.Join(entry.SwEntry.Path, key), &swSpec{
+		generate: entry.GenerateOpenApiSpec,
+	})
+}
+
+// internalPathPrefixes returns paths of internal entries which serve UI or files instead of APIs.
+func (entry *GinEntry) internalPathPrefixes() []string {
+	res := make([]string, 0)
+
+	if entry.IsSwEnabled() {
+		res = append(res, entry.SwEntry.Path)
+	}
+	if entry.IsDocsEnabled() {
+		res = append(res, entry.DocsEntry.Path)
+	}
+	if entry.IsRedocEnabled() {
+		res = append(res, entry.RedocEntry.Path)
+	}
+	if entry.IsRapiDocEnabled() {
+		res = append(res, entry.RapiDocEntry.Path)
+	}
+	if entry.IsStaticFileHandlerEnabled() {
+		res = append(res, entry.StaticFileEntry.Path)
+	}
+	if entry.IsPromEnabled() {
+		res = append(res, entry.PromEntry.Path)
+	}
+	if entry.IsPProfEnabled() {
+		res = append(res, entry.PProfEntry.Path)
+	}
+
+	for i := range res {
+		res[i] = path.Join("/", res[i])
+	}
+
+	return res
+}
+
+// hasAnyPrefix returns true if p equals to one of prefixes or is under one of them.
+func hasAnyPrefix(p string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if p == prefix || strings.HasPrefix(p, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toOpenApiPath converts gin path like /user/:id/*file into /user/{id}/{file} and returns names of path parameters.
+func toOpenApiPath(ginPath string) (string, []string) {
+	segments := strings.Split(ginPath, "/")
+	params := make([]string, 0)
+
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			params = append(params, seg[1:])
+			segments[i] = "{" + seg[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+// newOpenApiOperation creates operation object of OpenAPI, handler name is used as summary if doc is nil.
+func newOpenApiOperation(api *ApiInfo, pathParams []string, doc *RouteDoc) map[string]interface{} {
+	if doc == nil {
+		doc = &RouteDoc{}
+	}
+
+	op := map[string]interface{}{
+		"operationId": api.Method + " " + api.Path,
+	}
+
+	summary := doc.Summary
+	if len(summary) < 1 {
+		summary = api.Handler[strings.LastIndex(api.Handler, "/")+1:]
+	}
+	op["summary"] = summary
+
+	if len(doc.Description) > 0 {
+		op["description"] = doc.Description
+	}
+	if len(doc.Tags) > 0 {
+		op["tags"] = doc.Tags
+	}
+	if doc.Deprecated {
+		op["deprecated"] = true
+	}
+
+	// parameters
+	params := make([]interface{}, 0)
+	for _, name := range pathParams {
+		params = append(params, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	for _, param := range doc.Params {
+		if param == nil || param.In == "path" {
+			continue
+		}
+
+		paramType := param.Type
+		if len(paramType) < 1 {
+			paramType = "string"
+		}
+
+		params = append(params, map[string]interface{}{
+			"name":        param.Name,
+			"in":          param.In,
+			"description": param.Description,
+			"required":    param.Required,
+			"schema":      map[string]interface{}{"type": paramType},
+		})
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	// responses
+	responses := make(map[string]interface{})
+	for code, desc := range doc.Responses {
+		responses[strconv.Itoa(code)] = map[string]interface{}{"description": desc}
+	}
+	if len(responses) < 1 {
+		responses[strconv.Itoa(http.StatusOK)] = map[string]interface{}{"description": http.StatusText(http.StatusOK)}
+	}
+	op["responses"] = responses
+
+	return op
+}
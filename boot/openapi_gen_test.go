@@ -0,0 +1,139 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGinEntry_GenerateOpenApiSpec(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-openapi"),
+		WithPort(0),
+		WithSwEntry(rkentry.RegisterSWEntry(&rkentry.BootSW{Enabled: true}, rkentry.WithNameSWEntry("ut-openapi"))))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	handler := func(ctx *gin.Context) {}
+	entry.Router.GET("/v1/user/:id", handler)
+	entry.Router.DELETE("/v1/user/:id", handler)
+	entry.Router.GET("/v1/files/*path", handler)
+	entry.Router.GET(entry.SwEntry.Path+"*any", handler)
+
+	// nil doc ignored
+	entry.AddRouteDoc(http.MethodGet, "/v1/files/*path", nil)
+	entry.AddRouteDoc("get", "/v1/user/:id", &RouteDoc{
+		Summary:     "Get user",
+		Description: "Get user with id",
+		Tags:        []string{"user"},
+		Deprecated:  true,
+		Params: []*RouteDocParam{
+			{Name: "fields", In: "query", Required: false},
+			{Name: "id", In: "path"},
+			nil,
+		},
+		Responses: map[int]string{
+			http.StatusOK:       "user",
+			http.StatusNotFound: "not found",
+		},
+	})
+	assert.NotNil(t, entry.GetRouteDoc(http.MethodGet, "/v1/user/:id"))
+	assert.Nil(t, entry.GetRouteDoc(http.MethodGet, "/v1/files/*path"))
+
+	bytes, err := entry.GenerateOpenApiSpec()
+	assert.Nil(t, err)
+
+	spec := struct {
+		OpenApi string `json:"openapi"`
+		Info    struct {
+			Title string `json:"title"`
+		} `json:"info"`
+		Paths map[string]map[string]struct {
+			Summary    string                       `json:"summary"`
+			Tags       []string                     `json:"tags"`
+			Deprecated bool                         `json:"deprecated"`
+			Parameters []map[string]interface{}     `json:"parameters"`
+			Responses  map[string]map[string]string `json:"responses"`
+		} `json:"paths"`
+	}{}
+	assert.Nil(t, json.Unmarshal(bytes, &spec))
+
+	assert.Equal(t, "3.0.3", spec.OpenApi)
+	assert.Equal(t, "ut-openapi", spec.Info.Title)
+	assert.Len(t, spec.Paths, 2)
+
+	// annotated route
+	get := spec.Paths["/v1/user/{id}"]["get"]
+	assert.Equal(t, "Get user", get.Summary)
+	assert.Equal(t, []string{"user"}, get.Tags)
+	assert.True(t, get.Deprecated)
+	assert.Len(t, get.Parameters, 2)
+	assert.Equal(t, "id", get.Parameters[0]["name"])
+	assert.Equal(t, "path", get.Parameters[0]["in"])
+	assert.Equal(t, "fields", get.Parameters[1]["name"])
+	assert.Equal(t, "not found", get.Responses["404"]["description"])
+
+	// route without annotation
+	del := spec.Paths["/v1/user/{id}"]["delete"]
+	assert.NotEmpty(t, del.Summary)
+	assert.Equal(t, "OK", del.Responses["200"]["description"])
+	assert.Equal(t, "path", spec.Paths["/v1/files/{path}"]["get"].Parameters[0]["name"])
+}
+
+func TestGinEntry_initSwGeneratedSpec(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-openapi-sw"),
+		WithPort(0),
+		WithSwGenerateSpec(true),
+		WithSwEntry(rkentry.RegisterSWEntry(&rkentry.BootSW{Enabled: true}, rkentry.WithNameSWEntry("ut-openapi-sw"))))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.SwEntry.Bootstrap(context.TODO())
+	entry.initSwGeneratedSpec()
+	entry.Router.GET("/sw/*any", entry.swHandler())
+
+	urls := entry.swSpecStore.listUrls()
+	assert.Len(t, urls, 1)
+	assert.Equal(t, "ut-openapi-sw-generated.openapi.json", urls[0].Name)
+
+	// routes added after bootstrap are included
+	entry.Router.GET("/v1/greeter", func(ctx *gin.Context) {})
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/"+urls[0].Name, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"/v1/greeter"`)
+	assert.NotContains(t, w.Body.String(), `"/sw/{any}"`)
+
+	// disabled
+	entry = RegisterGinEntry(WithName("ut-openapi-disabled"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	entry.initSwGeneratedSpec()
+	assert.Empty(t, entry.swSpecStore.listUrls())
+}
+
+func TestToOpenApiPath(t *testing.T) {
+	p, params := toOpenApiPath("/v1/:org/user/*file")
+	assert.Equal(t, "/v1/{org}/user/{file}", p)
+	assert.Equal(t, []string{"org", "file"}, params)
+
+	p, params = toOpenApiPath("/")
+	assert.Equal(t, "/", p)
+	assert.Empty(t, params)
+}
+
+func TestHasAnyPrefix(t *testing.T) {
+	assert.True(t, hasAnyPrefix("/sw", []string{"/sw"}))
+	assert.True(t, hasAnyPrefix("/sw/*any", []string{"/sw/"}))
+	assert.False(t, hasAnyPrefix("/swagger", []string{"/sw"}))
+}
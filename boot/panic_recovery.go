@@ -0,0 +1,36 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/error"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/panic"
+)
+
+// WithPanicRecoveryHandler provide handler of error recovered by panic middleware, which is always enabled.
+func WithPanicRecoveryHandler(handler rkginpanic.RecoveryHandler) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.SetPanicRecoveryHandler(handler)
+	}
+}
+
+// SetPanicRecoveryHandler sets handler of error recovered by panic middlewares of entry and its groups,
+// error is written in format of panic middleware if handler is nil or writes nothing.
+// This function should be called before Bootstrap() called.
+func (entry *GinEntry) SetPanicRecoveryHandler(handler rkginpanic.RecoveryHandler) {
+	entry.recoveryHandler = handler
+}
+
+// panicRecoveryHandler returns handler of panic middleware which calls handler of entry,
+// handler set after middlewares are built is called too.
+func (entry *GinEntry) panicRecoveryHandler() rkginpanic.RecoveryHandler {
+	return func(ctx *gin.Context, err rkerror.ErrorInterface) {
+		if entry.recoveryHandler != nil {
+			entry.recoveryHandler(ctx, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/error"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestWithPanicRecoveryHandler(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-panic-recovery-option"),
+		WithPanicRecoveryHandler(func(*gin.Context, rkerror.ErrorInterface) {}))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.NotNil(t, entry.recoveryHandler)
+}
+
+func TestGinEntry_SetPanicRecoveryHandler(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-panic-recovery
+    port: 1949
+    enabled: true
+    groups:
+      - name: ut-panic-recovery-group
+        prefix: /group
+`
+
+	entry := RegisterGinEntryYAML([]byte(bootStr))["ut-panic-recovery"].(*GinEntry)
+	defer entry.unregister()
+
+	entry.Router.GET("/ut-panic", func(*gin.Context) {
+		panic("ut-panic")
+	})
+	entry.ListGroups()[0].Group.GET("/ut-panic", func(*gin.Context) {
+		panic("ut-panic")
+	})
+
+	// default response without handler
+	w := serveTest(entry, http.MethodGet, "/ut-panic", "", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// handler set after middlewares are built from config
+	entry.SetPanicRecoveryHandler(func(ctx *gin.Context, err rkerror.ErrorInterface) {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"reason": "ut-domain-error"})
+	})
+	w = serveTest(entry, http.MethodGet, "/ut-panic", "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"reason":"ut-domain-error"}`, w.Body.String())
+
+	// middlewares of groups use handler of entry
+	w = serveTest(entry, http.MethodGet, "/group/ut-panic", "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"mime"
+	"net/http"
+	"path"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// profileDefaultSeconds is duration of cpu, block and mutex profile if seconds is not provided.
+	profileDefaultSeconds = 30
+	// profileMaxSeconds is the maximum duration of profile.
+	profileMaxSeconds = 300
+)
+
+// profileCapturing is 1 while a profile is being captured, cpu profile and profile rates are process wide,
+// so only one capture is allowed at a time.
+var profileCapturing int32
+
+// ProfilePath returns path of profile API which sits next to common service paths, /rk/v1/profile by default.
+func (entry *GinEntry) ProfilePath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "profile")
+}
+
+// ProfileHandler captures profile of current process and returns it as pprof file which could be opened with go tool pprof.
+//
+// Query parameter type could be cpu, heap, allocs, goroutine, block, mutex or threadcreate, cpu by default.
+// cpu, block and mutex are sampled for seconds, 30 by default and 300 at most, others are snapshots.
+// 409 is returned if another profile is being captured.
+//
+//	curl -o cpu.pprof "localhost:8080/rk/v1/profile?type=cpu&seconds=30"
+func (entry *GinEntry) ProfileHandler(ctx *gin.Context) {
+	profileType := ctx.DefaultQuery("type", "cpu")
+	sampled := profileType == "cpu" || profileType == "block" || profileType == "mutex"
+	if !sampled && pprof.Lookup(profileType) == nil {
+		ctx.JSON(http.StatusBadRequest,
+			rkmid.GetErrorBuilder().New(http.StatusBadRequest, fmt.Sprintf("Unsupported profile type %s", profileType)))
+		return
+	}
+
+	seconds := profileDefaultSeconds
+	if v := ctx.Query("seconds"); len(v) > 0 {
+		var err error
+		if seconds, err = strconv.Atoi(v); err != nil || seconds < 1 || seconds > profileMaxSeconds {
+			ctx.JSON(http.StatusBadRequest,
+				rkmid.GetErrorBuilder().New(http.StatusBadRequest,
+					fmt.Sprintf("Invalid seconds %s, should be between 1 and %d", v, profileMaxSeconds)))
+			return
+		}
+	}
+
+	if !atomic.CompareAndSwapInt32(&profileCapturing, 0, 1) {
+		ctx.JSON(http.StatusConflict,
+			rkmid.GetErrorBuilder().New(http.StatusConflict, "Another profile is being captured"))
+		return
+	}
+	defer atomic.StoreInt32(&profileCapturing, 0)
+
+	buf := new(bytes.Buffer)
+	duration := time.Duration(seconds) * time.Second
+	var err error
+	switch profileType {
+	case "cpu":
+		if err = pprof.StartCPUProfile(buf); err == nil {
+			profileSleep(ctx, duration)
+			pprof.StopCPUProfile()
+		}
+	case "block":
+		// rate of block profile could not be read, it is disabled after capture
+		runtime.SetBlockProfileRate(1)
+		profileSleep(ctx, duration)
+		err = pprof.Lookup(profileType).WriteTo(buf, 0)
+		runtime.SetBlockProfileRate(0)
+	case "mutex":
+		prev := runtime.SetMutexProfileFraction(1)
+		profileSleep(ctx, duration)
+		err = pprof.Lookup(profileType).WriteTo(buf, 0)
+		runtime.SetMutexProfileFraction(prev)
+	default:
+		err = pprof.Lookup(profileType).WriteTo(buf, 0)
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError,
+			rkmid.GetErrorBuilder().New(http.StatusInternalServerError, "Failed to capture profile", err))
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.pprof", profileType, time.Now().Format("20060102150405"))
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	ctx.Data(http.StatusOK, "application/octet-stream", buf.Bytes())
+}
+
+// profileSleep waits for duration or until client is gone.
+func profileSleep(ctx *gin.Context, duration time.Duration) {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Request.Context().Done():
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGinEntry_ProfileHandler(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-profile"),
+		WithPort(0),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{Enabled: true})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, "/rk/v1/profile", entry.ProfilePath())
+	entry.Router.GET(entry.ProfilePath(), entry.ProfileHandler)
+
+	serve := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	// pprof files are gzipped protobuf
+	assertPprof := func(w *httptest.ResponseRecorder, profileType string) {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(w.Header().Get("Content-Disposition"), "attachment; filename="+profileType+"-"))
+		reader, err := gzip.NewReader(bytes.NewReader(w.Body.Bytes()))
+		assert.Nil(t, err)
+		data, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, data)
+	}
+
+	// snapshots
+	assertPprof(serve("/rk/v1/profile?type=heap"), "heap")
+	assertPprof(serve("/rk/v1/profile?type=goroutine"), "goroutine")
+
+	// sampled
+	assertPprof(serve("/rk/v1/profile?type=cpu&seconds=1"), "cpu")
+	assertPprof(serve("/rk/v1/profile?type=mutex&seconds=1"), "mutex")
+
+	// invalid type
+	assert.Equal(t, http.StatusBadRequest, serve("/rk/v1/profile?type=unknown").Code)
+
+	// invalid seconds
+	assert.Equal(t, http.StatusBadRequest, serve("/rk/v1/profile?seconds=abc").Code)
+	assert.Equal(t, http.StatusBadRequest, serve("/rk/v1/profile?seconds=0").Code)
+	assert.Equal(t, http.StatusBadRequest, serve("/rk/v1/profile?seconds=301").Code)
+
+	// concurrent capture
+	atomic.StoreInt32(&profileCapturing, 1)
+	assert.Equal(t, http.StatusConflict, serve("/rk/v1/profile?type=heap").Code)
+	atomic.StoreInt32(&profileCapturing, 0)
+
+	// disabled common service
+	disabled := RegisterGinEntry(WithName("ut-profile-disabled"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(disabled)
+	assert.Empty(t, disabled.ProfilePath())
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/otelmetric"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/prom"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// promBackendPrometheus records metrics with prometheus client, which is the default backend.
+	promBackendPrometheus = "prometheus"
+	// promBackendOtel records metrics with OpenTelemetry metrics API.
+	promBackendOtel = "otel"
+
+	// meterProviderShutdownTimeout is timeout of exporting metrics left while interrupting entry.
+	meterProviderShutdownTimeout = 5 * time.Second
+)
+
+// newMetricsSet returns metrics set registered into registerer which is owned by entry,
+// nil if prom middleware is disabled, backend of it is not prometheus or registerer is nil.
+func (config *BootMiddlewareProm) newMetricsSet(registerer prometheus.Registerer) (*rkmidprom.MetricsSet, error) {
+	if !config.Enabled || !IsLocaleValid(config.Locale) || registerer == nil {
+		return nil, nil
+	}
+
+	switch strings.ToLower(config.Backend) {
+	case "", promBackendPrometheus:
+		return rkginprom.NewMetricsSet(registerer)
+	}
+
+	return nil, nil
+}
+
+// newMeterProvider returns MeterProvider which exports metrics with Otlp and is shut down by entry on Interrupt,
+// nil if prom middleware is disabled, backend of it is not otel or Otlp is disabled.
+func (entry *GinEntry) newMeterProvider(config *BootMiddlewareProm, entryName string) (metric.MeterProvider, error) {
+	if !config.Enabled || !IsLocaleValid(config.Locale) || !config.Otlp.Enabled ||
+		strings.ToLower(config.Backend) != promBackendOtel {
+		return nil, nil
+	}
+
+	provider, err := rkginotelmetric.NewMeterProvider(&config.Otlp, entryName, GinEntryType)
+	if err != nil {
+		return nil, err
+	}
+	entry.meterProviders = append(entry.meterProviders, provider)
+
+	return provider, nil
+}
+
+// shutdownMeterProviders exports metrics left in MeterProviders of entry and its groups, and shuts down exporters.
+func (entry *GinEntry) shutdownMeterProviders(ctx context.Context, logger *zap.Logger) {
+	ctx, cancel := context.WithTimeout(ctx, meterProviderShutdownTimeout)
+	defer cancel()
+
+	for _, provider := range entry.meterProviders {
+		if err := provider.Shutdown(ctx); err != nil {
+			logger.Warn("Error occurs while shutting down meter provider.",
+				zap.String("entryName", entry.entryName), zap.Error(err))
+		}
+	}
+	entry.meterProviders = nil
+}
+
+// newHandler returns metrics middleware of Backend, prometheus by default.
+//
+// Metrics of prometheus backend are recorded into metricsSet owned by entry, or metrics set kept by rkmidprom if nil.
+// Metrics of otel backend are recorded with meterProvider owned by entry if Otlp is enabled, or global MeterProvider
+// of OpenTelemetry which should be set with exporter by application, histogram and slo are not used by otel backend.
+func (config *BootMiddlewareProm) newHandler(entryName string, metricsSet *rkmidprom.MetricsSet,
+	meterProvider metric.MeterProvider) (gin.HandlerFunc, error) {
+	var handler gin.HandlerFunc
+	var err error
+
+	switch strings.ToLower(config.Backend) {
+	case "", promBackendPrometheus:
+		handler, err = rkginprom.MiddlewareWithConfig(&rkginprom.Config{
+			Histogram:  config.Histogram,
+			SLO:        config.SLO,
+			Tenant:     config.Tenant,
+			MetricsSet: metricsSet,
+		},
+			rkmidprom.WithEntryNameAndType(entryName, GinEntryType),
+			rkmidprom.WithLabelerType(rkmidprom.LabelerTypeHttp),
+			rkmidprom.WithPathToIgnore(config.Ignore...))
+	case promBackendOtel:
+		handler, err = rkginotelmetric.Middleware(
+			rkginotelmetric.WithEntryNameAndType(entryName, GinEntryType),
+			rkginotelmetric.WithMeterProvider(meterProvider),
+			rkginotelmetric.WithPathToIgnore(config.Ignore...))
+	default:
+		return nil, fmt.Errorf("unsupported backend %s of prom middleware, should be one of prometheus or otel", config.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return config.wrapIgnoreRegex(handler)
+}
+
+// wrapIgnoreRegex returns handler which skips metrics of requests whose path matches any of IgnoreRegex,
+// error is returned if any of IgnoreRegex is invalid.
+func (config *BootMiddlewareProm) wrapIgnoreRegex(handler gin.HandlerFunc) (gin.HandlerFunc, error) {
+	if len(config.IgnoreRegex) < 1 {
+		return handler, nil
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(config.IgnoreRegex))
+	for _, v := range config.IgnoreRegex {
+		pattern, err := regexp.Compile(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignoreRegex %s of prom middleware, %v", v, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	return func(ctx *gin.Context) {
+		for i := range patterns {
+			if patterns[i].MatchString(ctx.Request.URL.Path) {
+				ctx.Next()
+				return
+			}
+		}
+
+		handler(ctx)
+	}, nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"crypto/tls"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"go.uber.org/zap"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// BootPromAuth credentials required to scrape metrics, access is not restricted if empty.
+type BootPromAuth struct {
+	Basic []string `yaml:"basic" json:"basic"`
+}
+
+// WithPromPort provide port of admin server which serves metrics instead of service port.
+func WithPromPort(port uint64) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.promPort = port
+	}
+}
+
+// WithPromCertEntry provide rkentry.CertEntry of admin server which serves metrics with TLS.
+//
+// Metrics served on service port follow TLS of entry.
+func WithPromCertEntry(certEntry *rkentry.CertEntry) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.promCertEntry = certEntry
+	}
+}
+
+// WithPromBasicAuth provide basic auth credentials formed as user:pass required to scrape metrics.
+func WithPromBasicAuth(cred ...string) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.promBasicAuth = append(entry.promBasicAuth, cred...)
+	}
+}
+
+// promHandlers returns handlers of metrics path, basic auth is required if configured.
+func (entry *GinEntry) promHandlers() []gin.HandlerFunc {
+	res := make([]gin.HandlerFunc, 0)
+
+	if len(entry.promBasicAuth) > 0 {
+		res = append(res, func(ctx *gin.Context) {
+			if matchBasicOrApiKey(ctx, entry.promBasicAuth, nil) {
+				ctx.Next()
+				return
+			}
+
+			ctx.Header("WWW-Authenticate", fmt.Sprintf(`Basic realm="%s"`, entry.entryName))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized,
+				rkmid.GetErrorBuilder().New(http.StatusUnauthorized, "Missing or invalid authorization of metrics"))
+		})
+	}
+
+	// OpenMetrics is negotiated with scraper, which exposes exemplars of latency histogram
+	return append(res, gin.WrapH(promhttp.HandlerFor(entry.PromEntry.Gatherer, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})))
+}
+
+// isPromTlsEnabled returns true if metrics are served with TLS.
+func (entry *GinEntry) isPromTlsEnabled() bool {
+	if entry.promPort > 0 {
+		return entry.promCertEntry != nil && entry.promCertEntry.Certificate != nil
+	}
+
+	return entry.IsTlsEnabled()
+}
+
+// startPromServer serves metrics on admin port, errors occur while serving are sent to channel returned by Errors().
+func (entry *GinEntry) startPromServer(logger *zap.Logger) error {
+	router := gin.New()
+	router.GET(entry.PromEntry.Path, entry.promHandlers()...)
+
+	server := &http.Server{
+		Addr:    "0.0.0.0:" + strconv.FormatUint(entry.promPort, 10),
+		Handler: router,
+	}
+
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return err
+	}
+
+	tlsEnabled := entry.isPromTlsEnabled()
+	if tlsEnabled {
+		server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{*entry.promCertEntry.Certificate}}
+	}
+	entry.promServer = server
+
+	go func() {
+		var err error
+		if tlsEnabled {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+
+		if err != nil && err != http.ErrServerClosed {
+			logger.Error("Error occurs while serving metrics.", zap.Error(err))
+
+			// drop error if nobody is reading and channel is full
+			select {
+			case entry.errCh <- err:
+			default:
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestGinEntry_PromBasicAuth(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-prom-auth"),
+		WithPromEntry(rkentry.RegisterPromEntry(&rkentry.BootProm{Enabled: true},
+			rkentry.WithRegistryPromEntry(prometheus.NewRegistry()))),
+		WithPromBasicAuth("user:pass"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.Nil(t, entry.BootstrapWithError(context.TODO()))
+	defer entry.Interrupt(context.TODO())
+
+	// without credentials
+	resp := httptest.NewRecorder()
+	entry.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Contains(t, resp.Header().Get("WWW-Authenticate"), "Basic")
+
+	// with credentials
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.SetBasicAuth("user", "pass")
+	resp = httptest.NewRecorder()
+	entry.Router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "go_goroutines")
+}
+
+func TestGinEntry_PromPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	port := uint64(listener.Addr().(*net.TCPAddr).Port)
+	assert.Nil(t, listener.Close())
+
+	entry := RegisterGinEntry(
+		WithName("ut-prom-port"),
+		WithPromEntry(rkentry.RegisterPromEntry(&rkentry.BootProm{Enabled: true},
+			rkentry.WithRegistryPromEntry(prometheus.NewRegistry()))),
+		WithPromPort(port))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.False(t, entry.isPromTlsEnabled())
+	assert.Nil(t, entry.BootstrapWithError(context.TODO()))
+
+	// not served on service router
+	resp := httptest.NewRecorder()
+	entry.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	// served on admin port
+	res, err := http.Get("http://127.0.0.1:" + strconv.FormatUint(port, 10) + "/metrics")
+	assert.Nil(t, err)
+	if res != nil {
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Nil(t, res.Body.Close())
+	}
+
+	// port in use
+	another := RegisterGinEntry(
+		WithName("ut-prom-port-in-use"),
+		WithPromEntry(rkentry.RegisterPromEntry(&rkentry.BootProm{Enabled: true},
+			rkentry.WithRegistryPromEntry(prometheus.NewRegistry()))),
+		WithPromPort(port))
+	defer rkentry.GlobalAppCtx.RemoveEntry(another)
+	assert.NotNil(t, another.BootstrapWithError(context.TODO()))
+
+	// stopped after interrupted
+	entry.Interrupt(context.TODO())
+	_, err = http.Get("http://127.0.0.1:" + strconv.FormatUint(port, 10) + "/metrics")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+)
+
+// BootProm boot config of prom entry with collectors registered besides Go collector.
+//
+// Metrics are served on service port by default, or on admin server listening on Port with TLS of CertEntry.
+type BootProm struct {
+	rkentry.BootProm `mapstructure:",squash" yaml:",inline"`
+	Collectors       BootPromCollectors `yaml:"collectors" json:"collectors"`
+	Port             uint64             `yaml:"port" json:"port"`
+	CertEntry        string             `yaml:"certEntry" json:"certEntry"`
+	Auth             BootPromAuth       `yaml:"auth" json:"auth"`
+}
+
+// BootPromCollectors collectors registered into registry of prom entry.
+//
+// Go collector which exports goroutines, GC and memory stats is always registered by prom entry,
+// Process exports CPU, RSS and open fds of process, BuildInfo exports go_build_info with module path and version.
+//
+// App exports rk_app_build_info with appName, version, entryName and gitCommit labels,
+// and rk_app_start_time_seconds, so rollouts and restarts are visible in prometheus.
+type BootPromCollectors struct {
+	Process   bool `yaml:"process" json:"process"`
+	BuildInfo bool `yaml:"buildInfo" json:"buildInfo"`
+	App       bool `yaml:"app" json:"app"`
+}
+
+// register registers enabled collectors into prom entry of GinEntry with entryName.
+func (config *BootPromCollectors) register(entry *rkentry.PromEntry, entryName string) {
+	if entry == nil {
+		return
+	}
+
+	if config.Process {
+		entry.RegisterCollectors(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	}
+
+	if config.BuildInfo {
+		entry.RegisterCollectors(collectors.NewBuildInfoCollector())
+	}
+
+	if config.App {
+		entry.RegisterCollectors(newAppCollectors(entryName)...)
+	}
+}
+
+// newAppCollectors returns build info and start time gauges of application,
+// app name and version are read from AppInfoEntry, commit is read from GitCommit or stamped by go build.
+func newAppCollectors(entryName string) []prometheus.Collector {
+	appInfo := rkentry.GlobalAppCtx.GetAppInfoEntry()
+
+	buildInfo := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "rk",
+		Subsystem: "app",
+		Name:      "build_info",
+		Help:      "A metric with a constant '1' value labeled by appName, version, entryName and gitCommit.",
+		ConstLabels: prometheus.Labels{
+			"appName":   appInfo.AppName,
+			"version":   appInfo.Version,
+			"entryName": entryName,
+			"gitCommit": newGitResponse().Commit,
+		},
+	})
+	buildInfo.Set(1)
+
+	startTime := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   "rk",
+		Subsystem:   "app",
+		Name:        "start_time_seconds",
+		Help:        "Start time of application since unix epoch in seconds.",
+		ConstLabels: prometheus.Labels{"entryName": entryName},
+	})
+	startTime.Set(float64(rkentry.GlobalAppCtx.GetStartTime().UnixNano()) / 1e9)
+
+	return []prometheus.Collector{buildInfo, startTime}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+	"math"
+	"testing"
+)
+
+func TestBootProm_Unmarshal(t *testing.T) {
+	config := &BootProm{}
+	assert.Nil(t, yaml.Unmarshal([]byte(`
+enabled: true
+path: /ut-metrics
+collectors:
+  process: true
+  buildInfo: true
+  app: true
+`), config))
+	assert.True(t, config.Enabled)
+	assert.Equal(t, "/ut-metrics", config.Path)
+	assert.True(t, config.Collectors.Process)
+	assert.True(t, config.Collectors.BuildInfo)
+	assert.True(t, config.Collectors.App)
+}
+
+func TestBootPromCollectors_Register(t *testing.T) {
+	// nil entry
+	(&BootPromCollectors{Process: true}).register(nil, "ut-collectors")
+
+	gather := func(config *BootPromCollectors) map[string]bool {
+		entry := rkentry.RegisterPromEntry(&rkentry.BootProm{Enabled: true},
+			rkentry.WithRegistryPromEntry(prometheus.NewRegistry()))
+		config.register(entry, "ut-collectors")
+
+		families, err := entry.Gatherer.Gather()
+		assert.Nil(t, err)
+		res := map[string]bool{}
+		for _, family := range families {
+			res[family.GetName()] = true
+		}
+		return res
+	}
+
+	// go collector only
+	names := gather(&BootPromCollectors{})
+	assert.True(t, names["go_goroutines"])
+	assert.False(t, names["go_build_info"])
+
+	// process and build info
+	names = gather(&BootPromCollectors{Process: true, BuildInfo: true})
+	assert.True(t, names["go_goroutines"])
+	assert.True(t, names["go_build_info"])
+	assert.True(t, names["process_start_time_seconds"])
+
+	// app
+	names = gather(&BootPromCollectors{App: true})
+	assert.True(t, names["rk_app_build_info"])
+	assert.True(t, names["rk_app_start_time_seconds"])
+	assert.False(t, names["go_build_info"])
+}
+
+func TestNewAppCollectors(t *testing.T) {
+	defer func(commit string) {
+		GitCommit = commit
+	}(GitCommit)
+	GitCommit = "ut-commit"
+
+	reg := prometheus.NewRegistry()
+	assert.Nil(t, reg.Register(newAppCollectors("ut-app")[0]))
+	assert.Nil(t, reg.Register(newAppCollectors("ut-app")[1]))
+
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+	assert.Len(t, families, 2)
+	for _, family := range families {
+		metric := family.GetMetric()[0]
+		labels := map[string]string{}
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		assert.Equal(t, "ut-app", labels["entryName"])
+
+		switch family.GetName() {
+		case "rk_app_build_info":
+			assert.Equal(t, float64(1), metric.GetGauge().GetValue())
+			assert.Equal(t, rkentry.GlobalAppCtx.GetAppInfoEntry().AppName, labels["appName"])
+			assert.Equal(t, rkentry.GlobalAppCtx.GetAppInfoEntry().Version, labels["version"])
+			assert.Equal(t, "ut-commit", labels["gitCommit"])
+		case "rk_app_start_time_seconds":
+			assert.Equal(t, float64(rkentry.GlobalAppCtx.GetStartTime().Unix()), math.Floor(metric.GetGauge().GetValue()))
+		default:
+			t.Errorf("unexpected metric %s", family.GetName())
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/otelmetric"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/prom"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBootMiddlewareProm_NewHandler(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	// prometheus by default
+	metricsSet, err := rkginprom.NewMetricsSet(prometheus.NewRegistry())
+	assert.Nil(t, err)
+	handler, err := (&BootMiddlewareProm{}).newHandler("ut-prom-default", metricsSet, nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, handler)
+
+	// otel
+	handler, err = (&BootMiddlewareProm{Backend: "OTel"}).newHandler("ut-prom-otel", nil, nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, handler)
+
+	// unsupported backend
+	handler, err = (&BootMiddlewareProm{Backend: "statsd"}).newHandler("ut-prom-statsd", nil, nil)
+	assert.NotNil(t, err)
+	assert.Nil(t, handler)
+
+	// invalid regex
+	handler, err = (&BootMiddlewareProm{Backend: "otel", IgnoreRegex: []string{"("}}).newHandler("ut-prom-regex", nil, nil)
+	assert.NotNil(t, err)
+	assert.Nil(t, handler)
+}
+
+func TestBootMiddlewareProm_NewMetricsSet(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	// disabled
+	set, err := (&BootMiddlewareProm{}).newMetricsSet(reg)
+	assert.Nil(t, err)
+	assert.Nil(t, set)
+
+	// otel
+	config := &BootMiddlewareProm{Backend: "otel"}
+	config.Enabled = true
+	set, err = config.newMetricsSet(reg)
+	assert.Nil(t, err)
+	assert.Nil(t, set)
+
+	// prometheus
+	config.Backend = ""
+	set, err = config.newMetricsSet(reg)
+	assert.Nil(t, err)
+	assert.True(t, reg == set.GetRegisterer())
+
+	// registered already
+	_, err = config.newMetricsSet(reg)
+	assert.NotNil(t, err)
+	rkginprom.Unregister(set)
+}
+
+func TestBootMiddlewareProm_WrapIgnoreRegex(t *testing.T) {
+	called := 0
+	handler := func(ctx *gin.Context) {
+		called++
+	}
+
+	// without regex
+	config := &BootMiddlewareProm{}
+	wrapped, err := config.wrapIgnoreRegex(handler)
+	assert.Nil(t, err)
+	assert.NotNil(t, wrapped)
+
+	// invalid regex
+	config = &BootMiddlewareProm{IgnoreRegex: []string{"("}}
+	wrapped, err = config.wrapIgnoreRegex(handler)
+	assert.NotNil(t, err)
+	assert.Nil(t, wrapped)
+
+	// happy case
+	config = &BootMiddlewareProm{IgnoreRegex: []string{"^/(healthz|readyz)$", "^/probe/.*"}}
+	wrapped, err = config.wrapIgnoreRegex(handler)
+	assert.Nil(t, err)
+
+	router := gin.New()
+	router.Use(wrapped)
+	router.GET("/*path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/healthz", "/readyz", "/probe/db", "/healthz/detail", "/v1/user"} {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, resp.Code)
+	}
+	assert.Equal(t, 2, called)
+}
+
+func TestRegisterGinEntryYAML_PromIsolation(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-prom-iso-a
+    port: 1949
+    enabled: true
+    prom:
+      enabled: true
+    middleware:
+      prom:
+        enabled: true
+  - name: ut-prom-iso-b
+    port: 1950
+    enabled: true
+    prom:
+      enabled: true
+    middleware:
+      prom:
+        enabled: true
+`
+	register := func() (*GinEntry, *GinEntry) {
+		entries := RegisterGinEntryYAML([]byte(bootStr))
+		a, b := entries["ut-prom-iso-a"].(*GinEntry), entries["ut-prom-iso-b"].(*GinEntry)
+		for _, entry := range []*GinEntry{a, b} {
+			entry.Router.GET("/ut", func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+			entry.Router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut", nil))
+		}
+		return a, b
+	}
+
+	// entryName label of resCode counter in registry of entry
+	entryNames := func(entry *GinEntry) []string {
+		families, err := entry.PromEntry.Registry.Gather()
+		assert.Nil(t, err)
+		res := make([]string, 0)
+		for _, family := range families {
+			if family.GetName() != "rk_prom_"+rkmidprom.MetricsNameResCode {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "entryName" {
+						res = append(res, label.GetValue())
+					}
+				}
+			}
+		}
+		return res
+	}
+
+	a, b := register()
+	defer b.unregister()
+	assert.Equal(t, []string{a.GetName()}, entryNames(a))
+	assert.Equal(t, []string{b.GetName()}, entryNames(b))
+	assert.True(t, a.PromEntry.Registry == a.GetMetricsSet().GetRegisterer())
+
+	// metrics unregistered after interrupted
+	a.Interrupt(context.TODO())
+	assert.Empty(t, entryNames(a))
+
+	// registered again with new registry
+	a, b = register()
+	defer a.unregister()
+	assert.Equal(t, []string{a.GetName()}, entryNames(a))
+	assert.Equal(t, []string{b.GetName()}, entryNames(b))
+}
+
+func TestGinEntry_NewMeterProvider(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-prom-meter-provider"))
+	defer entry.unregister()
+
+	// prometheus backend
+	provider, err := entry.newMeterProvider(&BootMiddlewareProm{
+		BootConfig: rkmidprom.BootConfig{Enabled: true},
+		Otlp:       rkginotelmetric.OtlpExporterConfig{Enabled: true},
+	}, entry.GetName())
+	assert.Nil(t, err)
+	assert.Nil(t, provider)
+
+	// otlp disabled
+	provider, err = entry.newMeterProvider(&BootMiddlewareProm{BootConfig: rkmidprom.BootConfig{Enabled: true}, Backend: "otel"}, entry.GetName())
+	assert.Nil(t, err)
+	assert.Nil(t, provider)
+
+	// unsupported protocol
+	provider, err = entry.newMeterProvider(&BootMiddlewareProm{
+		BootConfig: rkmidprom.BootConfig{Enabled: true},
+		Backend:    "otel",
+		Otlp:       rkginotelmetric.OtlpExporterConfig{Enabled: true, Protocol: "thrift"},
+	}, entry.GetName())
+	assert.NotNil(t, err)
+	assert.Nil(t, provider)
+	assert.Empty(t, entry.meterProviders)
+
+	// owned by entry and shut down with it
+	provider, err = entry.newMeterProvider(&BootMiddlewareProm{
+		BootConfig: rkmidprom.BootConfig{Enabled: true},
+		Backend:    "otel",
+		Otlp:       rkginotelmetric.OtlpExporterConfig{Enabled: true, Protocol: "http", Endpoint: "localhost:0"},
+	}, entry.GetName())
+	assert.Nil(t, err)
+	assert.NotNil(t, provider)
+	assert.Len(t, entry.meterProviders, 1)
+
+	entry.shutdownMeterProviders(context.TODO(), entry.LoggerEntry.Logger)
+	assert.Nil(t, entry.meterProviders)
+}
+
+func TestRegisterGinEntryYAML_PromOtlp(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-prom-otlp
+    port: 1951
+    enabled: true
+    middleware:
+      prom:
+        enabled: true
+        backend: otel
+        otlp:
+          enabled: true
+          protocol: http
+          endpoint: localhost:0
+          headers:
+            Authorization: ut-token
+`
+	entry := RegisterGinEntryYAML([]byte(bootStr))["ut-prom-otlp"].(*GinEntry)
+	defer entry.unregister()
+	assert.Len(t, entry.meterProviders, 1)
+
+	entry.Interrupt(context.TODO())
+	assert.Nil(t, entry.meterProviders)
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const (
+	// RapiDocEntryType type of entry
+	RapiDocEntryType = "RapiDocEntry"
+
+	defaultRapiDocJsUrl = "https://unpkg.com/rapidoc/dist/rapidoc-min.js"
+)
+
+var rapiDocIndexTemplate = template.Must(template.New("rapidoc").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <title>RapiDoc</title>
+  <meta charset="utf-8"/>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <script type="module" src="{{.JsUrl}}"></script>
+  <style>
+    body { margin: 0; padding: 0; }
+    nav { padding: 8px 16px; font-family: sans-serif; border-bottom: 1px solid #eee; }
+    nav a { margin-right: 16px; }
+  </style>
+</head>
+<body>
+  {{if gt (len .Specs) 1}}<nav>{{range .Specs}}<a href="?spec={{.Name}}">{{.Name}}</a>{{end}}</nav>{{end}}
+  {{if .Current}}<rapi-doc spec-url="{{.Current}}" theme="{{.Theme}}" render-style="read" show-header="false"></rapi-doc>{{else}}<p>No spec found.</p>{{end}}
+</body>
+</html>
+`))
+
+// BootRapiDoc bootstrap config of RapiDoc.
+// 1: Enabled: Enable RapiDoc.
+// 2: Path: RapiDoc path accessible from restful API.
+// 3: JsonPaths: The paths of where swagger or open API spec files were located, default is the same as sw.
+// 4: Headers: The headers that would be added into each API response.
+// 5: Style.Theme: Theme of RapiDoc, light or dark.
+type BootRapiDoc struct {
+	Enabled   bool     `yaml:"enabled" json:"enabled"`
+	Path      string   `yaml:"path" json:"path"`
+	JsonPaths []string `yaml:"jsonPaths" json:"jsonPaths"`
+	Headers   []string `yaml:"headers" json:"headers"`
+	Style     struct {
+		Theme string `yaml:"theme" json:"theme"`
+	} `yaml:"style" json:"style"`
+}
+
+// RapiDocEntry implements rkentry.Entry interface.
+//
+// RapiDocEntry renders swagger or open API spec files with RapiDoc web component, one spec at a time.
+type RapiDocEntry struct {
+	entryName        string            `json:"-" yaml:"-"`
+	entryType        string            `json:"-" yaml:"-"`
+	entryDescription string            `json:"-" yaml:"-"`
+	JsonPaths        []string          `json:"-" yaml:"-"`
+	Path             string            `json:"-" yaml:"-"`
+	Headers          map[string]string `json:"-" yaml:"-"`
+	Theme            string            `json:"-" yaml:"-"`
+	JsUrl            string            `json:"-" yaml:"-"`
+	specStore        *swSpecStore      `json:"-" yaml:"-"`
+	embedFS          fs.FS             `json:"-" yaml:"-"`
+}
+
+// RapiDocEntryOption option of RapiDocEntry
+type RapiDocEntryOption func(entry *RapiDocEntry)
+
+// WithNameRapiDocEntry provide name of RapiDocEntry
+func WithNameRapiDocEntry(name string) RapiDocEntryOption {
+	return func(entry *RapiDocEntry) {
+		entry.entryName = name
+	}
+}
+
+// WithJsonPathsRapiDocEntry provide paths of spec files, used if JsonPaths is empty in BootRapiDoc.
+func WithJsonPathsRapiDocEntry(paths ...string) RapiDocEntryOption {
+	return func(entry *RapiDocEntry) {
+		if len(entry.JsonPaths) < 1 {
+			entry.JsonPaths = append(entry.JsonPaths, paths...)
+		}
+	}
+}
+
+// WithJsUrlRapiDocEntry provide url of rapidoc-min.js, default is served from unpkg.
+func WithJsUrlRapiDocEntry(url string) RapiDocEntryOption {
+	return func(entry *RapiDocEntry) {
+		if len(url) > 0 {
+			entry.JsUrl = url
+		}
+	}
+}
+
+// RegisterRapiDocEntry register RapiDocEntry, nil will be returned if not enabled.
+func RegisterRapiDocEntry(boot *BootRapiDoc, opts ...RapiDocEntryOption) *RapiDocEntry {
+	if !boot.Enabled {
+		return nil
+	}
+
+	rapiDocEntry := &RapiDocEntry{
+		entryName:        "RapiDocEntry",
+		entryType:        RapiDocEntryType,
+		entryDescription: "Internal RK entry for RapiDoc documentation UI.",
+		JsonPaths:        boot.JsonPaths,
+		Path:             boot.Path,
+		Headers:          parseDocHeaders(boot.Headers),
+		Theme:            strings.ToLower(boot.Style.Theme),
+		JsUrl:            defaultRapiDocJsUrl,
+		specStore:        newSwSpecStore(),
+	}
+
+	for i := range opts {
+		opts[i](rapiDocEntry)
+	}
+
+	if embedFS := rkentry.GlobalAppCtx.GetEmbedFS(rapiDocEntry.GetType(), rapiDocEntry.GetName()); embedFS != nil {
+		rapiDocEntry.embedFS = embedFS
+	}
+
+	if len(rapiDocEntry.Path) < 1 {
+		rapiDocEntry.Path = "/rapidoc"
+	}
+
+	if rapiDocEntry.Theme != "light" && rapiDocEntry.Theme != "dark" {
+		rapiDocEntry.Theme = "light"
+	}
+
+	// add "/" at start and end side if missing
+	rapiDocEntry.Path = path.Join("/", rapiDocEntry.Path) + "/"
+
+	return rapiDocEntry
+}
+
+// Bootstrap reads spec files from JsonPaths, or default directories of docs, api/gen/v1 and api/gen.
+func (entry *RapiDocEntry) Bootstrap(context.Context) {
+	loadDocSpecs(entry.specStore, entry.embedFS, entry.entryName, entry.Path, entry.JsonPaths)
+}
+
+// Interrupt noop
+func (entry *RapiDocEntry) Interrupt(context.Context) {}
+
+// GetName get name of Entry
+func (entry *RapiDocEntry) GetName() string {
+	return entry.entryName
+}
+
+// GetType get type of Entry
+func (entry *RapiDocEntry) GetType() string {
+	return entry.entryType
+}
+
+// GetDescription get description of Entry
+func (entry *RapiDocEntry) GetDescription() string {
+	return entry.entryDescription
+}
+
+// String get string of Entry
+func (entry *RapiDocEntry) String() string {
+	bytes, _ := json.Marshal(entry)
+	return string(bytes)
+}
+
+// MarshalJSON Marshal entry
+func (entry *RapiDocEntry) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{
+		"name":        entry.GetName(),
+		"type":        entry.GetType(),
+		"description": entry.GetDescription(),
+		"jsonPaths":   entry.JsonPaths,
+		"path":        entry.Path,
+		"headers":     entry.Headers,
+		"theme":       entry.Theme,
+	}
+
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON Unmarshal entry
+func (entry *RapiDocEntry) UnmarshalJSON([]byte) error {
+	return nil
+}
+
+// ConfigFileHandler handler of RapiDoc page and spec files.
+//
+// Spec rendered in page could be selected with query parameter spec, first spec is rendered by default.
+func (entry *RapiDocEntry) ConfigFileHandler() http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		serveDocUI(writer, request, entry.Path, entry.Headers, entry.specStore, rapiDocIndexTemplate, map[string]interface{}{
+			"JsUrl": entry.JsUrl,
+			"Theme": entry.Theme,
+		})
+	}
+}
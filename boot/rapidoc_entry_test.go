@@ -0,0 +1,102 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegisterRapiDocEntry(t *testing.T) {
+	// disabled
+	assert.Nil(t, RegisterRapiDocEntry(&BootRapiDoc{}))
+
+	// with default values
+	boot := &BootRapiDoc{Enabled: true}
+	boot.Style.Theme = "invalid"
+	entry := RegisterRapiDocEntry(boot)
+	assert.Equal(t, "RapiDocEntry", entry.GetName())
+	assert.Equal(t, RapiDocEntryType, entry.GetType())
+	assert.NotEmpty(t, entry.GetDescription())
+	assert.Equal(t, "/rapidoc/", entry.Path)
+	assert.Equal(t, "light", entry.Theme)
+	assert.Equal(t, defaultRapiDocJsUrl, entry.JsUrl)
+	assert.NotEmpty(t, entry.String())
+	assert.Nil(t, entry.UnmarshalJSON(nil))
+
+	// with options
+	boot = &BootRapiDoc{Enabled: true, Path: "ut-rapidoc"}
+	boot.Style.Theme = "DARK"
+	entry = RegisterRapiDocEntry(boot,
+		WithNameRapiDocEntry("ut-rapidoc"),
+		WithJsonPathsRapiDocEntry("ut-sw-dir"),
+		WithJsUrlRapiDocEntry("/static/rapidoc-min.js"))
+	assert.Equal(t, "ut-rapidoc", entry.GetName())
+	assert.Equal(t, "/ut-rapidoc/", entry.Path)
+	assert.Equal(t, "dark", entry.Theme)
+	assert.Equal(t, []string{"ut-sw-dir"}, entry.JsonPaths)
+	assert.Equal(t, "/static/rapidoc-min.js", entry.JsUrl)
+}
+
+func TestRapiDocEntry_ConfigFileHandler(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"swagger":"2.0"}`), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "b.yml"), []byte(utOpenApi3Yaml), 0644))
+
+	boot := &BootRapiDoc{Enabled: true, JsonPaths: []string{dir}}
+	boot.Style.Theme = "dark"
+	entry := RegisterRapiDocEntry(boot, WithNameRapiDocEntry("ut"))
+	entry.Bootstrap(context.TODO())
+	defer entry.Interrupt(context.TODO())
+
+	handler := entry.ConfigFileHandler()
+
+	// index
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/rapidoc/?spec=ut-b.yml", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `spec-url="/rapidoc/ut-b.yml"`)
+	assert.Contains(t, w.Body.String(), `theme="dark"`)
+	assert.Contains(t, w.Body.String(), `href="?spec=ut-a.json"`)
+
+	// spec
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/rapidoc/ut-b.yml", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, utOpenApi3Yaml, w.Body.String())
+}
+
+func TestRegisterGinEntriesWithBootConfig_WithRapiDoc(t *testing.T) {
+	config := &BootConfig{}
+	rkentry.UnmarshalBootYAML([]byte(`
+gin:
+  - name: ut-rapidoc-gin
+    port: 8080
+    enabled: true
+    sw:
+      jsonPaths: ["ut-sw-dir"]
+    rapiDoc:
+      enabled: true
+      path: "/api-docs"
+      style:
+        theme: dark
+`), config)
+
+	entries := RegisterGinEntriesWithBootConfig(config)
+	entry := entries["ut-rapidoc-gin"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.True(t, entry.IsRapiDocEnabled())
+	assert.Equal(t, "/api-docs/", entry.RapiDocEntry.Path)
+	assert.Equal(t, "dark", entry.RapiDocEntry.Theme)
+	assert.Equal(t, []string{"ut-sw-dir"}, entry.RapiDocEntry.JsonPaths)
+}
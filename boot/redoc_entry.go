@@ -0,0 +1,192 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+)
+
+const (
+	// RedocEntryType type of entry
+	RedocEntryType = "RedocEntry"
+
+	defaultRedocJsUrl = "https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"
+)
+
+var redocIndexTemplate = template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <title>ReDoc</title>
+  <meta charset="utf-8"/>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <style>
+    body { margin: 0; padding: 0; }
+    nav { padding: 8px 16px; font-family: sans-serif; border-bottom: 1px solid #eee; }
+    nav a { margin-right: 16px; }
+  </style>
+</head>
+<body>
+  {{if gt (len .Specs) 1}}<nav>{{range .Specs}}<a href="?spec={{.Name}}">{{.Name}}</a>{{end}}</nav>{{end}}
+  {{if .Current}}<redoc spec-url="{{.Current}}"></redoc>{{else}}<p>No spec found.</p>{{end}}
+  <script src="{{.JsUrl}}"></script>
+</body>
+</html>
+`))
+
+// BootRedoc bootstrap config of ReDoc.
+// 1: Enabled: Enable ReDoc.
+// 2: Path: ReDoc path accessible from restful API.
+// 3: JsonPaths: The paths of where swagger or open API spec files were located, default is the same as sw.
+// 4: Headers: The headers that would be added into each API response.
+type BootRedoc struct {
+	Enabled   bool     `yaml:"enabled" json:"enabled"`
+	Path      string   `yaml:"path" json:"path"`
+	JsonPaths []string `yaml:"jsonPaths" json:"jsonPaths"`
+	Headers   []string `yaml:"headers" json:"headers"`
+}
+
+// RedocEntry implements rkentry.Entry interface.
+//
+// RedocEntry renders swagger or open API spec files with ReDoc, one spec at a time.
+type RedocEntry struct {
+	entryName        string            `json:"-" yaml:"-"`
+	entryType        string            `json:"-" yaml:"-"`
+	entryDescription string            `json:"-" yaml:"-"`
+	JsonPaths        []string          `json:"-" yaml:"-"`
+	Path             string            `json:"-" yaml:"-"`
+	Headers          map[string]string `json:"-" yaml:"-"`
+	JsUrl            string            `json:"-" yaml:"-"`
+	specStore        *swSpecStore      `json:"-" yaml:"-"`
+	embedFS          fs.FS             `json:"-" yaml:"-"`
+}
+
+// RedocEntryOption option of RedocEntry
+type RedocEntryOption func(entry *RedocEntry)
+
+// WithNameRedocEntry provide name of RedocEntry
+func WithNameRedocEntry(name string) RedocEntryOption {
+	return func(entry *RedocEntry) {
+		entry.entryName = name
+	}
+}
+
+// WithJsonPathsRedocEntry provide paths of spec files, used if JsonPaths is empty in BootRedoc.
+func WithJsonPathsRedocEntry(paths ...string) RedocEntryOption {
+	return func(entry *RedocEntry) {
+		if len(entry.JsonPaths) < 1 {
+			entry.JsonPaths = append(entry.JsonPaths, paths...)
+		}
+	}
+}
+
+// WithJsUrlRedocEntry provide url of redoc.standalone.js, default is served from CDN of ReDoc.
+func WithJsUrlRedocEntry(url string) RedocEntryOption {
+	return func(entry *RedocEntry) {
+		if len(url) > 0 {
+			entry.JsUrl = url
+		}
+	}
+}
+
+// RegisterRedocEntry register RedocEntry, nil will be returned if not enabled.
+func RegisterRedocEntry(boot *BootRedoc, opts ...RedocEntryOption) *RedocEntry {
+	if !boot.Enabled {
+		return nil
+	}
+
+	redocEntry := &RedocEntry{
+		entryName:        "RedocEntry",
+		entryType:        RedocEntryType,
+		entryDescription: "Internal RK entry for ReDoc documentation UI.",
+		JsonPaths:        boot.JsonPaths,
+		Path:             boot.Path,
+		Headers:          parseDocHeaders(boot.Headers),
+		JsUrl:            defaultRedocJsUrl,
+		specStore:        newSwSpecStore(),
+	}
+
+	for i := range opts {
+		opts[i](redocEntry)
+	}
+
+	if embedFS := rkentry.GlobalAppCtx.GetEmbedFS(redocEntry.GetType(), redocEntry.GetName()); embedFS != nil {
+		redocEntry.embedFS = embedFS
+	}
+
+	if len(redocEntry.Path) < 1 {
+		redocEntry.Path = "/redoc"
+	}
+
+	// add "/" at start and end side if missing
+	redocEntry.Path = path.Join("/", redocEntry.Path) + "/"
+
+	return redocEntry
+}
+
+// Bootstrap reads spec files from JsonPaths, or default directories of docs, api/gen/v1 and api/gen.
+func (entry *RedocEntry) Bootstrap(context.Context) {
+	loadDocSpecs(entry.specStore, entry.embedFS, entry.entryName, entry.Path, entry.JsonPaths)
+}
+
+// Interrupt noop
+func (entry *RedocEntry) Interrupt(context.Context) {}
+
+// GetName get name of Entry
+func (entry *RedocEntry) GetName() string {
+	return entry.entryName
+}
+
+// GetType get type of Entry
+func (entry *RedocEntry) GetType() string {
+	return entry.entryType
+}
+
+// GetDescription get description of Entry
+func (entry *RedocEntry) GetDescription() string {
+	return entry.entryDescription
+}
+
+// String get string of Entry
+func (entry *RedocEntry) String() string {
+	bytes, _ := json.Marshal(entry)
+	return string(bytes)
+}
+
+// MarshalJSON Marshal entry
+func (entry *RedocEntry) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{
+		"name":        entry.GetName(),
+		"type":        entry.GetType(),
+		"description": entry.GetDescription(),
+		"jsonPaths":   entry.JsonPaths,
+		"path":        entry.Path,
+		"headers":     entry.Headers,
+	}
+
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON Unmarshal entry
+func (entry *RedocEntry) UnmarshalJSON([]byte) error {
+	return nil
+}
+
+// ConfigFileHandler handler of ReDoc page and spec files.
+//
+// Spec rendered in page could be selected with query parameter spec, first spec is rendered by default.
+func (entry *RedocEntry) ConfigFileHandler() http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		serveDocUI(writer, request, entry.Path, entry.Headers, entry.specStore, redocIndexTemplate, map[string]interface{}{
+			"JsUrl": entry.JsUrl,
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegisterRedocEntry(t *testing.T) {
+	// disabled
+	assert.Nil(t, RegisterRedocEntry(&BootRedoc{}))
+
+	// with default values
+	entry := RegisterRedocEntry(&BootRedoc{
+		Enabled: true,
+		Headers: []string{"key:value", "invalid"},
+	})
+	assert.Equal(t, "RedocEntry", entry.GetName())
+	assert.Equal(t, RedocEntryType, entry.GetType())
+	assert.NotEmpty(t, entry.GetDescription())
+	assert.Equal(t, "/redoc/", entry.Path)
+	assert.Equal(t, defaultRedocJsUrl, entry.JsUrl)
+	assert.Equal(t, map[string]string{"key": "value"}, entry.Headers)
+	assert.NotEmpty(t, entry.String())
+	assert.Nil(t, entry.UnmarshalJSON(nil))
+
+	// with options, paths in config take precedence
+	entry = RegisterRedocEntry(&BootRedoc{
+		Enabled:   true,
+		Path:      "ut-redoc",
+		JsonPaths: []string{"ut-dir"},
+	}, WithNameRedocEntry("ut-redoc"), WithJsonPathsRedocEntry("ut-sw-dir"), WithJsUrlRedocEntry("/static/redoc.js"))
+	assert.Equal(t, "ut-redoc", entry.GetName())
+	assert.Equal(t, "/ut-redoc/", entry.Path)
+	assert.Equal(t, []string{"ut-dir"}, entry.JsonPaths)
+	assert.Equal(t, "/static/redoc.js", entry.JsUrl)
+
+	// paths from option
+	entry = RegisterRedocEntry(&BootRedoc{
+		Enabled: true,
+	}, WithJsonPathsRedocEntry("ut-sw-dir"))
+	assert.Equal(t, []string{"ut-sw-dir"}, entry.JsonPaths)
+
+	bytes, err := json.Marshal(entry)
+	assert.Nil(t, err)
+	assert.Contains(t, string(bytes), "ut-sw-dir")
+}
+
+func TestRedocEntry_ConfigFileHandler(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"swagger":"2.0"}`), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte(utOpenApi3Yaml), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("ut"), 0644))
+
+	entry := RegisterRedocEntry(&BootRedoc{
+		Enabled:   true,
+		JsonPaths: []string{dir},
+		Headers:   []string{"key:value"},
+	}, WithNameRedocEntry("ut"))
+	entry.Bootstrap(context.TODO())
+	defer entry.Interrupt(context.TODO())
+
+	handler := entry.ConfigFileHandler()
+	serve := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	// index renders first spec by default
+	w := serve("/redoc/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "value", w.Header().Get("key"))
+	assert.Contains(t, w.Body.String(), `spec-url="/redoc/ut-a.json"`)
+	assert.Contains(t, w.Body.String(), defaultRedocJsUrl)
+
+	// index renders selected spec
+	w = serve("/redoc?spec=ut-b.yaml")
+	assert.Contains(t, w.Body.String(), `spec-url="/redoc/ut-b.yaml"`)
+
+	// spec files
+	w = serve("/redoc/ut-a.json")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"swagger":"2.0"}`, w.Body.String())
+
+	w = serve("/redoc/ut-b.yaml")
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.Equal(t, utOpenApi3Yaml, w.Body.String())
+
+	// not exist
+	assert.Equal(t, http.StatusNotFound, serve("/redoc/ut-c.txt").Code)
+}
+
+func TestRedocEntry_ConfigFileHandler_WithoutSpec(t *testing.T) {
+	entry := RegisterRedocEntry(&BootRedoc{
+		Enabled:   true,
+		JsonPaths: []string{filepath.Join(t.TempDir(), "non-exist")},
+	})
+	entry.Bootstrap(context.TODO())
+
+	w := httptest.NewRecorder()
+	entry.ConfigFileHandler()(w, httptest.NewRequest(http.MethodGet, "/redoc/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "No spec found.")
+}
+
+func TestRegisterGinEntriesWithBootConfig_WithRedoc(t *testing.T) {
+	config := &BootConfig{}
+	rkentry.UnmarshalBootYAML([]byte(`
+gin:
+  - name: ut-redoc-gin
+    port: 8080
+    enabled: true
+    sw:
+      jsonPaths: ["ut-sw-dir"]
+    redoc:
+      enabled: true
+`), config)
+
+	entries := RegisterGinEntriesWithBootConfig(config)
+	entry := entries["ut-redoc-gin"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.True(t, entry.IsRedocEnabled())
+	assert.Equal(t, "ut-redoc-gin", entry.RedocEntry.GetName())
+	assert.Equal(t, []string{"ut-sw-dir"}, entry.RedocEntry.JsonPaths)
+}
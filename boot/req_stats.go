@@ -0,0 +1,162 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"math"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+)
+
+// ReqMetric request stats of a route gathered by prom middleware.
+//
+// Latency percentiles are weighted by count of each response code, since they are recorded per response code.
+type ReqMetric struct {
+	RestMethod      string          `json:"restMethod" yaml:"restMethod"`
+	RestPath        string          `json:"restPath" yaml:"restPath"`
+	Count           uint64          `json:"count" yaml:"count"`
+	ErrorCount      uint64          `json:"errorCount" yaml:"errorCount"`
+	ElapsedNanoP50  float64         `json:"elapsedNanoP50" yaml:"elapsedNanoP50"`
+	ElapsedNanoP90  float64         `json:"elapsedNanoP90" yaml:"elapsedNanoP90"`
+	ElapsedNanoP99  float64         `json:"elapsedNanoP99" yaml:"elapsedNanoP99"`
+	ElapsedNanoP999 float64         `json:"elapsedNanoP999" yaml:"elapsedNanoP999"`
+	ResCode         []*ResCodeCount `json:"resCode" yaml:"resCode"`
+}
+
+// ResCodeCount count of response code.
+type ResCodeCount struct {
+	ResCode string `json:"resCode" yaml:"resCode"`
+	Count   uint64 `json:"count" yaml:"count"`
+}
+
+// ReqResponse response of req API.
+type ReqResponse struct {
+	Metrics []*ReqMetric `json:"metrics" yaml:"metrics"`
+}
+
+// ReqPath returns path of req API which sits next to common service paths, /rk/v1/req by default.
+func (entry *GinEntry) ReqPath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "req")
+}
+
+// ReqHandler returns request stats of routes sorted by count, responses with code >= 400 are counted as errors.
+//
+// Metrics are empty if prom middleware is not enabled.
+func (entry *GinEntry) ReqHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, &ReqResponse{
+		Metrics: listReqMetrics(entry.GetMetricsSet()),
+	})
+}
+
+// GetMetricsSet returns metrics set of prom middleware built from boot config,
+// or metrics set kept by rkmidprom if prom middleware is added with AddMiddleware.
+//
+// Pass it to rkginprom.NewRoundTripper, so that outgoing requests are recorded next to requests of entry.
+func (entry *GinEntry) GetMetricsSet() *rkmidprom.MetricsSet {
+	if entry.metricsSet != nil {
+		return entry.metricsSet
+	}
+
+	return rkmidprom.GetServerMetricsSet(entry.entryName)
+}
+
+// listReqMetrics aggregates summary of elapsed time recorded by prom middleware by method and path.
+func listReqMetrics(set *rkmidprom.MetricsSet) []*ReqMetric {
+	res := make([]*ReqMetric, 0)
+	if set == nil || set.GetSummary(rkmidprom.MetricsNameElapsedNano) == nil {
+		return res
+	}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		set.GetSummary(rkmidprom.MetricsNameElapsedNano).Collect(ch)
+		close(ch)
+	}()
+
+	metrics := make(map[string]*ReqMetric)
+	// sum of quantile * count, with count of observations which quantile is available
+	weighted := make(map[string]map[float64][2]float64)
+
+	for m := range ch {
+		raw := &dto.Metric{}
+		if err := m.Write(raw); err != nil || raw.Summary == nil {
+			continue
+		}
+
+		labels := make(map[string]string)
+		for _, pair := range raw.Label {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+
+		key := labels["restMethod"] + " " + labels["restPath"]
+		metric, ok := metrics[key]
+		if !ok {
+			metric = &ReqMetric{
+				RestMethod: labels["restMethod"],
+				RestPath:   labels["restPath"],
+				ResCode:    make([]*ResCodeCount, 0),
+			}
+			metrics[key] = metric
+			weighted[key] = make(map[float64][2]float64)
+		}
+
+		count := raw.Summary.GetSampleCount()
+		metric.Count += count
+		metric.ResCode = append(metric.ResCode, &ResCodeCount{ResCode: labels["resCode"], Count: count})
+		if code, err := strconv.Atoi(labels["resCode"]); err == nil && code >= http.StatusBadRequest {
+			metric.ErrorCount += count
+		}
+
+		for _, q := range raw.Summary.Quantile {
+			if math.IsNaN(q.GetValue()) {
+				continue
+			}
+			w := weighted[key][q.GetQuantile()]
+			weighted[key][q.GetQuantile()] = [2]float64{w[0] + q.GetValue()*float64(count), w[1] + float64(count)}
+		}
+	}
+
+	for key, metric := range metrics {
+		quantile := func(q float64) float64 {
+			if w := weighted[key][q]; w[1] > 0 {
+				return w[0] / w[1]
+			}
+			return 0
+		}
+
+		metric.ElapsedNanoP50 = quantile(0.5)
+		metric.ElapsedNanoP90 = quantile(0.9)
+		metric.ElapsedNanoP99 = quantile(0.99)
+		metric.ElapsedNanoP999 = quantile(0.999)
+
+		sort.Slice(metric.ResCode, func(i, j int) bool {
+			return metric.ResCode[i].ResCode < metric.ResCode[j].ResCode
+		})
+		res = append(res, metric)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		if res[i].RestPath != res[j].RestPath {
+			return res[i].RestPath < res[j].RestPath
+		}
+		return res[i].RestMethod < res[j].RestMethod
+	})
+
+	return res
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/prom"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGinEntry_ReqHandler(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	entry := RegisterGinEntry(
+		WithName("ut-req"),
+		WithPort(0),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{Enabled: true})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, "/rk/v1/req", entry.ReqPath())
+	entry.Router.GET(entry.ReqPath(), entry.ReqHandler)
+
+	serve := func(method, p string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, httptest.NewRequest(method, p, nil))
+		return w
+	}
+
+	// without prom middleware
+	res := &ReqResponse{}
+	assert.Nil(t, json.Unmarshal(serve(http.MethodGet, "/rk/v1/req").Body.Bytes(), res))
+	assert.Empty(t, res.Metrics)
+
+	entry.Router.Use(rkginprom.Middleware(
+		rkmidprom.WithEntryNameAndType("ut-req", GinEntryType),
+		rkmidprom.WithRegisterer(prometheus.NewRegistry()),
+		rkmidprom.WithPathToIgnore("/rk/v1/req")))
+	entry.Router.GET("/ut-hot", func(ctx *gin.Context) {
+		if ctx.Query("fail") != "" {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		ctx.Status(http.StatusOK)
+	})
+	entry.Router.POST("/ut-cold", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	for i := 0; i < 3; i++ {
+		serve(http.MethodGet, "/ut-hot")
+	}
+	serve(http.MethodGet, "/ut-hot?fail=true")
+	serve(http.MethodPost, "/ut-cold")
+
+	res = &ReqResponse{}
+	assert.Nil(t, json.Unmarshal(serve(http.MethodGet, "/rk/v1/req").Body.Bytes(), res))
+	assert.Len(t, res.Metrics, 2)
+
+	// hot path first
+	hot := res.Metrics[0]
+	assert.Equal(t, http.MethodGet, hot.RestMethod)
+	assert.Equal(t, "/ut-hot", hot.RestPath)
+	assert.EqualValues(t, 4, hot.Count)
+	assert.EqualValues(t, 1, hot.ErrorCount)
+	assert.Equal(t, []*ResCodeCount{{ResCode: "200", Count: 3}, {ResCode: "500", Count: 1}}, hot.ResCode)
+	assert.True(t, hot.ElapsedNanoP50 > 0)
+	assert.True(t, hot.ElapsedNanoP999 >= hot.ElapsedNanoP50)
+
+	assert.Equal(t, "/ut-cold", res.Metrics[1].RestPath)
+	assert.EqualValues(t, 1, res.Metrics[1].Count)
+	assert.Zero(t, res.Metrics[1].ErrorCount)
+}
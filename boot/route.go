@@ -0,0 +1,166 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// BootRoute boot config of route declared in boot config without writing handlers.
+//
+// Exactly one of Redirect, Proxy, File or Body will be used in that order.
+//
+// Example:
+//
+//	routes:
+//	  - path: /robots.txt
+//	    file: static/robots.txt
+//	  - path: /healthz
+//	    contentType: application/json
+//	    body: '{"status":"ok"}'
+//	  - path: /old
+//	    redirect: /new
+//	  - path: /legacy/*any
+//	    method: ANY
+//	    proxy: http://legacy:8080
+type BootRoute struct {
+	Method      string   `yaml:"method" json:"method"`
+	Path        string   `yaml:"path" json:"path"`
+	Code        int      `yaml:"code" json:"code"`
+	ContentType string   `yaml:"contentType" json:"contentType"`
+	Headers     []string `yaml:"headers" json:"headers"`
+	Body        string   `yaml:"body" json:"body"`
+	File        string   `yaml:"file" json:"file"`
+	Redirect    string   `yaml:"redirect" json:"redirect"`
+	Proxy       string   `yaml:"proxy" json:"proxy"`
+}
+
+// AddRoutes register routes declared in boot config into Router.
+//
+// This function should be called before Bootstrap() called.
+func (entry *GinEntry) AddRoutes(routes ...*BootRoute) error {
+	for i := range routes {
+		route := routes[i]
+		if route == nil {
+			continue
+		}
+
+		handler, err := route.handler()
+		if err != nil {
+			return err
+		}
+
+		method := strings.ToUpper(route.Method)
+		switch method {
+		case "":
+			entry.Router.GET(route.Path, handler)
+		case "ANY", "*":
+			entry.Router.Any(route.Path, handler)
+		default:
+			entry.Router.Handle(method, route.Path, handler)
+		}
+	}
+
+	return nil
+}
+
+// handler build gin.HandlerFunc from route.
+func (route *BootRoute) handler() (gin.HandlerFunc, error) {
+	if len(route.Path) < 1 || !strings.HasPrefix(route.Path, "/") {
+		return nil, fmt.Errorf("invalid route path [%s], path must start with /", route.Path)
+	}
+
+	headers := make(map[string]string)
+	for _, h := range route.Headers {
+		tokens := strings.SplitN(h, ":", 2)
+		if len(tokens) != 2 {
+			return nil, fmt.Errorf("invalid header [%s] in route %s, should be key:value", h, route.Path)
+		}
+		headers[strings.TrimSpace(tokens[0])] = strings.TrimSpace(tokens[1])
+	}
+
+	writeHeaders := func(ctx *gin.Context) {
+		for k, v := range headers {
+			ctx.Header(k, v)
+		}
+	}
+
+	switch {
+	case len(route.Redirect) > 0:
+		code := route.Code
+		if code == 0 {
+			code = http.StatusMovedPermanently
+		}
+		// gin panics while redirecting with other codes
+		if code < http.StatusMultipleChoices || code > http.StatusPermanentRedirect {
+			return nil, fmt.Errorf("invalid redirect code %d in route %s, should be 300-308", code, route.Path)
+		}
+
+		return func(ctx *gin.Context) {
+			writeHeaders(ctx)
+			ctx.Redirect(code, route.Redirect)
+		}, nil
+	case len(route.Proxy) > 0:
+		target, err := url.Parse(route.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		if len(target.Scheme) < 1 || len(target.Host) < 1 {
+			return nil, fmt.Errorf("invalid proxy url [%s] in route %s", route.Proxy, route.Path)
+		}
+		proxy := httputil.NewSingleHostReverseProxy(target)
+
+		return func(ctx *gin.Context) {
+			writeHeaders(ctx)
+			proxy.ServeHTTP(ctx.Writer, ctx.Request)
+		}, nil
+	case len(route.File) > 0:
+		if _, err := os.Stat(route.File); err != nil {
+			return nil, err
+		}
+
+		return func(ctx *gin.Context) {
+			writeHeaders(ctx)
+			if len(route.ContentType) > 0 {
+				ctx.Header("Content-Type", route.ContentType)
+			}
+			ctx.File(route.File)
+		}, nil
+	case len(route.Body) > 0 || route.Code != 0:
+		code := route.Code
+		if code == 0 {
+			code = http.StatusOK
+		}
+
+		contentType := route.ContentType
+		if len(contentType) < 1 {
+			contentType = "text/plain; charset=utf-8"
+		}
+
+		body := []byte(route.Body)
+
+		return func(ctx *gin.Context) {
+			writeHeaders(ctx)
+			ctx.Data(code, contentType, body)
+		}, nil
+	}
+
+	return nil, errors.New("route " + route.Path + " should have one of redirect, proxy, file, body or code")
+}
+
+// WithRoutes provide routes declared in boot config.
+func WithRoutes(routes ...*BootRoute) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.routes = append(entry.routes, routes...)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestGinEntry_AddRoutes(t *testing.T) {
+	// upstream of proxy
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("upstream:" + r.URL.Path))
+	}))
+	defer upstream.Close()
+
+	file := path.Join(t.TempDir(), "robots.txt")
+	assert.Nil(t, os.WriteFile(file, []byte("User-agent: *"), 0644))
+
+	entry := RegisterGinEntry(WithName("ut-routes"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Nil(t, entry.AddRoutes(
+		nil,
+		&BootRoute{Path: "/healthz", ContentType: "application/json", Body: `{"status":"ok"}`, Headers: []string{"X-Ut: ut"}},
+		&BootRoute{Path: "/robots.txt", File: file},
+		&BootRoute{Path: "/old", Redirect: "/new"},
+		&BootRoute{Path: "/teapot", Method: "post", Code: http.StatusTeapot},
+		&BootRoute{Path: "/legacy/*any", Method: "ANY", Proxy: upstream.URL}))
+
+	// body
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"status":"ok"}`, w.Body.String())
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "ut", w.Header().Get("X-Ut"))
+
+	// file
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/robots.txt", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "User-agent: *", w.Body.String())
+
+	// redirect
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/old", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/new", w.Header().Get("Location"))
+
+	// code only
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/teapot", nil))
+	assert.Equal(t, http.StatusTeapot, w.Code)
+
+	// proxy, httptest.ResponseRecorder doesn't implement http.CloseNotifier, use real server instead
+	server := httptest.NewServer(entry.Router)
+	defer server.Close()
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/legacy/ut", nil)
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "upstream:/legacy/ut", string(body))
+}
+
+func TestGinEntry_AddRoutes_WithInvalidRoute(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-routes"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	// invalid path
+	assert.NotNil(t, entry.AddRoutes(&BootRoute{Path: "ut", Body: "ut"}))
+	// invalid header
+	assert.NotNil(t, entry.AddRoutes(&BootRoute{Path: "/ut", Body: "ut", Headers: []string{"invalid"}}))
+	// missing file
+	assert.NotNil(t, entry.AddRoutes(&BootRoute{Path: "/ut", File: "not-exist"}))
+	// invalid proxy
+	assert.NotNil(t, entry.AddRoutes(&BootRoute{Path: "/ut", Proxy: "not-a-url"}))
+	// invalid redirect code
+	assert.NotNil(t, entry.AddRoutes(&BootRoute{Path: "/ut", Redirect: "/new", Code: http.StatusOK}))
+	// nothing to respond
+	assert.NotNil(t, entry.AddRoutes(&BootRoute{Path: "/ut"}))
+}
+
+func TestRegisterGinEntryYAML_WithRoutes(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-routes
+    port: 1949
+    enabled: true
+    routes:
+      - path: /healthz
+        contentType: application/json
+        body: '{"Status":"ok"}'
+`
+	entries := RegisterGinEntryYAML([]byte(bootStr))
+	entry := entries["ut-routes"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Len(t, entry.routes, 1)
+	assert.Nil(t, entry.AddRoutes(entry.routes...))
+
+	w := httptest.NewRecorder()
+	ctx := gin.CreateTestContextOnly(w, entry.Router)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	entry.Router.HandleContext(ctx)
+	assert.Equal(t, `{"Status":"ok"}`, w.Body.String())
+}
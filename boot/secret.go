@@ -0,0 +1,281 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// vaultRequestTimeout is timeout of requests to Vault, so that unreachable Vault won't block boot forever.
+const vaultRequestTimeout = 10 * time.Second
+
+var secretResolvers = &secretResolverRegistry{
+	resolvers: map[string]SecretResolver{
+		"env":   EnvSecretResolver,
+		"file":  FileSecretResolver,
+		"vault": NewVaultSecretResolver("", ""),
+	},
+}
+
+// SecretResolver resolves secret reference into value.
+//
+// ref is the part after scheme://, like TOKEN in env://TOKEN.
+type SecretResolver func(ref string) (string, error)
+
+// secretResolverRegistry keeps resolvers by scheme.
+type secretResolverRegistry struct {
+	lock      sync.RWMutex
+	resolvers map[string]SecretResolver
+}
+
+// RegisterSecretResolver register resolver of scheme, resolver with same scheme will be replaced.
+//
+// env, file and vault are registered by default.
+//
+//	rkgin.RegisterSecretResolver("awssm", func(ref string) (string, error) {
+//		return fetchFromSecretsManager(ref)
+//	})
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	if len(scheme) < 1 || resolver == nil {
+		return
+	}
+
+	secretResolvers.lock.Lock()
+	defer secretResolvers.lock.Unlock()
+
+	secretResolvers.resolvers[strings.ToLower(scheme)] = resolver
+}
+
+// RemoveSecretResolver remove resolver of scheme.
+func RemoveSecretResolver(scheme string) {
+	secretResolvers.lock.Lock()
+	defer secretResolvers.lock.Unlock()
+
+	delete(secretResolvers.resolvers, strings.ToLower(scheme))
+}
+
+// ResolveSecret resolves value if it is a reference with scheme of registered resolver,
+// like vault://secret/app#apiKey, env://TOKEN or file:///run/secrets/key.
+//
+// Value will be returned as it is if it is not a reference.
+func ResolveSecret(value string) (string, error) {
+	resolver, ref := getSecretResolver(value)
+	if resolver == nil {
+		return value, nil
+	}
+
+	res, err := resolver(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %s, %v", value, err)
+	}
+
+	return res, nil
+}
+
+// getSecretResolver returns resolver and reference of value, nil if value is not a reference.
+func getSecretResolver(value string) (SecretResolver, string) {
+	i := strings.Index(value, "://")
+	if i < 1 {
+		return nil, ""
+	}
+
+	secretResolvers.lock.RLock()
+	defer secretResolvers.lock.RUnlock()
+
+	return secretResolvers.resolvers[strings.ToLower(value[:i])], value[i+3:]
+}
+
+// EnvSecretResolver resolves env://NAME from environment variable.
+func EnvSecretResolver(ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s not set", ref)
+	}
+
+	return value, nil
+}
+
+// FileSecretResolver resolves file:///path from content of file, trailing new line is trimmed.
+func FileSecretResolver(ref string) (string, error) {
+	bytes, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(bytes), "\r\n"), nil
+}
+
+// NewVaultSecretResolver returns resolver of vault://path#key with HTTP API of Vault.
+//
+// VAULT_ADDR and VAULT_TOKEN environment variables will be used if addr or token is empty.
+// Both KV v1 and v2 secret engines are supported, and each request times out in 10 seconds.
+func NewVaultSecretResolver(addr, token string) SecretResolver {
+	client := &http.Client{Timeout: vaultRequestTimeout}
+
+	return func(ref string) (string, error) {
+		addr, token := addr, token
+		if len(addr) < 1 {
+			addr = os.Getenv("VAULT_ADDR")
+		}
+		if len(token) < 1 {
+			token = os.Getenv("VAULT_TOKEN")
+		}
+
+		p, key := ref, ""
+		if i := strings.LastIndex(ref, "#"); i >= 0 {
+			p, key = ref[:i], ref[i+1:]
+		}
+		if len(addr) < 1 || len(key) < 1 {
+			return "", fmt.Errorf("vault address and key are required")
+		}
+
+		req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/"+strings.TrimPrefix(p, "/"), nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("X-Vault-Token", token)
+
+		raw, err := doConfigSourceRequest(client, req)
+		if err != nil {
+			return "", err
+		}
+
+		resp := struct {
+			Data map[string]interface{} `json:"data"`
+		}{}
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return "", err
+		}
+
+		data := resp.Data
+		// KV v2 wraps secrets in data.data
+		if inner, ok := data["data"].(map[string]interface{}); ok {
+			data = inner
+		}
+
+		value, ok := data[key]
+		if !ok {
+			return "", fmt.Errorf("key %s not found in vault path %s", key, p)
+		}
+
+		return fmt.Sprint(value), nil
+	}
+}
+
+// resolveGinSecrets resolves secret references in enabled gin elements, references in disabled elements and other
+// sections are kept, so that secrets which are not used won't be fetched.
+func resolveGinSecrets(m map[interface{}]interface{}, resolved *bool) error {
+	list, _ := m[findBootMapKey(m, "gin")].([]interface{})
+	for i := range list {
+		element, ok := list[i].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		if enabled, _ := element[findBootMapKey(element, "enabled")].(bool); !enabled {
+			continue
+		}
+		if _, err := resolveSecretsInValue(element, resolved); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveSecretsInValue walks maps and slices and resolves string values.
+func resolveSecretsInValue(in interface{}, resolved *bool) (interface{}, error) {
+	switch v := in.(type) {
+	case map[interface{}]interface{}:
+		for k := range v {
+			res, err := resolveSecretsInValue(v[k], resolved)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = res
+		}
+	case []interface{}:
+		for i := range v {
+			res, err := resolveSecretsInValue(v[i], resolved)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = res
+		}
+	case string:
+		if resolver, _ := getSecretResolver(v); resolver != nil {
+			*resolved = true
+			return ResolveSecret(v)
+		}
+	}
+
+	return in, nil
+}
+
+// resolveSecretsInStruct walks pointers, structs, slices and maps of typed config and resolves string values,
+// unexported fields are skipped.
+func resolveSecretsInStruct(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return resolveSecretsInStruct(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		res, err := resolveSecretsInValue(v.Interface(), new(bool))
+		if err != nil {
+			return err
+		}
+		if rv := reflect.ValueOf(res); v.CanSet() && rv.Type().AssignableTo(v.Type()) {
+			v.Set(rv)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).CanSet() {
+				continue
+			}
+			if err := resolveSecretsInStruct(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveSecretsInStruct(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// map values are not addressable, resolve copy and put it back
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			if err := resolveSecretsInStruct(value); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), value)
+		}
+	case reflect.String:
+		if resolver, _ := getSecretResolver(v.String()); resolver != nil && v.CanSet() {
+			res, err := ResolveSecret(v.String())
+			if err != nil {
+				return err
+			}
+			v.SetString(res)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"errors"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveSecret(t *testing.T) {
+	t.Setenv("UT_SECRET", "ut-value")
+
+	// not a reference
+	res, err := ResolveSecret("plain")
+	assert.Nil(t, err)
+	assert.Equal(t, "plain", res)
+
+	// unknown scheme
+	res, err = ResolveSecret("http://localhost")
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost", res)
+
+	// env
+	res, err = ResolveSecret("env://UT_SECRET")
+	assert.Nil(t, err)
+	assert.Equal(t, "ut-value", res)
+
+	_, err = ResolveSecret("env://UT_SECRET_NOT_EXIST")
+	assert.NotNil(t, err)
+
+	// file
+	p := writeBootConfigFile(t, t.TempDir(), "secret", "ut-file\n")
+	res, err = ResolveSecret("file://" + p)
+	assert.Nil(t, err)
+	assert.Equal(t, "ut-file", res)
+
+	_, err = ResolveSecret("file:///non-exist")
+	assert.NotNil(t, err)
+}
+
+func TestRegisterSecretResolver(t *testing.T) {
+	RegisterSecretResolver("", nil)
+
+	RegisterSecretResolver("UT", func(ref string) (string, error) {
+		if ref == "fail" {
+			return "", errors.New("ut")
+		}
+		return "resolved-" + ref, nil
+	})
+	defer RemoveSecretResolver("ut")
+
+	res, err := ResolveSecret("ut://key")
+	assert.Nil(t, err)
+	assert.Equal(t, "resolved-key", res)
+
+	_, err = ResolveSecret("ut://fail")
+	assert.NotNil(t, err)
+
+	RemoveSecretResolver("ut")
+	res, _ = ResolveSecret("ut://key")
+	assert.Equal(t, "ut://key", res)
+}
+
+func TestNewVaultSecretResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "ut-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/app":
+			w.Write([]byte(`{"data":{"data":{"apiKey":"v2-key"}}}`))
+		case "/v1/kv/app":
+			w.Write([]byte(`{"data":{"apiKey":"v1-key"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewVaultSecretResolver(server.URL, "ut-token")
+
+	res, err := resolver("secret/data/app#apiKey")
+	assert.Nil(t, err)
+	assert.Equal(t, "v2-key", res)
+
+	res, err = resolver("kv/app#apiKey")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1-key", res)
+
+	// missing key
+	_, err = resolver("kv/app#missing")
+	assert.NotNil(t, err)
+	_, err = resolver("kv/app")
+	assert.NotNil(t, err)
+
+	// from env
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "invalid")
+	_, err = NewVaultSecretResolver("", "")("kv/app#apiKey")
+	assert.NotNil(t, err)
+}
+
+func TestRegisterGinEntryYAML_WithSecret(t *testing.T) {
+	t.Setenv("UT_BASIC", "user:pass")
+
+	bootStr := `
+gin:
+  - name: ut-secret
+    port: 1949
+    enabled: true
+    description: "env://UT_BASIC"
+    middleware:
+      auth:
+        enabled: true
+        basic: ["env://UT_BASIC"]
+`
+	entries := RegisterGinEntryYAML([]byte(bootStr))
+	entry := entries["ut-secret"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, "user:pass", entry.GetDescription())
+}
+
+func TestRegisterGinEntryYAML_WithMissingSecret(t *testing.T) {
+	// secrets of disabled entry are not resolved
+	entries := RegisterGinEntryYAML([]byte(`
+gin:
+  - name: ut-secret
+    description: "env://UT_SECRET_NOT_EXIST"
+`))
+	assert.Empty(t, entries)
+
+	defer assertPanic(t)
+
+	RegisterGinEntryYAML([]byte(`
+gin:
+  - name: ut-secret
+    enabled: true
+    description: "env://UT_SECRET_NOT_EXIST"
+`))
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"fmt"
+	"github.com/rookie-ninja/rk-query"
+	"go.uber.org/zap"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultShutdownHookTimeout = 5 * time.Second
+
+// ShutdownHook will be called in GinEntry.Interrupt after server stopped accepting requests.
+type ShutdownHook func(ctx context.Context) error
+
+// ShutdownHookOption option of ShutdownHook.
+type ShutdownHookOption func(*shutdownHook)
+
+// WithShutdownHookPriority hooks with lower priority run first, hooks with same priority run in registration order.
+func WithShutdownHookPriority(priority int) ShutdownHookOption {
+	return func(hook *shutdownHook) {
+		hook.priority = priority
+	}
+}
+
+// WithShutdownHookTimeout provide timeout of hook, default is 5 seconds.
+func WithShutdownHookTimeout(timeout time.Duration) ShutdownHookOption {
+	return func(hook *shutdownHook) {
+		if timeout > 0 {
+			hook.timeout = timeout
+		}
+	}
+}
+
+type shutdownHook struct {
+	name     string
+	priority int
+	timeout  time.Duration
+	seq      int
+	f        ShutdownHook
+}
+
+// shutdownHookRegistry keeps shutdown hooks of a GinEntry.
+type shutdownHookRegistry struct {
+	lock  sync.Mutex
+	seq   int
+	hooks []*shutdownHook
+}
+
+func newShutdownHookRegistry() *shutdownHookRegistry {
+	return &shutdownHookRegistry{
+		hooks: make([]*shutdownHook, 0),
+	}
+}
+
+// AddShutdownHook register hook which will be called in Interrupt.
+//
+// Hook with same name will be replaced and keeps its registration order.
+func (entry *GinEntry) AddShutdownHook(name string, f ShutdownHook, opts ...ShutdownHookOption) {
+	if f == nil {
+		return
+	}
+
+	hook := &shutdownHook{
+		name:    name,
+		timeout: defaultShutdownHookTimeout,
+		f:       f,
+	}
+
+	for i := range opts {
+		opts[i](hook)
+	}
+
+	reg := entry.shutdownHookRegistry
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	// replaced hook keeps its registration order
+	for i := range reg.hooks {
+		if reg.hooks[i].name == name {
+			hook.seq = reg.hooks[i].seq
+			reg.hooks[i] = hook
+			return
+		}
+	}
+
+	reg.seq++
+	hook.seq = reg.seq
+	reg.hooks = append(reg.hooks, hook)
+}
+
+// RemoveShutdownHook remove hook with name, returns false if not exists.
+func (entry *GinEntry) RemoveShutdownHook(name string) bool {
+	reg := entry.shutdownHookRegistry
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	for i := range reg.hooks {
+		if reg.hooks[i].name == name {
+			reg.hooks = append(reg.hooks[:i], reg.hooks[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// ListShutdownHooks returns names of hooks in the order they will be called.
+func (entry *GinEntry) ListShutdownHooks() []string {
+	hooks := entry.shutdownHookRegistry.sorted()
+
+	res := make([]string, 0, len(hooks))
+	for i := range hooks {
+		res = append(res, hooks[i].name)
+	}
+
+	return res
+}
+
+// sorted returns copy of hooks sorted by priority and registration order.
+func (reg *shutdownHookRegistry) sorted() []*shutdownHook {
+	reg.lock.Lock()
+	hooks := make([]*shutdownHook, len(reg.hooks))
+	copy(hooks, reg.hooks)
+	reg.lock.Unlock()
+
+	sort.SliceStable(hooks, func(i, j int) bool {
+		if hooks[i].priority != hooks[j].priority {
+			return hooks[i].priority < hooks[j].priority
+		}
+		return hooks[i].seq < hooks[j].seq
+	})
+
+	return hooks
+}
+
+// runShutdownHooks call hooks one by one and record outcome of each hook into event.
+func (entry *GinEntry) runShutdownHooks(ctx context.Context, event rkquery.Event, logger *zap.Logger) {
+	for _, hook := range entry.shutdownHookRegistry.sorted() {
+		start := time.Now()
+		err := runShutdownHook(ctx, hook)
+		elapsed := time.Since(start)
+
+		outcome := "success"
+		if err != nil {
+			outcome = err.Error()
+			event.AddErr(err)
+			logger.Warn("Shutdown hook failed.", zap.String("hook", hook.name), zap.Error(err))
+		}
+
+		event.AddPayloads(
+			zap.String(fmt.Sprintf("shutdownHook.%s", hook.name), outcome),
+			zap.Duration(fmt.Sprintf("shutdownHook.%s.elapsed", hook.name), elapsed))
+	}
+}
+
+// runShutdownHook call hook with timeout, hook panics will be returned as error.
+func runShutdownHook(ctx context.Context, hook *shutdownHook) error {
+	return runWithTimeout(ctx, hook.timeout, hook.f)
+}
+
+// runWithTimeout call f with timeout, panics will be returned as error.
+func runWithTimeout(ctx context.Context, timeout time.Duration, f func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recv := recover(); recv != nil {
+				errCh <- fmt.Errorf("panic: %v", recv)
+			}
+		}()
+		errCh <- f(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+}
+
+// WithShutdownHook provide ShutdownHook.
+func WithShutdownHook(name string, f ShutdownHook, opts ...ShutdownHookOption) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.AddShutdownHook(name, f, opts...)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"errors"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGinEntry_AddShutdownHook(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-hook"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	noop := func(context.Context) error { return nil }
+
+	// nil hook should be ignored
+	entry.AddShutdownHook("nil", nil)
+	assert.Empty(t, entry.ListShutdownHooks())
+
+	entry.AddShutdownHook("queue", noop)
+	entry.AddShutdownHook("db", noop, WithShutdownHookPriority(10))
+	entry.AddShutdownHook("cache", noop)
+	entry.AddShutdownHook("first", noop, WithShutdownHookPriority(-1))
+	assert.Equal(t, []string{"first", "queue", "cache", "db"}, entry.ListShutdownHooks())
+
+	// replace hook with same name
+	entry.AddShutdownHook("queue", noop, WithShutdownHookPriority(20))
+	assert.Equal(t, []string{"first", "cache", "db", "queue"}, entry.ListShutdownHooks())
+
+	// replaced hook keeps registration order among hooks with same priority
+	entry.AddShutdownHook("queue", noop)
+	assert.Equal(t, []string{"first", "queue", "cache", "db"}, entry.ListShutdownHooks())
+
+	assert.True(t, entry.RemoveShutdownHook("cache"))
+	assert.False(t, entry.RemoveShutdownHook("cache"))
+	assert.Equal(t, []string{"first", "queue", "db"}, entry.ListShutdownHooks())
+}
+
+func TestGinEntry_runShutdownHooks(t *testing.T) {
+	defer assertNotPanic(t)
+
+	// hooks timed out keep running in background, guard order with lock
+	lock := sync.Mutex{}
+	order := make([]string, 0)
+	appendOrder := func(name string) {
+		lock.Lock()
+		defer lock.Unlock()
+		order = append(order, name)
+	}
+
+	entry := RegisterGinEntry(
+		WithName("ut-hook"),
+		WithPort(0),
+		WithShutdownHook("second", func(context.Context) error {
+			appendOrder("second")
+			return errors.New("ut-error")
+		}),
+		WithShutdownHook("slow", func(ctx context.Context) error {
+			appendOrder("slow")
+			<-ctx.Done()
+			return nil
+		}, WithShutdownHookTimeout(50*time.Millisecond)),
+		WithShutdownHook("panic", func(context.Context) error {
+			appendOrder("panic")
+			panic("ut-panic")
+		}),
+		WithShutdownHook("first", func(context.Context) error {
+			appendOrder("first")
+			return nil
+		}, WithShutdownHookPriority(-1)))
+
+	entry.Interrupt(context.TODO())
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, []string{"first", "second", "slow", "panic"}, order)
+}
+
+func TestRunShutdownHook(t *testing.T) {
+	// success
+	assert.Nil(t, runShutdownHook(context.TODO(), &shutdownHook{
+		timeout: time.Second,
+		f:       func(context.Context) error { return nil },
+	}))
+
+	// timeout
+	err := runShutdownHook(context.TODO(), &shutdownHook{
+		timeout: 10 * time.Millisecond,
+		f: func(context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		},
+	})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+
+	// panic
+	err = runShutdownHook(context.TODO(), &shutdownHook{
+		timeout: time.Second,
+		f:       func(context.Context) error { panic("ut-panic") },
+	})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "ut-panic")
+}
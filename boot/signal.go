@@ -0,0 +1,282 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"go.uber.org/zap"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// BootSignal config of signal handling in GinEntry.
+//
+// Interrupt registers InterruptSignalHandler for SIGTERM and SIGINT,
+// enable it if GinEntry runs without application which interrupts entries on shutdown, like rk-boot.
+type BootSignal struct {
+	Enabled   bool `yaml:"enabled" json:"enabled"`
+	Interrupt bool `yaml:"interrupt" json:"interrupt"`
+}
+
+// SignalHandler will be called once GinEntry receives signal it registered for.
+type SignalHandler func(ctx context.Context, entry *GinEntry, sig os.Signal)
+
+// ReloadHook will be called while GinEntry is reloading, usually triggered by SIGHUP.
+type ReloadHook func(ctx context.Context) error
+
+// signalRegistry keeps signal handlers and reload hooks of a GinEntry.
+type signalRegistry struct {
+	lock        sync.Mutex
+	enabled     bool
+	interrupt   bool
+	handlers    map[os.Signal][]SignalHandler
+	reloadHooks []ReloadHook
+	sigCh       chan os.Signal
+	quitCh      chan struct{}
+}
+
+func newSignalRegistry() *signalRegistry {
+	return &signalRegistry{
+		handlers:    make(map[os.Signal][]SignalHandler),
+		reloadHooks: make([]ReloadHook, 0),
+	}
+}
+
+// defaultSignalHandlers SIGHUP reloads entry, SIGTERM and SIGINT interrupt entry gracefully if interrupt is true.
+//
+// SIGTERM and SIGINT are left to application by default, like rk-boot which interrupts all entries in order on shutdown.
+func defaultSignalHandlers(interrupt bool) map[os.Signal][]SignalHandler {
+	reload := func(ctx context.Context, entry *GinEntry, sig os.Signal) {
+		entry.Reload(ctx)
+	}
+
+	res := map[os.Signal][]SignalHandler{
+		syscall.SIGHUP: {reload},
+	}
+	if interrupt {
+		res[syscall.SIGTERM] = []SignalHandler{InterruptSignalHandler}
+		res[syscall.SIGINT] = []SignalHandler{InterruptSignalHandler}
+	}
+
+	return res
+}
+
+// InterruptSignalHandler interrupts entry gracefully.
+//
+//	rkgin.WithSignalHandler(syscall.SIGTERM, rkgin.InterruptSignalHandler)
+func InterruptSignalHandler(ctx context.Context, entry *GinEntry, sig os.Signal) {
+	entry.Interrupt(ctx)
+}
+
+// AddSignalHandler register handler for signal.
+//
+// Handlers registered by user will replace default behavior of the signal,
+// call it multiple times with same signal in order to chain handlers.
+func (entry *GinEntry) AddSignalHandler(sig os.Signal, handler SignalHandler) {
+	if sig == nil || handler == nil {
+		return
+	}
+
+	reg := entry.signalRegistry
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	reg.handlers[sig] = append(reg.handlers[sig], handler)
+
+	// listen on new signal if listener already started
+	if reg.sigCh != nil {
+		signal.Notify(reg.sigCh, sig)
+	}
+}
+
+// RemoveSignalHandler remove all handlers of signal, default handler will be used if exists.
+//
+// Signal without default handler is not listened any more, so that default action of process applies.
+func (entry *GinEntry) RemoveSignalHandler(sig os.Signal) {
+	reg := entry.signalRegistry
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	delete(reg.handlers, sig)
+
+	// listen on remaining signals only if listener already started
+	if reg.sigCh != nil {
+		signal.Stop(reg.sigCh)
+		signal.Notify(reg.sigCh, reg.listenedSignals()...)
+	}
+}
+
+// AddReloadHook register hook which will be called in Reload.
+func (entry *GinEntry) AddReloadHook(hook ReloadHook) {
+	if hook == nil {
+		return
+	}
+
+	reg := entry.signalRegistry
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	reg.reloadHooks = append(reg.reloadHooks, hook)
+}
+
+// Reload config entries from their source files, sync loggers, reopen access log files and run reload hooks.
+//
+// Files of access logs written by logging middleware are reopened, so that files moved by logrotate are created
+// again, files of rk-entry logger and event entries are kept open, since their writers are not exposed.
+func (entry *GinEntry) Reload(ctx context.Context) {
+	event, logger := entry.logBasicInfo("Reload", ctx)
+	defer entry.EventEntry.Finish(event)
+
+	// reload config entries which were read from files
+	for _, v := range rkentry.GlobalAppCtx.ListEntriesByType(rkentry.ConfigEntryType) {
+		configEntry, ok := v.(*rkentry.ConfigEntry)
+		if !ok || len(configEntry.ConfigFileUsed()) < 1 {
+			continue
+		}
+
+		if err := configEntry.ReadInConfig(); err != nil {
+			event.AddErr(err)
+			logger.Warn("Failed to reload config entry.",
+				zap.String("configEntry", configEntry.GetName()), zap.Error(err))
+		}
+	}
+
+	// flush loggers and close access log files, lumberjack opens them again on next write
+	entry.LoggerEntry.Sync()
+	entry.EventEntry.Sync()
+	if err := reopenAccessLogFiles(); err != nil {
+		event.AddErr(err)
+		logger.Warn("Failed to reopen access log files.", zap.Error(err))
+	}
+
+	reg := entry.signalRegistry
+	reg.lock.Lock()
+	hooks := make([]ReloadHook, len(reg.reloadHooks))
+	copy(hooks, reg.reloadHooks)
+	reg.lock.Unlock()
+
+	for i := range hooks {
+		if err := hooks[i](ctx); err != nil {
+			event.AddErr(err)
+			logger.Warn(fmt.Sprintf("Reload hook %d failed.", i), zap.Error(err))
+		}
+	}
+}
+
+// IsSignalEnabled Is signal handling enabled?
+func (entry *GinEntry) IsSignalEnabled() bool {
+	return entry.signalRegistry.enabled
+}
+
+// listenedSignals signals with default handlers or handlers registered by user, lock is held by caller.
+func (reg *signalRegistry) listenedSignals() []os.Signal {
+	sigs := make([]os.Signal, 0)
+	for k := range defaultSignalHandlers(reg.interrupt) {
+		sigs = append(sigs, k)
+	}
+	for k := range reg.handlers {
+		if _, ok := defaultSignalHandlers(reg.interrupt)[k]; !ok {
+			sigs = append(sigs, k)
+		}
+	}
+
+	return sigs
+}
+
+// startSignalListener listen on signals with handlers and dispatch them until stopSignalListener called.
+func (entry *GinEntry) startSignalListener() {
+	reg := entry.signalRegistry
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	if !reg.enabled || reg.sigCh != nil {
+		return
+	}
+
+	reg.sigCh = make(chan os.Signal, 1)
+	reg.quitCh = make(chan struct{})
+
+	signal.Notify(reg.sigCh, reg.listenedSignals()...)
+
+	go func(sigCh chan os.Signal, quitCh chan struct{}) {
+		for {
+			select {
+			case sig := <-sigCh:
+				entry.handleSignal(sig)
+			case <-quitCh:
+				return
+			}
+		}
+	}(reg.sigCh, reg.quitCh)
+}
+
+// stopSignalListener stop listening signals, safe to call from signal handlers.
+func (entry *GinEntry) stopSignalListener() {
+	reg := entry.signalRegistry
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	if reg.sigCh == nil {
+		return
+	}
+
+	signal.Stop(reg.sigCh)
+	close(reg.quitCh)
+	reg.sigCh = nil
+	reg.quitCh = nil
+}
+
+// handleSignal run handlers registered by user, fallback to default ones.
+func (entry *GinEntry) handleSignal(sig os.Signal) {
+	reg := entry.signalRegistry
+	reg.lock.Lock()
+	handlers := reg.handlers[sig]
+	if len(handlers) < 1 {
+		handlers = defaultSignalHandlers(reg.interrupt)[sig]
+	}
+	handlers = append([]SignalHandler{}, handlers...)
+	reg.lock.Unlock()
+
+	entry.LoggerEntry.Info("Received signal.",
+		zap.String("entryName", entry.entryName), zap.String("signal", sig.String()))
+
+	ctx := context.Background()
+	for i := range handlers {
+		handlers[i](ctx, entry, sig)
+	}
+}
+
+// WithSignalEnabled enable signal handling of GinEntry.
+func WithSignalEnabled(enabled bool) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.signalRegistry.enabled = enabled
+	}
+}
+
+// WithSignalInterrupt interrupt GinEntry gracefully on SIGTERM and SIGINT if no handler registered for them.
+func WithSignalInterrupt(interrupt bool) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.signalRegistry.interrupt = interrupt
+	}
+}
+
+// WithSignalHandler provide SignalHandler for signal.
+func WithSignalHandler(sig os.Signal, handler SignalHandler) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.AddSignalHandler(sig, handler)
+	}
+}
+
+// WithReloadHook provide ReloadHook.
+func WithReloadHook(hook ReloadHook) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.AddReloadHook(hook)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"errors"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+)
+
+func TestGinEntry_AddSignalHandler(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-signal"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	// nil signal or handler should be ignored
+	entry.AddSignalHandler(nil, func(context.Context, *GinEntry, os.Signal) {})
+	entry.AddSignalHandler(syscall.SIGHUP, nil)
+	assert.Empty(t, entry.signalRegistry.handlers)
+
+	// custom handler replaces default one
+	called := 0
+	entry.AddSignalHandler(syscall.SIGHUP, func(ctx context.Context, e *GinEntry, sig os.Signal) {
+		assert.Equal(t, entry, e)
+		assert.Equal(t, syscall.SIGHUP, sig)
+		called++
+	})
+	entry.handleSignal(syscall.SIGHUP)
+	assert.Equal(t, 1, called)
+
+	// fallback to default handler which will reload entry
+	entry.RemoveSignalHandler(syscall.SIGHUP)
+	reloaded := false
+	entry.AddReloadHook(func(context.Context) error {
+		reloaded = true
+		return nil
+	})
+	entry.handleSignal(syscall.SIGHUP)
+	assert.Equal(t, 1, called)
+	assert.True(t, reloaded)
+
+	// SIGTERM is left to application unless handler registered
+	atomic.StoreInt32(&entry.ready, 1)
+	entry.handleSignal(syscall.SIGTERM)
+	assert.True(t, entry.IsReady())
+
+	entry.AddSignalHandler(syscall.SIGTERM, InterruptSignalHandler)
+	entry.handleSignal(syscall.SIGTERM)
+	assert.False(t, entry.IsReady())
+
+	// SIGTERM interrupts entry by default if interrupt enabled
+	entry.RemoveSignalHandler(syscall.SIGTERM)
+	WithSignalInterrupt(true)(entry)
+	atomic.StoreInt32(&entry.ready, 1)
+	entry.handleSignal(syscall.SIGTERM)
+	assert.False(t, entry.IsReady())
+}
+
+func TestGinEntry_Reload(t *testing.T) {
+	defer assertNotPanic(t)
+
+	hooks := 0
+	entry := RegisterGinEntry(
+		WithName("ut-reload"),
+		WithPort(0),
+		WithReloadHook(func(context.Context) error {
+			hooks++
+			return errors.New("ut-error")
+		}),
+		WithReloadHook(func(context.Context) error {
+			hooks++
+			return nil
+		}))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.Reload(context.TODO())
+	assert.Equal(t, 2, hooks)
+}
+
+func TestGinEntry_SignalListener(t *testing.T) {
+	defer assertNotPanic(t)
+
+	// disabled by default
+	entry := RegisterGinEntry(WithName("ut-signal-listener"), WithPort(0))
+	entry.startSignalListener()
+	assert.False(t, entry.IsSignalEnabled())
+	assert.Nil(t, entry.signalRegistry.sigCh)
+	rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	// enabled
+	entry = RegisterGinEntry(
+		WithName("ut-signal-listener"),
+		WithPort(0),
+		WithSignalEnabled(true),
+		WithSignalHandler(syscall.SIGTERM, func(context.Context, *GinEntry, os.Signal) {}))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.startSignalListener()
+	assert.True(t, entry.IsSignalEnabled())
+	assert.NotNil(t, entry.signalRegistry.sigCh)
+
+	assert.ElementsMatch(t, []os.Signal{syscall.SIGHUP, syscall.SIGTERM}, entry.signalRegistry.listenedSignals())
+
+	// start twice should be fine
+	entry.startSignalListener()
+
+	// SIGTERM without default handler is not listened any more
+	entry.RemoveSignalHandler(syscall.SIGTERM)
+	assert.ElementsMatch(t, []os.Signal{syscall.SIGHUP}, entry.signalRegistry.listenedSignals())
+
+	// SIGHUP keeps default handler
+	entry.RemoveSignalHandler(syscall.SIGHUP)
+	assert.ElementsMatch(t, []os.Signal{syscall.SIGHUP}, entry.signalRegistry.listenedSignals())
+
+	entry.stopSignalListener()
+	assert.Nil(t, entry.signalRegistry.sigCh)
+
+	// stop twice should be fine
+	entry.stopSignalListener()
+}
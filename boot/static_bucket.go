@@ -0,0 +1,544 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// sha256 of empty payload, all requests sent to bucket are without body
+	bucketEmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	bucketSignAlgorithm    = "AWS4-HMAC-SHA256"
+	bucketAmzDateFormat    = "20060102T150405Z"
+	defaultBucketTimeoutMs = 30000
+)
+
+// BootStaticBucket config of object storage bucket used as source of static file handler.
+//
+// Buckets are accessed with S3 compatible API signed with AWS signature version 4, GCS is accessed with
+// interoperability API and HMAC keys. Requests are sent anonymously if AccessKey is empty.
+// Each request, including reading of response body, times out in TimeoutMs, 30000 by default.
+type BootStaticBucket struct {
+	Endpoint  string `yaml:"endpoint" json:"endpoint"`
+	Region    string `yaml:"region" json:"region"`
+	Bucket    string `yaml:"bucket" json:"bucket"`
+	Prefix    string `yaml:"prefix" json:"prefix"`
+	AccessKey string `yaml:"accessKey" json:"accessKey"`
+	SecretKey string `yaml:"secretKey" json:"secretKey"`
+	PathStyle bool   `yaml:"pathStyle" json:"pathStyle"`
+	TimeoutMs int    `yaml:"timeoutMs" json:"timeoutMs"`
+}
+
+// bucketFS implements fs.FS with S3 compatible API.
+//
+// Objects are read lazily with range requests, so seeking in large objects does not download skipped bytes.
+// Directories are emulated with common prefixes delimited by "/".
+type bucketFS struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	prefix    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	client    *http.Client
+	now       func() time.Time
+}
+
+// newBucketFS creates bucketFS, defaultEndpoint and defaultRegion are used if missing in config.
+func newBucketFS(boot *BootStaticBucket, defaultEndpoint, defaultRegion string, pathStyle bool) (*bucketFS, error) {
+	if len(boot.Bucket) < 1 {
+		return nil, errors.New("bucket of static file handler is empty")
+	}
+
+	timeoutMs := boot.TimeoutMs
+	if timeoutMs < 1 {
+		timeoutMs = defaultBucketTimeoutMs
+	}
+
+	res := &bucketFS{
+		region:    boot.Region,
+		bucket:    boot.Bucket,
+		prefix:    strings.Trim(boot.Prefix, "/"),
+		accessKey: boot.AccessKey,
+		secretKey: boot.SecretKey,
+		pathStyle: boot.PathStyle || pathStyle,
+		client:    &http.Client{Timeout: time.Duration(timeoutMs) * time.Millisecond},
+		now:       time.Now,
+	}
+
+	if len(res.region) < 1 {
+		res.region = defaultRegion
+	}
+
+	endpoint := boot.Endpoint
+	if len(endpoint) < 1 {
+		endpoint = strings.ReplaceAll(defaultEndpoint, "{region}", res.region)
+	}
+	if len(endpoint) < 1 {
+		return nil, errors.New("endpoint of static file handler bucket is empty")
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint of static file handler bucket, %v", err)
+	}
+	res.endpoint = u
+
+	return res, nil
+}
+
+// Open opens object or directory, directory is returned if object does not exist but there are objects under it.
+func (b *bucketFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if name == "." {
+		return &bucketFile{fs: b, key: b.prefix, info: newBucketDirInfo(".")}, nil
+	}
+
+	key := b.key(name)
+	resp, err := b.do(http.MethodHead, key, nil, nil)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return &bucketFile{fs: b, key: key, info: newBucketFileInfoFromHeader(path.Base(name), resp.Header)}, nil
+	}
+
+	if resp.StatusCode != http.StatusNotFound {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: bucketStatusError(resp)}
+	}
+
+	// not an object, try as directory
+	res, err := b.list(key+"/", "", 1)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if len(res.Contents) < 1 && len(res.CommonPrefixes) < 1 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &bucketFile{fs: b, key: key, info: newBucketDirInfo(path.Base(name))}, nil
+}
+
+// key returns object key of name with prefix.
+func (b *bucketFS) key(name string) string {
+	if name == "." {
+		return b.prefix
+	}
+
+	if len(b.prefix) < 1 {
+		return name
+	}
+
+	return b.prefix + "/" + name
+}
+
+// readDir lists objects and common prefixes right under directory of key.
+func (b *bucketFS) readDir(key string) ([]fs.DirEntry, error) {
+	prefix := key
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+
+	res := make([]fs.DirEntry, 0)
+	token := ""
+	for {
+		page, err := b.list(prefix, token, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range page.CommonPrefixes {
+			res = append(res, fs.FileInfoToDirEntry(newBucketDirInfo(path.Base(strings.TrimSuffix(p.Prefix, "/")))))
+		}
+		for _, c := range page.Contents {
+			// skip directory marker created by console of cloud providers
+			if c.Key == prefix || strings.HasSuffix(c.Key, "/") {
+				continue
+			}
+			res = append(res, fs.FileInfoToDirEntry(&bucketFileInfo{
+				name:    path.Base(c.Key),
+				size:    c.Size,
+				modTime: c.LastModified,
+			}))
+		}
+
+		if !page.IsTruncated || len(page.NextContinuationToken) < 1 {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name() < res[j].Name()
+	})
+
+	return res, nil
+}
+
+// bucketListResult response of ListObjectsV2.
+type bucketListResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+}
+
+// list calls ListObjectsV2 with delimiter "/", maxKeys is ignored if not positive.
+func (b *bucketFS) list(prefix, token string, maxKeys int) (*bucketListResult, error) {
+	query := url.Values{
+		"list-type": []string{"2"},
+		"delimiter": []string{"/"},
+		"prefix":    []string{prefix},
+	}
+	if len(token) > 0 {
+		query.Set("continuation-token", token)
+	}
+	if maxKeys > 0 {
+		query.Set("max-keys", strconv.Itoa(maxKeys))
+	}
+
+	resp, err := b.do(http.MethodGet, "", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, bucketStatusError(resp)
+	}
+
+	res := &bucketListResult{}
+	if err := xml.NewDecoder(resp.Body).Decode(res); err != nil {
+		return nil, fmt.Errorf("failed to decode list result of bucket, %v", err)
+	}
+
+	return res, nil
+}
+
+// do sends signed request of object key, bucket itself is requested if key is empty.
+func (b *bucketFS) do(method, key string, query url.Values, header http.Header) (*http.Response, error) {
+	u := *b.endpoint
+	u.RawQuery = bucketCanonicalQuery(query)
+	if b.pathStyle {
+		u.Path = "/" + b.bucket + "/" + key
+	} else {
+		u.Host = b.bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+	u.RawPath = bucketUriEncode(u.Path, false)
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	b.sign(req, b.now())
+
+	return b.client.Do(req)
+}
+
+// sign request with AWS signature version 4, host, range and x-amz-* headers are signed.
+func (b *bucketFS) sign(req *http.Request, now time.Time) {
+	if len(b.accessKey) < 1 {
+		return
+	}
+
+	amzDate := now.UTC().Format(bucketAmzDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", bucketEmptyPayloadHash)
+
+	headers := map[string]string{
+		"host": req.URL.Host,
+	}
+	for k, v := range req.Header {
+		lower := strings.ToLower(k)
+		if lower == "range" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := &strings.Builder{}
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		bucketUriEncode(req.URL.Path, false),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		bucketEmptyPayloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{amzDate[:8], b.region, "s3", "aws4_request"}, "/")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{bucketSignAlgorithm, amzDate, scope, hex.EncodeToString(hash[:])}, "\n")
+
+	key := []byte("AWS4" + b.secretKey)
+	for _, v := range []string{amzDate[:8], b.region, "s3", "aws4_request"} {
+		key = bucketHmac(key, v)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		bucketSignAlgorithm, b.accessKey, scope, signedHeaders, hex.EncodeToString(bucketHmac(key, stringToSign))))
+}
+
+// bucketHmac returns HMAC-SHA256 of data.
+func bucketHmac(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// bucketCanonicalQuery encodes query sorted by key as required by signature version 4.
+func bucketCanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range query[k] {
+			pairs = append(pairs, bucketUriEncode(k, true)+"="+bucketUriEncode(v, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// bucketUriEncode encodes every byte except unreserved characters, "/" is kept unless encodeSlash is true.
+func bucketUriEncode(s string, encodeSlash bool) string {
+	res := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			res.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			res.WriteByte(c)
+		default:
+			res.WriteString(fmt.Sprintf("%%%02X", c))
+		}
+	}
+
+	return res.String()
+}
+
+// bucketStatusError converts unexpected status code into error, 404 and 403 are converted into fs errors.
+func bucketStatusError(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return fs.ErrNotExist
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return fs.ErrPermission
+	}
+
+	return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, (&url.URL{
+		Scheme: resp.Request.URL.Scheme,
+		Host:   resp.Request.URL.Host,
+		Path:   resp.Request.URL.Path,
+	}).String())
+}
+
+// bucketFileInfo implements fs.FileInfo of object or directory.
+type bucketFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+}
+
+func newBucketDirInfo(name string) *bucketFileInfo {
+	return &bucketFileInfo{name: name, isDir: true}
+}
+
+func newBucketFileInfoFromHeader(name string, header http.Header) *bucketFileInfo {
+	res := &bucketFileInfo{name: name}
+	res.size, _ = strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	res.modTime, _ = http.ParseTime(header.Get("Last-Modified"))
+	return res
+}
+
+func (i *bucketFileInfo) Name() string       { return i.name }
+func (i *bucketFileInfo) Size() int64        { return i.size }
+func (i *bucketFileInfo) ModTime() time.Time { return i.modTime }
+func (i *bucketFileInfo) IsDir() bool        { return i.isDir }
+func (i *bucketFileInfo) Sys() interface{}   { return nil }
+
+func (i *bucketFileInfo) Mode() fs.FileMode {
+	if i.isDir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+// bucketFile implements fs.File, io.Seeker and fs.ReadDirFile.
+type bucketFile struct {
+	fs         *bucketFS
+	key        string
+	info       *bucketFileInfo
+	offset     int64
+	body       io.ReadCloser
+	bodyOffset int64
+	entries    []fs.DirEntry
+	listed     bool
+}
+
+// Stat returns info of object or directory.
+func (f *bucketFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// Read reads object from current offset, object is requested from offset again after seeking.
+func (f *bucketFile) Read(p []byte) (int, error) {
+	if f.info.isDir {
+		return 0, &fs.PathError{Op: "read", Path: f.info.name, Err: errors.New("is a directory")}
+	}
+
+	if f.offset >= f.info.size {
+		return 0, io.EOF
+	}
+
+	if f.body == nil || f.bodyOffset != f.offset {
+		if f.body != nil {
+			f.body.Close()
+			f.body = nil
+		}
+
+		resp, err := f.fs.do(http.MethodGet, f.key, nil, http.Header{
+			"Range": []string{fmt.Sprintf("bytes=%d-", f.offset)},
+		})
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return 0, bucketStatusError(resp)
+		}
+
+		// range is ignored by server, skip bytes before offset
+		if resp.StatusCode == http.StatusOK && f.offset > 0 {
+			if _, err := io.CopyN(io.Discard, resp.Body, f.offset); err != nil {
+				resp.Body.Close()
+				return 0, err
+			}
+		}
+
+		f.body = resp.Body
+		f.bodyOffset = f.offset
+	}
+
+	n, err := f.body.Read(p)
+	f.offset += int64(n)
+	f.bodyOffset = f.offset
+	if err == io.EOF && f.offset < f.info.size {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return n, err
+}
+
+// Seek sets offset of next Read.
+func (f *bucketFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.info.size
+	}
+
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.info.name, Err: fs.ErrInvalid}
+	}
+
+	f.offset = offset
+	return offset, nil
+}
+
+// ReadDir lists directory with the same semantic as fs.ReadDirFile.
+func (f *bucketFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !f.info.isDir {
+		return nil, &fs.PathError{Op: "readdir", Path: f.info.name, Err: errors.New("not a directory")}
+	}
+
+	if !f.listed {
+		entries, err := f.fs.readDir(f.key)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: f.info.name, Err: err}
+		}
+		f.entries, f.listed = entries, true
+	}
+
+	if n <= 0 {
+		res := f.entries
+		f.entries = nil
+		return res, nil
+	}
+
+	if len(f.entries) < 1 {
+		return nil, io.EOF
+	}
+
+	if n > len(f.entries) {
+		n = len(f.entries)
+	}
+	res := f.entries[:n]
+	f.entries = f.entries[n:]
+
+	return res, nil
+}
+
+// Close closes body of object if opened.
+func (f *bucketFile) Close() error {
+	if f.body != nil {
+		return f.body.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// BootStaticCacheControl Cache-Control header of static files matching Pattern.
+//
+// Pattern follows path.Match and is matched against path of file relative to static file handler,
+// pattern without slash is matched against base name of file. The first matching rule wins.
+//
+//	cacheControl:
+//	  - pattern: "/assets/*"
+//	    value: "public, max-age=31536000, immutable"
+//	  - pattern: "*.html"
+//	    value: "no-cache"
+type BootStaticCacheControl struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	Value   string `yaml:"value" json:"value"`
+}
+
+// WithStaticCacheControl provide Cache-Control rules of static file handler.
+func WithStaticCacheControl(rules []BootStaticCacheControl) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.staticCacheControl = rules
+	}
+}
+
+// getStaticCacheControl returns Cache-Control value of file p, empty string is returned if no rule matches.
+func (entry *GinEntry) getStaticCacheControl(p string) string {
+	p = path.Clean("/" + p)
+	for _, rule := range entry.staticCacheControl {
+		target := p
+		if !strings.Contains(rule.Pattern, "/") {
+			target = path.Base(p)
+		}
+
+		if matched, _ := path.Match(rule.Pattern, target); matched {
+			return rule.Value
+		}
+	}
+
+	return ""
+}
+
+// staticETag returns weak ETag of file generated from size and modification time.
+func staticETag(info fs.FileInfo) string {
+	return fmt.Sprintf(`W/"%x-%x"`, info.Size(), info.ModTime().UnixNano())
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestGinEntry_GetStaticCacheControl(t *testing.T) {
+	// without rules
+	entry := &GinEntry{}
+	assert.Empty(t, entry.getStaticCacheControl("/a.js"))
+
+	WithStaticCacheControl([]BootStaticCacheControl{
+		{Pattern: "/assets/*", Value: "public, max-age=31536000, immutable"},
+		{Pattern: "*.html", Value: "no-cache"},
+		{Pattern: "*", Value: "max-age=60"},
+	})(entry)
+
+	assert.Equal(t, "public, max-age=31536000, immutable", entry.getStaticCacheControl("/assets/app.js"))
+	// first matching rule wins
+	assert.Equal(t, "public, max-age=31536000, immutable", entry.getStaticCacheControl("assets/index.html"))
+	// pattern without slash matches base name
+	assert.Equal(t, "no-cache", entry.getStaticCacheControl("/docs/index.html"))
+	assert.Equal(t, "max-age=60", entry.getStaticCacheControl("/docs/a.txt"))
+}
+
+func TestStaticETag(t *testing.T) {
+	modTime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"a.txt": &fstest.MapFile{Data: []byte("ut-content"), ModTime: modTime},
+		"b.txt": &fstest.MapFile{Data: []byte("ut-content"), ModTime: modTime.Add(time.Second)},
+	}
+
+	a, _ := fsys.Stat("a.txt")
+	b, _ := fsys.Stat("b.txt")
+
+	assert.Equal(t, staticETag(a), staticETag(a))
+	assert.NotEqual(t, staticETag(a), staticETag(b))
+	assert.Regexp(t, `^W/"a-[0-9a-f]+"$`, staticETag(a))
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// staticIcons maps file extension to icon in assets of rk-entry, same as StaticFileHandlerEntry.
+var staticIcons = map[string]string{
+	"ar": "pkg.png", "zip": "pkg.png", "rar": "pkg.png", "gz": "pkg.png", "xz": "pkg.png", "gz2": "pkg.png",
+	"tar": "pkg.png", "dep": "pkg.png", "rpm": "pkg.png",
+	"jpg": "image.png", "jpeg": "image.png", "png": "image.png", "gif": "image.png", "svg": "image.png",
+	"wav": "image.png", "ogg": "image.png", "flac": "image.png",
+	"mp3": "audio.png",
+	"pdf": "pdf.png",
+	"xls": "doc.png", "odt": "doc.png", "ods": "doc.png", "doc": "doc.png", "docx": "doc.png", "xlsx": "doc.png",
+	"ppt": "doc.png", "txt": "doc.png",
+}
+
+// staticHandler returns handler of static file handler which serves files from staticFS.
+//
+// Directories are rendered as listing if enabled, files are served as attachment.
+func (entry *GinEntry) staticHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		p := path.Clean("/" + strings.TrimPrefix(ctx.Request.URL.Path, entry.StaticFileEntry.Path))
+		name := strings.TrimPrefix(p, "/")
+		if len(name) < 1 {
+			name = "."
+		}
+
+		file, err := entry.staticFS.Open(name)
+		if err != nil {
+			code := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				code = http.StatusNotFound
+			case errors.Is(err, fs.ErrPermission):
+				code = http.StatusForbidden
+			}
+			ctx.JSON(code, rkmid.GetErrorBuilder().New(code, "Failed to open file", err))
+			return
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError,
+				rkmid.GetErrorBuilder().New(http.StatusInternalServerError, "Failed to stat file", err))
+			return
+		}
+
+		if info.IsDir() {
+			entry.serveStaticDir(ctx, file, name, p)
+			return
+		}
+
+		entry.serveStaticFile(ctx, file, info, p)
+	}
+}
+
+// serveStaticFile serves file as attachment, file is read into memory if it could not be seeked.
+//
+// ETag and Last-Modified are always set, conditional and range requests are handled by http.ServeContent.
+func (entry *GinEntry) serveStaticFile(ctx *gin.Context, file fs.File, info fs.FileInfo, p string) {
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError,
+				rkmid.GetErrorBuilder().New(http.StatusInternalServerError, "Failed to read file", err))
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": info.Name()}))
+	ctx.Header("Content-Type", "application/octet-stream")
+	ctx.Header("ETag", staticETag(info))
+	if cacheControl := entry.getStaticCacheControl(p); len(cacheControl) > 0 {
+		ctx.Header("Cache-Control", cacheControl)
+	}
+	http.ServeContent(ctx.Writer, ctx.Request, info.Name(), info.ModTime(), content)
+}
+
+// staticIcon returns base64 encoded icon of file.
+func staticIcon(name string, isDir bool) string {
+	icon := "file.png"
+	if isDir {
+		icon = "folder.png"
+	} else if v, ok := staticIcons[strings.TrimPrefix(path.Ext(name), ".")]; ok {
+		icon = v
+	}
+
+	data, _ := rkembed.AssetsFS.ReadFile(path.Join("assets/static/icons", icon))
+	return base64.StdEncoding.EncodeToString(data)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestGinEntry_StaticHandler(t *testing.T) {
+	staticEntry := rkentry.RegisterStaticFileHandlerEntry(&rkentry.BootStaticFileHandler{Enabled: true})
+	staticEntry.Bootstrap(context.TODO())
+
+	modTime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry := RegisterGinEntry(
+		WithName("ut-static-handler"),
+		WithStaticFileHandlerEntry(staticEntry),
+		WithStaticFS(fstest.MapFS{
+			"a.txt":     &fstest.MapFile{Data: []byte("ut-content"), ModTime: modTime},
+			"dir/b.zip": &fstest.MapFile{Data: []byte("ut-zip"), ModTime: modTime},
+		}),
+		WithStaticCacheControl([]BootStaticCacheControl{
+			{Pattern: "*.txt", Value: "max-age=60"},
+		}))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.Router.GET("/static/*any", entry.staticHandler())
+
+	serve := func(url string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	// root directory
+	w := serve("/static/", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<title>Index of /</title>")
+	assert.Contains(t, w.Body.String(), `href="/static/a.txt"`)
+	assert.Contains(t, w.Body.String(), `href="/static/dir/"`)
+
+	// sub directory
+	w = serve("/static/dir", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `href="/static/dir/b.zip"`)
+
+	// file
+	w = serve("/static/a.txt", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ut-content", w.Body.String())
+	assert.Equal(t, "attachment; filename=a.txt", w.Header().Get("Content-Disposition"))
+	assert.Equal(t, modTime.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+	assert.Equal(t, "max-age=60", w.Header().Get("Cache-Control"))
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// cache control not matched
+	w = serve("/static/dir/b.zip", nil)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+
+	// range
+	w = serve("/static/a.txt", http.Header{"Range": []string{"bytes=3-"}})
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "content", w.Body.String())
+
+	// if-none-match
+	w = serve("/static/a.txt", http.Header{"If-None-Match": []string{etag}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = serve("/static/a.txt", http.Header{"If-None-Match": []string{`W/"other"`}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// if-modified-since
+	w = serve("/static/a.txt", http.Header{"If-Modified-Since": []string{modTime.Format(http.TimeFormat)}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	w = serve("/static/a.txt", http.Header{"If-Modified-Since": []string{modTime.Add(-time.Hour).Format(http.TimeFormat)}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// if-range with stale etag serves full content
+	w = serve("/static/a.txt", http.Header{"Range": []string{"bytes=3-"}, "If-Range": []string{`"stale"`}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ut-content", w.Body.String())
+
+	// path traversal is cleaned
+	w = serve("/static/../a.txt", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// not exist
+	w = serve("/static/not-exist", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
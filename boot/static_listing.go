@@ -0,0 +1,227 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed assets/static_listing.html
+var staticListingRaw string
+
+var staticListingTemplate = template.Must(template.New("static-listing").Parse(staticListingRaw))
+
+// BootStaticListing config of directory listing of static file handler.
+//
+// Listing is enabled by default, Dirs overrides it per directory with the nearest configured ancestor winning.
+//
+//	listing:
+//	  enabled: true
+//	  dirs:
+//	    /private: false
+//	    /private/public: true
+type BootStaticListing struct {
+	Enabled *bool           `yaml:"enabled" json:"enabled"`
+	Dirs    map[string]bool `yaml:"dirs" json:"dirs"`
+}
+
+// WithStaticListing provide config of directory listing of static file handler.
+func WithStaticListing(listing *BootStaticListing) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.staticListing = listing
+	}
+}
+
+// isStaticListingEnabled returns true if listing of directory p is enabled.
+func (entry *GinEntry) isStaticListingEnabled(p string) bool {
+	if entry.staticListing == nil {
+		return true
+	}
+
+	res, matched := true, ""
+	if entry.staticListing.Enabled != nil {
+		res = *entry.staticListing.Enabled
+	}
+
+	p = path.Clean("/" + p)
+	for k, v := range entry.staticListing.Dirs {
+		dir := path.Clean("/" + k)
+		if (p == dir || dir == "/" || strings.HasPrefix(p, dir+"/")) && len(dir) > len(matched) {
+			res, matched = v, dir
+		}
+	}
+
+	return res
+}
+
+// staticListingPage data of directory listing page.
+type staticListingPage struct {
+	Path        string
+	Breadcrumbs []*staticListingLink
+	ParentUrl   string
+	FolderIcon  string
+	NextOrder   map[string]string
+	Files       []*staticListingFile
+}
+
+// staticListingLink link in breadcrumbs.
+type staticListingLink struct {
+	Name string
+	Url  string
+}
+
+// staticListingFile file or directory in listing.
+type staticListingFile struct {
+	Name    string
+	Url     string
+	Icon    string
+	IsDir   bool
+	Size    string
+	ModTime string
+	size    int64
+	modTime time.Time
+}
+
+// serveStaticDir renders listing of directory p, 403 is returned if listing is disabled.
+//
+// Files could be sorted with query parameters sort of name, size or time and order of asc or desc,
+// directories are always listed before files.
+func (entry *GinEntry) serveStaticDir(ctx *gin.Context, file fs.File, name, p string) {
+	if !entry.isStaticListingEnabled(p) {
+		ctx.JSON(http.StatusForbidden,
+			rkmid.GetErrorBuilder().New(http.StatusForbidden, "Directory listing is disabled"))
+		return
+	}
+
+	var dirEntries []fs.DirEntry
+	var err error
+	if dir, ok := file.(fs.ReadDirFile); ok {
+		dirEntries, err = dir.ReadDir(-1)
+	} else {
+		dirEntries, err = fs.ReadDir(entry.staticFS, name)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError,
+			rkmid.GetErrorBuilder().New(http.StatusInternalServerError, "Failed to read directory", err))
+		return
+	}
+
+	base := path.Join(entry.StaticFileEntry.Path, p)
+	files := make([]*staticListingFile, 0, len(dirEntries))
+	for _, v := range dirEntries {
+		info, err := v.Info()
+		if err != nil {
+			continue
+		}
+
+		f := &staticListingFile{
+			Name:    v.Name(),
+			Url:     path.Join(base, v.Name()),
+			Icon:    staticIcon(v.Name(), v.IsDir()),
+			IsDir:   v.IsDir(),
+			Size:    formatStaticSize(info.Size()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+		if !f.modTime.IsZero() {
+			f.ModTime = f.modTime.Format("2006-01-02 15:04:05")
+		}
+		files = append(files, f)
+	}
+
+	sortBy, order := ctx.Query("sort"), ctx.Query("order")
+	sortStaticListing(files, sortBy, order == "desc")
+
+	page := &staticListingPage{
+		Path:       p,
+		FolderIcon: staticIcon("", true),
+		NextOrder:  map[string]string{"name": "asc", "size": "asc", "time": "asc"},
+		Files:      files,
+	}
+	if len(sortBy) < 1 {
+		sortBy = "name"
+	}
+	if order != "desc" {
+		page.NextOrder[sortBy] = "desc"
+	}
+
+	page.Breadcrumbs = append(page.Breadcrumbs, &staticListingLink{
+		Name: strings.TrimSuffix(entry.StaticFileEntry.Path, "/"),
+		Url:  entry.StaticFileEntry.Path,
+	})
+	if p != "/" {
+		page.ParentUrl = path.Join(entry.StaticFileEntry.Path, path.Dir(p)) + "/"
+		segments := strings.Split(strings.Trim(p, "/"), "/")
+		for i := range segments {
+			page.Breadcrumbs = append(page.Breadcrumbs, &staticListingLink{
+				Name: segments[i],
+				Url:  path.Join(entry.StaticFileEntry.Path, strings.Join(segments[:i+1], "/")) + "/",
+			})
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := staticListingTemplate.Execute(buf, page); err != nil {
+		ctx.JSON(http.StatusInternalServerError,
+			rkmid.GetErrorBuilder().New(http.StatusInternalServerError, "Failed to execute go template", err))
+		return
+	}
+
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// sortStaticListing sorts files by name, size or time, directories are listed before files.
+func sortStaticListing(files []*staticListingFile, sortBy string, desc bool) {
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].IsDir != files[j].IsDir {
+			return files[i].IsDir
+		}
+
+		a, b := files[i], files[j]
+		if desc {
+			a, b = b, a
+		}
+
+		switch sortBy {
+		case "size":
+			if a.size != b.size {
+				return a.size < b.size
+			}
+		case "time":
+			if !a.modTime.Equal(b.modTime) {
+				return a.modTime.Before(b.modTime)
+			}
+		}
+
+		return a.Name < b.Name
+	})
+}
+
+// formatStaticSize formats size in bytes with binary units.
+func formatStaticSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value, unit := float64(size), 0
+	for value >= 1024 && unit < 4 {
+		value /= 1024
+		unit++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", value, "BKMGT"[unit])
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestGinEntry_IsStaticListingEnabled(t *testing.T) {
+	// enabled by default
+	entry := &GinEntry{}
+	assert.True(t, entry.isStaticListingEnabled("/"))
+
+	entry.staticListing = &BootStaticListing{}
+	assert.True(t, entry.isStaticListingEnabled("/any"))
+
+	// nearest configured ancestor wins
+	enabled := false
+	entry.staticListing = &BootStaticListing{
+		Enabled: &enabled,
+		Dirs: map[string]bool{
+			"public":          true,
+			"/public/private": false,
+			"/public/pri":     true,
+		},
+	}
+	assert.False(t, entry.isStaticListingEnabled("/"))
+	assert.True(t, entry.isStaticListingEnabled("/public"))
+	assert.True(t, entry.isStaticListingEnabled("/public/a"))
+	assert.False(t, entry.isStaticListingEnabled("/public/private"))
+	assert.False(t, entry.isStaticListingEnabled("/public/private/a"))
+	assert.False(t, entry.isStaticListingEnabled("/publicity"))
+
+	// root configured
+	entry.staticListing.Dirs["/"] = true
+	assert.True(t, entry.isStaticListingEnabled("/publicity"))
+}
+
+func TestGinEntry_ServeStaticDir(t *testing.T) {
+	staticEntry := rkentry.RegisterStaticFileHandlerEntry(&rkentry.BootStaticFileHandler{Enabled: true, Path: "/files"})
+	staticEntry.Bootstrap(context.TODO())
+
+	enabled := true
+	entry := RegisterGinEntry(
+		WithName("ut-static-listing"),
+		WithStaticFileHandlerEntry(staticEntry),
+		WithStaticListing(&BootStaticListing{
+			Enabled: &enabled,
+			Dirs:    map[string]bool{"/private": false},
+		}),
+		WithStaticFS(fstest.MapFS{
+			"a/b/small.txt":   &fstest.MapFile{Data: []byte("s"), ModTime: time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)},
+			"a/b/large.txt":   &fstest.MapFile{Data: make([]byte, 2048), ModTime: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+			"a/b/c/d.txt":     &fstest.MapFile{Data: []byte("d")},
+			"private/key.txt": &fstest.MapFile{Data: []byte("key")},
+		}))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.Router.GET("/files/*any", entry.staticHandler())
+
+	serve := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	// breadcrumbs, parent and sizes
+	w := serve("/files/a/b/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `<a href="/files/">/files</a>/<a href="/files/a/">a</a>/<a href="/files/a/b/">b</a>/`)
+	assert.Contains(t, body, `<a href="/files/a/">..</a>`)
+	assert.Contains(t, body, `<a href="/files/a/b/c/">c/</a>`)
+	assert.Contains(t, body, `<a href="/files/a/b/large.txt" download>large.txt</a>`)
+	assert.Contains(t, body, "2.0 KiB")
+	assert.Contains(t, body, "2022-01-02 00:00:00")
+	assert.Contains(t, body, `href="?sort=name&order=desc"`)
+	// directories first, then by name
+	assert.Less(t, strings.Index(body, "c/</a>"), strings.Index(body, "large.txt</a>"))
+	assert.Less(t, strings.Index(body, "large.txt</a>"), strings.Index(body, "small.txt</a>"))
+
+	// sorted by size desc
+	body = serve("/files/a/b/?sort=size&order=desc").Body.String()
+	assert.Less(t, strings.Index(body, "large.txt</a>"), strings.Index(body, "small.txt</a>"))
+	assert.Contains(t, body, `href="?sort=size&order=asc"`)
+
+	// sorted by time
+	body = serve("/files/a/b/?sort=time").Body.String()
+	assert.Less(t, strings.Index(body, "large.txt</a>"), strings.Index(body, "small.txt</a>"))
+
+	// disabled
+	w = serve("/files/private/")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// files in disabled directory are still served
+	w = serve("/files/private/key.txt")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestFormatStaticSize(t *testing.T) {
+	assert.Equal(t, "0 B", formatStaticSize(0))
+	assert.Equal(t, "1023 B", formatStaticSize(1023))
+	assert.Equal(t, "1.5 KiB", formatStaticSize(1536))
+	assert.Equal(t, "1.0 MiB", formatStaticSize(1<<20))
+	assert.Equal(t, "2048.0 TiB", formatStaticSize(1<<51))
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+)
+
+var staticSources = &staticSourceRegistry{
+	sources: map[string]StaticSource{
+		"local": localStaticSource,
+		"s3":    s3StaticSource,
+		"gcs":   gcsStaticSource,
+		"minio": minioStaticSource,
+	},
+}
+
+// BootStaticFileHandler bootstrap config of static file handler.
+//
+// SourceType could be embed, local, s3, gcs, minio or any type registered with RegisterStaticSource,
+// Bucket is used by s3, gcs and minio, Listing controls directory listing,
+// CacheControl sets Cache-Control header of files per path pattern.
+type BootStaticFileHandler struct {
+	rkentry.BootStaticFileHandler `mapstructure:",squash" yaml:",inline"`
+	Bucket                        BootStaticBucket         `yaml:"bucket" json:"bucket"`
+	Listing                       BootStaticListing        `yaml:"listing" json:"listing"`
+	CacheControl                  []BootStaticCacheControl `yaml:"cacheControl" json:"cacheControl"`
+}
+
+// StaticSource creates file system of static file handler from boot config.
+type StaticSource func(entryName string, boot *BootStaticFileHandler) (fs.FS, error)
+
+// staticSourceRegistry keeps sources by source type.
+type staticSourceRegistry struct {
+	lock    sync.RWMutex
+	sources map[string]StaticSource
+}
+
+// RegisterStaticSource register source of sourceType, source with same type will be replaced.
+//
+// local, s3, gcs and minio are registered by default.
+//
+//	rkgin.RegisterStaticSource("azblob", func(entryName string, boot *rkgin.BootStaticFileHandler) (fs.FS, error) {
+//		return newAzureBlobFS(boot.Bucket)
+//	})
+func RegisterStaticSource(sourceType string, source StaticSource) {
+	if len(sourceType) < 1 || source == nil {
+		return
+	}
+
+	staticSources.lock.Lock()
+	defer staticSources.lock.Unlock()
+
+	staticSources.sources[strings.ToLower(sourceType)] = source
+}
+
+// RemoveStaticSource remove source of sourceType.
+func RemoveStaticSource(sourceType string) {
+	staticSources.lock.Lock()
+	defer staticSources.lock.Unlock()
+
+	delete(staticSources.sources, strings.ToLower(sourceType))
+}
+
+// WithStaticFS provide file system served by static file handler instead of the one chosen by StaticFileHandlerEntry.
+func WithStaticFS(fsys fs.FS) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.staticFS = fsys
+	}
+}
+
+// newStaticFS creates file system of static file handler with source of boot.SourceType.
+//
+// embed.FS registered with GlobalAppCtx.AddEmbedFS is used if source type is empty or embed,
+// working directory is used if embed.FS is not registered either, same as StaticFileHandlerEntry.
+func newStaticFS(entryName string, boot *BootStaticFileHandler) (fs.FS, error) {
+	sourceType := strings.ToLower(boot.SourceType)
+	if len(sourceType) < 1 || sourceType == "embed" {
+		if embedFS := rkentry.GlobalAppCtx.GetEmbedFS(rkentry.StaticFileHandlerEntryType, entryName); embedFS != nil {
+			return embedFS, nil
+		}
+
+		return os.DirFS("."), nil
+	}
+
+	staticSources.lock.RLock()
+	source, ok := staticSources.sources[sourceType]
+	staticSources.lock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unsupported sourceType %s of static file handler", boot.SourceType)
+	}
+
+	fsys, err := source(entryName, boot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s source of static file handler, %v", sourceType, err)
+	}
+
+	return fsys, nil
+}
+
+// localStaticSource serves directory of SourcePath, relative path is resolved against working directory.
+func localStaticSource(_ string, boot *BootStaticFileHandler) (fs.FS, error) {
+	if len(boot.SourcePath) < 1 {
+		return os.DirFS("."), nil
+	}
+
+	return os.DirFS(boot.SourcePath), nil
+}
+
+// s3StaticSource serves bucket of AWS S3, endpoint of region is used by default.
+func s3StaticSource(_ string, boot *BootStaticFileHandler) (fs.FS, error) {
+	return newBucketFS(&boot.Bucket, "https://s3.{region}.amazonaws.com", "us-east-1", false)
+}
+
+// gcsStaticSource serves bucket of Google Cloud Storage with interoperability API and HMAC keys.
+func gcsStaticSource(_ string, boot *BootStaticFileHandler) (fs.FS, error) {
+	return newBucketFS(&boot.Bucket, "https://storage.googleapis.com", "auto", true)
+}
+
+// minioStaticSource serves bucket of MinIO, endpoint is required and path style is always used.
+func minioStaticSource(_ string, boot *BootStaticFileHandler) (fs.FS, error) {
+	return newBucketFS(&boot.Bucket, "", "us-east-1", true)
+}
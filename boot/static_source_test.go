@@ -0,0 +1,95 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"errors"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNewStaticFS(t *testing.T) {
+	// embed or working directory by default
+	fsys, err := newStaticFS("ut-static", &BootStaticFileHandler{})
+	assert.Nil(t, err)
+	assert.Equal(t, os.DirFS("."), fsys)
+
+	// local
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("ut"), 0644))
+	boot := &BootStaticFileHandler{}
+	boot.SourceType = "Local"
+	boot.SourcePath = dir
+	fsys, err = newStaticFS("ut-static", boot)
+	assert.Nil(t, err)
+	data, err := fs.ReadFile(fsys, "a.txt")
+	assert.Nil(t, err)
+	assert.Equal(t, "ut", string(data))
+
+	// buckets
+	for _, sourceType := range []string{"s3", "gcs", "minio"} {
+		boot = &BootStaticFileHandler{Bucket: BootStaticBucket{Bucket: "ut", Endpoint: "localhost:9000"}}
+		boot.SourceType = sourceType
+		fsys, err = newStaticFS("ut-static", boot)
+		assert.Nil(t, err)
+		assert.IsType(t, &bucketFS{}, fsys)
+	}
+
+	// bucket without name
+	boot = &BootStaticFileHandler{}
+	boot.SourceType = "s3"
+	_, err = newStaticFS("ut-static", boot)
+	assert.NotNil(t, err)
+
+	// unsupported
+	boot.SourceType = "ut-unknown"
+	_, err = newStaticFS("ut-static", boot)
+	assert.NotNil(t, err)
+}
+
+func TestRegisterStaticSource(t *testing.T) {
+	defer RemoveStaticSource("ut-source")
+
+	mapFS := fstest.MapFS{}
+	RegisterStaticSource("UT-Source", func(entryName string, boot *BootStaticFileHandler) (fs.FS, error) {
+		assert.Equal(t, "ut-static", entryName)
+		return mapFS, nil
+	})
+
+	// ignored
+	RegisterStaticSource("", nil)
+
+	boot := &BootStaticFileHandler{}
+	boot.SourceType = "ut-source"
+	fsys, err := newStaticFS("ut-static", boot)
+	assert.Nil(t, err)
+	assert.Equal(t, mapFS, fsys)
+
+	// with error
+	RegisterStaticSource("ut-source", func(string, *BootStaticFileHandler) (fs.FS, error) {
+		return nil, errors.New("ut-error")
+	})
+	_, err = newStaticFS("ut-static", boot)
+	assert.Contains(t, err.Error(), "ut-error")
+
+	// removed
+	RemoveStaticSource("ut-source")
+	_, err = newStaticFS("ut-static", boot)
+	assert.NotNil(t, err)
+}
+
+func TestWithStaticFS(t *testing.T) {
+	mapFS := fstest.MapFS{}
+	entry := RegisterGinEntry(WithName("ut-static-fs"), WithStaticFS(mapFS))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, mapFS, entry.staticFS)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"go.uber.org/zap"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// BootSWAuth credentials required to access swagger UI, access is not restricted if both are empty.
+type BootSWAuth struct {
+	Basic  []string `yaml:"basic" json:"basic"`
+	ApiKey []string `yaml:"apiKey" json:"apiKey"`
+}
+
+// WithSwBasicAuth provide basic auth credentials formed as user:pass required to access swagger UI.
+func WithSwBasicAuth(cred ...string) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.swBasicAuth = append(entry.swBasicAuth, cred...)
+	}
+}
+
+// WithSwApiKeyAuth provide API keys accepted in X-API-Key header to access swagger UI.
+func WithSwApiKeyAuth(key ...string) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.swApiKey = append(entry.swApiKey, key...)
+	}
+}
+
+// WithSwAllowedIps provide IPs or CIDRs allowed to access swagger UI.
+//
+// Client IP is resolved with gin.Context.ClientIP(), so trusted proxies of engine should be configured
+// if service runs behind proxy.
+func WithSwAllowedIps(ips ...string) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.swAllowedIps = append(entry.swAllowedIps, ips...)
+	}
+}
+
+// swAccessHandlers returns handlers which restrict access of swagger UI, empty if not restricted.
+//
+// IP allowlist is checked first, then either basic auth or API key is required.
+func (entry *GinEntry) swAccessHandlers() []gin.HandlerFunc {
+	res := make([]gin.HandlerFunc, 0)
+
+	if len(entry.swAllowedIps) > 0 {
+		res = append(res, entry.swIpAllowlist())
+	}
+
+	if len(entry.swBasicAuth) > 0 || len(entry.swApiKey) > 0 {
+		res = append(res, entry.swAuth())
+	}
+
+	return res
+}
+
+// swAuth returns handler which requires either basic auth or API key.
+//
+// Auth middleware is not used since swagger path is ignored globally by SwEntry.
+func (entry *GinEntry) swAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if matchBasicOrApiKey(ctx, entry.swBasicAuth, entry.swApiKey) {
+			ctx.Next()
+			return
+		}
+
+		if len(entry.swBasicAuth) > 0 {
+			ctx.Header("WWW-Authenticate", fmt.Sprintf(`Basic realm="%s"`, entry.entryName))
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized,
+			rkmid.GetErrorBuilder().New(http.StatusUnauthorized, "Missing or invalid authorization of swagger UI"))
+	}
+}
+
+// matchBasicOrApiKey returns true if request carries basic auth credentials formed as user:pass in basic,
+// or API key in X-API-Key header in apiKey, compared in constant time.
+func matchBasicOrApiKey(ctx *gin.Context, basic, apiKey []string) bool {
+	if user, pass, ok := ctx.Request.BasicAuth(); ok {
+		for i := range basic {
+			if subtle.ConstantTimeCompare([]byte(user+":"+pass), []byte(basic[i])) == 1 {
+				return true
+			}
+		}
+	}
+
+	if key := ctx.GetHeader(rkmid.HeaderApiKey); len(key) > 0 {
+		for i := range apiKey {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey[i])) == 1 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// swIpAllowlist returns handler which rejects clients not in swAllowedIps with 403.
+//
+// Invalid entries are ignored, all clients will be rejected if none of entries is valid.
+func (entry *GinEntry) swIpAllowlist() gin.HandlerFunc {
+	nets := make([]*net.IPNet, 0)
+
+	for _, raw := range entry.swAllowedIps {
+		raw = strings.TrimSpace(raw)
+		if !strings.Contains(raw, "/") {
+			if ip := net.ParseIP(raw); ip != nil && ip.To4() != nil {
+				raw += "/32"
+			} else {
+				raw += "/128"
+			}
+		}
+
+		_, ipNet, err := net.ParseCIDR(raw)
+		if err != nil {
+			entry.LoggerEntry.Warn("Invalid IP in swagger allowlist, ignored.", zap.String("ip", raw))
+			continue
+		}
+
+		nets = append(nets, ipNet)
+	}
+
+	return func(ctx *gin.Context) {
+		if ip := net.ParseIP(ctx.ClientIP()); ip != nil {
+			for i := range nets {
+				if nets[i].Contains(ip) {
+					ctx.Next()
+					return
+				}
+			}
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusForbidden,
+			rkmid.GetErrorBuilder().New(http.StatusForbidden, "Access to swagger UI is not allowed"))
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+// newSwAccessTestEntry bootstrap GinEntry with swagger enabled and sw section appended into boot config.
+func newSwAccessTestEntry(t *testing.T, sw string) *GinEntry {
+	return newBootstrappedTestEntry(t, `
+gin:
+  - name: ut-sw-access
+    port: 0
+    enabled: true
+    sw:
+      enabled: true
+`+sw)
+}
+
+// serveSwAccessTest serve swagger UI requested from client IP.
+func serveSwAccessTest(entry *GinEntry, clientIp string, header map[string]string) int {
+	h := map[string]string{"X-Forwarded-For": clientIp}
+	for k, v := range header {
+		h[k] = v
+	}
+
+	return serveTest(entry, http.MethodGet, "/sw/", "", h).Code
+}
+
+func TestGinEntry_swAccessHandlers(t *testing.T) {
+	defer assertNotPanic(t)
+
+	t.Run("without restriction", func(t *testing.T) {
+		entry := newSwAccessTestEntry(t, "")
+		assert.Empty(t, entry.swAccessHandlers())
+		assert.Equal(t, http.StatusOK, serveSwAccessTest(entry, "1.1.1.1", nil))
+	})
+
+	t.Run("with basic auth and API key", func(t *testing.T) {
+		entry := newSwAccessTestEntry(t, `
+      auth:
+        basic: ["user:pass"]
+        apiKey: ["key"]
+`)
+		assert.Equal(t, http.StatusUnauthorized, serveSwAccessTest(entry, "1.1.1.1", nil))
+		assert.Equal(t, http.StatusOK, serveSwAccessTest(entry, "1.1.1.1", map[string]string{
+			"Authorization": "Basic dXNlcjpwYXNz",
+		}))
+		assert.Equal(t, http.StatusOK, serveSwAccessTest(entry, "1.1.1.1", map[string]string{
+			"X-API-Key": "key",
+		}))
+		assert.Equal(t, http.StatusUnauthorized, serveSwAccessTest(entry, "1.1.1.1", map[string]string{
+			"X-API-Key": "invalid",
+		}))
+	})
+
+	t.Run("with allowed IPs", func(t *testing.T) {
+		entry := newSwAccessTestEntry(t, `
+      allowedIps: ["10.0.0.0/8", "127.0.0.1", "::1", "invalid"]
+`)
+		assert.Equal(t, http.StatusOK, serveSwAccessTest(entry, "10.1.2.3", nil))
+		assert.Equal(t, http.StatusOK, serveSwAccessTest(entry, "127.0.0.1", nil))
+		assert.Equal(t, http.StatusOK, serveSwAccessTest(entry, "::1", nil))
+		assert.Equal(t, http.StatusForbidden, serveSwAccessTest(entry, "1.1.1.1", nil))
+	})
+
+	t.Run("with allowed IPs and API key", func(t *testing.T) {
+		entry := newSwAccessTestEntry(t, `
+      auth:
+        apiKey: ["key"]
+      allowedIps: ["10.0.0.0/8"]
+`)
+		assert.Equal(t, http.StatusForbidden, serveSwAccessTest(entry, "1.1.1.1", map[string]string{
+			"X-API-Key": "key",
+		}))
+		assert.Equal(t, http.StatusUnauthorized, serveSwAccessTest(entry, "10.1.2.3", nil))
+		assert.Equal(t, http.StatusOK, serveSwAccessTest(entry, "10.1.2.3", map[string]string{
+			"X-API-Key": "key",
+		}))
+	})
+
+	t.Run("none of IPs is valid", func(t *testing.T) {
+		entry := newSwAccessTestEntry(t, `
+      allowedIps: ["invalid"]
+`)
+		assert.Equal(t, http.StatusForbidden, serveSwAccessTest(entry, "127.0.0.1", nil))
+	})
+}
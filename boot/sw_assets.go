@@ -0,0 +1,95 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// WithAssetsFS provide swagger UI assets, like custom build of swagger-ui.
+//
+// Files are looked up by path relative to swagger path, index.html is served for swagger path itself.
+// Files missing in assets, and swagger config and spec files, are still served by SwEntry.
+//
+//	//go:embed swagger-ui
+//	var assets embed.FS
+//
+//	sub, _ := fs.Sub(assets, "swagger-ui")
+//	rkgin.RegisterGinEntry(rkgin.WithAssetsFS(sub))
+func WithAssetsFS(assets fs.FS) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.assetsFS = assets
+	}
+}
+
+// swHandler returns handler of swagger UI.
+//
+// Spec files and swagger-config.json are served by GinEntry per entry, assets are served from assetsFS if exists,
+// the rest are served by SwEntry.
+func (entry *GinEntry) swHandler() gin.HandlerFunc {
+	next := gin.WrapF(entry.SwEntry.ConfigFileHandler())
+	swPath := strings.TrimSuffix(entry.SwEntry.Path, "/")
+
+	return func(ctx *gin.Context) {
+		name := strings.Trim(strings.TrimPrefix(ctx.Request.URL.Path, swPath), "/")
+
+		if name == "swagger-config.json" {
+			entry.serveSwConfig(ctx, next)
+			return
+		}
+
+		if entry.serveSwSpec(ctx, name) || entry.serveSwAsset(ctx, name) {
+			return
+		}
+
+		// spec files of other SwEntry are kept in the same package level variable of rk-entry
+		if hasSpecSuffix(name, specFileSuffixes) && name != entry.SwEntry.GetName()+swCommonSpecSuffix {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+
+		next(ctx)
+	}
+}
+
+// serveSwAsset serves file in assetsFS, index.html is served for swagger path itself, returns false if not exist.
+func (entry *GinEntry) serveSwAsset(ctx *gin.Context, name string) bool {
+	if entry.assetsFS == nil {
+		return false
+	}
+
+	if len(name) < 1 {
+		name = "index.html"
+	}
+
+	if !fs.ValidPath(name) {
+		return false
+	}
+
+	info, err := fs.Stat(entry.assetsFS, name)
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	file, err := fs.ReadFile(entry.assetsFS, name)
+	if err != nil {
+		return false
+	}
+
+	ctx.Header("cache-control", "no-cache")
+	for k, v := range entry.SwEntry.Headers {
+		ctx.Header(k, v)
+	}
+
+	// content type is detected by extension of name
+	http.ServeContent(ctx.Writer, ctx.Request, path.Base(name), info.ModTime(), bytes.NewReader(file))
+	return true
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGinEntry_swHandler_WithAssetsFS(t *testing.T) {
+	assets := fstest.MapFS{
+		"index.html":     &fstest.MapFile{Data: []byte("<html>custom</html>")},
+		"swagger-ui.css": &fstest.MapFile{Data: []byte("body {}")},
+		"dir/a.js":       &fstest.MapFile{Data: []byte("var a")},
+	}
+
+	entry := RegisterGinEntry(
+		WithName("ut-sw-assets"),
+		WithPort(0),
+		WithAssetsFS(assets),
+		WithSwEntry(rkentry.RegisterSWEntry(&rkentry.BootSW{
+			Enabled: true,
+			Headers: []string{"ut-key:ut-value"},
+		})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.Router.GET("/sw/*any", entry.swHandler())
+
+	serve := func(p string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, p, nil))
+		return w
+	}
+
+	// index
+	w := serve("/sw/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "<html>custom</html>", w.Body.String())
+	assert.Equal(t, "ut-value", w.Header().Get("ut-key"))
+
+	// css
+	w = serve("/sw/swagger-ui.css")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/css")
+
+	// nested file
+	assert.Equal(t, "var a", serve("/sw/dir/a.js").Body.String())
+
+	// directory and missing files fall back to SwEntry
+	assert.Equal(t, http.StatusNotFound, serve("/sw/dir").Code)
+	w = serve("/sw/swagger-ui-bundle.js")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, "var a", w.Body.String())
+
+	// swagger config
+	w = serve("/sw/swagger-config.json")
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+}
+
+func TestGinEntry_swHandler_WithoutAssetsFS(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-sw-assets"),
+		WithPort(0),
+		WithSwEntry(rkentry.RegisterSWEntry(&rkentry.BootSW{
+			Enabled: true,
+		})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.Router.GET("/sw/*any", entry.swHandler())
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "swagger")
+}
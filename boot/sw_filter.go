@@ -0,0 +1,179 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"path"
+	"strings"
+)
+
+// BootSWFilter boot config of operations published in specs served by swagger UI.
+//
+// Paths are prefixes like /internal, trailing /* is allowed. Operation is published only if
+// matched with include rules, if any, and not matched with exclude rules.
+type BootSWFilter struct {
+	IncludeTags  []string `yaml:"includeTags" json:"includeTags"`
+	ExcludeTags  []string `yaml:"excludeTags" json:"excludeTags"`
+	IncludePaths []string `yaml:"includePaths" json:"includePaths"`
+	ExcludePaths []string `yaml:"excludePaths" json:"excludePaths"`
+}
+
+// isEmpty returns true if no rules in filter.
+func (filter *BootSWFilter) isEmpty() bool {
+	return filter == nil ||
+		len(filter.IncludeTags)+len(filter.ExcludeTags)+len(filter.IncludePaths)+len(filter.ExcludePaths) < 1
+}
+
+// WithSwFilter filter operations in specs served by swagger UI with tags and path prefixes.
+func WithSwFilter(filter *BootSWFilter) GinEntryOption {
+	return func(entry *GinEntry) {
+		if filter.isEmpty() {
+			entry.swFilter = nil
+			return
+		}
+
+		entry.swFilter = filter
+	}
+}
+
+// loadSwSpec returns content of spec filtered with filter of entry.
+func (entry *GinEntry) loadSwSpec(spec *swSpec) ([]byte, string, error) {
+	content, contentType, err := spec.load()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if entry.swFilter.isEmpty() {
+		return content, contentType, nil
+	}
+
+	filtered, err := filterSwSpec(content, strings.Contains(contentType, "json"), entry.swFilter)
+	return filtered, contentType, err
+}
+
+// filterSwSpec removes operations not published with filter, paths without operations and unpublished tags.
+//
+// Objects like definitions and components are kept as they are.
+func filterSwSpec(content []byte, isJson bool, filter *BootSWFilter) ([]byte, error) {
+	raw := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse spec, %v", err)
+	}
+	doc := toJsonCompatible(raw).(map[string]interface{})
+
+	paths := asStringMap(doc["paths"])
+	for p, item := range paths {
+		if !filter.matchPath(p) {
+			delete(paths, p)
+			continue
+		}
+
+		ops := asStringMap(item)
+		for method, op := range ops {
+			// path level fields like parameters are not operations
+			if !isHttpMethod(method) {
+				continue
+			}
+
+			tags := make([]string, 0)
+			for _, tag := range asSlice(asStringMap(op)["tags"]) {
+				tags = append(tags, fmt.Sprint(tag))
+			}
+			if !filter.matchTags(tags) {
+				delete(ops, method)
+			}
+		}
+
+		if !hasHttpMethod(ops) {
+			delete(paths, p)
+		}
+	}
+
+	if tags, ok := doc["tags"].([]interface{}); ok {
+		res := make([]interface{}, 0, len(tags))
+		for _, tag := range tags {
+			name := fmt.Sprint(asStringMap(tag)["name"])
+			if !containsString(filter.ExcludeTags, name) &&
+				(len(filter.IncludeTags) < 1 || containsString(filter.IncludeTags, name)) {
+				res = append(res, tag)
+			}
+		}
+		doc["tags"] = res
+	}
+
+	if isJson {
+		return json.Marshal(doc)
+	}
+
+	return yaml.Marshal(doc)
+}
+
+// matchPath returns true if p is matched with include path rules, if any, and not matched with exclude path rules.
+func (filter *BootSWFilter) matchPath(p string) bool {
+	prefixes := func(in []string) []string {
+		res := make([]string, 0, len(in))
+		for i := range in {
+			res = append(res, path.Join("/", strings.TrimSuffix(in[i], "*")))
+		}
+		return res
+	}
+
+	if len(filter.IncludePaths) > 0 && !hasAnyPrefix(p, prefixes(filter.IncludePaths)) {
+		return false
+	}
+
+	return !hasAnyPrefix(p, prefixes(filter.ExcludePaths))
+}
+
+// matchTags returns true if tags are matched with include tag rules, if any, and not matched with exclude tag rules.
+func (filter *BootSWFilter) matchTags(tags []string) bool {
+	included := len(filter.IncludeTags) < 1
+	for _, tag := range tags {
+		if containsString(filter.ExcludeTags, tag) {
+			return false
+		}
+		if containsString(filter.IncludeTags, tag) {
+			included = true
+		}
+	}
+
+	return included
+}
+
+// isHttpMethod returns true if key of path item is operation, like get and post.
+func isHttpMethod(key string) bool {
+	switch key {
+	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+		return true
+	}
+
+	return false
+}
+
+// hasHttpMethod returns true if path item has any operation.
+func hasHttpMethod(item map[string]interface{}) bool {
+	for key := range item {
+		if isHttpMethod(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsString returns true if s is in list.
+func containsString(list []string, s string) bool {
+	for i := range list {
+		if list[i] == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const utSwFilterSpec = `{
+  "swagger": "2.0",
+  "tags": [{"name": "user"}, {"name": "admin"}, {"name": "order"}],
+  "paths": {
+    "/v1/user": {
+      "parameters": [{"name": "x", "in": "header"}],
+      "get": {"tags": ["user"]},
+      "delete": {"tags": ["user", "admin"]}
+    },
+    "/v1/order": {"get": {"tags": ["order"]}},
+    "/internal/reload": {"post": {"tags": ["user"]}},
+    "/internalx": {"get": {"tags": ["user"]}}
+  },
+  "definitions": {"User": {"type": "object"}}
+}`
+
+func TestWithSwFilter(t *testing.T) {
+	entry := RegisterGinEntry(WithSwFilter(&BootSWFilter{ExcludeTags: []string{"admin"}}))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.Equal(t, []string{"admin"}, entry.swFilter.ExcludeTags)
+
+	// empty filter
+	WithSwFilter(&BootSWFilter{})(entry)
+	assert.Nil(t, entry.swFilter)
+}
+
+func TestFilterSwSpec(t *testing.T) {
+	filter := func(f *BootSWFilter) map[string]interface{} {
+		content, err := filterSwSpec([]byte(utSwFilterSpec), true, f)
+		assert.Nil(t, err)
+		doc := make(map[string]interface{})
+		assert.Nil(t, json.Unmarshal(content, &doc))
+		return doc
+	}
+
+	// exclude
+	doc := filter(&BootSWFilter{ExcludeTags: []string{"admin"}, ExcludePaths: []string{"/internal/*"}})
+	paths := asStringMap(doc["paths"])
+	assert.Len(t, paths, 3)
+	assert.Nil(t, paths["/internal/reload"])
+	assert.NotNil(t, paths["/internalx"])
+	assert.NotNil(t, asStringMap(paths["/v1/user"])["get"])
+	assert.NotNil(t, asStringMap(paths["/v1/user"])["parameters"])
+	assert.Nil(t, asStringMap(paths["/v1/user"])["delete"])
+	assert.Len(t, asSlice(doc["tags"]), 2)
+	assert.NotNil(t, doc["definitions"])
+
+	// include
+	doc = filter(&BootSWFilter{IncludeTags: []string{"order"}, IncludePaths: []string{"/v1"}})
+	paths = asStringMap(doc["paths"])
+	assert.Len(t, paths, 1)
+	assert.NotNil(t, paths["/v1/order"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "order"}}, doc["tags"])
+
+	// yaml kept as yaml
+	content, err := filterSwSpec([]byte("paths:\n  /internal:\n    get: {}\n"), false,
+		&BootSWFilter{ExcludePaths: []string{"/internal"}})
+	assert.Nil(t, err)
+	res := make(map[string]interface{})
+	assert.Nil(t, yaml.Unmarshal(content, &res))
+	assert.Empty(t, res["paths"])
+
+	// invalid
+	_, err = filterSwSpec([]byte("- invalid"), false, &BootSWFilter{ExcludeTags: []string{"admin"}})
+	assert.NotNil(t, err)
+}
+
+func TestGinEntry_loadSwSpec(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-sw-filter"),
+		WithPort(0),
+		WithSwFilter(&BootSWFilter{ExcludePaths: []string{"/internal"}}))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.swSpecStore.add("a.json", "/sw/a.json", &swSpec{content: []byte(utSwFilterSpec), contentType: "application/json"})
+	serve := func(name string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/sw/"+name, nil)
+		assert.True(t, entry.serveSwSpec(ctx, name))
+		return w
+	}
+
+	w := serve("a.json")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "/internal/reload")
+	assert.Contains(t, w.Body.String(), "/v1/order")
+
+	// failed to filter
+	entry.swSpecStore.add("b.yaml", "/sw/b.yaml", &swSpec{content: []byte("- invalid"), contentType: "application/yaml"})
+	w = serve("b.yaml")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// swMergedSections sections of spec which hold named objects, merged by name.
+//
+// definitions, parameters, responses and securityDefinitions are sections of swagger 2.0,
+// components holds sections of OpenAPI 3 like schemas, which are merged one level deeper.
+var swMergedSections = []string{"definitions", "parameters", "responses", "securityDefinitions"}
+
+// swCommonSpecSuffix suffix of spec of common service APIs served by SwEntry, which is kept in merge mode.
+const swCommonSpecSuffix = "-rk-common.swagger.json"
+
+// swMergedDoc a spec file to be merged.
+type swMergedDoc struct {
+	name string
+	doc  map[string]interface{}
+}
+
+// WithSwMerge merge spec files in JsonPaths of SwEntry into one document listed in swagger UI.
+func WithSwMerge(enabled bool) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.swMerge = enabled
+	}
+}
+
+// initSwMergedSpec merges .json, .yaml and .yml spec files in JsonPaths of SwEntry into <swName>-merged.json.
+//
+// Conflicts, like same operation or different objects with same name, are logged and the first one wins.
+func (entry *GinEntry) initSwMergedSpec() {
+	if !entry.swMerge {
+		return
+	}
+
+	dirs := entry.SwEntry.JsonPaths
+	if len(dirs) < 1 {
+		dirs = []string{"docs", "api/gen/v1", "api/gen"}
+	}
+
+	var fsys fs.FS
+	if embedFS := rkentry.GlobalAppCtx.GetEmbedFS(rkentry.SWEntryType, entry.SwEntry.GetName()); embedFS != nil {
+		fsys = embedFS
+	}
+
+	key := entry.SwEntry.GetName() + "-merged.json"
+	docs := make([]*swMergedDoc, 0)
+	for _, dir := range dirs {
+		for _, file := range listSpecFiles(fsys, dir, specFileSuffixes) {
+			var content []byte
+			var err error
+			if fsys != nil {
+				content, err = fs.ReadFile(fsys, file)
+			} else {
+				content, err = os.ReadFile(file)
+			}
+			if err != nil {
+				continue
+			}
+
+			raw := make(map[interface{}]interface{})
+			if err := yaml.Unmarshal(content, &raw); err != nil {
+				entry.LoggerEntry.Warn("Failed to parse spec file, ignored while merging.",
+					zap.String("file", file), zap.Error(err))
+				continue
+			}
+
+			docs = append(docs, &swMergedDoc{
+				name: path.Base(filepath.ToSlash(file)),
+				doc:  toJsonCompatible(raw).(map[string]interface{}),
+			})
+		}
+	}
+
+	if len(docs) < 1 {
+		entry.swSpecStore.removeLocal(nil)
+		return
+	}
+
+	merged, conflicts := mergeSwDocs(docs)
+	for i := range conflicts {
+		entry.LoggerEntry.Warn("Conflict found while merging spec files, the first one wins.", zap.String("conflict", conflicts[i]))
+	}
+
+	content, err := json.Marshal(merged)
+	if err != nil {
+		entry.LoggerEntry.Warn("Failed to marshal merged spec.", zap.Error(err))
+		return
+	}
+
+	entry.swSpecStore.add(key, path.Join(entry.SwEntry.Path, key), &swSpec{
+		content:     content,
+		contentType: "application/json",
+		local:       true,
+	})
+}
+
+// mergeSwDocs merges docs into the first one and returns it with conflicts.
+//
+// Docs with different version of spec from the first one are skipped, since swagger 2.0 and OpenAPI 3 are not compatible.
+func mergeSwDocs(docs []*swMergedDoc) (map[string]interface{}, []string) {
+	conflicts := make([]string, 0)
+	merged := docs[0].doc
+	version := swDocVersion(merged)
+
+	for _, doc := range docs[1:] {
+		if v := swDocVersion(doc.doc); v != version {
+			conflicts = append(conflicts, fmt.Sprintf("%s: version %s differs from %s, skipped", doc.name, v, version))
+			continue
+		}
+
+		// paths, merged by path and method
+		for p, ops := range asStringMap(doc.doc["paths"]) {
+			mergedOps := ensureStringMap(ensureStringMap(merged, "paths"), p)
+			for method, op := range asStringMap(ops) {
+				if _, ok := mergedOps[method]; ok {
+					conflicts = append(conflicts, fmt.Sprintf("%s: operation %s %s already exists", doc.name, strings.ToUpper(method), p))
+					continue
+				}
+				mergedOps[method] = op
+			}
+		}
+
+		// named objects
+		for _, section := range swMergedSections {
+			conflicts = append(conflicts, mergeSwNamedObjects(merged, doc.doc, doc.name, section)...)
+		}
+		for section := range asStringMap(doc.doc["components"]) {
+			conflicts = append(conflicts,
+				mergeSwNamedObjects(ensureStringMap(merged, "components"), asStringMap(doc.doc["components"]), doc.name, section)...)
+		}
+
+		// tags, merged by name
+		mergedTags, _ := merged["tags"].([]interface{})
+		for _, tag := range asSlice(doc.doc["tags"]) {
+			name := asStringMap(tag)["name"]
+			exists := false
+			for i := range mergedTags {
+				if asStringMap(mergedTags[i])["name"] == name {
+					exists = true
+					break
+				}
+			}
+			if !exists {
+				mergedTags = append(mergedTags, tag)
+			}
+		}
+		if len(mergedTags) > 0 {
+			merged["tags"] = mergedTags
+		}
+	}
+
+	return merged, conflicts
+}
+
+// mergeSwNamedObjects merges objects in section of src into dest, objects with same name and different content are conflicts.
+func mergeSwNamedObjects(dest, src map[string]interface{}, srcName, section string) []string {
+	conflicts := make([]string, 0)
+
+	for name, obj := range asStringMap(src[section]) {
+		mergedSection := ensureStringMap(dest, section)
+		if exist, ok := mergedSection[name]; ok {
+			if !reflect.DeepEqual(exist, obj) {
+				conflicts = append(conflicts, fmt.Sprintf("%s: %s %s differs from existing one", srcName, section, name))
+			}
+			continue
+		}
+		mergedSection[name] = obj
+	}
+
+	return conflicts
+}
+
+// swDocVersion returns version of spec, like swagger 2.0 or openapi 3.0.0, major version is used.
+func swDocVersion(doc map[string]interface{}) string {
+	if v, ok := doc["openapi"]; ok {
+		return "openapi " + strings.SplitN(fmt.Sprint(v), ".", 2)[0]
+	}
+
+	return "swagger " + strings.SplitN(fmt.Sprint(doc["swagger"]), ".", 2)[0]
+}
+
+// toJsonCompatible converts map[interface{}]interface{} decoded from YAML into map[string]interface{} recursively.
+func toJsonCompatible(in interface{}) interface{} {
+	switch v := in.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			res[fmt.Sprint(k)] = toJsonCompatible(val)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i := range v {
+			res[i] = toJsonCompatible(v[i])
+		}
+		return res
+	default:
+		return in
+	}
+}
+
+// asStringMap returns in as map[string]interface{}, nil if not a map.
+func asStringMap(in interface{}) map[string]interface{} {
+	res, _ := in.(map[string]interface{})
+	return res
+}
+
+// asSlice returns in as []interface{}, nil if not a slice.
+func asSlice(in interface{}) []interface{} {
+	res, _ := in.([]interface{})
+	return res
+}
+
+// ensureStringMap returns map in m with key, a new one is created if missing.
+func ensureStringMap(m map[string]interface{}, key string) map[string]interface{} {
+	res, ok := m[key].(map[string]interface{})
+	if !ok {
+		res = make(map[string]interface{})
+		m[key] = res
+	}
+
+	return res
+}
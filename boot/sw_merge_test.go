@@ -0,0 +1,185 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	utSwMergeA = `{
+  "swagger": "2.0",
+  "info": {"title": "a", "version": "1.0"},
+  "tags": [{"name": "user"}],
+  "paths": {"/v1/user": {"get": {"summary": "list user"}}},
+  "definitions": {"User": {"type": "object"}, "Error": {"type": "object"}}
+}`
+	utSwMergeB = `swagger: "2.0"
+info:
+  title: b
+  version: "1.0"
+tags:
+  - name: user
+  - name: order
+paths:
+  /v1/user:
+    get:
+      summary: duplicated
+    post:
+      summary: create user
+  /v1/order:
+    get:
+      responses:
+        200:
+          description: OK
+definitions:
+  User:
+    type: object
+  Error:
+    type: string
+  Order:
+    type: object
+`
+	utSwMergeC = `openapi: 3.0.0
+info:
+  title: c
+  version: "1.0"
+paths:
+  /v1/skipped:
+    get: {}
+`
+)
+
+func TestMergeSwDocs(t *testing.T) {
+	a := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal([]byte(utSwMergeA), &a))
+
+	merged, conflicts := mergeSwDocs([]*swMergedDoc{
+		{name: "a.json", doc: a},
+		{name: "b.yaml", doc: map[string]interface{}{
+			"swagger": "2.0",
+			"paths": map[string]interface{}{
+				"/v1/user":  map[string]interface{}{"get": map[string]interface{}{}},
+				"/v1/order": map[string]interface{}{"get": map[string]interface{}{}},
+			},
+			"definitions": map[string]interface{}{
+				"User":  map[string]interface{}{"type": "object"},
+				"Error": map[string]interface{}{"type": "string"},
+			},
+		}},
+		{name: "c.yaml", doc: map[string]interface{}{"openapi": "3.0.0"}},
+	})
+
+	assert.Len(t, conflicts, 3)
+	assert.Contains(t, conflicts[0]+conflicts[1], "GET /v1/user")
+	assert.Contains(t, conflicts[0]+conflicts[1], "definitions Error")
+	assert.Contains(t, conflicts[2], "c.yaml: version openapi 3")
+
+	paths := asStringMap(merged["paths"])
+	assert.Len(t, paths, 2)
+	assert.Equal(t, "list user", asStringMap(asStringMap(paths["/v1/user"])["get"])["summary"])
+	assert.Equal(t, "object", asStringMap(asStringMap(merged["definitions"])["Error"])["type"])
+}
+
+func TestMergeSwDocs_WithComponents(t *testing.T) {
+	merged, conflicts := mergeSwDocs([]*swMergedDoc{
+		{name: "a.yaml", doc: map[string]interface{}{
+			"openapi":    "3.0.0",
+			"components": map[string]interface{}{"schemas": map[string]interface{}{"A": "a"}},
+		}},
+		{name: "b.yaml", doc: map[string]interface{}{
+			"openapi": "3.1.0",
+			"components": map[string]interface{}{
+				"schemas":         map[string]interface{}{"A": "changed", "B": "b"},
+				"securitySchemes": map[string]interface{}{"key": "key"},
+			},
+		}},
+	})
+
+	assert.Len(t, conflicts, 1)
+	components := asStringMap(merged["components"])
+	assert.Equal(t, map[string]interface{}{"A": "a", "B": "b"}, components["schemas"])
+	assert.Equal(t, map[string]interface{}{"key": "key"}, components["securitySchemes"])
+}
+
+func TestGinEntry_initSwMergedSpec(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(utSwMergeA), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte(utSwMergeB), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "c.yml"), []byte(utSwMergeC), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "d.yaml"), []byte("- invalid"), 0644))
+
+	entry := RegisterGinEntry(
+		WithName("ut-sw-merge"),
+		WithPort(0),
+		WithSwMerge(true),
+		WithSwEntry(rkentry.RegisterSWEntry(&rkentry.BootSW{
+			Enabled:   true,
+			JsonPaths: []string{dir},
+		}, rkentry.WithNameSWEntry("ut-sw-merge"))))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.SwEntry.Bootstrap(context.TODO())
+	entry.initSwMergedSpec()
+	entry.Router.GET("/sw/*any", entry.swHandler())
+
+	// merged spec
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/ut-sw-merge-merged.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	merged := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &merged))
+	assert.Equal(t, "a", asStringMap(merged["info"])["title"])
+	assert.Len(t, asStringMap(merged["paths"]), 2)
+	assert.Len(t, asStringMap(merged["definitions"]), 3)
+	assert.Len(t, asSlice(merged["tags"]), 2)
+	assert.NotNil(t, asStringMap(asStringMap(asStringMap(asStringMap(merged["paths"])["/v1/order"])["get"])["responses"])["200"])
+
+	// only merged spec listed
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/swagger-config.json", nil))
+	config := struct {
+		Urls []*swUrl `json:"urls"`
+	}{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &config))
+	for _, u := range config.Urls {
+		assert.NotEqual(t, "ut-sw-merge-a.json", u.Name)
+	}
+	assert.Equal(t, "ut-sw-merge-merged.json", config.Urls[len(config.Urls)-1].Name)
+}
+
+func TestGinEntry_initSwMergedSpec_WithoutSpec(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-sw-merge-empty"),
+		WithPort(0),
+		WithSwMerge(true),
+		WithSwEntry(rkentry.RegisterSWEntry(&rkentry.BootSW{
+			Enabled:   true,
+			JsonPaths: []string{filepath.Join(t.TempDir(), "non-exist")},
+		}, rkentry.WithNameSWEntry("ut-sw-merge-empty"))))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.initSwMergedSpec()
+	assert.Empty(t, entry.swSpecStore.listUrls())
+}
+
+func TestToJsonCompatible(t *testing.T) {
+	res := toJsonCompatible(map[interface{}]interface{}{
+		200: []interface{}{map[interface{}]interface{}{"a": 1}},
+	})
+	assert.Equal(t, map[string]interface{}{
+		"200": []interface{}{map[string]interface{}{"a": 1}},
+	}, res)
+}
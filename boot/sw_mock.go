@@ -0,0 +1,372 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v2"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// swMockHeader header set on responses served by mock mode.
+	swMockHeader = "X-RK-Mock"
+	// swMockMaxDepth max depth of $ref and nested schemas while building example from schema.
+	swMockMaxDepth = 8
+)
+
+// swMocker serves example responses from specs in swSpecStore for requests without registered handler.
+//
+// Parsed specs are cached and parsed again only if content of spec changed.
+type swMocker struct {
+	lock sync.Mutex
+	docs map[string]*swMockDoc
+}
+
+// swMockDoc parsed spec with content parsed from.
+type swMockDoc struct {
+	content []byte
+	doc     map[string]interface{}
+}
+
+// swMockOperation operation matched with request.
+type swMockOperation struct {
+	doc    map[string]interface{}
+	op     map[string]interface{}
+	params int
+}
+
+// WithSwMock serve example responses described in specs of SwEntry for routes without registered handler.
+//
+// Examples in spec are preferred, otherwise response is built from schema.
+func WithSwMock(enabled bool) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.swMock = nil
+		if enabled {
+			entry.swMock = &swMocker{
+				docs: make(map[string]*swMockDoc),
+			}
+		}
+	}
+}
+
+// initSwMock installs mock handler in front of handlers for requests without matched route.
+//
+// Requests not described in specs fall through to handlers provided with WithNoRouteHandler, or 404 of gin.
+func (entry *GinEntry) initSwMock() {
+	if entry.swMock == nil {
+		return
+	}
+
+	entry.Router.NoRoute(append([]gin.HandlerFunc{entry.swMockHandler()}, entry.noRouteHandlers...)...)
+}
+
+// swMockHandler returns handler which responds with example of operation matched with request.
+func (entry *GinEntry) swMockHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		match := entry.swMock.match(entry.swSpecStore, ctx.Request.Method, ctx.Request.URL.Path)
+		if match == nil {
+			ctx.Next()
+			return
+		}
+
+		code, contentType, example := swMockResponse(match.doc, match.op)
+
+		ctx.Header(swMockHeader, "true")
+		if example == nil {
+			ctx.AbortWithStatus(code)
+			return
+		}
+
+		if str, ok := example.(string); ok && !strings.Contains(contentType, "json") {
+			ctx.Data(code, contentType, []byte(str))
+			ctx.Abort()
+			return
+		}
+
+		body, err := json.Marshal(example)
+		if err != nil {
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if !strings.Contains(contentType, "json") {
+			contentType = "application/json"
+		}
+		ctx.Data(code, contentType, body)
+		ctx.Abort()
+	}
+}
+
+// match returns operation matched with method and path in specs of store, nil if not found.
+//
+// Operation with fewer path parameters wins if more than one matched, like /user/me over /user/{id}.
+func (m *swMocker) match(store *swSpecStore, method, reqPath string) *swMockOperation {
+	var res *swMockOperation
+
+	for _, u := range store.listUrls() {
+		spec := store.get(u.Name)
+		// generated spec describes registered routes only
+		if spec == nil || spec.generate != nil {
+			continue
+		}
+
+		doc := m.parse(u.Name, spec)
+		if doc == nil {
+			continue
+		}
+
+		basePath := swDocBasePath(doc)
+		for specPath, ops := range asStringMap(doc["paths"]) {
+			params, ok := matchSwMockPath(path.Join("/", basePath, specPath), reqPath)
+			if !ok {
+				continue
+			}
+
+			op := asStringMap(asStringMap(ops)[strings.ToLower(method)])
+			if op == nil {
+				continue
+			}
+
+			if res == nil || params < res.params {
+				res = &swMockOperation{doc: doc, op: op, params: params}
+			}
+		}
+	}
+
+	return res
+}
+
+// parse returns spec parsed from content of spec, cached with key.
+func (m *swMocker) parse(key string, spec *swSpec) map[string]interface{} {
+	content, _, err := spec.load()
+	if err != nil {
+		return nil
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if cached, ok := m.docs[key]; ok && bytes.Equal(cached.content, content) {
+		return cached.doc
+	}
+
+	raw := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		delete(m.docs, key)
+		return nil
+	}
+
+	doc := toJsonCompatible(raw).(map[string]interface{})
+	m.docs[key] = &swMockDoc{content: content, doc: doc}
+	return doc
+}
+
+// swDocBasePath returns basePath of swagger 2.0, or path of the first server of OpenAPI 3.
+func swDocBasePath(doc map[string]interface{}) string {
+	if basePath, ok := doc["basePath"].(string); ok {
+		return basePath
+	}
+
+	for _, server := range asSlice(doc["servers"]) {
+		if raw, ok := asStringMap(server)["url"].(string); ok {
+			if u, err := url.Parse(raw); err == nil {
+				return u.Path
+			}
+		}
+		break
+	}
+
+	return ""
+}
+
+// matchSwMockPath matches request path with templated path of spec like /user/{id},
+// and returns number of path parameters.
+func matchSwMockPath(specPath, reqPath string) (int, bool) {
+	specSegs := strings.Split(strings.Trim(specPath, "/"), "/")
+	reqSegs := strings.Split(strings.Trim(reqPath, "/"), "/")
+	if len(specSegs) != len(reqSegs) {
+		return 0, false
+	}
+
+	params := 0
+	for i := range specSegs {
+		if strings.HasPrefix(specSegs[i], "{") && strings.HasSuffix(specSegs[i], "}") && len(reqSegs[i]) > 0 {
+			params++
+			continue
+		}
+		if specSegs[i] != reqSegs[i] {
+			return 0, false
+		}
+	}
+
+	return params, true
+}
+
+// swMockResponse returns status code, content type and example of the first 2xx response of operation,
+// or default response with 200.
+//
+// Example is nil if response has no body.
+func swMockResponse(doc, op map[string]interface{}) (int, string, interface{}) {
+	responses := asStringMap(op["responses"])
+
+	codes := make([]int, 0)
+	for k := range responses {
+		if code, err := strconv.Atoi(k); err == nil && code >= 200 && code < 300 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+
+	code, key := http.StatusOK, "default"
+	if len(codes) > 0 {
+		code, key = codes[0], strconv.Itoa(codes[0])
+	}
+
+	resp := resolveSwRef(doc, responses[key], 0)
+	if resp == nil {
+		return code, "", nil
+	}
+
+	// OpenAPI 3
+	if content := asStringMap(resp["content"]); content != nil {
+		contentType := pickSwMediaType(content)
+		media := asStringMap(content[contentType])
+
+		if example, ok := media["example"]; ok {
+			return code, contentType, example
+		}
+		if examples := asStringMap(media["examples"]); len(examples) > 0 {
+			names := make([]string, 0, len(examples))
+			for name := range examples {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return code, contentType, resolveSwRef(doc, examples[names[0]], 0)["value"]
+		}
+		if schema, ok := media["schema"]; ok {
+			return code, contentType, swSchemaExample(doc, schema, 0)
+		}
+		return code, contentType, nil
+	}
+
+	// swagger 2.0
+	if examples := asStringMap(resp["examples"]); len(examples) > 0 {
+		contentType := pickSwMediaType(examples)
+		return code, contentType, examples[contentType]
+	}
+	if schema, ok := resp["schema"]; ok {
+		return code, "application/json", swSchemaExample(doc, schema, 0)
+	}
+
+	return code, "", nil
+}
+
+// pickSwMediaType returns application/json if exists in m, otherwise the first one in order.
+func pickSwMediaType(m map[string]interface{}) string {
+	if _, ok := m["application/json"]; ok {
+		return "application/json"
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys[0]
+}
+
+// resolveSwRef returns object referred with $ref in in, like #/definitions/User, in itself if no $ref.
+func resolveSwRef(doc map[string]interface{}, in interface{}, depth int) map[string]interface{} {
+	obj := asStringMap(in)
+
+	for ref, ok := obj["$ref"].(string); ok && depth < swMockMaxDepth; ref, ok = obj["$ref"].(string) {
+		depth++
+
+		var target interface{} = doc
+		for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			target = asStringMap(target)[token]
+		}
+		obj = asStringMap(target)
+	}
+
+	return obj
+}
+
+// swSchemaExample builds example from schema with example, default, enum or type of it.
+func swSchemaExample(doc map[string]interface{}, in interface{}, depth int) interface{} {
+	if depth > swMockMaxDepth {
+		return nil
+	}
+
+	schema := resolveSwRef(doc, in, depth)
+	if schema == nil {
+		return nil
+	}
+
+	for _, key := range []string{"example", "default"} {
+		if v, ok := schema[key]; ok {
+			return v
+		}
+	}
+	if enum := asSlice(schema["enum"]); len(enum) > 0 {
+		return enum[0]
+	}
+
+	// composed schemas
+	if allOf := asSlice(schema["allOf"]); len(allOf) > 0 {
+		res := make(map[string]interface{})
+		for i := range allOf {
+			for k, v := range asStringMap(swSchemaExample(doc, allOf[i], depth+1)) {
+				res[k] = v
+			}
+		}
+		return res
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if candidates := asSlice(schema[key]); len(candidates) > 0 {
+			return swSchemaExample(doc, candidates[0], depth+1)
+		}
+	}
+
+	switch fmt.Sprint(schema["type"]) {
+	case "array":
+		item := swSchemaExample(doc, schema["items"], depth+1)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "string":
+		switch schema["format"] {
+		case "date-time":
+			return "1970-01-01T00:00:00Z"
+		case "date":
+			return "1970-01-01"
+		}
+		return "string"
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	}
+
+	res := make(map[string]interface{})
+	for name, prop := range asStringMap(schema["properties"]) {
+		res[name] = swSchemaExample(doc, prop, depth+1)
+	}
+	return res
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	utSwMockOpenApi3 = `openapi: 3.0.0
+info:
+  title: mock
+  version: "1.0"
+servers:
+  - url: http://localhost:8080/api
+paths:
+  /v1/user/{id}:
+    get:
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+    delete:
+      responses:
+        204:
+          description: No content
+  /v1/user/me:
+    get:
+      responses:
+        201:
+          description: Created
+          content:
+            application/json:
+              example:
+                name: me
+        200:
+          description: OK
+          content:
+            application/json:
+              examples:
+                b:
+                  value:
+                    name: b
+                a:
+                  $ref: '#/components/examples/A'
+  /v1/text:
+    get:
+      responses:
+        default:
+          description: OK
+          content:
+            text/plain:
+              example: hello
+components:
+  examples:
+    A:
+      value:
+        name: a
+  schemas:
+    User:
+      type: object
+      properties:
+        name:
+          type: string
+        age:
+          type: integer
+          default: 18
+        role:
+          type: string
+          enum: [admin, user]
+        createdAt:
+          type: string
+          format: date-time
+        tags:
+          type: array
+          items:
+            type: string
+        parent:
+          $ref: '#/components/schemas/User'
+`
+	utSwMockSwagger2 = `{
+  "swagger": "2.0",
+  "basePath": "/v2",
+  "paths": {
+    "/order": {
+      "get": {"responses": {"200": {"examples": {"application/json": {"id": 1}}}}},
+      "post": {"responses": {"200": {"$ref": "#/responses/Order"}}}
+    }
+  },
+  "responses": {"Order": {"schema": {"allOf": [
+    {"properties": {"id": {"type": "integer"}}},
+    {"properties": {"paid": {"type": "boolean"}}}
+  ]}}}
+}`
+)
+
+func TestWithSwMock(t *testing.T) {
+	entry := RegisterGinEntry(WithSwMock(true))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.NotNil(t, entry.swMock)
+
+	WithSwMock(false)(entry)
+	assert.Nil(t, entry.swMock)
+}
+
+func TestGinEntry_swMockHandler(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-sw-mock"),
+		WithPort(0),
+		WithSwMock(true),
+		WithNoRouteHandler(NoRouteHandler("")))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.swSpecStore.add("a.yaml", "/sw/a.yaml", &swSpec{content: []byte(utSwMockOpenApi3), local: true})
+	entry.swSpecStore.add("b.json", "/sw/b.json", &swSpec{content: []byte(utSwMockSwagger2), local: true})
+	entry.swSpecStore.add("invalid.yaml", "/sw/invalid.yaml", &swSpec{content: []byte("- invalid")})
+	entry.initSwMock()
+
+	// registered handler comes first
+	entry.Router.GET("/v2/order", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "handler")
+	})
+
+	serve := func(method, p string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, httptest.NewRequest(method, p, nil))
+		body := make(map[string]interface{})
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w, body
+	}
+
+	// built from schema
+	w, body := serve(http.MethodGet, "/api/v1/user/1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get(swMockHeader))
+	assert.Equal(t, "string", body["name"])
+	assert.EqualValues(t, 18, body["age"])
+	assert.Equal(t, "admin", body["role"])
+	assert.Equal(t, "1970-01-01T00:00:00Z", body["createdAt"])
+	assert.Equal(t, []interface{}{"string"}, body["tags"])
+	assert.NotNil(t, body["parent"])
+
+	// literal path wins, the first 2xx response and example in it
+	w, body = serve(http.MethodGet, "/api/v1/user/me")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "a", body["name"])
+
+	// no content
+	w, _ = serve(http.MethodDelete, "/api/v1/user/1")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	// default response with text
+	w, _ = serve(http.MethodGet, "/api/v1/text")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello", w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+
+	// swagger 2.0 with registered handler, example and $ref
+	w, _ = serve(http.MethodGet, "/v2/order")
+	assert.Equal(t, "handler", w.Body.String())
+	assert.Empty(t, w.Header().Get(swMockHeader))
+
+	w, body = serve(http.MethodPost, "/v2/order")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, map[string]interface{}{"id": float64(0), "paid": false}, body)
+
+	// not described falls through to no route handlers
+	w, _ = serve(http.MethodPut, "/v2/order")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get(swMockHeader))
+	assert.Contains(t, w.Body.String(), "PUT /v2/order")
+
+	w, _ = serve(http.MethodGet, "/non-exist")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGinEntry_swMockHandler_WithSpecChanged(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-sw-mock-changed"), WithPort(0), WithSwMock(true))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.swSpecStore.add("a.json", "/sw/a.json", &swSpec{content: []byte(utSwMockSwagger2), local: true})
+	entry.initSwMock()
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/order", nil))
+	assert.Equal(t, `{"id":1}`, w.Body.String())
+
+	// reloaded spec replaces cached one
+	entry.swSpecStore.add("a.json", "/sw/a.json", &swSpec{content: []byte(`{"swagger": "2.0", "paths": {}}`), local: true})
+
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/order", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMatchSwMockPath(t *testing.T) {
+	params, ok := matchSwMockPath("/v1/user/{id}", "/v1/user/1")
+	assert.True(t, ok)
+	assert.Equal(t, 1, params)
+
+	_, ok = matchSwMockPath("/v1/user/{id}", "/v1/user")
+	assert.False(t, ok)
+
+	_, ok = matchSwMockPath("/v1/user/{id}", "/v1/order/1")
+	assert.False(t, ok)
+}
+
+func TestSwDocBasePath(t *testing.T) {
+	assert.Equal(t, "/v2", swDocBasePath(map[string]interface{}{"basePath": "/v2"}))
+	assert.Equal(t, "/api", swDocBasePath(map[string]interface{}{
+		"servers": []interface{}{map[string]interface{}{"url": "/api"}},
+	}))
+	assert.Empty(t, swDocBasePath(map[string]interface{}{}))
+}
@@ -0,0 +1,389 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"go.uber.org/zap"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultSwJsonUrlsTtl = time.Minute
+
+// BootSW boot config of swagger UI.
+//
+// Specs in JsonUrls will be fetched on demand and cached with JsonUrlsTtlMs,
+// last fetched spec will be served if fetching fails.
+//
+// With Mock enabled, requests without registered handler are served with examples described in specs.
+//
+// Operations published in specs could be filtered by tags and path prefixes with Filter.
+//
+// Access of swagger UI could be restricted with Auth and AllowedIps.
+type BootSW struct {
+	rkentry.BootSW  `mapstructure:",squash" yaml:",inline"`
+	JsonUrls        []string     `yaml:"jsonUrls" json:"jsonUrls"`
+	JsonUrlsTtlMs   int          `yaml:"jsonUrlsTtlMs" json:"jsonUrlsTtlMs"`
+	GenerateSpec    bool         `yaml:"generateSpec" json:"generateSpec"`
+	Merge           bool         `yaml:"merge" json:"merge"`
+	Mock            bool         `yaml:"mock" json:"mock"`
+	Watch           bool         `yaml:"watch" json:"watch"`
+	WatchIntervalMs int          `yaml:"watchIntervalMs" json:"watchIntervalMs"`
+	Auth            BootSWAuth   `yaml:"auth" json:"auth"`
+	AllowedIps      []string     `yaml:"allowedIps" json:"allowedIps"`
+	Filter          BootSWFilter `yaml:"filter" json:"filter"`
+}
+
+// swSpec spec file served in swagger UI.
+//
+// Spec with url is fetched from remote and cached with ttl, spec with generate is generated on every request.
+type swSpec struct {
+	lock        sync.Mutex
+	content     []byte
+	contentType string
+	url         string
+	ttl         time.Duration
+	fetchedAt   time.Time
+	client      *http.Client
+	generate    func() ([]byte, error)
+	local       bool
+}
+
+// load returns content of spec, remote spec will be fetched if expired.
+//
+// Cached content will be returned if fetching fails, error returned only if never fetched.
+func (spec *swSpec) load() ([]byte, string, error) {
+	if spec.generate != nil {
+		content, err := spec.generate()
+		return content, "application/json", err
+	}
+
+	spec.lock.Lock()
+	defer spec.lock.Unlock()
+
+	if len(spec.url) < 1 || time.Since(spec.fetchedAt) < spec.ttl {
+		return spec.content, spec.contentType, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, spec.url, nil)
+	if err == nil {
+		var resp *http.Response
+		if resp, err = spec.client.Do(req); err == nil {
+			defer resp.Body.Close()
+
+			var content []byte
+			content, err = io.ReadAll(resp.Body)
+			if err == nil && resp.StatusCode != http.StatusOK {
+				err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+			}
+
+			if err == nil {
+				spec.content = content
+				spec.contentType = resp.Header.Get("Content-Type")
+				if len(spec.contentType) < 1 {
+					spec.contentType = "application/json"
+				}
+				spec.fetchedAt = time.Now()
+			}
+		}
+	}
+
+	if err != nil && spec.content == nil {
+		return nil, "", err
+	}
+
+	return spec.content, spec.contentType, nil
+}
+
+// swUrl element of urls in swagger-config.json.
+type swUrl struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+// swSpecStore keeps spec files served in addition to JSON files served by SwEntry.
+//
+// Both swagger 2.0 and OpenAPI 3 documents are served as they are, swagger UI renders them natively.
+type swSpecStore struct {
+	lock  sync.RWMutex
+	specs map[string]*swSpec
+	urls  []*swUrl
+}
+
+func newSwSpecStore() *swSpecStore {
+	return &swSpecStore{
+		specs: make(map[string]*swSpec),
+		urls:  make([]*swUrl, 0),
+	}
+}
+
+// add spec with key, spec with same key will be replaced.
+func (store *swSpecStore) add(key, url string, spec *swSpec) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	if _, ok := store.specs[key]; !ok {
+		store.urls = append(store.urls, &swUrl{Name: key, Url: url})
+	}
+	store.specs[key] = spec
+}
+
+// get spec with key, nil if not exist.
+func (store *swSpecStore) get(key string) *swSpec {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	return store.specs[key]
+}
+
+// removeLocal removes specs read from local files whose key is not in keep.
+func (store *swSpecStore) removeLocal(keep map[string]bool) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	urls := make([]*swUrl, 0, len(store.urls))
+	for _, u := range store.urls {
+		if spec := store.specs[u.Name]; spec.local && !keep[u.Name] {
+			delete(store.specs, u.Name)
+			continue
+		}
+		urls = append(urls, u)
+	}
+	store.urls = urls
+}
+
+// listUrls returns copy of urls.
+func (store *swSpecStore) listUrls() []*swUrl {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	res := make([]*swUrl, len(store.urls))
+	copy(res, store.urls)
+	return res
+}
+
+// initSwSpecs reads spec files from JsonPaths of SwEntry, or default directories of docs, api/gen/v1 and api/gen.
+//
+// JSON spec files are served by GinEntry as well, since ones served by SwEntry are kept in package level variables
+// of rk-entry, which are shared by every SwEntry.
+// Specs of files which no longer exist are removed, so it could be called repeatedly.
+func (entry *GinEntry) initSwSpecs() {
+	dirs := entry.SwEntry.JsonPaths
+	if len(dirs) < 1 {
+		dirs = []string{"docs", "api/gen/v1", "api/gen"}
+	}
+
+	var fsys fs.FS
+	if embedFS := rkentry.GlobalAppCtx.GetEmbedFS(rkentry.SWEntryType, entry.SwEntry.GetName()); embedFS != nil {
+		fsys = embedFS
+	}
+
+	keys := make(map[string]bool)
+	for _, dir := range dirs {
+		for _, file := range listSpecFiles(fsys, dir, specFileSuffixes) {
+			var content []byte
+			var err error
+			if fsys != nil {
+				content, err = fs.ReadFile(fsys, file)
+			} else {
+				content, err = os.ReadFile(file)
+			}
+			if err != nil {
+				continue
+			}
+
+			contentType := "application/yaml"
+			if strings.HasSuffix(strings.ToLower(file), ".json") {
+				contentType = "application/json"
+			}
+
+			key := entry.SwEntry.GetName() + "-" + path.Base(filepath.ToSlash(file))
+			entry.swSpecStore.add(key, path.Join(entry.SwEntry.Path, key), &swSpec{
+				content:     content,
+				contentType: contentType,
+				local:       true,
+			})
+			keys[key] = true
+		}
+	}
+
+	entry.swSpecStore.removeLocal(keys)
+}
+
+// initSwRemoteSpecs adds specs of remote urls, urls which are not valid http(s) urls are ignored.
+func (entry *GinEntry) initSwRemoteSpecs() {
+	ttl := entry.swJsonUrlsTtl
+	if ttl <= 0 {
+		ttl = defaultSwJsonUrlsTtl
+	}
+
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	for _, raw := range entry.swJsonUrls {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			entry.LoggerEntry.Warn("Invalid swagger spec url, ignored.", zap.String("url", raw))
+			continue
+		}
+
+		name := strings.NewReplacer(":", "_", "/", "_").Replace(u.Host) + "-" + path.Base(u.Path)
+		key := entry.SwEntry.GetName() + "-" + name
+		entry.swSpecStore.add(key, path.Join(entry.SwEntry.Path, key), &swSpec{
+			url:    raw,
+			ttl:    ttl,
+			client: client,
+		})
+	}
+}
+
+// listSpecFiles returns sorted files with one of suffixes in dir, relative path will be joined with working directory.
+func listSpecFiles(fsys fs.FS, dir string, suffixes []string) []string {
+	res := make([]string, 0)
+
+	if fsys == nil && !filepath.IsAbs(dir) {
+		wd, _ := os.Getwd()
+		dir = filepath.Join(wd, dir)
+	}
+
+	var entries []fs.DirEntry
+	var err error
+	if fsys != nil {
+		entries, err = fs.ReadDir(fsys, path.Clean(filepath.ToSlash(dir)))
+	} else {
+		entries, err = os.ReadDir(dir)
+	}
+	if err != nil {
+		return res
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !hasSpecSuffix(e.Name(), suffixes) {
+			continue
+		}
+
+		if fsys != nil {
+			res = append(res, path.Join(filepath.ToSlash(dir), e.Name()))
+		} else {
+			res = append(res, filepath.Join(dir, e.Name()))
+		}
+	}
+
+	sort.Strings(res)
+	return res
+}
+
+// hasSpecSuffix returns true if name ends with one of suffixes, case insensitive.
+func hasSpecSuffix(name string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(strings.ToLower(name), suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serveSwSpec serves spec file with name, returns false if not exist.
+func (entry *GinEntry) serveSwSpec(ctx *gin.Context, name string) bool {
+	spec := entry.swSpecStore.get(name)
+	if spec == nil {
+		return false
+	}
+
+	content, contentType, err := entry.loadSwSpec(spec)
+	if err != nil {
+		ctx.JSON(http.StatusBadGateway, rkmid.GetErrorBuilder().New(http.StatusBadGateway, "Failed to fetch spec", err))
+		return true
+	}
+
+	ctx.Header("cache-control", "no-cache")
+	ctx.Header("Content-Type", contentType)
+	http.ServeContent(ctx.Writer, ctx.Request, name, time.Time{}, bytes.NewReader(content))
+	return true
+}
+
+// WithSwJsonUrls provide remote spec urls listed in swagger UI, specs are cached with ttl, default is one minute.
+func WithSwJsonUrls(ttl time.Duration, urls ...string) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.swJsonUrls = append(entry.swJsonUrls, urls...)
+		entry.swJsonUrlsTtl = ttl
+	}
+}
+
+// serveSwConfig serves swagger-config.json with urls of SwEntry and spec store merged.
+func (entry *GinEntry) serveSwConfig(ctx *gin.Context, next gin.HandlerFunc) {
+	// read urls of SwEntry
+	writer := newBufferedResponseWriter()
+	req := ctx.Request.Clone(ctx.Request.Context())
+	entry.SwEntry.ConfigFileHandler()(writer, req)
+
+	config := struct {
+		Urls []*swUrl `json:"urls"`
+	}{}
+	if err := json.Unmarshal(writer.body.Bytes(), &config); err != nil {
+		next(ctx)
+		return
+	}
+
+	// urls of SwEntry are shared by every SwEntry, only spec of common service APIs of this entry is kept,
+	// spec files in JsonPaths are served by GinEntry
+	urls := make([]*swUrl, 0)
+	for i := range config.Urls {
+		if config.Urls[i].Name == entry.SwEntry.GetName()+swCommonSpecSuffix {
+			urls = append(urls, config.Urls[i])
+		}
+	}
+	config.Urls = urls
+
+	config.Urls = append(config.Urls, entry.swSpecStore.listUrls()...)
+
+	ctx.Header("cache-control", "no-cache")
+	ctx.JSON(http.StatusOK, config)
+}
+
+// bufferedResponseWriter http.ResponseWriter which keeps response in memory.
+type bufferedResponseWriter struct {
+	header http.Header
+	body   *bytes.Buffer
+	code   int
+}
+
+func newBufferedResponseWriter() *bufferedResponseWriter {
+	return &bufferedResponseWriter{
+		header: make(http.Header),
+		body:   &bytes.Buffer{},
+		code:   http.StatusOK,
+	}
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	w.code = code
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const utOpenApi3Yaml = `openapi: 3.0.0
+info:
+  title: ut
+  version: 1.0.0
+paths: {}
+`
+
+func TestGinEntry_initSwSpecs(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "b.yml"), []byte(utOpenApi3Yaml), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte(utOpenApi3Yaml), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "c.json"), []byte(`{"swagger":"2.0"}`), 0644))
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "d.yaml"), 0755))
+
+	entry := RegisterGinEntry(
+		WithName("ut-sw-spec"),
+		WithPort(0),
+		WithSwEntry(rkentry.RegisterSWEntry(&rkentry.BootSW{
+			Enabled:   true,
+			JsonPaths: []string{dir},
+		}, rkentry.WithNameSWEntry("ut-sw-spec"))))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.SwEntry.Bootstrap(context.TODO())
+	entry.initSwSpecs()
+	entry.Router.GET("/sw/*any", entry.swHandler())
+
+	urls := entry.swSpecStore.listUrls()
+	assert.Len(t, urls, 3)
+	assert.Equal(t, "ut-sw-spec-a.yaml", urls[0].Name)
+	assert.Equal(t, "/sw/ut-sw-spec-a.yaml", urls[0].Url)
+	assert.Equal(t, "ut-sw-spec-b.yml", urls[1].Name)
+	assert.Equal(t, "ut-sw-spec-c.json", urls[2].Name)
+
+	// yaml spec
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/ut-sw-spec-a.yaml", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, utOpenApi3Yaml, w.Body.String())
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+
+	// json spec
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/ut-sw-spec-c.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	// merged config
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/swagger-config.json", nil))
+	config := struct {
+		Urls []*swUrl `json:"urls"`
+	}{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &config))
+
+	names := make([]string, 0)
+	for _, u := range config.Urls {
+		names = append(names, u.Name)
+	}
+	assert.Contains(t, names, "ut-sw-spec-a.yaml")
+	assert.Contains(t, names, "ut-sw-spec-b.yml")
+	assert.Contains(t, names, "ut-sw-spec-c.json")
+}
+
+func TestSwSpecStore(t *testing.T) {
+	store := newSwSpecStore()
+	assert.Nil(t, store.get("ut"))
+
+	store.add("ut", "/sw/ut", &swSpec{content: []byte("a")})
+	store.add("ut", "/sw/ut", &swSpec{content: []byte("b")})
+	assert.Len(t, store.listUrls(), 1)
+	assert.Equal(t, "b", string(store.get("ut").content))
+}
+
+func TestListSpecFiles(t *testing.T) {
+	assert.Empty(t, listSpecFiles(nil, filepath.Join(t.TempDir(), "non-exist"), specFileSuffixes))
+	assert.True(t, hasSpecSuffix("a.YAML", specFileSuffixes))
+	assert.True(t, hasSpecSuffix("a.json", specFileSuffixes))
+	assert.False(t, hasSpecSuffix("a.txt", specFileSuffixes))
+}
+
+func TestGinEntry_initSwRemoteSpecs(t *testing.T) {
+	fail := false
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write([]byte(utOpenApi3Yaml))
+	}))
+	defer remote.Close()
+
+	entry := RegisterGinEntry(
+		WithName("ut-sw-remote"),
+		WithPort(0),
+		WithSwJsonUrls(time.Nanosecond, remote.URL+"/spec/api.yaml", remote.URL+"/missing.yaml", "ftp://invalid/api.yaml"),
+		WithSwEntry(rkentry.RegisterSWEntry(&rkentry.BootSW{
+			Enabled: true,
+		}, rkentry.WithNameSWEntry("ut-sw-remote"))))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.SwEntry.Bootstrap(context.TODO())
+	entry.initSwRemoteSpecs()
+	entry.Router.GET("/sw/*any", entry.swHandler())
+
+	// invalid url ignored
+	urls := entry.swSpecStore.listUrls()
+	assert.Len(t, urls, 2)
+
+	u, _ := url.Parse(remote.URL)
+	key := "ut-sw-remote-" + strings.ReplaceAll(u.Host, ":", "_") + "-api.yaml"
+	assert.Equal(t, key, urls[0].Name)
+
+	// fetched from remote
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/"+key, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, utOpenApi3Yaml, w.Body.String())
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+
+	// cached spec served while remote fails
+	fail = true
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/"+key, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, utOpenApi3Yaml, w.Body.String())
+
+	// never fetched
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/"+urls[1].Name, nil))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
+
+func TestSwSpec_load(t *testing.T) {
+	count := 0
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.Write([]byte(`{"swagger":"2.0"}`))
+	}))
+	defer remote.Close()
+
+	spec := &swSpec{url: remote.URL, ttl: time.Hour, client: http.DefaultClient}
+	content, contentType, err := spec.load()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"swagger":"2.0"}`, string(content))
+	assert.NotEmpty(t, contentType)
+
+	// cached within ttl
+	spec.load()
+	assert.Equal(t, 1, count)
+}
+
+func TestBootSW_unmarshal(t *testing.T) {
+	config := &BootConfig{}
+	rkentry.UnmarshalBootYAML([]byte(`
+gin:
+  - name: ut
+    sw:
+      enabled: true
+      jsonPaths: ["docs"]
+      jsonUrls: ["http://localhost/api.yaml"]
+      jsonUrlsTtlMs: 1000
+      auth:
+        basic: ["user:pass"]
+        apiKey: ["key"]
+      allowedIps: ["10.0.0.0/8"]
+`), config)
+
+	assert.True(t, config.Gin[0].SW.Enabled)
+	assert.Equal(t, []string{"docs"}, config.Gin[0].SW.JsonPaths)
+	assert.Equal(t, []string{"http://localhost/api.yaml"}, config.Gin[0].SW.JsonUrls)
+	assert.Equal(t, 1000, config.Gin[0].SW.JsonUrlsTtlMs)
+	assert.Equal(t, []string{"user:pass"}, config.Gin[0].SW.Auth.Basic)
+	assert.Equal(t, []string{"key"}, config.Gin[0].SW.Auth.ApiKey)
+	assert.Equal(t, []string{"10.0.0.0/8"}, config.Gin[0].SW.AllowedIps)
+}
+
+func TestGinEntry_initSwSpecs_WithMultipleEntries(t *testing.T) {
+	newEntry := func(name string) *GinEntry {
+		dir := t.TempDir()
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name+".json"), []byte(`{"swagger":"2.0"}`), 0644))
+
+		entry := RegisterGinEntry(
+			WithName(name),
+			WithPort(0),
+			WithSwEntry(rkentry.RegisterSWEntry(&rkentry.BootSW{
+				Enabled:   true,
+				JsonPaths: []string{dir},
+			}, rkentry.WithNameSWEntry(name))))
+
+		entry.SwEntry.Bootstrap(context.TODO())
+		entry.initSwSpecs()
+		entry.Router.GET("/sw/*any", entry.swHandler())
+		return entry
+	}
+
+	listNames := func(entry *GinEntry) []string {
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/swagger-config.json", nil))
+		config := struct {
+			Urls []*swUrl `json:"urls"`
+		}{}
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &config))
+
+		res := make([]string, 0)
+		for _, u := range config.Urls {
+			if !strings.HasSuffix(u.Name, swCommonSpecSuffix) {
+				res = append(res, u.Name)
+			}
+		}
+		return res
+	}
+
+	first := newEntry("ut-sw-first")
+	defer rkentry.GlobalAppCtx.RemoveEntry(first)
+	second := newEntry("ut-sw-second")
+	defer rkentry.GlobalAppCtx.RemoveEntry(second)
+
+	// each entry lists its own spec files only
+	assert.Equal(t, []string{"ut-sw-first-ut-sw-first.json"}, listNames(first))
+	assert.Equal(t, []string{"ut-sw-second-ut-sw-second.json"}, listNames(second))
+
+	// spec of other entry is not served
+	w := httptest.NewRecorder()
+	first.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/ut-sw-second-ut-sw-second.json", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
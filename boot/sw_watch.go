@@ -0,0 +1,137 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"go.uber.org/zap"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultSwWatchInterval = 5 * time.Second
+
+// swWatcher polls JsonPaths of SwEntry and reloads spec files if changed.
+type swWatcher struct {
+	lock        sync.Mutex
+	interval    time.Duration
+	fingerprint string
+	quitCh      chan struct{}
+}
+
+// WithSwWatch watch spec files in JsonPaths of SwEntry and reload them if changed, default interval is 5 seconds.
+//
+// Spec files embedded with embed.FS are not watched.
+func WithSwWatch(interval time.Duration) GinEntryOption {
+	return func(entry *GinEntry) {
+		if interval <= 0 {
+			interval = defaultSwWatchInterval
+		}
+
+		entry.swWatch = &swWatcher{
+			interval: interval,
+		}
+	}
+}
+
+// reloadSwSpecs reads spec files in JsonPaths of SwEntry, merged if merge mode enabled.
+func (entry *GinEntry) reloadSwSpecs() {
+	if entry.swMerge {
+		entry.initSwMergedSpec()
+	} else {
+		entry.initSwSpecs()
+	}
+}
+
+// startSwWatcher starts polling spec files in background, noop if not enabled or already started.
+func (entry *GinEntry) startSwWatcher() {
+	w := entry.swWatch
+	if w == nil || !entry.IsSwEnabled() ||
+		rkentry.GlobalAppCtx.GetEmbedFS(rkentry.SWEntryType, entry.SwEntry.GetName()) != nil {
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.quitCh != nil {
+		return
+	}
+
+	w.quitCh = make(chan struct{})
+	w.fingerprint = entry.swSpecsFingerprint()
+
+	go func(quitCh chan struct{}) {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				entry.checkSwSpecs()
+			case <-quitCh:
+				return
+			}
+		}
+	}(w.quitCh)
+}
+
+// stopSwWatcher stops polling started with startSwWatcher.
+func (entry *GinEntry) stopSwWatcher() {
+	w := entry.swWatch
+	if w == nil {
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.quitCh != nil {
+		close(w.quitCh)
+		w.quitCh = nil
+	}
+}
+
+// checkSwSpecs reloads spec files if fingerprint changed.
+func (entry *GinEntry) checkSwSpecs() {
+	w := entry.swWatch
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	fingerprint := entry.swSpecsFingerprint()
+	if fingerprint == w.fingerprint {
+		return
+	}
+
+	w.fingerprint = fingerprint
+	entry.reloadSwSpecs()
+	entry.LoggerEntry.Info("Swagger spec files reloaded.", zap.String("entryName", entry.entryName))
+}
+
+// swSpecsFingerprint returns name, size and modification time of spec files in JsonPaths of SwEntry.
+func (entry *GinEntry) swSpecsFingerprint() string {
+	dirs := entry.SwEntry.JsonPaths
+	if len(dirs) < 1 {
+		dirs = []string{"docs", "api/gen/v1", "api/gen"}
+	}
+
+	builder := &strings.Builder{}
+	for _, dir := range dirs {
+		for _, file := range listSpecFiles(nil, dir, specFileSuffixes) {
+			info, err := os.Stat(file)
+			if err != nil {
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano()))
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithSwWatch(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-sw-watch-opt"), WithPort(0), WithSwWatch(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.Equal(t, defaultSwWatchInterval, entry.swWatch.interval)
+
+	// noop without SwEntry
+	entry.startSwWatcher()
+	assert.Nil(t, entry.swWatch.quitCh)
+	entry.stopSwWatcher()
+}
+
+func TestGinEntry_startSwWatcher(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"swagger":"2.0"}`), 0644))
+
+	entry := RegisterGinEntry(
+		WithName("ut-sw-watch"),
+		WithPort(0),
+		WithSwWatch(10*time.Millisecond),
+		WithSwEntry(rkentry.RegisterSWEntry(&rkentry.BootSW{
+			Enabled:   true,
+			JsonPaths: []string{dir},
+		}, rkentry.WithNameSWEntry("ut-sw-watch"))))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.SwEntry.Bootstrap(context.TODO())
+	entry.reloadSwSpecs()
+	entry.startSwWatcher()
+	// started only once
+	entry.startSwWatcher()
+	defer entry.stopSwWatcher()
+	entry.Router.GET("/sw/*any", entry.swHandler())
+
+	listNames := func() []string {
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/swagger-config.json", nil))
+		config := struct {
+			Urls []*swUrl `json:"urls"`
+		}{}
+		json.Unmarshal(w.Body.Bytes(), &config)
+
+		res := make([]string, 0)
+		for _, u := range config.Urls {
+			// spec of common service depends on whether common service ever bootstrapped
+			if !strings.HasSuffix(u.Name, swCommonSpecSuffix) {
+				res = append(res, u.Name)
+			}
+		}
+		return res
+	}
+
+	// json file served by GinEntry
+	assert.Equal(t, []string{"ut-sw-watch-a.json"}, listNames())
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/ut-sw-watch-a.json", nil))
+	assert.Equal(t, `{"swagger":"2.0"}`, w.Body.String())
+
+	// file added, changed and removed
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte(utOpenApi3Yaml), 0644))
+	assert.Nil(t, os.Remove(filepath.Join(dir, "a.json")))
+
+	assert.Eventually(t, func() bool {
+		names := listNames()
+		return len(names) == 1 && names[0] == "ut-sw-watch-b.yaml"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte(utOpenApi3Yaml+"# changed\n"), 0644))
+	assert.Eventually(t, func() bool {
+		w := httptest.NewRecorder()
+		entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sw/ut-sw-watch-b.yaml", nil))
+		return w.Body.String() == utOpenApi3Yaml+"# changed\n"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestGinEntry_startSwWatcher_WithMerge(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(utSwMergeA), 0644))
+
+	entry := RegisterGinEntry(
+		WithName("ut-sw-watch-merge"),
+		WithPort(0),
+		WithSwMerge(true),
+		WithSwWatch(10*time.Millisecond),
+		WithSwEntry(rkentry.RegisterSWEntry(&rkentry.BootSW{
+			Enabled:   true,
+			JsonPaths: []string{dir},
+		}, rkentry.WithNameSWEntry("ut-sw-watch-merge"))))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	entry.reloadSwSpecs()
+	entry.startSwWatcher()
+	defer entry.stopSwWatcher()
+
+	assert.Len(t, entry.swSpecStore.listUrls(), 1)
+
+	// all files removed
+	assert.Nil(t, os.Remove(filepath.Join(dir, "a.json")))
+	assert.Eventually(t, func() bool {
+		return len(entry.swSpecStore.listUrls()) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestSwSpecStore_removeLocal(t *testing.T) {
+	store := newSwSpecStore()
+	store.add("a", "/sw/a", &swSpec{local: true})
+	store.add("b", "/sw/b", &swSpec{local: true})
+	store.add("c", "/sw/c", &swSpec{})
+
+	store.removeLocal(map[string]bool{"b": true})
+	assert.Nil(t, store.get("a"))
+	assert.NotNil(t, store.get("b"))
+	assert.NotNil(t, store.get("c"))
+	assert.Len(t, store.listUrls(), 2)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"net/http"
+	"os"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockTicksPerSec USER_HZ of linux which unit of CPU time in /proc/self/stat is based on, 100 on all mainstream platforms.
+const clockTicksPerSec = 100
+
+// SysResponse response of sys API.
+//
+// CpuUsagePercentage is CPU usage of process over all CPUs since last request, or since process started for the first request.
+// CpuUsagePercentage and FdCount are -1 if not available on current OS.
+type SysResponse struct {
+	CpuCount           int     `json:"cpuCount" yaml:"cpuCount"`
+	CpuUsagePercentage float64 `json:"cpuUsagePercentage" yaml:"cpuUsagePercentage"`
+	MemAllocByte       uint64  `json:"memAllocByte" yaml:"memAllocByte"`
+	MemSysByte         uint64  `json:"memSysByte" yaml:"memSysByte"`
+	HeapObjects        uint64  `json:"heapObjects" yaml:"heapObjects"`
+	GcCount            uint32  `json:"gcCount" yaml:"gcCount"`
+	GcPauseTotalNs     uint64  `json:"gcPauseTotalNs" yaml:"gcPauseTotalNs"`
+	GcLastPauseNs      uint64  `json:"gcLastPauseNs" yaml:"gcLastPauseNs"`
+	Goroutines         int     `json:"goroutines" yaml:"goroutines"`
+	FdCount            int     `json:"fdCount" yaml:"fdCount"`
+	StartTime          string  `json:"startTime" yaml:"startTime"`
+	UpTimeSec          int64   `json:"upTimeSec" yaml:"upTimeSec"`
+}
+
+// cpuSampler keeps last CPU time sample of process to calculate usage between requests.
+type cpuSampler struct {
+	lock     sync.Mutex
+	cpuTime  time.Duration
+	sampleAt time.Time
+}
+
+// SysPath returns path of sys API which sits next to common service paths, /rk/v1/sys by default.
+func (entry *GinEntry) SysPath() string {
+	if !entry.IsCommonServiceEnabled() {
+		return ""
+	}
+
+	return path.Join(path.Dir(entry.CommonServiceEntry.ReadyPath), "sys")
+}
+
+// SysHandler returns CPU, memory, goroutine and file descriptor stats of process.
+func (entry *GinEntry) SysHandler(ctx *gin.Context) {
+	stats := runtime.MemStats{}
+	runtime.ReadMemStats(&stats)
+
+	ctx.JSON(http.StatusOK, &SysResponse{
+		CpuCount:           runtime.NumCPU(),
+		CpuUsagePercentage: entry.cpuSampler.usage(time.Now()),
+		MemAllocByte:       stats.Alloc,
+		MemSysByte:         stats.Sys,
+		HeapObjects:        stats.HeapObjects,
+		GcCount:            stats.NumGC,
+		GcPauseTotalNs:     stats.PauseTotalNs,
+		GcLastPauseNs:      stats.PauseNs[(stats.NumGC+255)%256],
+		Goroutines:         runtime.NumGoroutine(),
+		FdCount:            countFds(),
+		StartTime:          rkentry.GlobalAppCtx.GetStartTime().Format(time.RFC3339),
+		UpTimeSec:          int64(rkentry.GlobalAppCtx.GetUpTime().Seconds()),
+	})
+}
+
+// usage returns CPU usage percentage over all CPUs since last sample, -1 if not available.
+func (sampler *cpuSampler) usage(now time.Time) float64 {
+	cpuTime, ok := readProcessCpuTime()
+	if !ok {
+		return -1
+	}
+
+	sampler.lock.Lock()
+	defer sampler.lock.Unlock()
+
+	lastCpuTime, lastSampleAt := sampler.cpuTime, sampler.sampleAt
+	if lastSampleAt.IsZero() {
+		lastSampleAt = rkentry.GlobalAppCtx.GetStartTime()
+	}
+	sampler.cpuTime, sampler.sampleAt = cpuTime, now
+
+	wall := now.Sub(lastSampleAt)
+	if wall <= 0 {
+		return 0
+	}
+
+	return float64(cpuTime-lastCpuTime) / float64(wall) / float64(runtime.NumCPU()) * 100
+}
+
+// readProcessCpuTime returns user and system CPU time of process read from /proc/self/stat.
+func readProcessCpuTime() (time.Duration, bool) {
+	raw, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return 0, false
+	}
+
+	// comm in the second field may contain spaces, fields after it are separated by spaces
+	idx := strings.LastIndex(string(raw), ")")
+	if idx < 0 {
+		return 0, false
+	}
+
+	// utime and stime are the 14th and 15th fields, which are 12th and 13th after comm
+	fields := strings.Fields(string(raw[idx+1:]))
+	if len(fields) < 13 {
+		return 0, false
+	}
+
+	utime, err1 := strconv.ParseInt(fields[11], 10, 64)
+	stime, err2 := strconv.ParseInt(fields[12], 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+
+	return time.Duration(utime+stime) * time.Second / clockTicksPerSec, true
+}
+
+// countFds returns number of open file descriptors of process listed in /proc/self/fd, -1 if not available.
+func countFds() int {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+
+	return len(entries)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestGinEntry_SysHandler(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-sys"),
+		WithPort(0),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{Enabled: true})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, "/rk/v1/sys", entry.SysPath())
+	entry.Router.GET(entry.SysPath(), entry.SysHandler)
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rk/v1/sys", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	res := &SysResponse{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, runtime.NumCPU(), res.CpuCount)
+	assert.NotZero(t, res.MemSysByte)
+	assert.NotZero(t, res.Goroutines)
+	assert.NotEmpty(t, res.StartTime)
+
+	if _, err := os.Stat("/proc/self/stat"); err == nil {
+		assert.True(t, res.CpuUsagePercentage >= 0)
+		assert.True(t, res.FdCount > 0)
+	} else {
+		assert.Equal(t, float64(-1), res.CpuUsagePercentage)
+		assert.Equal(t, -1, res.FdCount)
+	}
+
+	// disabled common service
+	disabled := RegisterGinEntry(WithName("ut-sys-disabled"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(disabled)
+	assert.Empty(t, disabled.SysPath())
+}
+
+func TestCpuSampler_usage(t *testing.T) {
+	if _, ok := readProcessCpuTime(); !ok {
+		t.Skip("/proc/self/stat not available")
+	}
+
+	sampler := &cpuSampler{}
+	now := time.Now()
+	assert.True(t, sampler.usage(now) >= 0)
+	assert.Equal(t, now, sampler.sampleAt)
+
+	// no time elapsed
+	assert.Zero(t, sampler.usage(now))
+
+	// burn CPU
+	for start := time.Now(); time.Since(start) < 50*time.Millisecond; {
+	}
+	assert.True(t, sampler.usage(now.Add(time.Second)) >= 0)
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/tracing"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"time"
+)
+
+// BootTraceExporter exporters of tracing middleware, which is exporter of rkmidtrace.BootConfig with Otlp extended
+// by http protocol, TLS and headers.
+type BootTraceExporter struct {
+	File struct {
+		Enabled    bool   `yaml:"enabled" json:"enabled"`
+		OutputPath string `yaml:"outputPath" json:"outputPath"`
+	} `yaml:"file" json:"file"`
+	Otlp   rkgintrace.OtlpExporterConfig `yaml:"otlp" json:"otlp"`
+	Zipkin struct {
+		Enabled  bool   `yaml:"enabled" json:"enabled"`
+		Endpoint string `yaml:"endpoint" json:"endpoint"`
+	} `yaml:"zipkin" json:"zipkin"`
+}
+
+// defaultTraceFlushTimeout is the default timeout of flushing spans while interrupting entry.
+const defaultTraceFlushTimeout = 5 * time.Second
+
+// WithTraceFlushTimeout provide timeout of flushing spans batched by tracing middlewares while interrupting,
+// default is 5 seconds.
+func WithTraceFlushTimeout(timeout time.Duration) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.traceFlushTimeout = timeout
+	}
+}
+
+// flushTraces flushes spans batched by tracing middlewares of entry and its groups, and shuts down exporters,
+// spans not exported within traceFlushTimeout are dropped.
+func (entry *GinEntry) flushTraces(ctx context.Context, logger *zap.Logger) {
+	timeout := entry.traceFlushTimeout
+	if timeout <= 0 {
+		timeout = defaultTraceFlushTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	entry.takeTraceProviders()
+	for _, provider := range entry.traceProviders {
+		if err := provider.Shutdown(ctx); err != nil {
+			logger.Warn("Error occurs while flushing spans.", zap.String("entryName", entry.entryName), zap.Error(err))
+		}
+	}
+	entry.traceProviders = nil
+}
+
+// takeTraceProviders moves tracer providers registered with names of entry and its groups into entry,
+// so that they are not flushed by another GinEntry registered with the same name while reloading.
+//
+// Providers registered with names taken over by another GinEntry are left to it.
+func (entry *GinEntry) takeTraceProviders() {
+	for i := range entry.groups {
+		if entry.groups[i].isRegistered() {
+			entry.traceProviders = append(entry.traceProviders, rkgintrace.Deregister(entry.groups[i].entryName)...)
+		}
+	}
+
+	if entry.isRegistered() {
+		entry.traceProviders = append(entry.traceProviders, rkgintrace.Deregister(entry.entryName)...)
+	}
+}
+
+// newHandler returns tracing middleware, spans are sampled with Sampler if Type of it is not empty and then
+// with TailSampling if enabled,
+// propagated with Propagators if not empty with fallback to FallbackHeaders, and bodies are recorded as span events
+// if Body is enabled. Resource of tracer provider contains attributes of Resource detectors.
+//
+// Requests matching IgnorePattern skip the middleware entirely.
+func (config *BootMiddlewareTrace) newHandler(entryName string) (gin.HandlerFunc, error) {
+	exporters, err := config.newExporters()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []rkmidtrace.Option{
+		rkmidtrace.WithEntryNameAndType(entryName, GinEntryType),
+		rkmidtrace.WithPathToIgnore(append(append([]string{}, config.Ignore...), config.IgnorePrefix...)...),
+	}
+
+	var sampler sdktrace.Sampler
+	if len(config.Sampler.Type) > 0 {
+		if sampler, err = rkgintrace.NewSampler(&config.Sampler); err != nil {
+			return nil, err
+		}
+	}
+
+	// sampler of provider config samples spans with span context of legacy headers as root spans
+	if sampler != nil || len(exporters) > 1 || config.TailSampling.Enabled || len(config.Resource.Detectors) > 0 ||
+		len(config.FallbackHeaders) > 0 {
+		providerConfig := &rkgintrace.ProviderConfig{Sampler: sampler, Resource: &config.Resource}
+		if config.TailSampling.Enabled {
+			providerConfig.TailSampling = &config.TailSampling
+		}
+
+		opt, err := rkgintrace.WithProviderConfig(providerConfig, entryName, GinEntryType, exporters...)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	} else {
+		opts = append(opts, rkmidtrace.WithExporter(exporters[0]))
+	}
+
+	if len(config.Propagators) > 0 || len(config.FallbackHeaders) > 0 {
+		propagator, err := rkgintrace.NewPropagator(config.Propagators...)
+		if err != nil {
+			return nil, err
+		}
+		// legacy headers are used only if no span context extracted by propagators
+		if len(config.FallbackHeaders) > 0 {
+			propagator = propagation.NewCompositeTextMapPropagator(
+				propagator, rkgintrace.NewFallbackPropagator(config.FallbackHeaders...))
+		}
+		opts = append(opts, rkmidtrace.WithPropagator(propagator))
+	}
+
+	return wrapIgnorePattern(config.IgnorePattern, rkgintrace.MiddlewareWithConfig(&rkgintrace.Config{
+		Body:                 config.Body,
+		TraceIdHeader:        config.TraceIdHeader,
+		DisableTraceIdHeader: config.DisableTraceIdHeader,
+	}, opts...)), nil
+}
+
+// newExporters returns enabled exporters of File, Otlp, Zipkin and JaegerAgent in order,
+// only the last one is created unless FanOut is true, noop exporter is returned if none is enabled.
+//
+// Precedence is the same as rkmidtrace.ToOptions, zipkin replaces otlp which replaces file.
+func (config *BootMiddlewareTrace) newExporters() ([]sdktrace.SpanExporter, error) {
+	factories := make([]func() (sdktrace.SpanExporter, error), 0)
+	if config.Exporter.File.Enabled {
+		factories = append(factories, func() (sdktrace.SpanExporter, error) {
+			return rkmidtrace.NewFileExporter(config.Exporter.File.OutputPath), nil
+		})
+	}
+	if config.Exporter.Otlp.Enabled {
+		factories = append(factories, func() (sdktrace.SpanExporter, error) {
+			return rkgintrace.CreateOtlpExporter(&config.Exporter.Otlp)
+		})
+	}
+	if config.Exporter.Zipkin.Enabled {
+		factories = append(factories, func() (sdktrace.SpanExporter, error) {
+			return rkmidtrace.NewZipkinExporter(config.Exporter.Zipkin.Endpoint), nil
+		})
+	}
+	if config.JaegerAgent.Enabled {
+		factories = append(factories, func() (sdktrace.SpanExporter, error) {
+			return rkgintrace.CreateJaegerAgentExporter(&config.JaegerAgent)
+		})
+	}
+
+	if len(factories) < 1 {
+		return []sdktrace.SpanExporter{rkmidtrace.NewNoopExporter()}, nil
+	}
+	if !config.FanOut {
+		factories = factories[len(factories)-1:]
+	}
+
+	res := make([]sdktrace.SpanExporter, 0, len(factories))
+	for _, factory := range factories {
+		exporter, err := factory()
+		if err != nil {
+			// exporters created are not used
+			for i := range res {
+				res[i].Shutdown(context.Background())
+			}
+			return nil, err
+		}
+		res = append(res, exporter)
+	}
+
+	return res, nil
+}
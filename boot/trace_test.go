@@ -0,0 +1,242 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/log"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/tracing"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBootMiddlewareTrace_NewHandler(t *testing.T) {
+	sampled := func(handler gin.HandlerFunc) bool {
+		res := false
+		router := gin.New()
+		router.Use(handler)
+		router.GET("/ut", func(ctx *gin.Context) {
+			span, _ := ctx.Get(rkmid.SpanKey.String())
+			res = span.(trace.Span).SpanContext().IsSampled()
+		})
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut", nil))
+		return res
+	}
+
+	// always sample by default
+	handler, err := (&BootMiddlewareTrace{}).newHandler("ut-trace-default")
+	assert.Nil(t, err)
+	assert.True(t, sampled(handler))
+
+	// never sample
+	handler, err = (&BootMiddlewareTrace{
+		Sampler: rkgintrace.SamplerConfig{Type: rkgintrace.SamplerNever},
+	}).newHandler("ut-trace-never")
+	assert.Nil(t, err)
+	assert.False(t, sampled(handler))
+
+	// tail sampling
+	handler, err = (&BootMiddlewareTrace{
+		TailSampling: rkgintrace.TailSamplingConfig{Enabled: true},
+	}).newHandler("ut-trace-tail")
+	assert.Nil(t, err)
+	assert.True(t, sampled(handler))
+	assert.Nil(t, rkgintrace.ShutdownExporters(context.TODO(), "ut-trace-tail"))
+
+	// propagators
+	handler, err = (&BootMiddlewareTrace{Propagators: []string{"b3", "xray"}}).newHandler("ut-trace-propagators")
+	assert.Nil(t, err)
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/ut", func(ctx *gin.Context) {
+		span, _ := ctx.Get(rkmid.SpanKey.String())
+		assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", span.(trace.Span).SpanContext().TraceID().String())
+	})
+	req := httptest.NewRequest(http.MethodGet, "/ut", nil)
+	req.Header.Set("X-Amzn-Trace-Id", "Root=1-01020304-05060708090a0b0c0d0e0f10;Parent=0102030405060708;Sampled=1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// fallback headers
+	handler, err = (&BootMiddlewareTrace{FallbackHeaders: []string{"X-Correlation-Id"}}).newHandler("ut-trace-fallback")
+	assert.Nil(t, err)
+	router = gin.New()
+	router.Use(handler)
+	router.GET("/ut", func(ctx *gin.Context) {
+		span, _ := ctx.Get(rkmid.SpanKey.String())
+		assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", span.(trace.Span).SpanContext().TraceID().String())
+	})
+	req = httptest.NewRequest(http.MethodGet, "/ut", nil)
+	req.Header.Set("X-Correlation-Id", "01020304-0506-0708-090a-0b0c0d0e0f10")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// invalid propagator
+	handler, err = (&BootMiddlewareTrace{Propagators: []string{"ut-propagator"}}).newHandler("ut-trace-invalid")
+	assert.NotNil(t, err)
+	assert.Nil(t, handler)
+
+	// invalid sampler
+	handler, err = (&BootMiddlewareTrace{
+		Sampler: rkgintrace.SamplerConfig{Type: rkgintrace.SamplerTraceIdRatio, Ratio: 2},
+	}).newHandler("ut-trace-invalid")
+	assert.NotNil(t, err)
+	assert.Nil(t, handler)
+}
+
+func TestBootMiddlewareTrace_Ignore(t *testing.T) {
+	handler, err := (&BootMiddlewareTrace{
+		Ignore:        []string{"/metrics"},
+		IgnorePrefix:  []string{"/rk/v1"},
+		IgnorePattern: []string{"/healthz", "*.js"},
+	}).newHandler("ut-trace-ignore")
+	assert.Nil(t, err)
+
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/*any", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	traced := make([]string, 0)
+	for _, p := range []string{"/metrics", "/rk/v1/ready", "/healthz", "/sw/app.js", "/v1/user"} {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, p, nil))
+		assert.Equal(t, http.StatusOK, resp.Code)
+		if len(resp.Header().Get(rkmid.HeaderTraceId)) > 0 {
+			traced = append(traced, p)
+		}
+	}
+	assert.Equal(t, []string{"/v1/user"}, traced)
+}
+
+func TestNewBuiltInMiddleware_TraceBody(t *testing.T) {
+	config := &BootMiddleware{
+		Logging: BootMiddlewareLogging{Body: rkginlog.BodyConfig{Redact: []string{"secret"}}},
+		Trace: BootMiddlewareTrace{
+			Enabled: true,
+			Body:    rkginlog.BodyConfig{Enabled: true},
+		},
+	}
+
+	router := gin.New()
+	router.Use(newTestMiddleware(t, "trace", config, "ut-trace-body"))
+	router.POST("/ut", func(ctx *gin.Context) {
+		body, _ := io.ReadAll(ctx.Request.Body)
+		ctx.Data(http.StatusOK, "application/json", body)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/ut", strings.NewReader(`{"secret":"ut-secret"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, `{"secret":"ut-secret"}`, resp.Body.String())
+	assert.NotEmpty(t, resp.Header().Get(rkmid.HeaderTraceId))
+
+	// redact of logging body is not changed
+	assert.Equal(t, []string{"secret"}, config.Logging.Body.Redact)
+	assert.Empty(t, config.Trace.Body.Redact)
+}
+
+func TestBootMiddlewareTrace_NewExporters(t *testing.T) {
+	// noop by default
+	exporters, err := (&BootMiddlewareTrace{}).newExporters()
+	assert.Nil(t, err)
+	assert.Len(t, exporters, 1)
+	assert.IsType(t, &rkmidtrace.NoopExporter{}, exporters[0])
+
+	// file exporter
+	config := &BootMiddlewareTrace{}
+	config.Exporter.File.Enabled = true
+	exporters, err = config.newExporters()
+	assert.Nil(t, err)
+	assert.Len(t, exporters, 1)
+
+	// zipkin replaces file exporter as rkmidtrace.ToOptions does
+	config.Exporter.Zipkin.Enabled = true
+	exporters, err = config.newExporters()
+	assert.Nil(t, err)
+	assert.Len(t, exporters, 1)
+	assert.Equal(t, "*zipkin.Exporter", fmt.Sprintf("%T", exporters[0]))
+	config.Exporter.Zipkin.Enabled = false
+
+	// jaeger agent replaces other exporters
+	config.JaegerAgent = rkgintrace.JaegerAgentExporterConfig{Enabled: true, Host: "127.0.0.1"}
+	exporters, err = config.newExporters()
+	assert.Nil(t, err)
+	assert.Len(t, exporters, 1)
+	assert.NotEqual(t, "*stdouttrace.Exporter", fmt.Sprintf("%T", exporters[0]))
+	assert.Nil(t, exporters[0].Shutdown(context.TODO()))
+
+	// fan out to all enabled exporters
+	config.FanOut = true
+	exporters, err = config.newExporters()
+	assert.Nil(t, err)
+	assert.Len(t, exporters, 2)
+	assert.Equal(t, "*stdouttrace.Exporter", fmt.Sprintf("%T", exporters[0]))
+	assert.Nil(t, exporters[1].Shutdown(context.TODO()))
+
+	handler, err := config.newHandler("ut-trace-fan-out")
+	assert.Nil(t, err)
+	assert.NotNil(t, handler)
+
+	// otlp with error
+	config.Exporter.Otlp = rkgintrace.OtlpExporterConfig{Enabled: true, Protocol: "thrift"}
+	_, err = config.newExporters()
+	assert.NotNil(t, err)
+}
+
+// countingExporter counts exported spans.
+type countingExporter struct {
+	count int
+}
+
+func (e *countingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.count += len(spans)
+	return nil
+}
+
+func (e *countingExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestGinEntry_FlushTraces(t *testing.T) {
+	entry := RegisterGinEntry(
+		WithName("ut-trace-flush"),
+		WithTraceFlushTimeout(time.Second))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.Equal(t, time.Second, entry.traceFlushTimeout)
+	group := entry.AddGroup("ut-trace-flush-group", "/ut")
+	defer rkentry.GlobalAppCtx.RemoveEntry(group)
+
+	exporters := make([]*countingExporter, 0)
+	for _, entryName := range []string{"ut-trace-flush", "ut-trace-flush-group"} {
+		exporter := &countingExporter{}
+		exporters = append(exporters, exporter)
+		provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+		rkgintrace.Middleware(
+			rkmidtrace.WithEntryNameAndType(entryName, GinEntryType),
+			rkmidtrace.WithTracerProvider(provider))
+
+		_, span := provider.Tracer("ut-tracer").Start(context.TODO(), "ut-span")
+		span.End()
+	}
+
+	entry.flushTraces(context.TODO(), rkentry.GlobalAppCtx.GetLoggerEntryDefault().Logger)
+	for _, exporter := range exporters {
+		assert.Equal(t, 1, exporter.count)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"path"
+)
+
+const (
+	// TvEntryType type of entry
+	TvEntryType = "TvEntry"
+
+	defaultTvRefreshMs = 5000
+)
+
+//go:embed assets/tv.html
+var tvIndexRaw string
+
+var tvIndexTemplate = template.Must(template.New("tv").Parse(tvIndexRaw))
+
+// BootTV bootstrap config of TV.
+// 1: Enabled: Enable TV, common service is required since TV is rendered from common service APIs.
+// 2: RefreshMs: Interval of refreshing dashboard in milliseconds, default is 5000, set negative value to disable.
+type BootTV struct {
+	Enabled   bool `yaml:"enabled" json:"enabled"`
+	RefreshMs int  `yaml:"refreshMs" json:"refreshMs"`
+}
+
+// TvEntry implements rkentry.Entry interface.
+//
+// TvEntry serves a dashboard at <common service path prefix>/tv/ which visualizes info, health,
+// system and request stats, entries, routes and middleware config read from common service APIs.
+type TvEntry struct {
+	entryName        string `json:"-" yaml:"-"`
+	entryType        string `json:"-" yaml:"-"`
+	entryDescription string `json:"-" yaml:"-"`
+	Path             string `json:"-" yaml:"-"`
+	ApiPath          string `json:"-" yaml:"-"`
+	RefreshMs        int    `json:"-" yaml:"-"`
+}
+
+// TvEntryOption option of TvEntry
+type TvEntryOption func(entry *TvEntry)
+
+// WithNameTvEntry provide name of TvEntry
+func WithNameTvEntry(name string) TvEntryOption {
+	return func(entry *TvEntry) {
+		entry.entryName = name
+	}
+}
+
+// WithPathPrefixTvEntry provide path prefix of common service, TV is served at <prefix>/tv/, default is /rk/v1.
+func WithPathPrefixTvEntry(prefix string) TvEntryOption {
+	return func(entry *TvEntry) {
+		if len(prefix) > 0 {
+			entry.ApiPath = prefix
+		}
+	}
+}
+
+// RegisterTvEntry register TvEntry, nil will be returned if not enabled.
+func RegisterTvEntry(boot *BootTV, opts ...TvEntryOption) *TvEntry {
+	if !boot.Enabled {
+		return nil
+	}
+
+	tvEntry := &TvEntry{
+		entryName:        "TvEntry",
+		entryType:        TvEntryType,
+		entryDescription: "Internal RK entry for TV dashboard of common service.",
+		ApiPath:          "/rk/v1",
+		RefreshMs:        boot.RefreshMs,
+	}
+
+	for i := range opts {
+		opts[i](tvEntry)
+	}
+
+	if tvEntry.RefreshMs == 0 {
+		tvEntry.RefreshMs = defaultTvRefreshMs
+	}
+
+	// add "/" at start side if missing
+	tvEntry.ApiPath = path.Join("/", tvEntry.ApiPath)
+	tvEntry.Path = path.Join(tvEntry.ApiPath, "tv") + "/"
+
+	return tvEntry
+}
+
+// Bootstrap noop
+func (entry *TvEntry) Bootstrap(context.Context) {}
+
+// Interrupt noop
+func (entry *TvEntry) Interrupt(context.Context) {}
+
+// GetName get name of Entry
+func (entry *TvEntry) GetName() string {
+	return entry.entryName
+}
+
+// GetType get type of Entry
+func (entry *TvEntry) GetType() string {
+	return entry.entryType
+}
+
+// GetDescription get description of Entry
+func (entry *TvEntry) GetDescription() string {
+	return entry.entryDescription
+}
+
+// String get string of Entry
+func (entry *TvEntry) String() string {
+	bytes, _ := json.Marshal(entry)
+	return string(bytes)
+}
+
+// MarshalJSON Marshal entry
+func (entry *TvEntry) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{
+		"name":        entry.GetName(),
+		"type":        entry.GetType(),
+		"description": entry.GetDescription(),
+		"path":        entry.Path,
+		"apiPath":     entry.ApiPath,
+		"refreshMs":   entry.RefreshMs,
+	}
+
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON Unmarshal entry
+func (entry *TvEntry) UnmarshalJSON([]byte) error {
+	return nil
+}
+
+// ConfigFileHandler handler of TV page, the same page is served for any path under TV path.
+func (entry *TvEntry) ConfigFileHandler() http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := tvIndexTemplate.Execute(writer, map[string]interface{}{
+			"Name":      entry.entryName,
+			"ApiPath":   entry.ApiPath,
+			"RefreshMs": entry.RefreshMs,
+		}); err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
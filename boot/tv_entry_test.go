@@ -0,0 +1,87 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterTvEntry(t *testing.T) {
+	// disabled
+	assert.Nil(t, RegisterTvEntry(&BootTV{}))
+
+	// with default values
+	entry := RegisterTvEntry(&BootTV{
+		Enabled: true,
+	})
+	assert.Equal(t, "TvEntry", entry.GetName())
+	assert.Equal(t, TvEntryType, entry.GetType())
+	assert.NotEmpty(t, entry.GetDescription())
+	assert.Equal(t, "/rk/v1/tv/", entry.Path)
+	assert.Equal(t, "/rk/v1", entry.ApiPath)
+	assert.Equal(t, defaultTvRefreshMs, entry.RefreshMs)
+	assert.NotEmpty(t, entry.String())
+	assert.Nil(t, entry.UnmarshalJSON(nil))
+	entry.Bootstrap(context.TODO())
+	entry.Interrupt(context.TODO())
+
+	// with options
+	entry = RegisterTvEntry(&BootTV{
+		Enabled:   true,
+		RefreshMs: -1,
+	}, WithNameTvEntry("ut-tv"), WithPathPrefixTvEntry("ut/v2/"))
+	assert.Equal(t, "ut-tv", entry.GetName())
+	assert.Equal(t, "/ut/v2/tv/", entry.Path)
+	assert.Equal(t, "/ut/v2", entry.ApiPath)
+	assert.Equal(t, -1, entry.RefreshMs)
+
+	bytes, err := json.Marshal(entry)
+	assert.Nil(t, err)
+	assert.Contains(t, string(bytes), "/ut/v2/tv/")
+}
+
+func TestTvEntry_ConfigFileHandler(t *testing.T) {
+	entry := RegisterTvEntry(&BootTV{
+		Enabled: true,
+	}, WithNameTvEntry("ut-tv"))
+
+	w := httptest.NewRecorder()
+	entry.ConfigFileHandler()(w, httptest.NewRequest(http.MethodGet, "/rk/v1/tv/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "<title>ut-tv - TV</title>")
+	assert.Contains(t, w.Body.String(), `var apiPath = "/rk/v1";`)
+	assert.Contains(t, w.Body.String(), "var refreshMs =  5000 ;")
+}
+
+func TestGinEntry_IsTvEnabled(t *testing.T) {
+	tvEntry := RegisterTvEntry(&BootTV{Enabled: true})
+
+	// common service is required
+	entry := RegisterGinEntry(
+		WithName("ut-tv"),
+		WithTvEntry(tvEntry))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.False(t, entry.IsTvEnabled())
+
+	entry = RegisterGinEntry(
+		WithName("ut-tv-common"),
+		WithTvEntry(tvEntry),
+		WithCommonServiceEntry(rkentry.RegisterCommonServiceEntry(&rkentry.BootCommonService{Enabled: true})))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.True(t, entry.IsTvEnabled())
+
+	// not registered
+	entry = RegisterGinEntry(WithName("ut-tv-disabled"))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+	assert.False(t, entry.IsTvEnabled())
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VersionGroupOption option of versioned router group.
+type VersionGroupOption func(*versionGroup)
+
+type versionGroup struct {
+	version      string
+	deprecated   bool
+	deprecatedAt time.Time
+	sunset       time.Time
+	link         string
+}
+
+// WithVersionDeprecated mark version as deprecated since time, Deprecation header will be returned.
+//
+// Zero time means deprecated without specific date.
+func WithVersionDeprecated(since time.Time) VersionGroupOption {
+	return func(g *versionGroup) {
+		g.deprecated = true
+		g.deprecatedAt = since
+	}
+}
+
+// WithVersionSunset provide time when version will be removed, Sunset header will be returned.
+func WithVersionSunset(at time.Time) VersionGroupOption {
+	return func(g *versionGroup) {
+		g.sunset = at
+	}
+}
+
+// WithVersionLink provide link of migration guide, returned in Link header with rel="deprecation".
+func WithVersionLink(link string) VersionGroupOption {
+	return func(g *versionGroup) {
+		g.link = link
+	}
+}
+
+// VersionGroup creates router group with prefix of version, like /v1.
+//
+// Requests served by the group will be tagged with API version in context, event, span and metrics.
+// Deprecation, Sunset and Link headers will be returned for deprecated versions.
+//
+//	v1 := entry.VersionGroup("v1", rkgin.WithVersionDeprecated(time.Now()), rkgin.WithVersionSunset(sunset))
+//	v1.GET("/greeter", greeter)
+func (entry *GinEntry) VersionGroup(version string, opts ...VersionGroupOption) *gin.RouterGroup {
+	g := &versionGroup{
+		version: strings.Trim(version, "/"),
+	}
+
+	for i := range opts {
+		opts[i](g)
+	}
+
+	group := entry.Router.Group("/" + g.version)
+	group.Use(g.middleware(entry.apiVersionCounter()))
+
+	return group
+}
+
+// middleware tags request with version and writes deprecation headers.
+func (g *versionGroup) middleware(counter *prometheus.CounterVec) gin.HandlerFunc {
+	headers := make(map[string]string)
+	if g.deprecated {
+		headers["Deprecation"] = "true"
+		if !g.deprecatedAt.IsZero() {
+			headers["Deprecation"] = "@" + strconv.FormatInt(g.deprecatedAt.Unix(), 10)
+		}
+	}
+	if !g.sunset.IsZero() {
+		headers["Sunset"] = g.sunset.UTC().Format(http.TimeFormat)
+	}
+	if len(g.link) > 0 {
+		headers["Link"] = fmt.Sprintf(`<%s>; rel="deprecation"`, g.link)
+	}
+
+	deprecated := strconv.FormatBool(g.deprecated)
+
+	return func(ctx *gin.Context) {
+		ctx.Set(rkginctx.ApiVersionKey, g.version)
+
+		for k, v := range headers {
+			ctx.Header(k, v)
+		}
+
+		rkginctx.GetEvent(ctx).AddPayloads(zap.String("apiVersion", g.version))
+		rkginctx.GetTraceSpan(ctx).SetAttributes(attribute.String("api.version", g.version))
+
+		if counter != nil {
+			counter.WithLabelValues(rkginctx.GetEntryName(ctx), g.version, deprecated).Inc()
+		}
+
+		ctx.Next()
+	}
+}
+
+// apiVersionCounter returns counter of requests per API version, nil if prom entry disabled.
+func (entry *GinEntry) apiVersionCounter() *prometheus.CounterVec {
+	if !entry.IsPromEnabled() || entry.PromEntry.Registerer == nil {
+		return nil
+	}
+
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "rk",
+		Subsystem: "gin",
+		Name:      "api_version",
+		Help:      "Number of requests served by versioned router groups",
+	}, []string{"entryName", "apiVersion", "deprecated"})
+
+	if err := entry.PromEntry.Registerer.Register(counter); err != nil {
+		are := prometheus.AlreadyRegisteredError{}
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(*prometheus.CounterVec); ok {
+				return existing
+			}
+		}
+		return nil
+	}
+
+	return counter
+}
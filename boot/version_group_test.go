@@ -0,0 +1,76 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGinEntry_VersionGroup(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	entry := RegisterGinEntry(
+		WithName("ut-version"),
+		WithPort(0),
+		WithPromEntry(rkentry.RegisterPromEntry(&rkentry.BootProm{Enabled: true},
+			rkentry.WithRegistryPromEntry(registry))))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	since := time.Unix(1688169599, 0)
+	sunset := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	handler := func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, rkginctx.GetApiVersion(ctx))
+	}
+
+	entry.VersionGroup("/v1/",
+		WithVersionDeprecated(since),
+		WithVersionSunset(sunset),
+		WithVersionLink("https://example.com/migrate")).GET("/ut", handler)
+	entry.VersionGroup("v2").GET("/ut", handler)
+
+	// deprecated version
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/ut", nil))
+	assert.Equal(t, "v1", w.Body.String())
+	assert.Equal(t, "@1688169599", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Tue, 01 Jan 2030 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `<https://example.com/migrate>; rel="deprecation"`, w.Header().Get("Link"))
+
+	// current version
+	w = httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/ut", nil))
+	assert.Equal(t, "v2", w.Body.String())
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+
+	// counter shared by groups
+	counter := entry.apiVersionCounter()
+	assert.Equal(t, float64(1), testutil.ToFloat64(counter.WithLabelValues("", "v1", "true")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(counter.WithLabelValues("", "v2", "false")))
+	assert.Contains(t, counter.WithLabelValues("", "v2", "false").Desc().String(), `"rk_gin_api_version"`)
+}
+
+func TestGinEntry_VersionGroup_WithoutProm(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-version"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Nil(t, entry.apiVersionCounter())
+
+	entry.VersionGroup("v1", WithVersionDeprecated(time.Time{})).GET("/ut", func(ctx *gin.Context) {})
+
+	w := httptest.NewRecorder()
+	entry.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/ut", nil))
+	assert.Equal(t, "true", w.Header().Get("Deprecation"))
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/rookie-ninja/rk-query"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"net/http"
+	"path"
+	"time"
+)
+
+const defaultWarmupTimeout = 10 * time.Second
+
+// BootWarmup boot config of warmup phase.
+//
+// Paths will be requested with GET after listener binds and before GinEntry turns ready.
+type BootWarmup struct {
+	Enabled   bool     `yaml:"enabled" json:"enabled"`
+	Paths     []string `yaml:"paths" json:"paths"`
+	TimeoutMs int      `yaml:"timeoutMs" json:"timeoutMs"`
+}
+
+// WarmupFunc will be called in GinEntry.Bootstrap after listener binds and before GinEntry turns ready.
+type WarmupFunc func(ctx context.Context) error
+
+type warmupFunc struct {
+	name string
+	f    WarmupFunc
+}
+
+// AddWarmupFunc register warmup function which will be called in Bootstrap.
+//
+// Function with same name will be replaced.
+func (entry *GinEntry) AddWarmupFunc(name string, f WarmupFunc) {
+	if f == nil {
+		return
+	}
+
+	for i := range entry.warmupFuncs {
+		if entry.warmupFuncs[i].name == name {
+			entry.warmupFuncs[i].f = f
+			return
+		}
+	}
+
+	entry.warmupFuncs = append(entry.warmupFuncs, &warmupFunc{name: name, f: f})
+}
+
+// runWarmup request warmup paths and call warmup functions one by one.
+//
+// Failures are recorded into event and logged, they won't fail bootstrap.
+func (entry *GinEntry) runWarmup(ctx context.Context, addr net.Addr, event rkquery.Event, logger *zap.Logger) {
+	timeout := entry.warmupTimeout
+	if timeout <= 0 {
+		timeout = defaultWarmupTimeout
+	}
+
+	record := func(key string, start time.Time, err error) {
+		outcome := "success"
+		if err != nil {
+			outcome = err.Error()
+			logger.Warn("Warmup failed.", zap.String("warmup", key), zap.Error(err))
+		}
+
+		event.AddPayloads(
+			zap.String(fmt.Sprintf("warmup.%s", key), outcome),
+			zap.Duration(fmt.Sprintf("warmup.%s.elapsed", key), time.Since(start)))
+	}
+
+	if addr != nil && len(entry.warmupPaths) > 0 {
+		client := entry.warmupClient(timeout)
+		defer client.CloseIdleConnections()
+
+		for _, p := range entry.warmupPaths {
+			start := time.Now()
+			record(p, start, entry.warmupPath(ctx, client, addr, p))
+		}
+	}
+
+	for _, w := range entry.warmupFuncs {
+		start := time.Now()
+		record(w.name, start, runWithTimeout(ctx, timeout, w.f))
+	}
+}
+
+// warmupClient returns http client which trusts certificate of GinEntry since requests are sent to itself.
+func (entry *GinEntry) warmupClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+}
+
+// warmupPath send GET request to path of local server, status code over 499 will be treated as error.
+func (entry *GinEntry) warmupPath(ctx context.Context, client *http.Client, addr net.Addr, p string) error {
+	_, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return err
+	}
+
+	scheme := "http"
+	if entry.IsTlsEnabled() {
+		scheme = "https"
+	}
+
+	url := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort("localhost", port), path.Join("/", p))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// WithWarmupPaths provide paths requested with GET in warmup phase.
+func WithWarmupPaths(paths ...string) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.warmupPaths = append(entry.warmupPaths, paths...)
+	}
+}
+
+// WithWarmupTimeout provide timeout of each warmup path or function, default is 10 seconds.
+func WithWarmupTimeout(timeout time.Duration) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.warmupTimeout = timeout
+	}
+}
+
+// WithWarmupFunc provide WarmupFunc.
+func WithWarmupFunc(name string, f WarmupFunc) GinEntryOption {
+	return func(entry *GinEntry) {
+		entry.AddWarmupFunc(name, f)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgin
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGinEntry_AddWarmupFunc(t *testing.T) {
+	entry := RegisterGinEntry(WithName("ut-warmup"), WithPort(0))
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	// nil func
+	entry.AddWarmupFunc("nil", nil)
+	assert.Empty(t, entry.warmupFuncs)
+
+	entry.AddWarmupFunc("ut", func(context.Context) error { return nil })
+	entry.AddWarmupFunc("ut", func(context.Context) error { return errors.New("replaced") })
+	assert.Len(t, entry.warmupFuncs, 1)
+	assert.NotNil(t, entry.warmupFuncs[0].f(context.TODO()))
+}
+
+func TestGinEntry_Bootstrap_WithWarmup(t *testing.T) {
+	var pathCalls, funcCalls int32
+	var readyWhileWarmup int32
+
+	entry := RegisterGinEntry(
+		WithName("ut-warmup"),
+		WithPort(8080),
+		WithWarmupPaths("/ut-warmup", "ut-warmup-fail"),
+		WithWarmupTimeout(time.Second),
+		WithWarmupFunc("ut-func", func(context.Context) error {
+			atomic.AddInt32(&funcCalls, 1)
+			return nil
+		}),
+		WithWarmupFunc("ut-func-fail", func(context.Context) error {
+			panic("ut")
+		}))
+
+	entry.Router.GET("/ut-warmup", func(ctx *gin.Context) {
+		atomic.AddInt32(&pathCalls, 1)
+		if entry.IsReady() {
+			atomic.StoreInt32(&readyWhileWarmup, 1)
+		}
+		ctx.Status(http.StatusOK)
+	})
+	entry.Router.GET("/ut-warmup-fail", func(ctx *gin.Context) {
+		ctx.Status(http.StatusInternalServerError)
+	})
+
+	assert.Nil(t, entry.BootstrapWithError(context.TODO()))
+	defer entry.Interrupt(context.TODO())
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&pathCalls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&funcCalls))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&readyWhileWarmup))
+	assert.True(t, entry.IsReady())
+}
+
+func TestGinEntry_ReadyHandler_WithWarmup(t *testing.T) {
+	entries, err := RegisterGinEntryYAMLWithError([]byte(`
+gin:
+  - name: ut-warmup-ready
+    port: 0
+    enabled: true
+    commonService:
+      enabled: true
+`))
+	assert.Nil(t, err)
+	entry := entries["ut-warmup-ready"].(*GinEntry)
+
+	// not ready while warming up
+	codeWhileWarmup := 0
+	entry.AddWarmupFunc("ut-ready", func(context.Context) error {
+		codeWhileWarmup = serveTest(entry, http.MethodGet, "/rk/v1/ready", "", nil).Code
+		return nil
+	})
+
+	assert.Nil(t, entry.BootstrapWithError(context.TODO()))
+	assert.Equal(t, http.StatusServiceUnavailable, codeWhileWarmup)
+	assert.Equal(t, http.StatusOK, serveTest(entry, http.MethodGet, "/rk/v1/ready", "", nil).Code)
+
+	// readiness check of application is applied once ready
+	entry.SetReadinessCheck(func(req *http.Request, resp http.ResponseWriter) bool {
+		resp.WriteHeader(http.StatusServiceUnavailable)
+		return false
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, serveTest(entry, http.MethodGet, "/rk/v1/ready", "", nil).Code)
+	entry.SetReadinessCheck(nil)
+
+	// not ready once interrupted
+	entry.Interrupt(context.TODO())
+	assert.Equal(t, http.StatusServiceUnavailable, serveTest(entry, http.MethodGet, "/rk/v1/ready", "", nil).Code)
+}
+
+func TestRegisterGinEntryYAML_WithWarmup(t *testing.T) {
+	bootStr := `
+gin:
+  - name: ut-warmup
+    port: 1949
+    enabled: true
+    warmup:
+      enabled: true
+      paths: ["/rk/v1/ready"]
+      timeoutMs: 500
+`
+	entries := RegisterGinEntryYAML([]byte(bootStr))
+	entry := entries["ut-warmup"].(*GinEntry)
+	defer rkentry.GlobalAppCtx.RemoveEntry(entry)
+
+	assert.Equal(t, []string{"/rk/v1/ready"}, entry.warmupPaths)
+	assert.Equal(t, 500*time.Millisecond, entry.warmupTimeout)
+}
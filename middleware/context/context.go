@@ -8,10 +8,13 @@ package rkginctx
 
 import (
 	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rookie-ninja/rk-entry/v2/cursor"
 	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/prom"
 	"github.com/rookie-ninja/rk-logger"
 	"github.com/rookie-ninja/rk-query"
 	otelcodes "go.opentelemetry.io/otel/codes"
@@ -21,6 +24,11 @@ import (
 	"net/http"
 )
 
+const (
+	// ApiVersionKey key of API version set by versioned router group of GinEntry
+	ApiVersionKey = "rkApiVersion"
+)
+
 var (
 	noopTracerProvider = trace.NewNoopTracerProvider()
 	noopEvent          = rkquery.NewEventFactory().CreateEventNoop()
@@ -146,6 +154,14 @@ func GetEntryName(ctx *gin.Context) string {
 	return ""
 }
 
+// GetApiVersion extract API version of versioned router group from context.
+func GetApiVersion(ctx *gin.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	return ctx.GetString(ApiVersionKey)
+}
+
 // GetTraceSpan extract the call-scoped span from context.
 func GetTraceSpan(ctx *gin.Context) trace.Span {
 	_, span := noopTracerProvider.Tracer("rk-trace-noop").Start(ctx, "noop-span")
@@ -223,6 +239,26 @@ func NewTraceSpan(ctx *gin.Context, name string) trace.Span {
 	return span
 }
 
+// NewLinkedSpan starts a new root span linked to span in context and links provided, for async work enqueued by
+// handler or batched items processed by handler, whose lifetime is not bounded by request.
+//
+// Returned context carries the new span and should be passed to the async work, context of request is not changed.
+func NewLinkedSpan(ctx *gin.Context, name string, links ...trace.Link) (context.Context, trace.Span) {
+	parent := context.Background()
+	allLinks := make([]trace.Link, 0, len(links)+1)
+	if ctx != nil {
+		if ctx.Request != nil {
+			parent = ctx.Request.Context()
+		}
+		if spanCtx := GetTraceSpan(ctx).SpanContext(); spanCtx.IsValid() {
+			allLinks = append(allLinks, trace.Link{SpanContext: spanCtx})
+		}
+	}
+	allLinks = append(allLinks, links...)
+
+	return GetTracer(ctx).Start(parent, name, trace.WithNewRoot(), trace.WithLinks(allLinks...))
+}
+
 // EndTraceSpan end span
 func EndTraceSpan(ctx *gin.Context, span trace.Span, success bool) {
 	if success {
@@ -232,6 +268,71 @@ func EndTraceSpan(ctx *gin.Context, span trace.Span, success bool) {
 	span.End()
 }
 
+// GetTraceState returns W3C tracestate of span in context, which contains vendor entries propagated from upstream.
+func GetTraceState(ctx *gin.Context) trace.TraceState {
+	return GetTraceSpan(ctx).SpanContext().TraceState()
+}
+
+// GetTraceStateValue returns value of vendor entry with key in tracestate of span in context, empty if missing.
+func GetTraceStateValue(ctx *gin.Context, key string) string {
+	return GetTraceState(ctx).Get(key)
+}
+
+// SetTraceStateValue inserts or updates vendor entry in tracestate of span in context, which becomes the first entry
+// as W3C requires. Tracestate is injected by InjectSpanToHttpRequest and inherited by spans created by NewTraceSpan.
+//
+// Error is returned if key or value is invalid, or there is no span in context.
+func SetTraceStateValue(ctx *gin.Context, key, value string) error {
+	if ctx == nil || ctx.Request == nil {
+		return errors.New("no span in context")
+	}
+
+	span, err := withTraceStateValue(GetTraceSpan(ctx), key, value)
+	if err != nil {
+		return err
+	}
+	ctx.Set(rkmid.SpanKey.String(), span)
+
+	// current span of request could be a child span created by NewTraceSpan
+	span, err = withTraceStateValue(trace.SpanFromContext(ctx.Request.Context()), key, value)
+	if err == nil {
+		ctx.Request = ctx.Request.WithContext(trace.ContextWithSpan(ctx.Request.Context(), span))
+	}
+
+	return nil
+}
+
+// withTraceStateValue returns span whose span context has vendor entry in tracestate,
+// and records into the same span.
+func withTraceStateValue(span trace.Span, key, value string) (trace.Span, error) {
+	spanCtx := span.SpanContext()
+	if !spanCtx.IsValid() {
+		return nil, errors.New("no span in context")
+	}
+
+	traceState, err := spanCtx.TraceState().Insert(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, ok := span.(*traceStateSpan); ok {
+		span = v.Span
+	}
+
+	return &traceStateSpan{Span: span, spanCtx: spanCtx.WithTraceState(traceState)}, nil
+}
+
+// traceStateSpan overrides tracestate of span context, since span context of span is immutable.
+type traceStateSpan struct {
+	trace.Span
+	spanCtx trace.SpanContext
+}
+
+// SpanContext returns span context with tracestate overridden.
+func (span *traceStateSpan) SpanContext() trace.SpanContext {
+	return span.spanCtx
+}
+
 // GetJwtToken return jwt.Token if exists
 func GetJwtToken(ctx *gin.Context) *jwt.Token {
 	if ctx == nil {
@@ -261,3 +362,43 @@ func GetCsrfToken(ctx *gin.Context) string {
 
 	return ""
 }
+
+// GetCustomCounter returns counter with name and labelKeys in MetricsSet of prom middleware of entry,
+// the counter is registered into registerer of prom middleware at the first call.
+//
+// Nil is returned if prom middleware is not enabled or counter could not be registered,
+// label values passed to returned counter should match labelKeys of the first call.
+func GetCustomCounter(ctx *gin.Context, name string, labelKeys ...string) *prometheus.CounterVec {
+	set := rkginprom.GetMetricsSet(ctx)
+	if set == nil {
+		return nil
+	}
+
+	if res := set.GetCounter(name); res != nil {
+		return res
+	}
+
+	// counter may be registered by another request concurrently
+	set.RegisterCounter(name, labelKeys...)
+	return set.GetCounter(name)
+}
+
+// GetCustomGauge returns gauge with name and labelKeys in MetricsSet of prom middleware of entry,
+// the gauge is registered into registerer of prom middleware at the first call.
+//
+// Nil is returned if prom middleware is not enabled or gauge could not be registered,
+// label values passed to returned gauge should match labelKeys of the first call.
+func GetCustomGauge(ctx *gin.Context, name string, labelKeys ...string) *prometheus.GaugeVec {
+	set := rkginprom.GetMetricsSet(ctx)
+	if set == nil {
+		return nil
+	}
+
+	if res := set.GetGauge(name); res != nil {
+		return res
+	}
+
+	// gauge may be registered by another request concurrently
+	set.RegisterGauge(name, labelKeys...)
+	return set.GetGauge(name)
+}
@@ -6,14 +6,20 @@
 package rkginctx
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/prometheus/client_golang/prometheus"
 	rkcursor "github.com/rookie-ninja/rk-entry/v2/cursor"
 	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/prom"
 	"github.com/rookie-ninja/rk-logger"
 	"github.com/rookie-ninja/rk-query"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
 	"net/http"
 	"net/http/httptest"
@@ -153,6 +159,19 @@ func TestGetEntryName(t *testing.T) {
 	assert.Equal(t, "ut-entry-name", GetEntryName(ctx))
 }
 
+func TestGetApiVersion(t *testing.T) {
+	// With nil context
+	assert.Empty(t, GetApiVersion(nil))
+
+	// With no version in context
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.Empty(t, GetApiVersion(ctx))
+
+	// Happy case
+	ctx.Set(ApiVersionKey, "v1")
+	assert.Equal(t, "v1", GetApiVersion(ctx))
+}
+
 func TestGetTraceSpan(t *testing.T) {
 	// With no span in context
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
@@ -227,6 +246,38 @@ func TestNewTraceSpan(t *testing.T) {
 	assert.NotNil(t, NewTraceSpan(ctx, "ut-span"))
 }
 
+func TestNewLinkedSpan(t *testing.T) {
+	// without span
+	newCtx, span := NewLinkedSpan(nil, "ut-span")
+	assert.NotNil(t, newCtx)
+	assert.NotNil(t, span)
+
+	// linked to server span and links provided
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("ut-tracer")
+	reqCtx, serverSpan := tracer.Start(context.TODO(), "ut-server-span")
+	_, itemSpan := tracer.Start(context.TODO(), "ut-item-span")
+
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/ut", nil).WithContext(reqCtx)
+	ctx.Set(rkmid.SpanKey.String(), serverSpan)
+	ctx.Set(rkmid.TracerKey.String(), tracer)
+
+	newCtx, span = NewLinkedSpan(ctx, "ut-linked-span", trace.Link{SpanContext: itemSpan.SpanContext()})
+	span.End()
+	assert.Equal(t, span, trace.SpanFromContext(newCtx))
+	assert.NotEqual(t, serverSpan.SpanContext().TraceID(), span.SpanContext().TraceID())
+	// context of request is not changed
+	assert.Equal(t, serverSpan, trace.SpanFromContext(ctx.Request.Context()))
+
+	ended := recorder.Ended()
+	assert.Len(t, ended, 1)
+	assert.False(t, ended[0].Parent().IsValid())
+	assert.Len(t, ended[0].Links(), 2)
+	assert.Equal(t, serverSpan.SpanContext(), ended[0].Links()[0].SpanContext)
+	assert.Equal(t, itemSpan.SpanContext(), ended[0].Links()[1].SpanContext)
+}
+
 func TestEndTraceSpan(t *testing.T) {
 	defer assertNotPanic(t)
 
@@ -241,6 +292,47 @@ func TestEndTraceSpan(t *testing.T) {
 	EndTraceSpan(ctx, span, false)
 }
 
+func TestTraceState(t *testing.T) {
+	// without span
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/ut", nil)
+	assert.Equal(t, 0, GetTraceState(ctx).Len())
+	assert.NotNil(t, SetTraceStateValue(ctx, "mesh", "ut-route"))
+	assert.NotNil(t, SetTraceStateValue(nil, "mesh", "ut-route"))
+
+	// with span propagated from upstream
+	traceState, _ := trace.ParseTraceState("upstream=ut-value")
+	span := trace.SpanFromContext(trace.ContextWithSpanContext(context.TODO(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		TraceState: traceState,
+	})))
+	ctx.Set(rkmid.SpanKey.String(), span)
+	ctx.Request = ctx.Request.WithContext(trace.ContextWithSpan(ctx.Request.Context(), span))
+	ctx.Set(rkmid.PropagatorKey.String(), propagation.TraceContext{})
+	assert.Equal(t, "ut-value", GetTraceStateValue(ctx, "upstream"))
+
+	// invalid key
+	assert.NotNil(t, SetTraceStateValue(ctx, "UT KEY", "ut-route"))
+
+	// new entry is the first one
+	assert.Nil(t, SetTraceStateValue(ctx, "mesh", "ut-route"))
+	assert.Nil(t, SetTraceStateValue(ctx, "mesh", "ut-route-new"))
+	assert.Equal(t, "mesh=ut-route-new,upstream=ut-value", GetTraceState(ctx).String())
+	assert.Equal(t, span.SpanContext().SpanID(), GetTraceSpan(ctx).SpanContext().SpanID())
+
+	// inherited by child span
+	ctx.Set(rkmid.TracerKey.String(), sdktrace.NewTracerProvider().Tracer("ut-tracer"))
+	child := NewTraceSpan(ctx, "ut-span")
+	assert.Equal(t, "ut-route-new", child.SpanContext().TraceState().Get("mesh"))
+
+	// propagated to downstream
+	req := httptest.NewRequest(http.MethodGet, "/ut", nil)
+	InjectSpanToHttpRequest(ctx, req)
+	assert.Equal(t, "mesh=ut-route-new,upstream=ut-value", req.Header.Get("tracestate"))
+}
+
 func TestGetJwtToken(t *testing.T) {
 	defer assertNotPanic(t)
 
@@ -271,6 +363,46 @@ func TestGetCsrfToken(t *testing.T) {
 	assert.Equal(t, "value", GetCsrfToken(ctx))
 }
 
+func TestGetCustomCounter(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	// without prom middleware
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.Nil(t, GetCustomCounter(ctx, "ut_items"))
+
+	// happy case
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/ut", nil)
+	rkginprom.Middleware(rkmidprom.WithEntryNameAndType("ut-custom-counter", "ut-type"),
+		rkmidprom.WithRegisterer(prometheus.NewRegistry()))(ctx)
+	counter := GetCustomCounter(ctx, "ut_items", "kind")
+	assert.NotNil(t, counter)
+	counter.WithLabelValues("book").Inc()
+	assert.Equal(t, counter, GetCustomCounter(ctx, "ut_items", "kind"))
+
+	// invalid name
+	assert.Nil(t, GetCustomCounter(ctx, "ut-items"))
+}
+
+func TestGetCustomGauge(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	// without prom middleware
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.Nil(t, GetCustomGauge(ctx, "ut_cache_size"))
+
+	// happy case
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/ut", nil)
+	rkginprom.Middleware(rkmidprom.WithEntryNameAndType("ut-custom-gauge", "ut-type"),
+		rkmidprom.WithRegisterer(prometheus.NewRegistry()))(ctx)
+	gauge := GetCustomGauge(ctx, "ut_cache_size")
+	assert.NotNil(t, gauge)
+	gauge.WithLabelValues().Set(10)
+	assert.Equal(t, gauge, GetCustomGauge(ctx, "ut_cache_size"))
+
+	// invalid name
+	assert.Nil(t, GetCustomGauge(ctx, "ut-cache-size"))
+}
+
 func TestSetPointerCreator(t *testing.T) {
 	assert.Nil(t, pointerCreator)
 
@@ -0,0 +1,299 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-query"
+	"go.uber.org/zap"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// redactedValue replaces value of redacted field.
+	redactedValue = "***"
+	// defaultBodyMaxBytes is the default size cap of recorded body.
+	defaultBodyMaxBytes = 4096
+)
+
+var (
+	// defaultBodyContentTypes textual content types recorded by default.
+	defaultBodyContentTypes = []string{"application/json", "application/x-www-form-urlencoded", "text/*"}
+	// defaultBodyRedact field names redacted by default.
+	defaultBodyRedact = []string{"password", "token"}
+)
+
+// BodyConfig config of recording request and response bodies into payloads of event.
+//
+// Bodies are recorded up to MaxBytes, 4096 by default, if media type is one of ContentTypes,
+// which could be exact type or wildcard like text/*, JSON and form bodies are recorded by default.
+//
+// Redact contains field names like password, which are redacted at any depth, or paths from root like user.token,
+// values of JSON and form fields matching them are replaced with ***, password and token are redacted by default.
+type BodyConfig struct {
+	Enabled      bool     `yaml:"enabled" json:"enabled"`
+	MaxBytes     int      `yaml:"maxBytes" json:"maxBytes"`
+	Redact       []string `yaml:"redact" json:"redact"`
+	ContentTypes []string `yaml:"contentTypes" json:"contentTypes"`
+}
+
+// bodyExtension records request and response bodies as reqBody and resBody in payloads of event.
+type bodyExtension struct {
+	redactor *BodyRedactor
+}
+
+// NewBodyExtension creates Extension which records bodies, nil is returned if config is not enabled.
+func NewBodyExtension(config *BodyConfig) Extension {
+	if config == nil || !config.Enabled {
+		return nil
+	}
+
+	return &bodyExtension{redactor: NewBodyRedactor(config)}
+}
+
+// Before reads request body up to maxBytes and replaces response writer to capture response body.
+func (ext *bodyExtension) Before(ctx *gin.Context, event rkquery.Event) {
+	if body := ext.redactor.ReadRequest(ctx.Request); len(body) > 0 {
+		event.AddPayloads(zap.String("reqBody", body))
+	}
+
+	ctx.Writer = &bodyWriter{ResponseWriter: ctx.Writer, maxBytes: ext.redactor.maxBytes}
+}
+
+// After records captured response body.
+func (ext *bodyExtension) After(ctx *gin.Context, event rkquery.Event) bool {
+	writer, ok := ctx.Writer.(*bodyWriter)
+	if !ok {
+		return true
+	}
+
+	if body := ext.redactor.FormatResponse(writer, writer.buf.Bytes()); len(body) > 0 {
+		event.AddPayloads(zap.String("resBody", body))
+	}
+
+	return true
+}
+
+// BodyRedactor redacts and truncates bodies with rules of BodyConfig, which is shared by middlewares recording bodies,
+// like span events of tracing middleware.
+type BodyRedactor struct {
+	maxBytes     int
+	names        map[string]bool
+	paths        map[string]bool
+	contentTypes []string
+	pattern      *regexp.Regexp
+}
+
+// NewBodyRedactor creates BodyRedactor with config, defaults are used for empty fields, Enabled is not checked.
+func NewBodyRedactor(config *BodyConfig) *BodyRedactor {
+	if config == nil {
+		config = &BodyConfig{}
+	}
+
+	r := &BodyRedactor{
+		maxBytes:     config.MaxBytes,
+		names:        make(map[string]bool),
+		paths:        make(map[string]bool),
+		contentTypes: config.ContentTypes,
+	}
+	if r.maxBytes < 1 {
+		r.maxBytes = defaultBodyMaxBytes
+	}
+	if len(r.contentTypes) < 1 {
+		r.contentTypes = defaultBodyContentTypes
+	}
+
+	redact := config.Redact
+	if len(redact) < 1 {
+		redact = defaultBodyRedact
+	}
+
+	// last segment of paths are used to redact bodies which could not be parsed, like truncated JSON
+	quoted := make([]string, 0, len(redact))
+	for _, v := range redact {
+		v = strings.TrimPrefix(v, "$.")
+		if strings.Contains(v, ".") {
+			r.paths[v] = true
+		} else {
+			r.names[v] = true
+		}
+		quoted = append(quoted, regexp.QuoteMeta(v[strings.LastIndex(v, ".")+1:]))
+	}
+	r.pattern = regexp.MustCompile(fmt.Sprintf(`("(?:%s)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]*)`, strings.Join(quoted, "|")))
+
+	return r
+}
+
+// MaxBytes returns size cap of recorded body.
+func (r *BodyRedactor) MaxBytes() int {
+	return r.maxBytes
+}
+
+// ReadRequest reads request body up to MaxBytes and restores it for handlers,
+// redacted body is returned, empty if body is missing or not recordable.
+func (r *BodyRedactor) ReadRequest(req *http.Request) string {
+	if req.Body == nil || !r.IsRecordable(req.Header.Get("Content-Type")) {
+		return ""
+	}
+
+	head, _ := io.ReadAll(io.LimitReader(req.Body, int64(r.maxBytes+1)))
+	req.Body = &bodyReader{Reader: io.MultiReader(bytes.NewReader(head), req.Body), Closer: req.Body}
+	if len(head) < 1 {
+		return ""
+	}
+
+	return r.Format(head, req.Header.Get("Content-Type"))
+}
+
+// FormatResponse returns redacted response body captured from writer, empty if body is missing or not recordable.
+func (r *BodyRedactor) FormatResponse(writer http.ResponseWriter, body []byte) string {
+	contentType := writer.Header().Get("Content-Type")
+	if len(body) < 1 || !r.IsRecordable(contentType) {
+		return ""
+	}
+
+	return r.Format(body, contentType)
+}
+
+// IsRecordable returns true if media type of contentType is allowed.
+func (r *BodyRedactor) IsRecordable(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, v := range r.contentTypes {
+		if v == mediaType || (strings.HasSuffix(v, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(v, "*"))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Format redacts body and marks it if longer than MaxBytes, which is truncated.
+func (r *BodyRedactor) Format(body []byte, contentType string) string {
+	truncated := len(body) > r.maxBytes
+	if truncated {
+		body = body[:r.maxBytes]
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	res := ""
+	switch {
+	case !truncated && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")):
+		res = r.redactJson(body)
+	case !truncated && mediaType == "application/x-www-form-urlencoded":
+		res = r.redactForm(body)
+	default:
+		res = r.pattern.ReplaceAllString(string(body), `${1}"`+redactedValue+`"`)
+	}
+
+	if truncated {
+		res += "...(truncated)"
+	}
+
+	return res
+}
+
+// redactJson replaces values of redacted fields, falls back to pattern if body is not valid JSON.
+func (r *BodyRedactor) redactJson(body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return r.pattern.ReplaceAllString(string(body), `${1}"`+redactedValue+`"`)
+	}
+
+	res, _ := json.Marshal(r.redactValue(value, ""))
+	return string(res)
+}
+
+// redactValue walks value and replaces values of fields matching names or paths, array elements share path of array.
+func (r *BodyRedactor) redactValue(value interface{}, p string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key := range v {
+			child := key
+			if len(p) > 0 {
+				child = p + "." + key
+			}
+
+			if r.names[key] || r.paths[child] {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = r.redactValue(v[key], child)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = r.redactValue(v[i], p)
+		}
+	}
+
+	return value
+}
+
+// redactForm replaces values of redacted form fields.
+func (r *BodyRedactor) redactForm(body []byte) string {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return r.pattern.ReplaceAllString(string(body), `${1}"`+redactedValue+`"`)
+	}
+
+	for key := range values {
+		if r.names[key] || r.paths[key] {
+			for i := range values[key] {
+				values[key][i] = redactedValue
+			}
+		}
+	}
+
+	return values.Encode()
+}
+
+// bodyReader restores request body which is partially read.
+type bodyReader struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyWriter captures response body up to maxBytes, one more byte is kept to detect truncation.
+type bodyWriter struct {
+	gin.ResponseWriter
+	maxBytes int
+	buf      bytes.Buffer
+}
+
+// Write captures data before writing it into response.
+func (w *bodyWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString captures data before writing it into response.
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyWriter) capture(data []byte) {
+	if remain := w.maxBytes + 1 - w.buf.Len(); remain > 0 {
+		if len(data) > remain {
+			data = data[:remain]
+		}
+		w.buf.Write(data)
+	}
+}
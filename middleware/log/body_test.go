@@ -0,0 +1,147 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-logger"
+	"github.com/rookie-ninja/rk-query"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serveWithExtension serves request with extension and returns payloads of event and body read by handler.
+func serveWithExtension(ext Extension, req *http.Request, contentType, resBody string) (map[string]string, string) {
+	event := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+
+	var reqBody string
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		ext.Before(ctx, event)
+		ctx.Next()
+		ext.After(ctx, event)
+	})
+	router.Any("/ut-path", func(ctx *gin.Context) {
+		data, _ := io.ReadAll(ctx.Request.Body)
+		reqBody = string(data)
+		ctx.Data(http.StatusOK, contentType, []byte(resBody))
+	})
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	payloads := make(map[string]string)
+	for _, v := range event.ListPayloads() {
+		payloads[v.Key] = v.String
+	}
+
+	return payloads, reqBody
+}
+
+func TestNewBodyExtension(t *testing.T) {
+	assert.Nil(t, NewBodyExtension(nil))
+	assert.Nil(t, NewBodyExtension(&BodyConfig{}))
+
+	ext := NewBodyExtension(&BodyConfig{Enabled: true}).(*bodyExtension)
+	assert.Equal(t, defaultBodyMaxBytes, ext.redactor.maxBytes)
+	assert.Equal(t, defaultBodyContentTypes, ext.redactor.contentTypes)
+	assert.True(t, ext.redactor.names["password"])
+	assert.True(t, ext.redactor.names["token"])
+
+	ext = NewBodyExtension(&BodyConfig{Enabled: true, Redact: []string{"secret", "$.user.token"}}).(*bodyExtension)
+	assert.True(t, ext.redactor.names["secret"])
+	assert.True(t, ext.redactor.paths["user.token"])
+}
+
+func TestBodyExtension_Json(t *testing.T) {
+	ext := NewBodyExtension(&BodyConfig{
+		Enabled: true,
+		Redact:  []string{"password", "user.token"},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/ut-path",
+		strings.NewReader(`{"name":"ut","password":"p","nested":{"password":"p"},"user":{"token":"t","id":1},"token":"kept"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	payloads, reqBody := serveWithExtension(ext, req, "application/json", `{"token":"kept","list":[{"password":"p"}]}`)
+
+	// handler reads the whole body
+	assert.Contains(t, reqBody, `"password":"p"`)
+	assert.Equal(t, `{"name":"ut","nested":{"password":"***"},"password":"***","token":"kept","user":{"id":1,"token":"***"}}`, payloads["reqBody"])
+	assert.Equal(t, `{"list":[{"password":"***"}],"token":"kept"}`, payloads["resBody"])
+}
+
+func TestBodyExtension_Form(t *testing.T) {
+	ext := NewBodyExtension(&BodyConfig{Enabled: true})
+
+	req := httptest.NewRequest(http.MethodPost, "/ut-path", strings.NewReader("user=ut&password=p"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	payloads, _ := serveWithExtension(ext, req, "text/plain", "ok")
+	assert.Equal(t, "password=%2A%2A%2A&user=ut", payloads["reqBody"])
+	assert.Equal(t, "ok", payloads["resBody"])
+}
+
+func TestBodyExtension_Truncated(t *testing.T) {
+	ext := NewBodyExtension(&BodyConfig{Enabled: true, MaxBytes: 30})
+
+	body := `{"password":"secret-value","name":"a-very-long-name-which-is-truncated"}`
+	req := httptest.NewRequest(http.MethodPost, "/ut-path", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	payloads, reqBody := serveWithExtension(ext, req, "application/json", body)
+
+	// body is restored for handler
+	assert.Equal(t, body, reqBody)
+	assert.Equal(t, `{"password":"***","na...(truncated)`, payloads["reqBody"])
+	assert.Equal(t, `{"password":"***","na...(truncated)`, payloads["resBody"])
+}
+
+func TestBodyExtension_ContentTypes(t *testing.T) {
+	ext := NewBodyExtension(&BodyConfig{Enabled: true, ContentTypes: []string{"application/json"}})
+
+	req := httptest.NewRequest(http.MethodPost, "/ut-path", strings.NewReader("plain"))
+	req.Header.Set("Content-Type", "text/plain")
+
+	payloads, reqBody := serveWithExtension(ext, req, "application/octet-stream", "binary")
+	assert.Equal(t, "plain", reqBody)
+	assert.NotContains(t, payloads, "reqBody")
+	assert.NotContains(t, payloads, "resBody")
+
+	// wildcard
+	ext = NewBodyExtension(&BodyConfig{Enabled: true, ContentTypes: []string{"text/*"}})
+	assert.True(t, ext.(*bodyExtension).redactor.IsRecordable("text/html; charset=utf-8"))
+	assert.False(t, ext.(*bodyExtension).redactor.IsRecordable("application/json"))
+	assert.False(t, ext.(*bodyExtension).redactor.IsRecordable(""))
+}
+
+func TestBodyRedactor(t *testing.T) {
+	// defaults without config
+	redactor := NewBodyRedactor(nil)
+	assert.Equal(t, defaultBodyMaxBytes, redactor.MaxBytes())
+
+	// request without body
+	req := httptest.NewRequest(http.MethodGet, "/ut", nil)
+	req.Body = nil
+	assert.Empty(t, redactor.ReadRequest(req))
+
+	// request body is redacted and restored
+	req = httptest.NewRequest(http.MethodPost, "/ut", strings.NewReader("password=ut-pass&user=ut-user"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	assert.Equal(t, "password=%2A%2A%2A&user=ut-user", redactor.ReadRequest(req))
+	body, _ := io.ReadAll(req.Body)
+	assert.Equal(t, "password=ut-pass&user=ut-user", string(body))
+
+	// response which is not recordable
+	resp := httptest.NewRecorder()
+	resp.Header().Set("Content-Type", "image/png")
+	assert.Empty(t, redactor.FormatResponse(resp, []byte("ut-image")))
+
+	resp.Header().Set("Content-Type", "application/json")
+	assert.Equal(t, `{"token":"***"}`, redactor.FormatResponse(resp, []byte(`{"token":"ut-token"}`)))
+}
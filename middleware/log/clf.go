@@ -0,0 +1,157 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-query"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// CLFCommon is the Common Log Format of Apache and Nginx.
+	CLFCommon = "common"
+	// CLFCombined is the Combined Log Format which appends referer and user agent to Common Log Format.
+	CLFCombined = "combined"
+	// clfTimeLayout is the time layout of Common Log Format.
+	clfTimeLayout = "02/Jan/2006:15:04:05 -0700"
+)
+
+// CLFConfig config of writing Common or Combined Log Format lines for existing log parsing pipelines.
+//
+// Format could be common or combined, combined by default. Lines are written in addition to rk events,
+// rk events are dropped if Exclusive is true. OutputPaths is used by boot package to open writer, stdout by default.
+type CLFConfig struct {
+	Enabled     bool     `yaml:"enabled" json:"enabled"`
+	Format      string   `yaml:"format" json:"format"`
+	OutputPaths []string `yaml:"outputPaths" json:"outputPaths"`
+	Exclusive   bool     `yaml:"exclusive" json:"exclusive"`
+}
+
+// clfExtension writes a line of Common or Combined Log Format for every request.
+type clfExtension struct {
+	combined  bool
+	exclusive bool
+	writer    io.Writer
+	lock      sync.Mutex
+}
+
+// NewCLFExtension creates Extension which writes lines into writer, nil is returned if config is not enabled.
+func NewCLFExtension(config *CLFConfig, writer io.Writer) (Extension, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	if writer == nil {
+		return nil, fmt.Errorf("nil writer of log format %s", config.Format)
+	}
+
+	ext := &clfExtension{
+		exclusive: config.Exclusive,
+		writer:    writer,
+	}
+
+	switch strings.ToLower(config.Format) {
+	case "", CLFCombined:
+		ext.combined = true
+	case CLFCommon:
+	default:
+		return nil, fmt.Errorf("unsupported log format %s, should be one of common or combined", config.Format)
+	}
+
+	return ext, nil
+}
+
+// Before does nothing.
+func (ext *clfExtension) Before(*gin.Context, rkquery.Event) {}
+
+// After writes line of request, false is returned if extension is exclusive.
+func (ext *clfExtension) After(ctx *gin.Context, event rkquery.Event) bool {
+	line := ext.format(ctx, event)
+
+	ext.lock.Lock()
+	_, _ = io.WriteString(ext.writer, line)
+	ext.lock.Unlock()
+
+	return !ext.exclusive
+}
+
+// format returns line like:
+// 127.0.0.1 - user [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.1" 200 2326 "http://referer" "Mozilla/5.0"
+func (ext *clfExtension) format(ctx *gin.Context, event rkquery.Event) string {
+	req := ctx.Request
+
+	user := "-"
+	if name, _, ok := req.BasicAuth(); ok && len(name) > 0 {
+		user = clfEscape(name)
+	}
+
+	uri := req.RequestURI
+	if len(uri) < 1 {
+		uri = req.URL.RequestURI()
+	}
+
+	size := "-"
+	if ctx.Writer.Size() > 0 {
+		size = strconv.Itoa(ctx.Writer.Size())
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString(clfOrDash(ctx.ClientIP()))
+	builder.WriteString(" - ")
+	builder.WriteString(user)
+	builder.WriteString(" [")
+	builder.WriteString(event.GetStartTime().Format(clfTimeLayout))
+	builder.WriteString("] \"")
+	builder.WriteString(clfEscape(req.Method + " " + uri + " " + req.Proto))
+	builder.WriteString("\" ")
+	builder.WriteString(strconv.Itoa(ctx.Writer.Status()))
+	builder.WriteString(" ")
+	builder.WriteString(size)
+
+	if ext.combined {
+		builder.WriteString(" \"")
+		builder.WriteString(clfOrDash(clfEscape(req.Referer())))
+		builder.WriteString("\" \"")
+		builder.WriteString(clfOrDash(clfEscape(req.UserAgent())))
+		builder.WriteString("\"")
+	}
+
+	builder.WriteString("\n")
+
+	return builder.String()
+}
+
+// clfOrDash returns - if s is empty.
+func clfOrDash(s string) string {
+	if len(s) < 1 {
+		return "-"
+	}
+	return s
+}
+
+// clfEscape escapes quotes, backslashes and non-printable bytes like Apache does.
+func clfEscape(s string) string {
+	builder := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			builder.WriteByte('\\')
+			builder.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&builder, "\\x%02x", c)
+		default:
+			builder.WriteByte(c)
+		}
+	}
+
+	return builder.String()
+}
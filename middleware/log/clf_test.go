@@ -0,0 +1,101 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-logger"
+	"github.com/rookie-ninja/rk-query"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// serveWithCLFExtension serves request with extension and returns whether event is kept.
+func serveWithCLFExtension(ext Extension, req *http.Request) bool {
+	event := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+	event.SetStartTime(time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*3600)))
+
+	keep := false
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		ext.Before(ctx, event)
+		ctx.Next()
+		keep = ext.After(ctx, event)
+	})
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "ut-body")
+	})
+	router.GET("/ut-empty", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	return keep
+}
+
+func TestNewCLFExtension(t *testing.T) {
+	ext, err := NewCLFExtension(nil, nil)
+	assert.Nil(t, ext)
+	assert.Nil(t, err)
+
+	ext, err = NewCLFExtension(&CLFConfig{}, nil)
+	assert.Nil(t, ext)
+	assert.Nil(t, err)
+
+	// nil writer
+	ext, err = NewCLFExtension(&CLFConfig{Enabled: true}, nil)
+	assert.Nil(t, ext)
+	assert.NotNil(t, err)
+
+	// invalid format
+	ext, err = NewCLFExtension(&CLFConfig{Enabled: true, Format: "invalid"}, &bytes.Buffer{})
+	assert.Nil(t, ext)
+	assert.NotNil(t, err)
+
+	ext, err = NewCLFExtension(&CLFConfig{Enabled: true}, &bytes.Buffer{})
+	assert.Nil(t, err)
+	assert.True(t, ext.(*clfExtension).combined)
+
+	ext, err = NewCLFExtension(&CLFConfig{Enabled: true, Format: "Common"}, &bytes.Buffer{})
+	assert.Nil(t, err)
+	assert.False(t, ext.(*clfExtension).combined)
+}
+
+func TestCLFExtension_Combined(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ext, _ := NewCLFExtension(&CLFConfig{Enabled: true}, buf)
+
+	req := httptest.NewRequest(http.MethodGet, "/ut-path?k=v", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.SetBasicAuth("ut-user", "pass")
+	req.Header.Set("Referer", "http://ut-referer")
+	req.Header.Set("User-Agent", `ut-"agent"`)
+
+	assert.True(t, serveWithCLFExtension(ext, req))
+	assert.Equal(t,
+		`10.0.0.1 - ut-user [10/Oct/2000:13:55:36 -0700] "GET /ut-path?k=v HTTP/1.1" 200 7 "http://ut-referer" "ut-\"agent\""`+"\n",
+		buf.String())
+}
+
+func TestCLFExtension_Common(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ext, _ := NewCLFExtension(&CLFConfig{Enabled: true, Format: CLFCommon, Exclusive: true}, buf)
+
+	req := httptest.NewRequest(http.MethodGet, "/ut-empty", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	// event is dropped if exclusive
+	assert.False(t, serveWithCLFExtension(ext, req))
+	assert.Equal(t, `10.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /ut-empty HTTP/1.1" 204 -`+"\n", buf.String())
+}
+
+func TestCLFEscape(t *testing.T) {
+	assert.Equal(t, `a\"b\\c\x0a\xe4`, clfEscape("a\"b\\c\n\xe4"))
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-query"
+)
+
+// EventEnricher appends fields like tenantId or orderId into event before it is finished.
+//
+// EventEnricher is an Extension which is called after handlers, it never drops event.
+type EventEnricher func(ctx *gin.Context, event rkquery.Event)
+
+// Before does nothing.
+func (f EventEnricher) Before(*gin.Context, rkquery.Event) {}
+
+// After calls enricher and returns true.
+func (f EventEnricher) After(ctx *gin.Context, event rkquery.Event) bool {
+	if f != nil {
+		f(ctx, event)
+	}
+
+	return true
+}
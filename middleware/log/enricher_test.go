@@ -0,0 +1,32 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-logger"
+	"github.com/rookie-ninja/rk-query"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventEnricher(t *testing.T) {
+	event := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+	ctx := newCtx()
+	ctx.Set("tenantId", "ut-tenant")
+
+	var ext Extension = EventEnricher(func(ctx *gin.Context, event rkquery.Event) {
+		event.AddPair("tenantId", ctx.GetString("tenantId"))
+	})
+
+	ext.Before(ctx, event)
+	assert.Empty(t, event.GetValueFromPair("tenantId"))
+	assert.True(t, ext.After(ctx, event))
+	assert.Equal(t, "ut-tenant", event.GetValueFromPair("tenantId"))
+
+	// nil enricher
+	assert.True(t, EventEnricher(nil).After(ctx, event))
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-query"
+	"net/http"
+)
+
+const (
+	// ErrorClassKey is the key of pair added into event of failed request.
+	ErrorClassKey = "errorClass"
+	// ErrorClassClient is the class of 4xx responses.
+	ErrorClassClient = "client_error"
+	// ErrorClassServer is the class of 5xx responses.
+	ErrorClassServer = "server_error"
+	// ErrorClassTimeout is the class of 408 and 504 responses or requests whose deadline exceeded.
+	ErrorClassTimeout = "timeout"
+	// ErrorClassCanceled is the class of requests canceled by client, 499 responses included.
+	ErrorClassCanceled = "canceled"
+	// statusClientClosedRequest is the non-standard status of Nginx for requests closed by client.
+	statusClientClosedRequest = 499
+)
+
+// errorClassExtension adds errorClass pair into event of failed request.
+type errorClassExtension struct{}
+
+// NewErrorClassExtension creates Extension which classifies failed requests with status code,
+// errors in gin.Context and context of request.
+func NewErrorClassExtension() Extension {
+	return &errorClassExtension{}
+}
+
+// Before does nothing.
+func (ext *errorClassExtension) Before(*gin.Context, rkquery.Event) {}
+
+// After adds errorClass pair if request failed, it always returns true.
+func (ext *errorClassExtension) After(ctx *gin.Context, event rkquery.Event) bool {
+	if class := classifyError(ctx); len(class) > 0 {
+		event.AddPair(ErrorClassKey, class)
+	}
+
+	return true
+}
+
+// classifyError returns class of failed request, empty string if request succeeded.
+//
+// Cancellation and timeout are checked before status code since they are usually responded with 5xx.
+func classifyError(ctx *gin.Context) string {
+	status := ctx.Writer.Status()
+
+	switch {
+	case status == statusClientClosedRequest || isContextError(ctx, context.Canceled):
+		return ErrorClassCanceled
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout ||
+		isContextError(ctx, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case status >= http.StatusInternalServerError:
+		return ErrorClassServer
+	case status >= http.StatusBadRequest:
+		return ErrorClassClient
+	}
+
+	return ""
+}
+
+// isContextError returns true if context of request or any error in gin.Context is target.
+func isContextError(ctx *gin.Context, target error) bool {
+	if errors.Is(ctx.Request.Context().Err(), target) {
+		return true
+	}
+
+	for _, err := range ctx.Errors {
+		if errors.Is(err.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"context"
+	"fmt"
+	"github.com/rookie-ninja/rk-logger"
+	"github.com/rookie-ninja/rk-query"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	for status, class := range map[int]string{
+		http.StatusOK:                  "",
+		http.StatusFound:               "",
+		http.StatusNotFound:            ErrorClassClient,
+		http.StatusRequestTimeout:      ErrorClassTimeout,
+		http.StatusInternalServerError: ErrorClassServer,
+		http.StatusGatewayTimeout:      ErrorClassTimeout,
+		statusClientClosedRequest:      ErrorClassCanceled,
+	} {
+		ctx := newCtx()
+		ctx.Status(status)
+		assert.Equal(t, class, classifyError(ctx), status)
+	}
+
+	// canceled request
+	ctx := newCtx()
+	reqCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ctx.Request = ctx.Request.WithContext(reqCtx)
+	ctx.Status(http.StatusInternalServerError)
+	assert.Equal(t, ErrorClassCanceled, classifyError(ctx))
+
+	// deadline exceeded error in gin.Context
+	ctx = newCtx()
+	ctx.Status(http.StatusInternalServerError)
+	_ = ctx.Error(fmt.Errorf("query db: %w", context.DeadlineExceeded))
+	assert.Equal(t, ErrorClassTimeout, classifyError(ctx))
+}
+
+func TestErrorClassExtension(t *testing.T) {
+	ext := NewErrorClassExtension()
+	event := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+
+	ctx := newCtx()
+	ctx.Status(http.StatusOK)
+	assert.True(t, ext.After(ctx, event))
+	assert.Empty(t, event.GetValueFromPair(ErrorClassKey))
+
+	ctx.Status(http.StatusBadRequest)
+	assert.True(t, ext.After(ctx, event))
+	assert.Equal(t, ErrorClassClient, event.GetValueFromPair(ErrorClassKey))
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-query"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+// defaultHeaderMask headers which are always masked.
+var defaultHeaderMask = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// HeaderConfig config of recording request and response headers into payloads of event.
+//
+// Request and Response are names of headers to record, * records all headers.
+// Values of Authorization, Proxy-Authorization, Cookie, Set-Cookie and headers in Mask are masked,
+// scheme of authorization like Bearer is kept.
+type HeaderConfig struct {
+	Enabled  bool     `yaml:"enabled" json:"enabled"`
+	Request  []string `yaml:"request" json:"request"`
+	Response []string `yaml:"response" json:"response"`
+	Mask     []string `yaml:"mask" json:"mask"`
+}
+
+// headerExtension records headers as reqHeaders and resHeaders in payloads of event.
+type headerExtension struct {
+	request  []string
+	response []string
+	mask     map[string]bool
+}
+
+// NewHeaderExtension creates Extension which records headers, nil is returned if config is not enabled.
+func NewHeaderExtension(config *HeaderConfig) Extension {
+	if config == nil || !config.Enabled {
+		return nil
+	}
+
+	ext := &headerExtension{
+		request:  canonicalHeaders(config.Request),
+		response: canonicalHeaders(config.Response),
+		mask:     make(map[string]bool),
+	}
+	for _, v := range canonicalHeaders(append(append([]string{}, defaultHeaderMask...), config.Mask...)) {
+		ext.mask[v] = true
+	}
+
+	return ext
+}
+
+// Before records request headers.
+func (ext *headerExtension) Before(ctx *gin.Context, event rkquery.Event) {
+	if headers := ext.record(ctx.Request.Header, ext.request); len(headers) > 0 {
+		event.AddPayloads(zap.Any("reqHeaders", headers))
+	}
+}
+
+// After records response headers.
+func (ext *headerExtension) After(ctx *gin.Context, event rkquery.Event) bool {
+	if headers := ext.record(ctx.Writer.Header(), ext.response); len(headers) > 0 {
+		event.AddPayloads(zap.Any("resHeaders", headers))
+	}
+
+	return true
+}
+
+// record returns allowed headers with masked values, multiple values are joined with comma.
+func (ext *headerExtension) record(header http.Header, allowed []string) map[string]string {
+	res := make(map[string]string)
+	for _, name := range allowed {
+		if name == "*" {
+			for k := range header {
+				res[k] = ext.value(k, header.Values(k))
+			}
+			continue
+		}
+
+		if values := header.Values(name); len(values) > 0 {
+			res[name] = ext.value(name, values)
+		}
+	}
+
+	return res
+}
+
+// value joins values of header and masks them if needed.
+func (ext *headerExtension) value(name string, values []string) string {
+	if !ext.mask[name] {
+		return strings.Join(values, ",")
+	}
+
+	masked := make([]string, 0, len(values))
+	for _, v := range values {
+		if scheme, _, ok := strings.Cut(v, " "); ok && strings.HasSuffix(name, "Authorization") {
+			masked = append(masked, scheme+" "+redactedValue)
+		} else {
+			masked = append(masked, redactedValue)
+		}
+	}
+
+	return strings.Join(masked, ",")
+}
+
+// canonicalHeaders returns canonical form of header names.
+func canonicalHeaders(names []string) []string {
+	res := make([]string, 0, len(names))
+	for _, v := range names {
+		if v == "*" {
+			res = append(res, v)
+			continue
+		}
+		res = append(res, http.CanonicalHeaderKey(v))
+	}
+
+	return res
+}
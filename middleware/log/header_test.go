@@ -0,0 +1,103 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-logger"
+	"github.com/rookie-ninja/rk-query"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// serveWithHeaderExtension serves request with extension and returns recorded headers.
+func serveWithHeaderExtension(ext Extension, req *http.Request) (interface{}, interface{}) {
+	event := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		ext.Before(ctx, event)
+		ctx.Next()
+		ext.After(ctx, event)
+	})
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Header("X-Res", "res")
+		ctx.Header("Set-Cookie", "session=abc")
+		ctx.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var reqHeaders, resHeaders interface{}
+	for _, v := range event.ListPayloads() {
+		switch v.Key {
+		case "reqHeaders":
+			reqHeaders = v.Interface
+		case "resHeaders":
+			resHeaders = v.Interface
+		}
+	}
+
+	return reqHeaders, resHeaders
+}
+
+func TestNewHeaderExtension(t *testing.T) {
+	assert.Nil(t, NewHeaderExtension(nil))
+	assert.Nil(t, NewHeaderExtension(&HeaderConfig{}))
+
+	ext := NewHeaderExtension(&HeaderConfig{
+		Enabled: true,
+		Request: []string{"x-req", "*"},
+		Mask:    []string{"x-api-key"},
+	}).(*headerExtension)
+	assert.Equal(t, []string{"X-Req", "*"}, ext.request)
+	assert.True(t, ext.mask["Authorization"])
+	assert.True(t, ext.mask["Cookie"])
+	assert.True(t, ext.mask["X-Api-Key"])
+}
+
+func TestHeaderExtension(t *testing.T) {
+	ext := NewHeaderExtension(&HeaderConfig{
+		Enabled:  true,
+		Request:  []string{"authorization", "x-req", "x-api-key", "x-missing"},
+		Response: []string{"x-res", "set-cookie"},
+		Mask:     []string{"x-api-key"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ut-path", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Add("X-Req", "a")
+	req.Header.Add("X-Req", "b")
+	req.Header.Set("X-Api-Key", "key")
+	req.Header.Set("X-Other", "other")
+
+	reqHeaders, resHeaders := serveWithHeaderExtension(ext, req)
+	assert.Equal(t, map[string]string{
+		"Authorization": "Bearer ***",
+		"X-Req":         "a,b",
+		"X-Api-Key":     "***",
+	}, reqHeaders)
+	assert.Equal(t, map[string]string{
+		"X-Res":      "res",
+		"Set-Cookie": "***",
+	}, resHeaders)
+}
+
+func TestHeaderExtension_All(t *testing.T) {
+	ext := NewHeaderExtension(&HeaderConfig{Enabled: true, Request: []string{"*"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/ut-path", nil)
+	req.Header.Set("Cookie", "session=abc")
+	req.Header.Set("X-Other", "other")
+
+	reqHeaders, resHeaders := serveWithHeaderExtension(ext, req)
+	assert.Equal(t, map[string]string{
+		"Cookie":  "***",
+		"X-Other": "other",
+	}, reqHeaders)
+	assert.Nil(t, resHeaders)
+}
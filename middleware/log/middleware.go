@@ -11,26 +11,75 @@ import (
 	"github.com/rookie-ninja/rk-entry/v2/middleware"
 	"github.com/rookie-ninja/rk-entry/v2/middleware/log"
 	"github.com/rookie-ninja/rk-gin/v2/middleware/context"
+	"github.com/rookie-ninja/rk-query"
+	"net/http"
 	"strconv"
 )
 
+// Extension enriches or filters event of logging middleware with gin.Context.
+type Extension interface {
+	// Before is called after event is created and before handlers.
+	Before(ctx *gin.Context, event rkquery.Event)
+
+	// After is called after handlers and before event is finished, event is not logged if false is returned.
+	After(ctx *gin.Context, event rkquery.Event) bool
+}
+
+// requestFilter is implemented by extensions which change request recorded in event, request handled is not changed.
+type requestFilter interface {
+	filterRequest(req *http.Request) *http.Request
+}
+
 // Middleware returns a gin.HandlerFunc (middleware) that logs requests using uber-go/zap.
 func Middleware(opts ...rkmidlog.Option) gin.HandlerFunc {
+	return MiddlewareWithExtensions(nil, opts...)
+}
+
+// MiddlewareWithExtensions returns a gin.HandlerFunc (middleware) that logs requests using uber-go/zap,
+// extensions are called in order on requests which are not ignored.
+func MiddlewareWithExtensions(extensions []Extension, opts ...rkmidlog.Option) gin.HandlerFunc {
 	set := rkmidlog.NewOptionSet(opts...)
 
 	return func(ctx *gin.Context) {
 		ctx.Set(rkmid.EntryNameKey.String(), set.GetEntryName())
 
+		exts := extensions
+		if set.ShouldIgnore(ctx.Request.URL.Path) {
+			exts = nil
+		}
+
+		// request recorded in event could be changed by extensions, like redacted query
+		req := ctx.Request
+		for i := range exts {
+			if filter, ok := exts[i].(requestFilter); ok {
+				req = filter.filterRequest(req)
+			}
+		}
+
 		// call before
-		beforeCtx := set.BeforeCtx(ctx.Request)
+		beforeCtx := set.BeforeCtx(req)
 		set.Before(beforeCtx)
 
 		ctx.Set(rkmid.EventKey.String(), beforeCtx.Output.Event)
 		ctx.Set(rkmid.LoggerKey.String(), beforeCtx.Output.Logger)
 
+		for i := range exts {
+			exts[i].Before(ctx, beforeCtx.Output.Event)
+		}
+
 		// call next
 		ctx.Next()
 
+		keep := true
+		for i := range exts {
+			if !exts[i].After(ctx, beforeCtx.Output.Event) {
+				keep = false
+			}
+		}
+		if !keep {
+			return
+		}
+
 		// call after
 		afterCtx := set.AfterCtx(
 			rkginctx.GetRequestId(ctx),
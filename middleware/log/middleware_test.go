@@ -64,3 +64,46 @@ func TestMain(m *testing.M) {
 	gin.SetMode(gin.ReleaseMode)
 	os.Exit(m.Run())
 }
+
+// utExtension counts calls and keeps event if keep is true.
+type utExtension struct {
+	before, after int
+	keep          bool
+}
+
+func (ext *utExtension) Before(ctx *gin.Context, event rkquery.Event) {
+	ext.before++
+}
+
+func (ext *utExtension) After(ctx *gin.Context, event rkquery.Event) bool {
+	ext.after++
+	return ext.keep
+}
+
+func TestMiddlewareWithExtensions(t *testing.T) {
+	defer assertNotPanic(t)
+
+	keep, drop := &utExtension{keep: true}, &utExtension{keep: false}
+	inter := MiddlewareWithExtensions([]Extension{keep, drop},
+		rkmidlog.WithEventEntry(rkentry.EventEntryNoop),
+		rkmidlog.WithLoggerEntry(rkentry.LoggerEntryNoop),
+		rkmidlog.WithPathToIgnore("/ut-ignore"))
+
+	// extensions are called in order, both of them are called even if one drops event
+	inter(newCtx())
+	assert.Equal(t, 1, keep.before)
+	assert.Equal(t, 1, keep.after)
+	assert.Equal(t, 1, drop.before)
+	assert.Equal(t, 1, drop.after)
+
+	// ignored path
+	ctx := newCtx()
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/ut-ignore", nil)
+	inter(ctx)
+	assert.Equal(t, 1, keep.before)
+	assert.Equal(t, 1, drop.after)
+
+	// extensions are kept for following requests
+	inter(newCtx())
+	assert.Equal(t, 2, keep.before)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-query"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// defaultQueryMaxLength is the default length cap of recorded query.
+	defaultQueryMaxLength = 1024
+)
+
+// defaultQueryRedact parameters redacted by default.
+var defaultQueryRedact = []string{"token", "access_token", "api_key", "apikey", "password", "secret"}
+
+// QueryConfig config of query string recorded as apiQuery in payloads of event.
+//
+// Values of parameters in Redact, token, access_token, api_key, apikey, password and secret by default,
+// are replaced with *** with case-insensitive match, query is truncated to MaxLength, 1024 by default.
+type QueryConfig struct {
+	Enabled   bool     `yaml:"enabled" json:"enabled"`
+	Redact    []string `yaml:"redact" json:"redact"`
+	MaxLength int      `yaml:"maxLength" json:"maxLength"`
+}
+
+// queryExtension redacts and truncates query recorded in event.
+type queryExtension struct {
+	redact    map[string]bool
+	maxLength int
+}
+
+// NewQueryExtension creates Extension which redacts query, nil is returned if config is not enabled.
+func NewQueryExtension(config *QueryConfig) Extension {
+	if config == nil || !config.Enabled {
+		return nil
+	}
+
+	ext := &queryExtension{
+		redact:    make(map[string]bool),
+		maxLength: config.MaxLength,
+	}
+	if ext.maxLength < 1 {
+		ext.maxLength = defaultQueryMaxLength
+	}
+
+	redact := config.Redact
+	if len(redact) < 1 {
+		redact = defaultQueryRedact
+	}
+	for _, v := range redact {
+		ext.redact[strings.ToLower(v)] = true
+	}
+
+	return ext
+}
+
+// Before does nothing.
+func (ext *queryExtension) Before(*gin.Context, rkquery.Event) {}
+
+// After returns true.
+func (ext *queryExtension) After(*gin.Context, rkquery.Event) bool {
+	return true
+}
+
+// filterRequest returns copy of request whose query is redacted and truncated.
+func (ext *queryExtension) filterRequest(req *http.Request) *http.Request {
+	if len(req.URL.RawQuery) < 1 {
+		return req
+	}
+
+	u := *req.URL
+	u.RawQuery = ext.format(req.URL.RawQuery)
+
+	res := *req
+	res.URL = &u
+
+	return &res
+}
+
+// format redacts values of parameters in order and truncates query.
+func (ext *queryExtension) format(rawQuery string) string {
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		rawKey, _, _ := strings.Cut(param, "=")
+		key := rawKey
+		if unescaped, err := url.QueryUnescape(rawKey); err == nil {
+			key = unescaped
+		}
+
+		if ext.redact[strings.ToLower(key)] {
+			params[i] = rawKey + "=" + redactedValue
+		}
+	}
+
+	res := strings.Join(params, "&")
+	if len(res) > ext.maxLength {
+		res = res[:ext.maxLength] + "...(truncated)"
+	}
+
+	return res
+}
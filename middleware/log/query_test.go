@@ -0,0 +1,79 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/log"
+	"github.com/rookie-ninja/rk-logger"
+	"github.com/rookie-ninja/rk-query"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewQueryExtension(t *testing.T) {
+	assert.Nil(t, NewQueryExtension(nil))
+	assert.Nil(t, NewQueryExtension(&QueryConfig{}))
+
+	ext := NewQueryExtension(&QueryConfig{Enabled: true}).(*queryExtension)
+	assert.Equal(t, defaultQueryMaxLength, ext.maxLength)
+	assert.True(t, ext.redact["api_key"])
+
+	ext = NewQueryExtension(&QueryConfig{Enabled: true, Redact: []string{"Sig"}, MaxLength: 10}).(*queryExtension)
+	assert.Equal(t, 10, ext.maxLength)
+	assert.True(t, ext.redact["sig"])
+	assert.False(t, ext.redact["token"])
+}
+
+func TestQueryExtension_FilterRequest(t *testing.T) {
+	ext := NewQueryExtension(&QueryConfig{Enabled: true}).(*queryExtension)
+
+	req := httptest.NewRequest(http.MethodGet, "/ut-path?id=1&TOKEN=secret&api%5Fkey=key&flag&password", nil)
+	filtered := ext.filterRequest(req)
+
+	assert.Equal(t, "id=1&TOKEN=***&api%5Fkey=***&flag&password=***", filtered.URL.RawQuery)
+	assert.Equal(t, "/ut-path", filtered.URL.Path)
+
+	// request handled is not changed
+	assert.Equal(t, "id=1&TOKEN=secret&api%5Fkey=key&flag&password", req.URL.RawQuery)
+
+	// empty query
+	req = httptest.NewRequest(http.MethodGet, "/ut-path", nil)
+	assert.Equal(t, req, ext.filterRequest(req))
+}
+
+func TestQueryExtension_Truncated(t *testing.T) {
+	ext := NewQueryExtension(&QueryConfig{Enabled: true, MaxLength: 12}).(*queryExtension)
+	assert.Equal(t, "token=***&id...(truncated)", ext.format("token=secret&id=123456"))
+}
+
+func TestMiddlewareWithExtensions_Query(t *testing.T) {
+	factory := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger))
+	eventEntry := &rkentry.EventEntry{EventFactory: factory, EventHelper: rkquery.NewEventHelper(factory)}
+
+	var apiQuery string
+	recorder := EventEnricher(func(ctx *gin.Context, event rkquery.Event) {
+		for _, v := range event.ListPayloads() {
+			if v.Key == "apiQuery" {
+				apiQuery = v.String
+			}
+		}
+	})
+
+	inter := MiddlewareWithExtensions([]Extension{NewQueryExtension(&QueryConfig{Enabled: true}), recorder},
+		rkmidlog.WithEventEntry(eventEntry),
+		rkmidlog.WithLoggerEntry(rkentry.LoggerEntryNoop))
+
+	ctx := newCtx()
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/ut-path?token=secret&id=1", nil)
+	inter(ctx)
+
+	assert.Equal(t, "token=***&id=1", apiQuery)
+	assert.Equal(t, "token=secret&id=1", ctx.Request.URL.RawQuery)
+}
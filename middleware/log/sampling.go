@@ -0,0 +1,73 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-query"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// samplingRand could be replaced in unit test.
+var samplingRand = rand.Float64
+
+// SamplingConfig config of sampling events of successful requests.
+//
+// Only events of 2xx responses are sampled, one of every Every events is kept if Every is set,
+// otherwise events are kept with probability of Rate between 0 and 1.
+// Events of other responses, requests slower than SlowMs and requests marked by slow extension are always kept.
+type SamplingConfig struct {
+	Enabled bool    `yaml:"enabled" json:"enabled"`
+	Rate    float64 `yaml:"rate" json:"rate"`
+	Every   uint64  `yaml:"every" json:"every"`
+	SlowMs  int64   `yaml:"slowMs" json:"slowMs"`
+}
+
+// samplingExtension drops events of successful requests which are not sampled.
+type samplingExtension struct {
+	// counter is the first field to keep 64-bit alignment of atomic operations on 32-bit platforms
+	counter uint64
+	rate    float64
+	every   uint64
+	slow    time.Duration
+}
+
+// NewSamplingExtension creates Extension which samples events, nil is returned if config is not enabled.
+func NewSamplingExtension(config *SamplingConfig) Extension {
+	if config == nil || !config.Enabled {
+		return nil
+	}
+
+	return &samplingExtension{
+		rate:  config.Rate,
+		every: config.Every,
+		slow:  time.Duration(config.SlowMs) * time.Millisecond,
+	}
+}
+
+// Before does nothing.
+func (ext *samplingExtension) Before(*gin.Context, rkquery.Event) {}
+
+// After returns true if event should be logged.
+func (ext *samplingExtension) After(ctx *gin.Context, event rkquery.Event) bool {
+	status := ctx.Writer.Status()
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return true
+	}
+
+	if isSlow(event) || (ext.slow > 0 && time.Since(event.GetStartTime()) >= ext.slow) {
+		return true
+	}
+
+	if ext.every > 0 {
+		return (atomic.AddUint64(&ext.counter, 1)-1)%ext.every == 0
+	}
+
+	return samplingRand() < ext.rate
+}
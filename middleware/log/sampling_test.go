@@ -0,0 +1,76 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/rookie-ninja/rk-logger"
+	"github.com/rookie-ninja/rk-query"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewSamplingExtension(t *testing.T) {
+	assert.Nil(t, NewSamplingExtension(nil))
+	assert.Nil(t, NewSamplingExtension(&SamplingConfig{}))
+	assert.NotNil(t, NewSamplingExtension(&SamplingConfig{Enabled: true}))
+}
+
+func TestSamplingExtension_Every(t *testing.T) {
+	ext := NewSamplingExtension(&SamplingConfig{Enabled: true, Every: 3})
+	event := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+
+	kept := 0
+	for i := 0; i < 9; i++ {
+		ctx := newCtx()
+		ctx.Status(http.StatusOK)
+		if ext.After(ctx, event) {
+			kept++
+		}
+	}
+	assert.Equal(t, 3, kept)
+}
+
+func TestSamplingExtension_Rate(t *testing.T) {
+	defer func() {
+		samplingRand = rand.Float64
+	}()
+
+	ext := NewSamplingExtension(&SamplingConfig{Enabled: true, Rate: 0.1})
+	event := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+
+	ctx := newCtx()
+	ctx.Status(http.StatusOK)
+
+	samplingRand = func() float64 { return 0.05 }
+	assert.True(t, ext.After(ctx, event))
+
+	samplingRand = func() float64 { return 0.5 }
+	assert.False(t, ext.After(ctx, event))
+}
+
+func TestSamplingExtension_AlwaysKept(t *testing.T) {
+	ext := NewSamplingExtension(&SamplingConfig{Enabled: true, Rate: 0, SlowMs: 100})
+	event := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+
+	// errors
+	for _, code := range []int{http.StatusNotFound, http.StatusInternalServerError, http.StatusFound} {
+		ctx := newCtx()
+		ctx.Status(code)
+		assert.True(t, ext.After(ctx, event))
+	}
+
+	// successful requests are dropped
+	ctx := newCtx()
+	ctx.Status(http.StatusOK)
+	assert.False(t, ext.After(ctx, event))
+
+	// slow requests
+	event.SetStartTime(time.Now().Add(-time.Second))
+	assert.True(t, ext.After(ctx, event))
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/context"
+	"github.com/rookie-ninja/rk-query"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// slowKey is the key of pair and counter added into event of slow request.
+	slowKey = "slow"
+)
+
+// slowExtension marks events of requests slower than threshold and logs them at WARN level.
+type slowExtension struct {
+	threshold time.Duration
+}
+
+// NewSlowExtension creates Extension which marks slow requests, nil is returned if thresholdMs is not positive.
+//
+// Event of slow request contains pair slow=true and counter slow, a WARN log is written with logger of request.
+func NewSlowExtension(thresholdMs int64) Extension {
+	if thresholdMs < 1 {
+		return nil
+	}
+
+	return &slowExtension{
+		threshold: time.Duration(thresholdMs) * time.Millisecond,
+	}
+}
+
+// Before does nothing.
+func (ext *slowExtension) Before(*gin.Context, rkquery.Event) {}
+
+// After marks event if request is slow, it always returns true.
+func (ext *slowExtension) After(ctx *gin.Context, event rkquery.Event) bool {
+	elapsed := time.Since(event.GetStartTime())
+	if elapsed < ext.threshold {
+		return true
+	}
+
+	event.AddPair(slowKey, "true")
+	event.IncCounter(slowKey, 1)
+
+	rkginctx.GetLogger(ctx).Warn("slow request",
+		zap.Bool(slowKey, true),
+		zap.String("method", ctx.Request.Method),
+		zap.String("path", ctx.Request.URL.Path),
+		zap.Int("resCode", ctx.Writer.Status()),
+		zap.Int64("elapsedMs", elapsed.Milliseconds()),
+		zap.Int64("thresholdMs", ext.threshold.Milliseconds()))
+
+	return true
+}
+
+// isSlow returns true if event is marked by slowExtension.
+func isSlow(event rkquery.Event) bool {
+	return event.GetValueFromPair(slowKey) == "true"
+}
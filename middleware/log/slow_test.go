@@ -0,0 +1,64 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-logger"
+	"github.com/rookie-ninja/rk-query"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewSlowExtension(t *testing.T) {
+	assert.Nil(t, NewSlowExtension(0))
+	assert.Nil(t, NewSlowExtension(-1))
+	assert.NotNil(t, NewSlowExtension(100))
+}
+
+func TestSlowExtension(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	ext := NewSlowExtension(100)
+
+	// fast request
+	event := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+	ctx := newCtx()
+	ctx.Set(rkmid.LoggerKey.String(), zap.New(core))
+	ctx.Status(http.StatusOK)
+	assert.True(t, ext.After(ctx, event))
+	assert.False(t, isSlow(event))
+	assert.Equal(t, 0, logs.Len())
+
+	// slow request
+	event.SetStartTime(time.Now().Add(-time.Second))
+	assert.True(t, ext.After(ctx, event))
+	assert.True(t, isSlow(event))
+	assert.Equal(t, int64(1), event.GetCounter(slowKey))
+
+	assert.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, zapcore.WarnLevel, entry.Level)
+	assert.Equal(t, true, entry.ContextMap()[slowKey])
+	assert.Equal(t, "/ut-path", entry.ContextMap()["path"])
+	assert.Equal(t, int64(100), entry.ContextMap()["thresholdMs"])
+}
+
+func TestSamplingExtension_SlowMarked(t *testing.T) {
+	ext := NewSamplingExtension(&SamplingConfig{Enabled: true})
+	event := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+
+	ctx := newCtx()
+	ctx.Status(http.StatusOK)
+	assert.False(t, ext.After(ctx, event))
+
+	event.AddPair(slowKey, "true")
+	assert.True(t, ext.After(ctx, event))
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-query"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// traceAwareEventKey is the key of deferred event in gin.Context.
+const traceAwareEventKey = "rkginlogTraceAwareEvent"
+
+// deferredEvent holds payloads added by detail extensions until sampling decision of trace is known.
+type deferredEvent struct {
+	rkquery.Event
+	payloads []zap.Field
+}
+
+// AddPayloads holds payloads instead of adding them into event.
+func (e *deferredEvent) AddPayloads(fields ...zap.Field) {
+	e.payloads = append(e.payloads, fields...)
+}
+
+// ListPayloads returns held payloads.
+func (e *deferredEvent) ListPayloads() []zap.Field {
+	return e.payloads
+}
+
+// traceAwareExtension records payloads of detail extensions only if trace of request is sampled.
+type traceAwareExtension struct {
+	extensions []Extension
+}
+
+// NewTraceAwareExtension creates Extension which wraps detail extensions like body and header,
+// payloads added by them are kept only if span of request is sampled, so minimal events are logged otherwise.
+//
+// Sampling decision is made by tracing middleware which should be enabled, nil is returned if no extension is passed.
+func NewTraceAwareExtension(extensions ...Extension) Extension {
+	ext := &traceAwareExtension{}
+	for i := range extensions {
+		if extensions[i] != nil {
+			ext.extensions = append(ext.extensions, extensions[i])
+		}
+	}
+
+	if len(ext.extensions) < 1 {
+		return nil
+	}
+
+	return ext
+}
+
+// Before calls detail extensions with deferred event since trace is not started yet.
+func (ext *traceAwareExtension) Before(ctx *gin.Context, event rkquery.Event) {
+	deferred := &deferredEvent{Event: event}
+	ctx.Set(traceAwareEventKey, deferred)
+
+	for i := range ext.extensions {
+		ext.extensions[i].Before(ctx, deferred)
+	}
+}
+
+// After adds held payloads and calls detail extensions with event if trace is sampled,
+// otherwise payloads are discarded.
+func (ext *traceAwareExtension) After(ctx *gin.Context, event rkquery.Event) bool {
+	deferred, ok := ctx.Value(traceAwareEventKey).(*deferredEvent)
+	if !ok {
+		deferred = &deferredEvent{Event: event}
+	}
+
+	var target rkquery.Event = deferred
+	if isTraceSampled(ctx) {
+		event.AddPayloads(deferred.payloads...)
+		target = event
+	}
+
+	keep := true
+	for i := range ext.extensions {
+		if !ext.extensions[i].After(ctx, target) {
+			keep = false
+		}
+	}
+
+	return keep
+}
+
+// isTraceSampled returns true if span in context of request is sampled.
+func isTraceSampled(ctx *gin.Context) bool {
+	if ctx.Request == nil {
+		return false
+	}
+
+	return trace.SpanContextFromContext(ctx.Request.Context()).IsSampled()
+}
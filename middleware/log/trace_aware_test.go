@@ -0,0 +1,61 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginlog
+
+import (
+	"github.com/rookie-ninja/rk-logger"
+	"github.com/rookie-ninja/rk-query"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func TestNewTraceAwareExtension(t *testing.T) {
+	assert.Nil(t, NewTraceAwareExtension())
+	assert.Nil(t, NewTraceAwareExtension(nil, nil))
+
+	ext := NewTraceAwareExtension(nil, NewHeaderExtension(&HeaderConfig{Enabled: true, Request: []string{"*"}}))
+	assert.Len(t, ext.(*traceAwareExtension).extensions, 1)
+}
+
+func TestTraceAwareExtension(t *testing.T) {
+	ext := NewTraceAwareExtension(NewHeaderExtension(&HeaderConfig{
+		Enabled:  true,
+		Request:  []string{"*"},
+		Response: []string{"*"},
+	}))
+
+	// trace not sampled
+	event := rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+	ctx := newCtx()
+	ctx.Request.Header.Set("X-Req", "req")
+	ext.Before(ctx, event)
+	ctx.Writer.Header().Set("X-Res", "res")
+	assert.True(t, ext.After(ctx, event))
+	assert.Empty(t, event.ListPayloads())
+
+	// trace sampled after Before, just like tracing middleware does
+	event = rkquery.NewEventFactory(rkquery.WithZapLogger(rklogger.NoopLogger)).CreateEvent()
+	ctx = newCtx()
+	ctx.Request.Header.Set("X-Req", "req")
+	ext.Before(ctx, event)
+	assert.Empty(t, event.ListPayloads())
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx.Request = ctx.Request.WithContext(trace.ContextWithSpanContext(ctx.Request.Context(), spanCtx))
+	ctx.Writer.Header().Set("X-Res", "res")
+	assert.True(t, ext.After(ctx, event))
+
+	keys := make([]string, 0)
+	for _, field := range event.ListPayloads() {
+		keys = append(keys, field.Key)
+	}
+	assert.Equal(t, []string{"reqHeaders", "resHeaders"}, keys)
+}
@@ -13,8 +13,32 @@ import (
 	"github.com/rookie-ninja/rk-gin/v2/middleware/context"
 )
 
+// Config config of meta middleware besides options of rkmidmeta.
+//
+// RequestIdHeader is name of response header with request id, which is X-Request-Id by default,
+// and the header is not written if DisableRequestIdHeader is true.
+type Config struct {
+	RequestIdHeader        string `yaml:"requestIdHeader" json:"requestIdHeader"`
+	DisableRequestIdHeader bool   `yaml:"disableRequestIdHeader" json:"disableRequestIdHeader"`
+}
+
 // Middleware will add common headers as extension style in http response.
 func Middleware(opts ...rkmidmeta.Option) gin.HandlerFunc {
+	return MiddlewareWithConfig(nil, opts...)
+}
+
+// MiddlewareWithConfig will add common headers as extension style in http response,
+// header of request id is renamed or removed as config.
+func MiddlewareWithConfig(config *Config, opts ...rkmidmeta.Option) gin.HandlerFunc {
+	if config == nil {
+		config = &Config{}
+	}
+
+	requestIdHeader := config.RequestIdHeader
+	if len(requestIdHeader) < 1 {
+		requestIdHeader = rkmid.HeaderRequestId
+	}
+
 	set := rkmidmeta.NewOptionSet(opts...)
 
 	return func(ctx *gin.Context) {
@@ -28,6 +52,12 @@ func Middleware(opts ...rkmidmeta.Option) gin.HandlerFunc {
 		}
 
 		for k, v := range beforeCtx.Output.HeadersToReturn {
+			if k == rkmid.HeaderRequestId {
+				if config.DisableRequestIdHeader {
+					continue
+				}
+				k = requestIdHeader
+			}
 			ctx.Header(k, v)
 		}
 
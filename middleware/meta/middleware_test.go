@@ -8,6 +8,7 @@ package rkginmeta
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
 	"github.com/rookie-ninja/rk-entry/v2/middleware/meta"
 	"github.com/stretchr/testify/assert"
 	"net/http"
@@ -37,6 +38,33 @@ func TestInterceptor(t *testing.T) {
 	assert.Equal(t, "value", ctx.Writer.Header().Get("key"))
 }
 
+func TestMiddlewareWithConfig(t *testing.T) {
+	beforeCtx := rkmidmeta.NewBeforeCtx()
+	mock := rkmidmeta.NewOptionSetMock(beforeCtx)
+	beforeCtx.Input.Event = rkentry.EventEntryNoop.EventFactory.CreateEventNoop()
+	beforeCtx.Output.RequestId = "ut-request-id"
+	beforeCtx.Output.HeadersToReturn[rkmid.HeaderRequestId] = "ut-request-id"
+	beforeCtx.Output.HeadersToReturn["key"] = "value"
+
+	// renamed
+	ctx := newCtx()
+	MiddlewareWithConfig(&Config{RequestIdHeader: "X-Correlation-Id"}, rkmidmeta.WithMockOptionSet(mock))(ctx)
+	assert.Equal(t, "ut-request-id", ctx.Writer.Header().Get("X-Correlation-Id"))
+	assert.Empty(t, ctx.Writer.Header().Get(rkmid.HeaderRequestId))
+	assert.Equal(t, "ut-request-id", ctx.GetString(rkmid.HeaderRequestId))
+
+	// disabled
+	ctx = newCtx()
+	MiddlewareWithConfig(&Config{DisableRequestIdHeader: true}, rkmidmeta.WithMockOptionSet(mock))(ctx)
+	assert.Empty(t, ctx.Writer.Header().Get(rkmid.HeaderRequestId))
+	assert.Equal(t, "value", ctx.Writer.Header().Get("key"))
+
+	// default
+	ctx = newCtx()
+	MiddlewareWithConfig(nil, rkmidmeta.WithMockOptionSet(mock))(ctx)
+	assert.Equal(t, "ut-request-id", ctx.Writer.Header().Get(rkmid.HeaderRequestId))
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.ReleaseMode)
 	os.Exit(m.Run())
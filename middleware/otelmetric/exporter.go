@@ -0,0 +1,165 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginotelmetric
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"google.golang.org/grpc/credentials"
+	"strings"
+	"time"
+)
+
+const (
+	// OtlpProtocolGrpc exports metrics with OTLP over gRPC, which is the default protocol.
+	OtlpProtocolGrpc = "grpc"
+	// OtlpProtocolHttp exports metrics with OTLP over HTTP with protobuf encoding.
+	OtlpProtocolHttp = "http"
+
+	defaultOtlpTimeoutMs  = 10000
+	defaultOtlpIntervalMs = 60000
+)
+
+// OtlpExporterConfig config of OTLP exporter of MeterProvider created by NewMeterProvider, which sends metrics
+// to OpenTelemetry collector periodically.
+//
+// Endpoint is host:port of collector, localhost:4317 for grpc and localhost:4318 for http by default,
+// UrlPath is used by http only, /v1/metrics by default.
+// Plain connection is used unless Tls is true or CertEntry is set, CertEntry references cert entry whose CA
+// verifies collector, and whose certificate is sent as client certificate if exists, system roots are used
+// if CertEntry is empty. Metrics are exported every IntervalMs, 60000 by default.
+type OtlpExporterConfig struct {
+	Enabled    bool              `yaml:"enabled" json:"enabled"`
+	Endpoint   string            `yaml:"endpoint" json:"endpoint"`
+	Protocol   string            `yaml:"protocol" json:"protocol"`
+	UrlPath    string            `yaml:"urlPath" json:"urlPath"`
+	Tls        bool              `yaml:"tls" json:"tls"`
+	CertEntry  string            `yaml:"certEntry" json:"certEntry"`
+	Headers    map[string]string `yaml:"headers" json:"headers"`
+	TimeoutMs  int               `yaml:"timeoutMs" json:"timeoutMs"`
+	IntervalMs int               `yaml:"intervalMs" json:"intervalMs"`
+}
+
+// NewMeterProvider creates SDK MeterProvider which exports metrics with OTLP exporter of config,
+// it should be provided with WithMeterProvider and shut down by caller, so that metrics left are exported.
+func NewMeterProvider(config *OtlpExporterConfig, entryName, entryType string) (*sdkmetric.MeterProvider, error) {
+	if config == nil {
+		config = &OtlpExporterConfig{}
+	}
+
+	exporter, err := CreateOtlpExporter(config)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(config.IntervalMs) * time.Millisecond
+	if config.IntervalMs < 1 {
+		interval = defaultOtlpIntervalMs * time.Millisecond
+	}
+
+	res := sdkresource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(rkentry.GlobalAppCtx.GetAppInfoEntry().AppName),
+		semconv.ServiceVersionKey.String(rkentry.GlobalAppCtx.GetAppInfoEntry().Version),
+		attribute.String("service.entryName", entryName),
+		attribute.String("service.entryType", entryType))
+
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval))),
+		sdkmetric.WithResource(res)), nil
+}
+
+// CreateOtlpExporter creates OTLP metric exporter with config, metrics could be sent to any OpenTelemetry collector.
+func CreateOtlpExporter(config *OtlpExporterConfig) (sdkmetric.Exporter, error) {
+	if config == nil {
+		config = &OtlpExporterConfig{}
+	}
+
+	timeout := time.Duration(config.TimeoutMs) * time.Millisecond
+	if config.TimeoutMs < 1 {
+		timeout = defaultOtlpTimeoutMs * time.Millisecond
+	}
+
+	var tlsConfig *tls.Config
+	if config.Tls || len(config.CertEntry) > 0 {
+		var err error
+		if tlsConfig, err = newOtlpTlsConfig(config.CertEntry); err != nil {
+			return nil, err
+		}
+	}
+
+	switch strings.ToLower(config.Protocol) {
+	case "", OtlpProtocolGrpc:
+		opts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithHeaders(config.Headers),
+			otlpmetricgrpc.WithTimeout(timeout),
+		}
+		if len(config.Endpoint) > 0 {
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(config.Endpoint))
+		}
+		if tlsConfig != nil {
+			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		} else {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+
+		return otlpmetricgrpc.New(context.Background(), opts...)
+	case OtlpProtocolHttp:
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithHeaders(config.Headers),
+			otlpmetrichttp.WithTimeout(timeout),
+		}
+		if len(config.Endpoint) > 0 {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(config.Endpoint))
+		}
+		if len(config.UrlPath) > 0 {
+			opts = append(opts, otlpmetrichttp.WithURLPath("/"+strings.TrimPrefix(config.UrlPath, "/")))
+		}
+		if tlsConfig != nil {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+		} else {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+
+		return otlpmetrichttp.New(context.Background(), opts...)
+	}
+
+	return nil, fmt.Errorf("unsupported protocol %s of otlp exporter, should be one of grpc or http", config.Protocol)
+}
+
+// newOtlpTlsConfig returns TLS config with CA and client certificate of cert entry, system roots are used if empty.
+func newOtlpTlsConfig(certEntryName string) (*tls.Config, error) {
+	res := &tls.Config{}
+	if len(certEntryName) < 1 {
+		return res, nil
+	}
+
+	certEntry := rkentry.GlobalAppCtx.GetCertEntry(certEntryName)
+	if certEntry == nil {
+		return nil, fmt.Errorf("cert entry %s of otlp exporter not found", certEntryName)
+	}
+
+	// certs are loaded only once, so it is safe to bootstrap before cert entry bootstrapped by application
+	certEntry.Bootstrap(context.Background())
+
+	if certEntry.RootCA != nil {
+		res.RootCAs = x509.NewCertPool()
+		res.RootCAs.AddCert(certEntry.RootCA)
+	}
+	if certEntry.Certificate != nil {
+		res.Certificates = []tls.Certificate{*certEntry.Certificate}
+	}
+
+	return res, nil
+}
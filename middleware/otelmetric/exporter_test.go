@@ -0,0 +1,98 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginotelmetric
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCreateOtlpExporter(t *testing.T) {
+	// grpc by default
+	exporter, err := CreateOtlpExporter(nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, exporter)
+	assert.Nil(t, exporter.Shutdown(context.TODO()))
+
+	// grpc with endpoint and TLS
+	exporter, err = CreateOtlpExporter(&OtlpExporterConfig{Protocol: "GRPC", Endpoint: "localhost:0", Tls: true})
+	assert.Nil(t, err)
+	assert.Nil(t, exporter.Shutdown(context.TODO()))
+
+	// http with TLS
+	exporter, err = CreateOtlpExporter(&OtlpExporterConfig{Protocol: OtlpProtocolHttp, Tls: true})
+	assert.Nil(t, err)
+	assert.Nil(t, exporter.Shutdown(context.TODO()))
+
+	// unsupported protocol
+	_, err = CreateOtlpExporter(&OtlpExporterConfig{Protocol: "thrift"})
+	assert.NotNil(t, err)
+
+	// cert entry not found
+	_, err = CreateOtlpExporter(&OtlpExporterConfig{CertEntry: "ut-not-exist"})
+	assert.NotNil(t, err)
+}
+
+func TestNewMeterProvider(t *testing.T) {
+	lock := sync.Mutex{}
+	var received *colmetricpb.ExportMetricsServiceRequest
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		assert.Equal(t, "/ut/metrics", r.URL.Path)
+		headers = r.Header
+		body, _ := io.ReadAll(r.Body)
+		received = &colmetricpb.ExportMetricsServiceRequest{}
+		assert.Nil(t, proto.Unmarshal(body, received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	provider, err := NewMeterProvider(&OtlpExporterConfig{
+		Protocol: OtlpProtocolHttp,
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		UrlPath:  "ut/metrics",
+		Headers:  map[string]string{"Authorization": "ut-token"},
+	}, "ut-entry", "ut-type")
+	assert.Nil(t, err)
+
+	handler, err := Middleware(WithEntryNameAndType("ut-entry", "ut-type"), WithMeterProvider(provider))
+	assert.Nil(t, err)
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+
+	// metrics left are exported while shutting down
+	assert.Nil(t, provider.Shutdown(context.TODO()))
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.NotNil(t, received)
+	assert.Equal(t, "ut-token", headers.Get("Authorization"))
+	names := make([]string, 0)
+	for _, metric := range received.GetResourceMetrics()[0].GetScopeMetrics()[0].GetMetrics() {
+		names = append(names, metric.GetName())
+	}
+	assert.Contains(t, names, MetricsNameDuration)
+
+	// unsupported protocol
+	_, err = NewMeterProvider(&OtlpExporterConfig{Protocol: "thrift"}, "ut-entry", "ut-type")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+// Package rkginotelmetric is a middleware for gin framework which records RPC metrics with OpenTelemetry metrics API.
+//
+// Metrics are recorded with MeterProvider, which should be an SDK MeterProvider with exporter like OTLP
+// set with otel.SetMeterProvider() or WithMeterProvider(), metrics are dropped by global noop MeterProvider otherwise.
+package rkginotelmetric
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"time"
+)
+
+// routeUnmatched is value of http.route of requests which matched no route.
+const routeUnmatched = "unmatched"
+
+// Middleware create a new metrics middleware with options.
+//
+// Elapsed milliseconds are recorded in http.server.duration histogram and requests in flight are recorded
+// in http.server.active_requests counter, with entryName, entryType, http.method, http.route and http.status_code.
+func Middleware(opts ...Option) (gin.HandlerFunc, error) {
+	set := newOptionSet(opts...)
+
+	meter := set.meterProvider.Meter(instrumentationName)
+	duration, err := meter.Float64Histogram(MetricsNameDuration,
+		metric.WithUnit("ms"),
+		metric.WithDescription("Duration of HTTP server requests"))
+	if err != nil {
+		return nil, err
+	}
+	activeRequests, err := meter.Int64UpDownCounter(MetricsNameActiveRequests,
+		metric.WithUnit("{request}"),
+		metric.WithDescription("Number of active HTTP server requests"))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx *gin.Context) {
+		ctx.Set(rkmid.EntryNameKey.String(), set.entryName)
+
+		if set.ShouldIgnore(ctx.Request.URL.Path) {
+			ctx.Next()
+			return
+		}
+
+		attrs := []attribute.KeyValue{
+			attribute.String("entryName", set.entryName),
+			attribute.String("entryType", set.entryType),
+			attribute.String("http.method", ctx.Request.Method),
+			attribute.String("http.route", route(ctx)),
+		}
+		reqCtx := ctx.Request.Context()
+
+		activeRequests.Add(reqCtx, 1, metric.WithAttributes(attrs...))
+		startTime := time.Now()
+
+		ctx.Next()
+
+		activeRequests.Add(reqCtx, -1, metric.WithAttributes(attrs...))
+		duration.Record(ctx.Request.Context(), float64(time.Since(startTime))/float64(time.Millisecond),
+			metric.WithAttributes(append(attrs, attribute.Int("http.status_code", ctx.Writer.Status()))...))
+	}, nil
+}
+
+// route returns route template like /v1/user/:id matched by request, routeUnmatched if no route matched.
+func route(ctx *gin.Context) string {
+	if fullPath := ctx.FullPath(); len(fullPath) > 0 {
+		return fullPath
+	}
+
+	return routeUnmatched
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginotelmetric
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+)
+
+// fakeMeterProvider records measurements of instruments created by middleware.
+type fakeMeterProvider struct {
+	noop.MeterProvider
+	meter *fakeMeter
+}
+
+func (p *fakeMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return p.meter
+}
+
+type fakeMeter struct {
+	noop.Meter
+	err       error
+	lock      sync.Mutex
+	durations []attribute.Set
+	active    map[string]int64
+}
+
+func (m *fakeMeter) Float64Histogram(string, ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return &fakeHistogram{meter: m}, m.err
+}
+
+func (m *fakeMeter) Int64UpDownCounter(string, ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	return &fakeUpDownCounter{meter: m}, nil
+}
+
+type fakeHistogram struct {
+	noop.Float64Histogram
+	meter *fakeMeter
+}
+
+func (h *fakeHistogram) Record(_ context.Context, _ float64, opts ...metric.RecordOption) {
+	h.meter.lock.Lock()
+	defer h.meter.lock.Unlock()
+	h.meter.durations = append(h.meter.durations, metric.NewRecordConfig(opts).Attributes())
+}
+
+type fakeUpDownCounter struct {
+	noop.Int64UpDownCounter
+	meter *fakeMeter
+}
+
+func (c *fakeUpDownCounter) Add(_ context.Context, incr int64, opts ...metric.AddOption) {
+	c.meter.lock.Lock()
+	defer c.meter.lock.Unlock()
+	attrs := metric.NewAddConfig(opts).Attributes()
+	route, _ := attrs.Value("http.route")
+	c.meter.active[route.AsString()] += incr
+}
+
+func TestMiddleware(t *testing.T) {
+	provider := &fakeMeterProvider{meter: &fakeMeter{active: map[string]int64{}}}
+	handler, err := Middleware(
+		WithEntryNameAndType("ut-entry", "ut-type"),
+		WithMeterProvider(provider),
+		WithPathToIgnore("/ut-ignore"))
+	assert.Nil(t, err)
+
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/ut-user/:id", func(ctx *gin.Context) {
+		assert.Equal(t, int64(1), provider.meter.active["/ut-user/:id"])
+		ctx.Status(http.StatusAccepted)
+	})
+	router.GET("/ut-ignore", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/ut-user/1", "/ut-missing", "/ut-ignore"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Len(t, provider.meter.durations, 2)
+	assert.Equal(t, attribute.NewSet(
+		attribute.String("entryName", "ut-entry"),
+		attribute.String("entryType", "ut-type"),
+		attribute.String("http.method", http.MethodGet),
+		attribute.String("http.route", "/ut-user/:id"),
+		attribute.Int("http.status_code", http.StatusAccepted)), provider.meter.durations[0])
+	route, _ := provider.meter.durations[1].Value("http.route")
+	assert.Equal(t, routeUnmatched, route.AsString())
+	assert.Equal(t, map[string]int64{"/ut-user/:id": 0, routeUnmatched: 0}, provider.meter.active)
+
+	// failed to create instrument
+	provider.meter.err = errors.New("ut-error")
+	handler, err = Middleware(WithMeterProvider(provider))
+	assert.NotNil(t, err)
+	assert.Nil(t, handler)
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.ReleaseMode)
+	os.Exit(m.Run())
+}
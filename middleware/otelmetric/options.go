@@ -0,0 +1,95 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginotelmetric
+
+import (
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"strings"
+)
+
+const (
+	// instrumentationName is name of meter which creates instruments of middleware.
+	instrumentationName = "github.com/rookie-ninja/rk-gin/v2/middleware/otelmetric"
+	// MetricsNameDuration is name of histogram which records elapsed milliseconds of requests.
+	MetricsNameDuration = "http.server.duration"
+	// MetricsNameActiveRequests is name of counter which records number of requests in flight.
+	MetricsNameActiveRequests = "http.server.active_requests"
+)
+
+// optionSet which is used for middleware implementation
+type optionSet struct {
+	entryName     string
+	entryType     string
+	meterProvider metric.MeterProvider
+	pathToIgnore  []string
+}
+
+// newOptionSet creates new optionSet with options, global MeterProvider of otel is used by default.
+func newOptionSet(opts ...Option) *optionSet {
+	set := &optionSet{
+		entryName:    "fake-entry",
+		pathToIgnore: make([]string, 0),
+	}
+
+	for i := range opts {
+		opts[i](set)
+	}
+
+	if set.meterProvider == nil {
+		set.meterProvider = otel.GetMeterProvider()
+	}
+
+	return set
+}
+
+// ShouldIgnore determine whether metrics should be ignored based on path
+func (set *optionSet) ShouldIgnore(path string) bool {
+	for i := range set.pathToIgnore {
+		if strings.HasPrefix(path, set.pathToIgnore[i]) {
+			return true
+		}
+	}
+
+	return rkmid.ShouldIgnoreGlobal(path)
+}
+
+// Option options provided to middleware while creating
+type Option func(*optionSet)
+
+// WithEntryNameAndType provide entry name and entry type.
+func WithEntryNameAndType(entryName, entryType string) Option {
+	return func(set *optionSet) {
+		if len(entryName) > 0 {
+			set.entryName = entryName
+		}
+
+		if len(entryType) > 0 {
+			set.entryType = entryType
+		}
+	}
+}
+
+// WithMeterProvider provide metric.MeterProvider, global MeterProvider of otel is used if not provided.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(set *optionSet) {
+		if provider != nil {
+			set.meterProvider = provider
+		}
+	}
+}
+
+// WithPathToIgnore provide paths prefix that will ignore.
+func WithPathToIgnore(paths ...string) Option {
+	return func(set *optionSet) {
+		for i := range paths {
+			if len(paths[i]) > 0 {
+				set.pathToIgnore = append(set.pathToIgnore, paths[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginotelmetric
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric/noop"
+	"testing"
+)
+
+func TestNewOptionSet(t *testing.T) {
+	// default
+	set := newOptionSet()
+	assert.Equal(t, "fake-entry", set.entryName)
+	assert.Equal(t, otel.GetMeterProvider(), set.meterProvider)
+	assert.Empty(t, set.pathToIgnore)
+
+	// with options
+	provider := noop.NewMeterProvider()
+	set = newOptionSet(
+		WithEntryNameAndType("ut-entry", "ut-type"),
+		WithMeterProvider(provider),
+		WithPathToIgnore("", "/ut-ignore"))
+	assert.Equal(t, "ut-entry", set.entryName)
+	assert.Equal(t, "ut-type", set.entryType)
+	assert.Equal(t, provider, set.meterProvider)
+	assert.Equal(t, []string{"/ut-ignore"}, set.pathToIgnore)
+	assert.True(t, set.ShouldIgnore("/ut-ignore/path"))
+	assert.False(t, set.ShouldIgnore("/ut-path"))
+}
@@ -13,18 +13,101 @@ import (
 	"github.com/rookie-ninja/rk-entry/v2/middleware/panic"
 	"github.com/rookie-ninja/rk-gin/v2/middleware/context"
 	"net/http"
+	"strings"
 )
 
+const (
+	// FormatJson writes error recovered from panic as JSON of rkerror, which is the default format.
+	FormatJson = "json"
+	// FormatProblem writes error recovered from panic as problem details of RFC 7807.
+	FormatProblem = "problem"
+
+	// MIMEProblemJSON is content type of problem details of RFC 7807.
+	MIMEProblemJSON = "application/problem+json"
+)
+
+// Problem is problem details of RFC 7807.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// RecoveryHandler handles error recovered from panic instead of writing it as JSON with status 500,
+// like translating it into error of domain or triggering alerts. Panic is logged and recorded into event
+// before handler is called, and error is written with status 500 if handler writes nothing.
+type RecoveryHandler func(ctx *gin.Context, err rkerror.ErrorInterface)
+
+// Config config of panic middleware besides options of rkmidpanic.
+//
+// Format is preferred format of response which is json or problem, json by default. The other format is written
+// if Accept header of request accepts it but not the preferred one.
+type Config struct {
+	Format          string          `yaml:"format" json:"format"`
+	RecoveryHandler RecoveryHandler `yaml:"-" json:"-"`
+}
+
+// ConfigOption sets field of Config.
+type ConfigOption func(*Config)
+
+// WithFormat provide preferred format of response, one of json or problem.
+func WithFormat(format string) ConfigOption {
+	return func(config *Config) {
+		config.Format = format
+	}
+}
+
+// WithRecoveryHandler provide RecoveryHandler which handles error recovered from panic.
+//
+//	rkginpanic.MiddlewareWithConfig(rkginpanic.NewConfig(rkginpanic.WithRecoveryHandler(handler)))
+func WithRecoveryHandler(handler RecoveryHandler) ConfigOption {
+	return func(config *Config) {
+		config.RecoveryHandler = handler
+	}
+}
+
+// NewConfig creates Config with options.
+func NewConfig(opts ...ConfigOption) *Config {
+	config := &Config{}
+	for i := range opts {
+		opts[i](config)
+	}
+
+	return config
+}
+
 // Middleware returns a gin.HandlerFunc (middleware)
 func Middleware(opts ...rkmidpanic.Option) gin.HandlerFunc {
+	return MiddlewareWithConfig(nil, opts...)
+}
+
+// MiddlewareWithConfig returns a gin.HandlerFunc (middleware), error recovered from panic is handled by
+// RecoveryHandler of config if provided.
+func MiddlewareWithConfig(config *Config, opts ...rkmidpanic.Option) gin.HandlerFunc {
+	if config == nil {
+		config = &Config{}
+	}
+
+	offered := []string{gin.MIMEJSON, MIMEProblemJSON}
+	if strings.EqualFold(config.Format, FormatProblem) {
+		offered = []string{MIMEProblemJSON, gin.MIMEJSON}
+	}
+
 	set := rkmidpanic.NewOptionSet(opts...)
 
 	return func(ctx *gin.Context) {
 		ctx.Set(rkmid.EntryNameKey.String(), set.GetEntryName())
 
 		handlerFunc := func(resp rkerror.ErrorInterface) {
+			if config.RecoveryHandler != nil {
+				ctx.Abort()
+				config.RecoveryHandler(ctx, resp)
+			}
+
 			if ctx.Writer.Size() < 1 {
-				ctx.AbortWithStatusJSON(http.StatusInternalServerError, resp)
+				writeError(ctx, offered, resp)
 			}
 		}
 		beforeCtx := set.BeforeCtx(rkginctx.GetEvent(ctx), rkginctx.GetLogger(ctx), handlerFunc)
@@ -35,3 +118,27 @@ func Middleware(opts ...rkmidpanic.Option) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// writeError writes error with status 500 in format negotiated with Accept header of request,
+// the first one of offered is used if request accepts none of them.
+func writeError(ctx *gin.Context, offered []string, resp rkerror.ErrorInterface) {
+	format := ctx.NegotiateFormat(offered...)
+	if len(format) < 1 {
+		format = offered[0]
+	}
+
+	if format != MIMEProblemJSON {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, resp)
+		return
+	}
+
+	// content type is not overridden by gin if set already
+	ctx.Header("Content-Type", MIMEProblemJSON)
+	ctx.AbortWithStatusJSON(http.StatusInternalServerError, &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(http.StatusInternalServerError),
+		Status:   http.StatusInternalServerError,
+		Detail:   resp.Message(),
+		Instance: ctx.Request.URL.Path,
+	})
+}
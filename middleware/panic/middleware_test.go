@@ -8,6 +8,7 @@ package rkginpanic
 import (
 	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/error"
 	"github.com/rookie-ninja/rk-entry/v2/middleware/panic"
 	"github.com/stretchr/testify/assert"
 	"net/http"
@@ -30,6 +31,92 @@ func TestInterceptor(t *testing.T) {
 	router.HandleContext(ctx)
 }
 
+func TestMiddlewareWithConfig(t *testing.T) {
+	serve := func(handler RecoveryHandler) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(MiddlewareWithConfig(NewConfig(WithRecoveryHandler(handler)),
+			rkmidpanic.WithEntryNameAndType("ut-entry", "ut-type")))
+		router.GET("/ut", func(ctx *gin.Context) {
+			panic(errors.New("ut panic"))
+		}, func(ctx *gin.Context) {
+			assert.Fail(t, "handler after panic should not be called")
+		})
+
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ut", nil))
+		return resp
+	}
+
+	// domain error
+	var recovered rkerror.ErrorInterface
+	resp := serve(func(ctx *gin.Context, err rkerror.ErrorInterface) {
+		recovered = err
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"reason": "ut-domain-error"})
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.JSONEq(t, `{"reason":"ut-domain-error"}`, resp.Body.String())
+	assert.Equal(t, http.StatusInternalServerError, recovered.Code())
+	assert.Contains(t, recovered.Error(), "ut panic")
+
+	// fallback to default response if handler writes nothing
+	resp = serve(func(ctx *gin.Context, err rkerror.ErrorInterface) {})
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Panic occurs")
+
+	// default response
+	resp = serve(nil)
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Panic occurs")
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+	assert.Empty(t, config.Format)
+	assert.Nil(t, config.RecoveryHandler)
+
+	config = NewConfig(
+		WithFormat(FormatProblem),
+		WithRecoveryHandler(func(*gin.Context, rkerror.ErrorInterface) {}))
+	assert.Equal(t, FormatProblem, config.Format)
+	assert.NotNil(t, config.RecoveryHandler)
+}
+
+func TestMiddlewareWithConfig_Format(t *testing.T) {
+	serve := func(format, accept string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(MiddlewareWithConfig(&Config{Format: format}))
+		router.GET("/ut", func(ctx *gin.Context) {
+			panic("ut panic")
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/ut", nil)
+		req.Header.Set("Accept", accept)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		return resp
+	}
+
+	isProblem := func(resp *httptest.ResponseRecorder) bool {
+		return resp.Header().Get("Content-Type") == MIMEProblemJSON
+	}
+
+	// json by default
+	assert.False(t, isProblem(serve("", "")))
+	assert.False(t, isProblem(serve("", "*/*")))
+	assert.False(t, isProblem(serve("", "text/html")))
+	assert.True(t, isProblem(serve("", "application/problem+json")))
+	assert.False(t, isProblem(serve("", "application/json, application/problem+json")))
+
+	// problem preferred
+	resp := serve("Problem", "")
+	assert.True(t, isProblem(resp))
+	assert.JSONEq(t, `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"Panic occurs","instance":"/ut"}`,
+		resp.Body.String())
+	assert.True(t, isProblem(serve(FormatProblem, "*/*")))
+	assert.False(t, isProblem(serve(FormatProblem, "application/json")))
+}
+
 func assertNotPanic(t *testing.T) {
 	if r := recover(); r != nil {
 		// Expect panic to be called with non nil error
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"strconv"
+	"sync"
+)
+
+// routeKey identifies requests which share labels of metrics.
+type routeKey struct {
+	method   string
+	restPath string
+	resCode  int
+	tenant   string
+}
+
+// routeMetrics are children of metrics vectors labeled with values of a routeKey, nil if metrics is not enabled.
+type routeMetrics struct {
+	elapsed       prometheus.Observer
+	resCode       prometheus.Counter
+	histogram     prometheus.Observer
+	resCodeClass  prometheus.Counter
+	tenantElapsed prometheus.Observer
+	tenantResCode prometheus.Counter
+}
+
+// routeMetricsCache caches children of metrics vectors per routeKey.
+//
+// Static labels like entryName and domain are resolved once, so label values are neither built nor hashed,
+// and lock of MetricsSet is not acquired for each request. Size of cache is bounded by cardinality of metrics,
+// since restPath is route template.
+type routeMetricsCache struct {
+	staticValues []string
+	// metricsSet owned by entry, nil if summary and counter are recorded by rkmidprom
+	metricsSet   *rkmidprom.MetricsSet
+	hist         *histogram
+	classCounter *statusClassCounter
+	tenant       *tenant
+	routes       sync.Map
+}
+
+// newRouteMetricsCache creates routeMetricsCache of entry.
+func newRouteMetricsCache(set rkmidprom.OptionSetInterface, metricsSet *rkmidprom.MetricsSet,
+	hist *histogram, classCounter *statusClassCounter, tenant *tenant) *routeMetricsCache {
+	return &routeMetricsCache{
+		staticValues: []string{
+			set.GetEntryName(),
+			set.GetEntryType(),
+			rkmid.Domain.String,
+			rkmid.LocalHostname.String,
+		},
+		metricsSet:   metricsSet,
+		hist:         hist,
+		classCounter: classCounter,
+		tenant:       tenant,
+	}
+}
+
+// get returns cached routeMetrics of request, children of metrics vectors are looked up at the first call.
+//
+// Tenant should be empty if tenant metrics is not enabled.
+func (c *routeMetricsCache) get(method, restPath string, resCode int, tenant string) *routeMetrics {
+	key := routeKey{method: method, restPath: restPath, resCode: resCode, tenant: tenant}
+	if res, ok := c.routes.Load(key); ok {
+		return res.(*routeMetrics)
+	}
+
+	values := make([]string, 0, len(c.staticValues)+4)
+	values = append(values, c.staticValues...)
+	values = append(values, method, restPath, strconv.Itoa(resCode))
+
+	res := &routeMetrics{}
+	if c.metricsSet != nil {
+		res.elapsed = c.metricsSet.GetSummaryWithValues(rkmidprom.MetricsNameElapsedNano, values...)
+		res.resCode = c.metricsSet.GetCounterWithValues(rkmidprom.MetricsNameResCode, values...)
+	}
+	if c.hist != nil {
+		res.histogram = c.hist.metricsSet.GetHistogramWithValues(c.hist.name, values...)
+	}
+	if c.tenant != nil {
+		tenantValues := append(values, tenant)
+		res.tenantElapsed = c.tenant.metricsSet.GetSummaryWithValues(MetricsNameTenantElapsedNano, tenantValues...)
+		res.tenantResCode = c.tenant.metricsSet.GetCounterWithValues(MetricsNameTenantResCode, tenantValues...)
+	}
+	if c.classCounter != nil {
+		values[len(values)-1] = resCodeClass(resCode)
+		res.resCodeClass = c.classCounter.metricsSet.GetCounterWithValues(MetricsNameResCodeClass, values...)
+	}
+
+	actual, _ := c.routes.LoadOrStore(key, res)
+	return actual.(*routeMetrics)
+}
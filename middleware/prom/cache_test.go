@@ -0,0 +1,68 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestRouteMetricsCache_Get(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	set := rkmidprom.NewOptionSet(rkmidprom.WithEntryNameAndType("ut-cache", "ut-type"),
+		rkmidprom.WithRegisterer(prometheus.NewRegistry()))
+
+	// nothing enabled
+	cache := newRouteMetricsCache(set, nil, nil, nil, nil)
+	assert.Equal(t, &routeMetrics{}, cache.get(http.MethodGet, "/ut", http.StatusOK, ""))
+
+	// all enabled
+	reg := prometheus.NewRegistry()
+	metricsSet, err := NewMetricsSet(reg)
+	assert.Nil(t, err)
+	defer Unregister(metricsSet)
+	hist, err := newHistogram(&HistogramConfig{Enabled: true}, metricsSet)
+	assert.Nil(t, err)
+	classCounter, err := newStatusClassCounter(metricsSet)
+	assert.Nil(t, err)
+
+	cache = newRouteMetricsCache(set, metricsSet, hist, classCounter, nil)
+	metrics := cache.get(http.MethodGet, "/ut", http.StatusNotFound, "")
+	assert.NotNil(t, metrics.elapsed)
+	assert.NotNil(t, metrics.resCode)
+	assert.NotNil(t, metrics.histogram)
+	assert.NotNil(t, metrics.resCodeClass)
+
+	// cached
+	assert.True(t, metrics == cache.get(http.MethodGet, "/ut", http.StatusNotFound, ""))
+	assert.False(t, metrics == cache.get(http.MethodGet, "/ut", http.StatusOK, ""))
+
+	// labels of children
+	metrics.resCode.Inc()
+	metrics.resCodeClass.Inc()
+	for name, counter := range map[string]prometheus.Counter{"resCode": metrics.resCode, "resCodeClass": metrics.resCodeClass} {
+		raw := &dto.Metric{}
+		assert.Nil(t, counter.Write(raw))
+		labels := map[string]string{}
+		for _, label := range raw.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		assert.Equal(t, "ut-cache", labels["entryName"])
+		assert.Equal(t, "ut-type", labels["entryType"])
+		assert.Equal(t, "/ut", labels["restPath"])
+		assert.Equal(t, float64(1), raw.GetCounter().GetValue())
+		if name == "resCode" {
+			assert.Equal(t, "404", labels["resCode"])
+		} else {
+			assert.Equal(t, "4xx", labels["resCodeClass"])
+		}
+	}
+}
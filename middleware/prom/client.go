@@ -0,0 +1,107 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// MetricsNameClientElapsedNano is name of summary observing elapsed nanoseconds of outgoing requests.
+	MetricsNameClientElapsedNano = "clientElapsedNano"
+	// MetricsNameClientResCode is name of counter counting outgoing requests by response code.
+	MetricsNameClientResCode = "clientResCode"
+
+	// ClientResCodeError is value of resCode label of outgoing requests failed without response.
+	ClientResCodeError = "error"
+)
+
+// labelKeysClient are labels of client metrics, remoteHost is used instead of path to keep cardinality bounded.
+var labelKeysClient = []string{
+	"entryName",
+	"entryType",
+	"domain",
+	"instance",
+	"restMethod",
+	"remoteHost",
+	"resCode",
+}
+
+// roundTripper records elapsed time and response code of outgoing requests.
+type roundTripper struct {
+	metricsSet *rkmidprom.MetricsSet
+	entryName  string
+	entryType  string
+	next       http.RoundTripper
+}
+
+// NewRoundTripper wraps next with http.RoundTripper which records elapsed time and response code of outgoing requests
+// into metricsSet of entry, so latency of dependencies shows up next to latency of server.
+//
+// http.DefaultTransport is used if next is nil, requests are not recorded if metricsSet is nil.
+func NewRoundTripper(metricsSet *rkmidprom.MetricsSet, entryName, entryType string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &roundTripper{
+		metricsSet: metricsSet,
+		entryName:  entryName,
+		entryType:  entryType,
+		next:       next,
+	}
+}
+
+// RoundTrip sends request with next http.RoundTripper and records it.
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	startTime := time.Now()
+	resp, err := rt.next.RoundTrip(req)
+
+	resCode := ClientResCodeError
+	if err == nil && resp != nil {
+		resCode = strconv.Itoa(resp.StatusCode)
+	}
+	rt.observe(req, resCode, time.Since(startTime))
+
+	return resp, err
+}
+
+// observe records request into metrics set of entry, client metrics are registered at the first call.
+func (rt *roundTripper) observe(req *http.Request, resCode string, elapsed time.Duration) {
+	metricsSet := rt.metricsSet
+	if metricsSet == nil {
+		return
+	}
+
+	if metricsSet.GetSummary(MetricsNameClientElapsedNano) == nil {
+		// summary may be registered by another request concurrently
+		metricsSet.RegisterSummary(MetricsNameClientElapsedNano, rkmidprom.SummaryObjectives, labelKeysClient...)
+	}
+	if metricsSet.GetCounter(MetricsNameClientResCode) == nil {
+		metricsSet.RegisterCounter(MetricsNameClientResCode, labelKeysClient...)
+	}
+
+	values := []string{
+		rt.entryName,
+		rt.entryType,
+		rkmid.Domain.String,
+		rkmid.LocalHostname.String,
+		req.Method,
+		req.URL.Host,
+		resCode,
+	}
+
+	if observer := metricsSet.GetSummaryWithValues(MetricsNameClientElapsedNano, values...); observer != nil {
+		observer.Observe(float64(elapsed.Nanoseconds()))
+	}
+	if counter := metricsSet.GetCounterWithValues(MetricsNameClientResCode, values...); counter != nil {
+		counter.Inc()
+	}
+}
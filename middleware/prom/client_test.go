@@ -0,0 +1,89 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// roundTripperFunc implements http.RoundTripper with function.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewRoundTripper(t *testing.T) {
+	rt := NewRoundTripper(nil, "ut-client", "ut-type", nil)
+	assert.Equal(t, http.DefaultTransport, rt.(*roundTripper).next)
+}
+
+func TestRoundTripper_RoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	// metrics set not exist
+	client := &http.Client{Transport: NewRoundTripper(nil, "ut-client", "ut-type", nil)}
+	resp, err := client.Get(server.URL)
+	assert.Nil(t, err)
+	assert.Nil(t, resp.Body.Close())
+
+	reg := prometheus.NewRegistry()
+	metricsSet, err := NewMetricsSet(reg)
+	assert.Nil(t, err)
+	defer Unregister(metricsSet)
+	client = &http.Client{Transport: NewRoundTripper(metricsSet, "ut-client", "ut-type", nil)}
+
+	// recorded
+	for i := 0; i < 2; i++ {
+		resp, err = client.Get(server.URL)
+		assert.Nil(t, err)
+		assert.Nil(t, resp.Body.Close())
+	}
+
+	// failed without response
+	failed := NewRoundTripper(metricsSet, "ut-client", "ut-type", roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("ut-error")
+	}))
+	_, err = failed.RoundTrip(&http.Request{Method: http.MethodPost, URL: &url.URL{Host: "ut-host"}})
+	assert.NotNil(t, err)
+
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+	counts := map[string]float64{}
+	summaries := 0
+	for _, family := range families {
+		switch family.GetName() {
+		case "rk_prom_" + MetricsNameClientResCode:
+			for _, metric := range family.GetMetric() {
+				labels := map[string]string{}
+				for _, label := range metric.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				assert.Equal(t, "ut-client", labels["entryName"])
+				counts[labels["restMethod"]+" "+labels["remoteHost"]+" "+labels["resCode"]] = metric.GetCounter().GetValue()
+			}
+		case "rk_prom_" + MetricsNameClientElapsedNano:
+			summaries = len(family.GetMetric())
+		}
+	}
+
+	assert.Equal(t, map[string]float64{
+		"GET " + host + " 418":               2,
+		"POST ut-host " + ClientResCodeError: 1,
+	}, counts)
+	assert.Equal(t, 2, summaries)
+}
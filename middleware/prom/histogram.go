@@ -0,0 +1,148 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// HistogramUnitMs observes elapsed time in milliseconds.
+	HistogramUnitMs = "ms"
+	// HistogramUnitNs observes elapsed time in nanoseconds.
+	HistogramUnitNs = "ns"
+
+	// MetricsNameElapsedMsHistogram is name of histogram observing elapsed milliseconds.
+	MetricsNameElapsedMsHistogram = "elapsedMsHistogram"
+	// MetricsNameElapsedNanoHistogram is name of histogram observing elapsed nanoseconds.
+	MetricsNameElapsedNanoHistogram = "elapsedNanoHistogram"
+
+	// exemplarKeyRawPath is label of exemplar which keeps raw path of request.
+	exemplarKeyRawPath = "rawPath"
+	// exemplarKeyTraceId is label of exemplar which keeps trace id, same as the one expected by Grafana.
+	exemplarKeyTraceId = "trace_id"
+)
+
+var (
+	// DefaultHistogramBucketsMs are buckets of histogram in milliseconds if not configured.
+	DefaultHistogramBucketsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+)
+
+// labelKeysHistogram are labels of histogram, same as labels of summary registered by rkmidprom.
+var labelKeysHistogram = []string{
+	"entryName",
+	"entryType",
+	"domain",
+	"instance",
+	"restMethod",
+	"restPath",
+	"resCode",
+}
+
+// HistogramConfig config of histogram which observes elapsed time of requests along with summary.
+//
+// Histograms could be aggregated across instances, unlike summaries.
+// Unit is ms or ns, ms by default, Buckets are upper bounds in unit, DefaultHistogramBucketsMs is used if empty,
+// nanosecond buckets are converted from it if unit is ns.
+//
+// Observations are labeled with route template, trace id of sampled span is attached as exemplar if tracing middleware
+// is enabled, and raw path of request as well if RawPathExemplar is true,
+// exemplars are exposed only if OpenMetrics format is requested by scraper.
+type HistogramConfig struct {
+	Enabled         bool      `yaml:"enabled" json:"enabled"`
+	Unit            string    `yaml:"unit" json:"unit"`
+	Buckets         []float64 `yaml:"buckets" json:"buckets"`
+	RawPathExemplar bool      `yaml:"rawPathExemplar" json:"rawPathExemplar"`
+}
+
+// histogram observes elapsed time of requests.
+type histogram struct {
+	name            string
+	unit            time.Duration
+	rawPathExemplar bool
+	metricsSet      *rkmidprom.MetricsSet
+}
+
+// newHistogram registers histogram into metrics set of entry, nil is returned if config is not enabled.
+//
+// Histogram registered before with same name is reused, so buckets are not changed after registered.
+func newHistogram(config *HistogramConfig, metricsSet *rkmidprom.MetricsSet) (*histogram, error) {
+	if config == nil || !config.Enabled || metricsSet == nil {
+		return nil, nil
+	}
+
+	res := &histogram{metricsSet: metricsSet, rawPathExemplar: config.RawPathExemplar}
+	buckets := config.Buckets
+
+	switch strings.ToLower(config.Unit) {
+	case "", HistogramUnitMs:
+		res.name, res.unit = MetricsNameElapsedMsHistogram, time.Millisecond
+		if len(buckets) < 1 {
+			buckets = DefaultHistogramBucketsMs
+		}
+	case HistogramUnitNs:
+		res.name, res.unit = MetricsNameElapsedNanoHistogram, time.Nanosecond
+		if len(buckets) < 1 {
+			buckets = make([]float64, 0, len(DefaultHistogramBucketsMs))
+			for _, v := range DefaultHistogramBucketsMs {
+				buckets = append(buckets, v*float64(time.Millisecond))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported unit %s of histogram, should be one of ms or ns", config.Unit)
+	}
+
+	if metricsSet.GetHistogram(res.name) == nil {
+		if err := metricsSet.RegisterHistogram(res.name, buckets, labelKeysHistogram...); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// observe records elapsed time of request with observer of route,
+// trace id of sampled span and raw path if enabled are attached as exemplar.
+func (h *histogram) observe(observer prometheus.Observer, ctx *gin.Context, elapsed time.Duration) {
+	value := float64(elapsed) / float64(h.unit)
+	if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+		if exemplar := h.exemplar(ctx); len(exemplar) > 0 {
+			exemplarObserver.ObserveWithExemplar(value, exemplar)
+			return
+		}
+	}
+
+	observer.Observe(value)
+}
+
+// exemplar returns exemplar labels of request, trace id is set if span started by tracing middleware is sampled,
+// raw path is set if enabled and truncated to limit of exemplar, it is skipped if not valid UTF-8.
+func (h *histogram) exemplar(ctx *gin.Context) prometheus.Labels {
+	res := prometheus.Labels{}
+	remaining := prometheus.ExemplarMaxRunes
+
+	if spanCtx := trace.SpanContextFromContext(ctx.Request.Context()); spanCtx.IsSampled() {
+		res[exemplarKeyTraceId] = spanCtx.TraceID().String()
+		remaining -= len(exemplarKeyTraceId) + len(res[exemplarKeyTraceId])
+	}
+
+	if rawPath := ctx.Request.URL.Path; h.rawPathExemplar && utf8.ValidString(rawPath) {
+		remaining -= len(exemplarKeyRawPath)
+		if runes := []rune(rawPath); len(runes) > remaining {
+			rawPath = string(runes[:remaining])
+		}
+		res[exemplarKeyRawPath] = rawPath
+	}
+
+	return res
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serveWithHistogram serves request with middleware built from config and returns histogram gathered from registry.
+func serveWithHistogram(t *testing.T, config *HistogramConfig, name string) *dto.Histogram {
+	defer rkmidprom.ClearAllMetrics()
+
+	reg := prometheus.NewRegistry()
+	handler, err := MiddlewareWithHistogram(config,
+		rkmidprom.WithEntryNameAndType("ut-histogram", "ut-type"),
+		rkmidprom.WithRegisterer(reg),
+		rkmidprom.WithPathToIgnore("/ut-ignore"))
+	assert.Nil(t, err)
+
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/ut-user/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.GET("/ut-ignore", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-user/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-ignore", nil))
+
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+	for _, family := range families {
+		if family.GetName() != "rk_prom_"+name {
+			continue
+		}
+
+		assert.Len(t, family.GetMetric(), 1)
+		labels := map[string]string{}
+		for _, label := range family.GetMetric()[0].GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		assert.Equal(t, "ut-histogram", labels["entryName"])
+		assert.Equal(t, "/ut-user/:id", labels["restPath"])
+		assert.Equal(t, "200", labels["resCode"])
+
+		return family.GetMetric()[0].GetHistogram()
+	}
+
+	return nil
+}
+
+func TestMiddlewareWithHistogram(t *testing.T) {
+	// disabled
+	assert.Nil(t, serveWithHistogram(t, nil, MetricsNameElapsedMsHistogram))
+	assert.Nil(t, serveWithHistogram(t, &HistogramConfig{}, MetricsNameElapsedMsHistogram))
+
+	// milliseconds with default buckets
+	hist := serveWithHistogram(t, &HistogramConfig{Enabled: true}, MetricsNameElapsedMsHistogram)
+	assert.NotNil(t, hist)
+	assert.Equal(t, uint64(1), hist.GetSampleCount())
+	assert.Len(t, hist.GetBucket(), len(DefaultHistogramBucketsMs))
+	assert.Equal(t, float64(5), hist.GetBucket()[0].GetUpperBound())
+
+	// nanoseconds with default buckets
+	hist = serveWithHistogram(t, &HistogramConfig{Enabled: true, Unit: "NS"}, MetricsNameElapsedNanoHistogram)
+	assert.NotNil(t, hist)
+	assert.Equal(t, float64(5000000), hist.GetBucket()[0].GetUpperBound())
+
+	// custom buckets
+	hist = serveWithHistogram(t, &HistogramConfig{Enabled: true, Buckets: []float64{1, 2}}, MetricsNameElapsedMsHistogram)
+	assert.NotNil(t, hist)
+	assert.Len(t, hist.GetBucket(), 2)
+
+	// unsupported unit
+	_, err := MiddlewareWithHistogram(&HistogramConfig{Enabled: true, Unit: "s"},
+		rkmidprom.WithEntryNameAndType("ut-histogram-unit", "ut-type"),
+		rkmidprom.WithRegisterer(prometheus.NewRegistry()))
+	assert.NotNil(t, err)
+	rkmidprom.ClearAllMetrics()
+}
+
+func TestHistogram_Exemplar(t *testing.T) {
+	newCtx := func(path string, sampled bool) *gin.Context {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx.Request.URL.Path = path
+		if sampled {
+			spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    trace.TraceID{1},
+				SpanID:     trace.SpanID{1},
+				TraceFlags: trace.FlagsSampled,
+			})
+			ctx.Request = ctx.Request.WithContext(trace.ContextWithSpanContext(ctx.Request.Context(), spanCtx))
+		}
+		return ctx
+	}
+	traceId := trace.TraceID{1}.String()
+
+	// no trace and raw path disabled
+	h := &histogram{}
+	assert.Empty(t, h.exemplar(newCtx("/ut-user/1", false)))
+
+	// trace id of sampled span
+	assert.Equal(t, prometheus.Labels{"trace_id": traceId}, h.exemplar(newCtx("/ut-user/1", true)))
+
+	// raw path
+	h = &histogram{rawPathExemplar: true}
+	assert.Equal(t, prometheus.Labels{"rawPath": "/ut-user/1"}, h.exemplar(newCtx("/ut-user/1", false)))
+	assert.Equal(t, prometheus.Labels{"trace_id": traceId, "rawPath": "/ut-user/1"}, h.exemplar(newCtx("/ut-user/1", true)))
+
+	// truncated to limit of exemplar
+	exemplar := h.exemplar(newCtx("/"+strings.Repeat("a", 200), false))
+	assert.Len(t, exemplar["rawPath"], prometheus.ExemplarMaxRunes-len("rawPath"))
+	exemplar = h.exemplar(newCtx("/"+strings.Repeat("a", 200), true))
+	assert.Len(t, exemplar["rawPath"], prometheus.ExemplarMaxRunes-len("rawPath")-len("trace_id")-len(traceId))
+
+	// invalid UTF-8
+	assert.Empty(t, h.exemplar(newCtx("/\xff", false)))
+
+	// attached to histogram
+	defer rkmidprom.ClearAllMetrics()
+	reg := prometheus.NewRegistry()
+	handler, err := MiddlewareWithHistogram(&HistogramConfig{Enabled: true, RawPathExemplar: true},
+		rkmidprom.WithEntryNameAndType("ut-exemplar", "ut-type"),
+		rkmidprom.WithRegisterer(reg))
+	assert.Nil(t, err)
+
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/ut-user/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-user/1", nil))
+
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+	found := false
+	for _, family := range families {
+		if family.GetName() != "rk_prom_"+MetricsNameElapsedMsHistogram {
+			continue
+		}
+		for _, bucket := range family.GetMetric()[0].GetHistogram().GetBucket() {
+			if bucket.GetExemplar() != nil {
+				assert.Equal(t, "/ut-user/1", bucket.GetExemplar().GetLabel()[0].GetValue())
+				found = true
+			}
+		}
+	}
+	assert.True(t, found)
+}
@@ -8,24 +8,147 @@ package rkginprom
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rookie-ninja/rk-entry/v2/middleware"
 	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
 	"strconv"
+	"time"
 )
 
+// RestPathUnmatched is value of restPath label of requests which matched no route.
+const RestPathUnmatched = "unmatched"
+
+// Config config of metrics recorded besides summary and counters registered by rkmidprom.
+//
+// Metrics are recorded into MetricsSet created with NewMetricsSet if provided, instead of metrics set kept globally
+// by rkmidprom, so entries in one process could use different registerers, and metrics could be removed with Unregister.
+type Config struct {
+	Histogram  HistogramConfig       `yaml:"histogram" json:"histogram"`
+	SLO        SLOConfig             `yaml:"slo" json:"slo"`
+	Tenant     TenantConfig          `yaml:"tenant" json:"tenant"`
+	MetricsSet *rkmidprom.MetricsSet `yaml:"-" json:"-"`
+}
+
 // Middleware create a new prometheus metrics interceptor with options.
+//
+// It panics if metrics could not be registered, use MiddlewareWithConfig to handle the error.
 func Middleware(opts ...rkmidprom.Option) gin.HandlerFunc {
+	handler, err := MiddlewareWithConfig(nil, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return handler
+}
+
+// MiddlewareWithHistogram create a new prometheus metrics interceptor with options,
+// elapsed time is observed with histogram as well if config is enabled.
+func MiddlewareWithHistogram(config *HistogramConfig, opts ...rkmidprom.Option) (gin.HandlerFunc, error) {
+	res := &Config{}
+	if config != nil {
+		res.Histogram = *config
+	}
+
+	return MiddlewareWithConfig(res, opts...)
+}
+
+// MiddlewareWithConfig create a new prometheus metrics interceptor with options,
+// histogram, latency objectives and metrics by tenant are recorded if enabled in config.
+//
+// Requests are counted by class of response code like 2xx in resCodeClass counter besides resCode counter.
+func MiddlewareWithConfig(config *Config, opts ...rkmidprom.Option) (gin.HandlerFunc, error) {
+	if config == nil {
+		config = &Config{}
+	}
+
+	metricsSet := config.MetricsSet
+	if metricsSet != nil {
+		// summary and counter of option set are never recorded, they are recorded into metrics set of config instead
+		opts = append(append([]rkmidprom.Option{}, opts...), rkmidprom.WithRegisterer(prometheus.NewRegistry()))
+	}
+
 	set := rkmidprom.NewOptionSet(opts...)
+	if metricsSet == nil {
+		metricsSet = rkmidprom.GetServerMetricsSet(set.GetEntryName())
+	}
+
+	hist, err := newHistogram(&config.Histogram, metricsSet)
+	if err != nil {
+		return nil, err
+	}
+	classCounter, err := newStatusClassCounter(metricsSet)
+	if err != nil {
+		return nil, err
+	}
+	objectives, err := newSLO(&config.SLO, metricsSet)
+	if err != nil {
+		return nil, err
+	}
+	tenantExtractor, err := newTenant(&config.Tenant, metricsSet)
+	if err != nil {
+		return nil, err
+	}
+
+	// summary and counter are recorded by rkmidprom if metrics set is not provided
+	cache := newRouteMetricsCache(set, config.MetricsSet, hist, classCounter, tenantExtractor)
 
 	return func(ctx *gin.Context) {
 		ctx.Set(rkmid.EntryNameKey.String(), set.GetEntryName())
+		ctx.Set(metricsSetKey, metricsSet)
 
 		beforeCtx := set.BeforeCtx(ctx.Request)
 		set.Before(beforeCtx)
 
 		ctx.Next()
 
-		afterCtx := set.AfterCtx(strconv.Itoa(ctx.Writer.Status()))
-		set.After(beforeCtx, afterCtx)
+		if set.ShouldIgnore(ctx.Request.URL.Path) {
+			return
+		}
+
+		// label metrics with route template instead of raw path, so cardinality of restPath is bounded
+		beforeCtx.Input.RestPath = routePath(ctx)
+
+		elapsed := time.Since(beforeCtx.Output.StartTime)
+		if config.MetricsSet == nil {
+			afterCtx := set.AfterCtx(strconv.Itoa(ctx.Writer.Status()))
+			set.After(beforeCtx, afterCtx)
+		}
+
+		tenantLabel := ""
+		if tenantExtractor != nil {
+			tenantLabel = tenantExtractor.get(ctx)
+		}
+
+		metrics := cache.get(ctx.Request.Method, beforeCtx.Input.RestPath, ctx.Writer.Status(), tenantLabel)
+		if metrics.elapsed != nil {
+			metrics.elapsed.Observe(float64(elapsed.Nanoseconds()))
+		}
+		if metrics.resCode != nil {
+			metrics.resCode.Inc()
+		}
+		if metrics.histogram != nil {
+			hist.observe(metrics.histogram, ctx, elapsed)
+		}
+		if metrics.resCodeClass != nil {
+			metrics.resCodeClass.Inc()
+		}
+		if metrics.tenantElapsed != nil {
+			metrics.tenantElapsed.Observe(float64(elapsed.Nanoseconds()))
+		}
+		if metrics.tenantResCode != nil {
+			metrics.tenantResCode.Inc()
+		}
+		if objectives != nil {
+			objectives.observe(set, ctx, beforeCtx.Input.RestPath, elapsed)
+		}
+	}, nil
+}
+
+// routePath returns route template like /v1/user/:id matched by request, RestPathUnmatched if no route matched.
+func routePath(ctx *gin.Context) string {
+	if fullPath := ctx.FullPath(); len(fullPath) > 0 {
+		return fullPath
 	}
+
+	return RestPathUnmatched
 }
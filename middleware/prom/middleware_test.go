@@ -7,6 +7,7 @@ package rkginprom
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
 	"github.com/stretchr/testify/assert"
 	"net/http"
@@ -32,6 +33,59 @@ func TestInterceptor(t *testing.T) {
 	rkmidprom.ClearAllMetrics()
 }
 
+func TestMiddleware_RoutePath(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	reg := prometheus.NewRegistry()
+	router := gin.New()
+	router.Use(Middleware(
+		rkmidprom.WithEntryNameAndType("ut-route-path", "ut-type"),
+		rkmidprom.WithRegisterer(reg),
+		rkmidprom.WithPathToIgnore("/ut-ignore")))
+	router.GET("/ut-user/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/ut-user/1", "/ut-user/2", "/ut-missing/1", "/ut-missing/2", "/ut-ignore"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+	counts := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != "rk_prom_"+rkmidprom.MetricsNameResCode {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "restPath" {
+					counts[label.GetValue()] = metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{"/ut-user/:id": 2, RestPathUnmatched: 2}, counts)
+}
+
+func TestMiddleware_RegisterFailed(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	// counter of response code class conflicts with gauge registered already
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "rk",
+		Subsystem: "prom",
+		Name:      MetricsNameResCodeClass,
+	}))
+
+	assert.Panics(t, func() {
+		Middleware(
+			rkmidprom.WithEntryNameAndType("ut-register-failed", "ut-type"),
+			rkmidprom.WithRegisterer(reg))
+	})
+}
+
 func assertNotPanic(t *testing.T) {
 	if r := recover(); r != nil {
 		// Expect panic to be called with non nil error
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+)
+
+// metricsSetKey is key of metrics set of prom middleware in gin.Context.
+const metricsSetKey = "rkginprom.metricsSet"
+
+// NewMetricsSet returns metrics set owned by registerer, summary and counter same as rkmidprom are registered.
+//
+// Pass it with MetricsSet of Config, metrics of entry are recorded into it instead of metrics set kept globally
+// by rkmidprom, so entries in one process could use different registerers. Call Unregister once entry stopped.
+func NewMetricsSet(registerer prometheus.Registerer) (*rkmidprom.MetricsSet, error) {
+	set := rkmidprom.NewMetricsSet("rk", "prom", registerer)
+	if err := set.RegisterSummary(rkmidprom.MetricsNameElapsedNano, rkmidprom.SummaryObjectives, labelKeysHistogram...); err != nil {
+		return nil, err
+	}
+	if err := set.RegisterCounter(rkmidprom.MetricsNameResCode, labelKeysHistogram...); err != nil {
+		Unregister(set)
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// GetMetricsSet returns metrics set of prom middleware which handled request, nil if prom middleware is not enabled.
+func GetMetricsSet(ctx *gin.Context) *rkmidprom.MetricsSet {
+	if ctx == nil {
+		return nil
+	}
+
+	if raw, ok := ctx.Get(metricsSetKey); ok {
+		if res, ok := raw.(*rkmidprom.MetricsSet); ok {
+			return res
+		}
+	}
+
+	return nil
+}
+
+// Unregister unregisters all metrics of set from its registerer, so metrics set of entry with same name
+// could be created again.
+//
+// UnRegister functions of MetricsSet are not used since they unregister from default registerer.
+func Unregister(set *rkmidprom.MetricsSet) {
+	if set == nil {
+		return
+	}
+
+	registerer := set.GetRegisterer()
+	for _, v := range set.ListCounters() {
+		registerer.Unregister(v)
+	}
+	for _, v := range set.ListGauges() {
+		registerer.Unregister(v)
+	}
+	for _, v := range set.ListHistograms() {
+		registerer.Unregister(v)
+	}
+	for _, v := range set.ListSummaries() {
+		registerer.Unregister(v)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// serveWithMetricsSet serves one request with middleware of entry which records into metricsSet,
+// metrics set in gin.Context is returned.
+func serveWithMetricsSet(t *testing.T, entryName string, metricsSet *rkmidprom.MetricsSet) *rkmidprom.MetricsSet {
+	handler, err := MiddlewareWithConfig(&Config{
+		Histogram:  HistogramConfig{Enabled: true},
+		MetricsSet: metricsSet,
+	}, rkmidprom.WithEntryNameAndType(entryName, "ut-type"))
+	assert.Nil(t, err)
+
+	var res *rkmidprom.MetricsSet
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/ut-user/:id", func(ctx *gin.Context) {
+		res = GetMetricsSet(ctx)
+		ctx.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-user/1", nil))
+
+	return res
+}
+
+// gatherNames returns names of metric families in registry.
+func gatherNames(t *testing.T, reg *prometheus.Registry) []string {
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+
+	res := make([]string, 0)
+	for _, family := range families {
+		res = append(res, family.GetName())
+	}
+	return res
+}
+
+func TestMiddlewareWithConfig_MetricsSet(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	// entries with different registerers
+	regA, regB := prometheus.NewRegistry(), prometheus.NewRegistry()
+	setA, err := NewMetricsSet(regA)
+	assert.Nil(t, err)
+	setB, err := NewMetricsSet(regB)
+	assert.Nil(t, err)
+	defer Unregister(setB)
+
+	assert.True(t, setA == serveWithMetricsSet(t, "ut-entry-a", setA))
+	assert.True(t, setB == serveWithMetricsSet(t, "ut-entry-b", setB))
+
+	for _, reg := range []*prometheus.Registry{regA, regB} {
+		names := gatherNames(t, reg)
+		assert.Contains(t, names, "rk_prom_"+rkmidprom.MetricsNameElapsedNano)
+		assert.Contains(t, names, "rk_prom_"+rkmidprom.MetricsNameResCode)
+		assert.Contains(t, names, "rk_prom_"+MetricsNameResCodeClass)
+		assert.Contains(t, names, "rk_prom_"+MetricsNameElapsedMsHistogram)
+	}
+
+	// registered into registerer already
+	_, err = NewMetricsSet(regA)
+	assert.NotNil(t, err)
+
+	// unregistered
+	Unregister(setA)
+	Unregister(nil)
+	assert.Empty(t, gatherNames(t, regA))
+	assert.NotEmpty(t, gatherNames(t, regB))
+
+	// entry with same name created again
+	setA, err = NewMetricsSet(regA)
+	assert.Nil(t, err)
+	defer Unregister(setA)
+	serveWithMetricsSet(t, "ut-entry-a", setA)
+	assert.Contains(t, gatherNames(t, regA), "rk_prom_"+rkmidprom.MetricsNameResCode)
+}
+
+func TestGetMetricsSet(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	assert.Nil(t, GetMetricsSet(nil))
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.Nil(t, GetMetricsSet(ctx))
+
+	// metrics set kept by rkmidprom
+	handler := Middleware(rkmidprom.WithEntryNameAndType("ut-metrics-set", "ut-type"),
+		rkmidprom.WithRegisterer(prometheus.NewRegistry()))
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/ut", nil)
+	handler(ctx)
+	assert.True(t, rkmidprom.GetServerMetricsSet("ut-metrics-set") == GetMetricsSet(ctx))
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"net/http"
+	"path"
+	"time"
+)
+
+const (
+	// MetricsNameApdex is name of counter of requests by apdex class, satisfied, tolerating or frustrated.
+	MetricsNameApdex = "apdex"
+	// MetricsNameSLOErrorBudgetBurn is name of counter of requests which violate latency objective or failed with 5xx.
+	MetricsNameSLOErrorBudgetBurn = "sloErrorBudgetBurn"
+
+	// ApdexSatisfied is class of requests finished within target.
+	ApdexSatisfied = "satisfied"
+	// ApdexTolerating is class of requests finished within tolerating threshold.
+	ApdexTolerating = "tolerating"
+	// ApdexFrustrated is class of requests slower than tolerating threshold or failed with 5xx.
+	ApdexFrustrated = "frustrated"
+)
+
+var (
+	// labelKeysApdex are labels of apdex counter.
+	labelKeysApdex = []string{
+		"entryName",
+		"entryType",
+		"domain",
+		"instance",
+		"restMethod",
+		"restPath",
+		"apdex",
+	}
+
+	// labelKeysSLOErrorBudgetBurn are labels of error budget burn counter.
+	labelKeysSLOErrorBudgetBurn = []string{
+		"entryName",
+		"entryType",
+		"domain",
+		"instance",
+		"restMethod",
+		"restPath",
+	}
+)
+
+// SLOConfig config of latency objectives, requests of routes matched by objectives are counted
+// by apdex class, and requests violating objective are counted as error budget burn.
+//
+// Objectives are matched in order, the first one matched with route template is applied.
+type SLOConfig struct {
+	Enabled    bool           `yaml:"enabled" json:"enabled"`
+	Objectives []SLOObjective `yaml:"objectives" json:"objectives"`
+}
+
+// SLOObjective latency objective of routes.
+//
+// Path is route template like /v1/user/:id or path.Match pattern like /v1/*, empty Path matches every route.
+// Requests finished within TargetMs are satisfied, within ToleratingMs, 4 times of TargetMs by default,
+// are tolerating, others and requests failed with 5xx are frustrated.
+// Requests slower than TargetMs or failed with 5xx burn error budget.
+type SLOObjective struct {
+	Path         string `yaml:"path" json:"path"`
+	TargetMs     int64  `yaml:"targetMs" json:"targetMs"`
+	ToleratingMs int64  `yaml:"toleratingMs" json:"toleratingMs"`
+}
+
+// slo counts requests of routes with objectives.
+type slo struct {
+	objectives []SLOObjective
+	metricsSet *rkmidprom.MetricsSet
+}
+
+// newSLO registers counters into metrics set of entry, nil is returned if config is not enabled.
+func newSLO(config *SLOConfig, metricsSet *rkmidprom.MetricsSet) (*slo, error) {
+	if config == nil || !config.Enabled || len(config.Objectives) < 1 {
+		return nil, nil
+	}
+
+	res := &slo{}
+	for _, objective := range config.Objectives {
+		if objective.TargetMs < 1 {
+			return nil, fmt.Errorf("targetMs of latency objective %s should be positive", objective.Path)
+		}
+		if _, err := path.Match(objective.Path, ""); err != nil {
+			return nil, fmt.Errorf("invalid path %s of latency objective, %v", objective.Path, err)
+		}
+		if objective.ToleratingMs < objective.TargetMs {
+			objective.ToleratingMs = 4 * objective.TargetMs
+		}
+		res.objectives = append(res.objectives, objective)
+	}
+
+	if res.metricsSet = metricsSet; res.metricsSet == nil {
+		return nil, nil
+	}
+
+	if res.metricsSet.GetCounter(MetricsNameApdex) == nil {
+		if err := res.metricsSet.RegisterCounter(MetricsNameApdex, labelKeysApdex...); err != nil {
+			return nil, err
+		}
+	}
+	if res.metricsSet.GetCounter(MetricsNameSLOErrorBudgetBurn) == nil {
+		if err := res.metricsSet.RegisterCounter(MetricsNameSLOErrorBudgetBurn, labelKeysSLOErrorBudgetBurn...); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// match returns the first objective matched with route template, nil if none matched.
+func (s *slo) match(restPath string) *SLOObjective {
+	if restPath == RestPathUnmatched {
+		return nil
+	}
+
+	for i := range s.objectives {
+		if len(s.objectives[i].Path) < 1 || s.objectives[i].Path == restPath {
+			return &s.objectives[i]
+		}
+		if matched, _ := path.Match(s.objectives[i].Path, restPath); matched {
+			return &s.objectives[i]
+		}
+	}
+
+	return nil
+}
+
+// observe counts request by apdex class and error budget burn if route has objective.
+func (s *slo) observe(set rkmidprom.OptionSetInterface, ctx *gin.Context, restPath string, elapsed time.Duration) {
+	objective := s.match(restPath)
+	if objective == nil {
+		return
+	}
+
+	failed := ctx.Writer.Status() >= http.StatusInternalServerError
+	class := ApdexFrustrated
+	switch {
+	case failed:
+	case elapsed <= time.Duration(objective.TargetMs)*time.Millisecond:
+		class = ApdexSatisfied
+	case elapsed <= time.Duration(objective.ToleratingMs)*time.Millisecond:
+		class = ApdexTolerating
+	}
+
+	values := []string{
+		set.GetEntryName(),
+		set.GetEntryType(),
+		rkmid.Domain.String,
+		rkmid.LocalHostname.String,
+		ctx.Request.Method,
+		restPath,
+	}
+
+	if counter := s.metricsSet.GetCounterWithValues(MetricsNameApdex, append(values, class)...); counter != nil {
+		counter.Inc()
+	}
+
+	if class == ApdexSatisfied {
+		return
+	}
+
+	if counter := s.metricsSet.GetCounterWithValues(MetricsNameSLOErrorBudgetBurn, values...); counter != nil {
+		counter.Inc()
+	}
+}
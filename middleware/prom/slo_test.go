@@ -0,0 +1,112 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewSLO(t *testing.T) {
+	metricsSet := rkmidprom.NewMetricsSet("rk", "prom", prometheus.NewRegistry())
+
+	// disabled
+	res, err := newSLO(nil, metricsSet)
+	assert.Nil(t, res)
+	assert.Nil(t, err)
+	res, err = newSLO(&SLOConfig{Enabled: true}, metricsSet)
+	assert.Nil(t, res)
+	assert.Nil(t, err)
+
+	// invalid target
+	_, err = newSLO(&SLOConfig{Enabled: true, Objectives: []SLOObjective{{Path: "/v1/*"}}}, metricsSet)
+	assert.NotNil(t, err)
+
+	// invalid path
+	_, err = newSLO(&SLOConfig{Enabled: true, Objectives: []SLOObjective{{Path: "/v1/[", TargetMs: 10}}}, metricsSet)
+	assert.NotNil(t, err)
+
+	// tolerating threshold
+	res, err = newSLO(&SLOConfig{Enabled: true, Objectives: []SLOObjective{
+		{Path: "/v1/user/:id", TargetMs: 100},
+		{Path: "/v1/*", TargetMs: 100, ToleratingMs: 200},
+		{TargetMs: 1000},
+	}}, metricsSet)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(400), res.objectives[0].ToleratingMs)
+	assert.Equal(t, int64(200), res.objectives[1].ToleratingMs)
+
+	// match
+	assert.Equal(t, &res.objectives[0], res.match("/v1/user/:id"))
+	assert.Equal(t, &res.objectives[1], res.match("/v1/order"))
+	assert.Equal(t, &res.objectives[2], res.match("/v2/order"))
+	assert.Nil(t, res.match(RestPathUnmatched))
+}
+
+func TestMiddleware_SLO(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	reg := prometheus.NewRegistry()
+	handler, err := MiddlewareWithConfig(&Config{
+		SLO: SLOConfig{
+			Enabled:    true,
+			Objectives: []SLOObjective{{Path: "/ut-*", TargetMs: 10, ToleratingMs: 1000}},
+		},
+	}, rkmidprom.WithEntryNameAndType("ut-slo", "ut-type"), rkmidprom.WithRegisterer(reg))
+	assert.Nil(t, err)
+
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/ut-fast", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.GET("/ut-slow", func(ctx *gin.Context) {
+		time.Sleep(15 * time.Millisecond)
+		ctx.Status(http.StatusOK)
+	})
+	router.GET("/ut-error", func(ctx *gin.Context) {
+		ctx.Status(http.StatusInternalServerError)
+	})
+	router.GET("/other", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/ut-fast", "/ut-fast", "/ut-slow", "/ut-error", "/other"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+	apdex, burn := map[string]float64{}, map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+
+			switch family.GetName() {
+			case "rk_prom_" + MetricsNameApdex:
+				apdex[labels["restPath"]+":"+labels["apdex"]] = metric.GetCounter().GetValue()
+			case "rk_prom_" + MetricsNameSLOErrorBudgetBurn:
+				burn[labels["restPath"]] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]float64{
+		"/ut-fast:" + ApdexSatisfied:   2,
+		"/ut-slow:" + ApdexTolerating:  1,
+		"/ut-error:" + ApdexFrustrated: 1,
+	}, apdex)
+	assert.Equal(t, map[string]float64{"/ut-slow": 1, "/ut-error": 1}, burn)
+}
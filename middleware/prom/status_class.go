@@ -0,0 +1,58 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"strconv"
+)
+
+const (
+	// MetricsNameResCodeClass is name of counter of requests by class of response code like 2xx.
+	MetricsNameResCodeClass = "resCodeClass"
+	// resCodeClassUnknown is class of response code out of range from 100 to 599.
+	resCodeClassUnknown = "unknown"
+)
+
+// labelKeysResCodeClass are labels of resCodeClass counter.
+var labelKeysResCodeClass = []string{
+	"entryName",
+	"entryType",
+	"domain",
+	"instance",
+	"restMethod",
+	"restPath",
+	"resCodeClass",
+}
+
+// statusClassCounter counts requests by class of response code, so rules don't need to match over resCode series.
+type statusClassCounter struct {
+	metricsSet *rkmidprom.MetricsSet
+}
+
+// newStatusClassCounter registers counter into metrics set of entry, nil is returned if metrics set not exist.
+func newStatusClassCounter(metricsSet *rkmidprom.MetricsSet) (*statusClassCounter, error) {
+	if metricsSet == nil {
+		return nil, nil
+	}
+
+	if metricsSet.GetCounter(MetricsNameResCodeClass) == nil {
+		if err := metricsSet.RegisterCounter(MetricsNameResCodeClass, labelKeysResCodeClass...); err != nil {
+			return nil, err
+		}
+	}
+
+	return &statusClassCounter{metricsSet: metricsSet}, nil
+}
+
+// resCodeClass returns class of response code like 2xx, resCodeClassUnknown if code is not in range from 100 to 599.
+func resCodeClass(code int) string {
+	if code < 100 || code > 599 {
+		return resCodeClassUnknown
+	}
+
+	return strconv.Itoa(code/100) + "xx"
+}
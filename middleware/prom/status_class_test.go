@@ -0,0 +1,63 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestResCodeClass(t *testing.T) {
+	assert.Equal(t, "1xx", resCodeClass(http.StatusContinue))
+	assert.Equal(t, "2xx", resCodeClass(http.StatusOK))
+	assert.Equal(t, "3xx", resCodeClass(http.StatusFound))
+	assert.Equal(t, "4xx", resCodeClass(http.StatusNotFound))
+	assert.Equal(t, "5xx", resCodeClass(http.StatusServiceUnavailable))
+	assert.Equal(t, resCodeClassUnknown, resCodeClass(0))
+	assert.Equal(t, resCodeClassUnknown, resCodeClass(600))
+}
+
+func TestMiddleware_ResCodeClass(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	reg := prometheus.NewRegistry()
+	router := gin.New()
+	router.Use(Middleware(
+		rkmidprom.WithEntryNameAndType("ut-res-code-class", "ut-type"),
+		rkmidprom.WithRegisterer(reg)))
+	router.GET("/ut-user/:code", func(ctx *gin.Context) {
+		code, _ := strconv.Atoi(ctx.Param("code"))
+		ctx.Status(code)
+	})
+
+	for _, path := range []string{"/ut-user/200", "/ut-user/201", "/ut-user/404", "/ut-user/500", "/ut-user/503"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+	counts := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != "rk_prom_"+MetricsNameResCodeClass {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			assert.Equal(t, "/ut-user/:code", labels["restPath"])
+			counts[labels["resCodeClass"]] = metric.GetCounter().GetValue()
+		}
+	}
+	assert.Equal(t, map[string]float64{"2xx": 2, "4xx": 1, "5xx": 2}, counts)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+)
+
+const (
+	// MetricsNameTenantElapsedNano is name of summary observing elapsed nanoseconds of requests by tenant.
+	MetricsNameTenantElapsedNano = "tenantElapsedNano"
+	// MetricsNameTenantResCode is name of counter counting requests by tenant and response code.
+	MetricsNameTenantResCode = "tenantResCode"
+
+	// TenantUnknown is value of tenant label of requests without tenant.
+	TenantUnknown = "unknown"
+	// TenantOther is value of tenant label of requests whose tenant is not allowed.
+	TenantOther = "other"
+)
+
+// labelKeysTenant are labels of tenant metrics.
+var labelKeysTenant = []string{
+	"entryName",
+	"entryType",
+	"domain",
+	"instance",
+	"restMethod",
+	"restPath",
+	"resCode",
+	"tenant",
+}
+
+// TenantConfig config of metrics labeled with tenant of request.
+//
+// Tenant is read from Header, or from Claim of JWT token parsed by jwt middleware if header is missing.
+// Only tenants in Allowed are used as label, others are labeled as TenantOther, so cardinality is bounded.
+type TenantConfig struct {
+	Enabled bool     `yaml:"enabled" json:"enabled"`
+	Header  string   `yaml:"header" json:"header"`
+	Claim   string   `yaml:"claim" json:"claim"`
+	Allowed []string `yaml:"allowed" json:"allowed"`
+}
+
+// tenant extracts tenant of requests.
+type tenant struct {
+	header     string
+	claim      string
+	allowed    map[string]bool
+	metricsSet *rkmidprom.MetricsSet
+}
+
+// newTenant registers tenant metrics into metrics set of entry, nil is returned if config is not enabled.
+func newTenant(config *TenantConfig, metricsSet *rkmidprom.MetricsSet) (*tenant, error) {
+	if config == nil || !config.Enabled || metricsSet == nil {
+		return nil, nil
+	}
+
+	if len(config.Header) < 1 && len(config.Claim) < 1 {
+		return nil, errors.New("header or claim of tenant should be provided")
+	}
+	if len(config.Allowed) < 1 {
+		return nil, errors.New("allowed of tenant should not be empty")
+	}
+
+	res := &tenant{
+		header:     config.Header,
+		claim:      config.Claim,
+		allowed:    make(map[string]bool),
+		metricsSet: metricsSet,
+	}
+	for _, v := range config.Allowed {
+		res.allowed[v] = true
+	}
+
+	if metricsSet.GetSummary(MetricsNameTenantElapsedNano) == nil {
+		if err := metricsSet.RegisterSummary(MetricsNameTenantElapsedNano, rkmidprom.SummaryObjectives, labelKeysTenant...); err != nil {
+			return nil, err
+		}
+	}
+	if metricsSet.GetCounter(MetricsNameTenantResCode) == nil {
+		if err := metricsSet.RegisterCounter(MetricsNameTenantResCode, labelKeysTenant...); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// get returns tenant label of request, TenantUnknown if missing and TenantOther if not allowed.
+func (t *tenant) get(ctx *gin.Context) string {
+	res := ""
+	if len(t.header) > 0 {
+		res = ctx.GetHeader(t.header)
+	}
+
+	if len(res) < 1 && len(t.claim) > 0 {
+		if raw, ok := ctx.Get(rkmid.JwtTokenKey.String()); ok {
+			if token, ok := raw.(*jwt.Token); ok && token != nil {
+				if claims, ok := token.Claims.(jwt.MapClaims); ok && claims[t.claim] != nil {
+					res = fmt.Sprintf("%v", claims[t.claim])
+				}
+			}
+		}
+	}
+
+	switch {
+	case len(res) < 1:
+		return TenantUnknown
+	case t.allowed[res]:
+		return res
+	default:
+		return TenantOther
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkginprom
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rookie-ninja/rk-entry/v2/middleware"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/prom"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewTenant(t *testing.T) {
+	metricsSet := rkmidprom.NewMetricsSet("rk", "prom", prometheus.NewRegistry())
+
+	// disabled
+	res, err := newTenant(nil, metricsSet)
+	assert.Nil(t, res)
+	assert.Nil(t, err)
+	res, err = newTenant(&TenantConfig{Enabled: true, Header: "X-Tenant-Id", Allowed: []string{"a"}}, nil)
+	assert.Nil(t, res)
+	assert.Nil(t, err)
+
+	// neither header nor claim
+	_, err = newTenant(&TenantConfig{Enabled: true, Allowed: []string{"a"}}, metricsSet)
+	assert.NotNil(t, err)
+
+	// empty allowed
+	_, err = newTenant(&TenantConfig{Enabled: true, Header: "X-Tenant-Id"}, metricsSet)
+	assert.NotNil(t, err)
+
+	// happy case
+	res, err = newTenant(&TenantConfig{Enabled: true, Header: "X-Tenant-Id", Allowed: []string{"a"}}, metricsSet)
+	assert.Nil(t, err)
+	assert.NotNil(t, res)
+	assert.NotNil(t, metricsSet.GetSummary(MetricsNameTenantElapsedNano))
+	assert.NotNil(t, metricsSet.GetCounter(MetricsNameTenantResCode))
+}
+
+func TestTenant_Get(t *testing.T) {
+	ext := &tenant{
+		header:  "X-Tenant-Id",
+		claim:   "tenant",
+		allowed: map[string]bool{"a": true, "b": true},
+	}
+
+	newCtx := func(header string, claims jwt.MapClaims) *gin.Context {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		if len(header) > 0 {
+			ctx.Request.Header.Set("X-Tenant-Id", header)
+		}
+		if claims != nil {
+			ctx.Set(rkmid.JwtTokenKey.String(), jwt.NewWithClaims(jwt.SigningMethodHS256, claims))
+		}
+		return ctx
+	}
+
+	// missing
+	assert.Equal(t, TenantUnknown, ext.get(newCtx("", nil)))
+	assert.Equal(t, TenantUnknown, ext.get(newCtx("", jwt.MapClaims{"sub": "user"})))
+
+	// from header
+	assert.Equal(t, "a", ext.get(newCtx("a", jwt.MapClaims{"tenant": "b"})))
+
+	// from claim
+	assert.Equal(t, "b", ext.get(newCtx("", jwt.MapClaims{"tenant": "b"})))
+
+	// not allowed
+	assert.Equal(t, TenantOther, ext.get(newCtx("c", nil)))
+	assert.Equal(t, TenantOther, ext.get(newCtx("", jwt.MapClaims{"tenant": 1})))
+}
+
+func TestMiddleware_Tenant(t *testing.T) {
+	defer rkmidprom.ClearAllMetrics()
+
+	reg := prometheus.NewRegistry()
+	handler, err := MiddlewareWithConfig(&Config{
+		Tenant: TenantConfig{
+			Enabled: true,
+			Header:  "X-Tenant-Id",
+			Allowed: []string{"a"},
+		},
+	}, rkmidprom.WithEntryNameAndType("ut-tenant", "ut-type"), rkmidprom.WithRegisterer(reg))
+	assert.Nil(t, err)
+
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/ut-user/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	for _, v := range []string{"a", "a", "b", ""} {
+		req := httptest.NewRequest(http.MethodGet, "/ut-user/1", nil)
+		req.Header.Set("X-Tenant-Id", v)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+	counts := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != "rk_prom_"+MetricsNameTenantResCode {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			assert.Equal(t, "/ut-user/:id", labels["restPath"])
+			counts[labels["tenant"]] = metric.GetCounter().GetValue()
+		}
+	}
+
+	assert.Equal(t, map[string]float64{"a": 2, TenantOther: 1, TenantUnknown: 1}, counts)
+
+	// invalid config
+	_, err = MiddlewareWithConfig(&Config{Tenant: TenantConfig{Enabled: true}},
+		rkmidprom.WithEntryNameAndType("ut-tenant-invalid", "ut-type"), rkmidprom.WithRegisterer(prometheus.NewRegistry()))
+	assert.NotNil(t, err)
+}
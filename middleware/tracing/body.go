@@ -0,0 +1,76 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// requestBodyEvent is name of span event with redacted request body.
+	requestBodyEvent = "http.request.body"
+	// responseBodyEvent is name of span event with redacted response body.
+	responseBodyEvent = "http.response.body"
+	// bodyAttributeKey is attribute of body in span events.
+	bodyAttributeKey = attribute.Key("body")
+)
+
+// recordRequestBody adds redacted request body as event of span,
+// and replaces response writer to capture response body which is added by recordResponseBody.
+func recordRequestBody(ctx *gin.Context, redactor *rkginlog.BodyRedactor, span trace.Span) *bodyWriter {
+	if body := redactor.ReadRequest(ctx.Request); len(body) > 0 {
+		span.AddEvent(requestBodyEvent, trace.WithAttributes(bodyAttributeKey.String(body)))
+	}
+
+	writer := &bodyWriter{ResponseWriter: ctx.Writer, maxBytes: redactor.MaxBytes()}
+	ctx.Writer = writer
+
+	return writer
+}
+
+// recordResponseBody adds redacted response body as event of span, response writer replaced by recordRequestBody
+// is restored, so writers replaced by middlewares before, like body of logging middleware, are kept.
+func recordResponseBody(ctx *gin.Context, redactor *rkginlog.BodyRedactor, span trace.Span, writer *bodyWriter) {
+	if body := redactor.FormatResponse(writer, writer.buf.Bytes()); len(body) > 0 {
+		span.AddEvent(responseBodyEvent, trace.WithAttributes(bodyAttributeKey.String(body)))
+	}
+
+	if ctx.Writer == gin.ResponseWriter(writer) {
+		ctx.Writer = writer.ResponseWriter
+	}
+}
+
+// bodyWriter captures response body up to maxBytes, one more byte is kept to detect truncation.
+type bodyWriter struct {
+	gin.ResponseWriter
+	maxBytes int
+	buf      bytes.Buffer
+}
+
+// Write captures data before writing it into response.
+func (w *bodyWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString captures data before writing it into response.
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyWriter) capture(data []byte) {
+	if remain := w.maxBytes + 1 - w.buf.Len(); remain > 0 {
+		if len(data) > remain {
+			data = data[:remain]
+		}
+		w.buf.Write(data)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/log"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareWithConfig_Body(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	handler := MiddlewareWithConfig(&Config{
+		Body: rkginlog.BodyConfig{Enabled: true, MaxBytes: 32},
+	}, rkmidtrace.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+
+	var reqBody []byte
+	router := gin.New()
+	router.Use(handler)
+	router.POST("/ut", func(ctx *gin.Context) {
+		// request body is restored for handlers
+		reqBody, _ = io.ReadAll(ctx.Request.Body)
+		ctx.JSON(http.StatusOK, gin.H{"token": "ut-token", "data": strings.Repeat("a", 32)})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/ut", strings.NewReader(`{"user":"ut-user","password":"ut-pass"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, `{"user":"ut-user","password":"ut-pass"}`, string(reqBody))
+	assert.Contains(t, resp.Body.String(), "ut-token")
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	events := spans[0].Events()
+	assert.Len(t, events, 2)
+
+	// request body is truncated
+	assert.Equal(t, requestBodyEvent, events[0].Name)
+	assert.Equal(t, `{"user":"ut-user","password":"***"...(truncated)`, events[0].Attributes[0].Value.AsString())
+
+	// response body is truncated and redacted
+	assert.Equal(t, responseBodyEvent, events[1].Name)
+	assert.True(t, strings.HasSuffix(events[1].Attributes[0].Value.AsString(), "...(truncated)"))
+	assert.NotContains(t, events[1].Attributes[0].Value.AsString(), "ut-token")
+}
+
+func TestMiddlewareWithConfig_BodyNotSampled(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	handler := MiddlewareWithConfig(&Config{
+		Body: rkginlog.BodyConfig{Enabled: true},
+	}, rkmidtrace.WithTracerProvider(sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.NeverSample()),
+		sdktrace.WithSpanProcessor(recorder))))
+
+	var writer gin.ResponseWriter
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		writer = ctx.Writer
+		ctx.Next()
+		// response writer is not replaced
+		assert.Equal(t, writer, ctx.Writer)
+	})
+	router.Use(handler)
+	router.POST("/ut", func(ctx *gin.Context) {
+		assert.Equal(t, writer, ctx.Writer)
+		ctx.String(http.StatusOK, "ut-response")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/ut", strings.NewReader("ut-request"))
+	req.Header.Set("Content-Type", "text/plain")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestRecordResponseBody_RestoreWriter(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	handler := MiddlewareWithConfig(&Config{
+		Body: rkginlog.BodyConfig{Enabled: true},
+	}, rkmidtrace.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+
+	var writer gin.ResponseWriter
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		writer = ctx.Writer
+		ctx.Next()
+		// writer replaced by tracing middleware is restored
+		assert.Equal(t, writer, ctx.Writer)
+	})
+	router.Use(handler)
+	router.GET("/ut", func(ctx *gin.Context) {
+		assert.IsType(t, &bodyWriter{}, ctx.Writer)
+		ctx.String(http.StatusOK, "ut-response")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut", nil))
+
+	events := recorder.Ended()[0].Events()
+	assert.Len(t, events, 1)
+	assert.Equal(t, responseBodyEvent, events[0].Name)
+	assert.Equal(t, "ut-response", events[0].Attributes[0].Value.AsString())
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"go.opentelemetry.io/otel/exporters/jaeger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"strconv"
+)
+
+const (
+	defaultJaegerAgentHost          = "localhost"
+	defaultJaegerAgentPort          = 6831
+	defaultJaegerAgentMaxPacketSize = 65000
+)
+
+// JaegerAgentExporterConfig config of exporter which sends spans to jaeger agent with UDP in thrift compact protocol,
+// so spans could be sent to agent deployed as sidecar, localhost:6831 by default.
+//
+// It is kept for agents since jaeger agent does not ingest OTLP, use exporter.otlp for collectors of jaeger 1.35
+// or later which ingest OTLP directly.
+//
+// Spans are batched into packets up to MaxPacketSize bytes, 65000 by default, span larger than it is dropped.
+type JaegerAgentExporterConfig struct {
+	Enabled       bool   `yaml:"enabled" json:"enabled"`
+	Host          string `yaml:"host" json:"host"`
+	Port          int    `yaml:"port" json:"port"`
+	MaxPacketSize int    `yaml:"maxPacketSize" json:"maxPacketSize"`
+}
+
+// CreateJaegerAgentExporter creates exporter which sends spans to jaeger agent with UDP,
+// connection is re-established if address of agent could not be resolved.
+func CreateJaegerAgentExporter(config *JaegerAgentExporterConfig) (sdktrace.SpanExporter, error) {
+	if config == nil {
+		config = &JaegerAgentExporterConfig{}
+	}
+
+	host, port, maxPacketSize := config.Host, config.Port, config.MaxPacketSize
+	if len(host) < 1 {
+		host = defaultJaegerAgentHost
+	}
+	if port < 1 {
+		port = defaultJaegerAgentPort
+	}
+	if maxPacketSize < 1 {
+		maxPacketSize = defaultJaegerAgentMaxPacketSize
+	}
+
+	return jaeger.New(jaeger.WithAgentEndpoint(
+		jaeger.WithAgentHost(host),
+		jaeger.WithAgentPort(strconv.Itoa(port)),
+		jaeger.WithMaxPacketSize(maxPacketSize)))
+}
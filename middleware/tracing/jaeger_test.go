@@ -0,0 +1,54 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestCreateJaegerAgentExporter(t *testing.T) {
+	// defaults
+	exporter, err := CreateJaegerAgentExporter(nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, exporter)
+	assert.Nil(t, exporter.Shutdown(context.TODO()))
+}
+
+func TestJaegerAgentExporter_ExportSpans(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	exporter, err := CreateJaegerAgentExporter(&JaegerAgentExporterConfig{
+		Host: "127.0.0.1",
+		Port: conn.LocalAddr().(*net.UDPAddr).Port,
+	})
+	assert.Nil(t, err)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "ut-service"))))
+	_, span := provider.Tracer("ut-tracer").Start(context.TODO(), "ut-span")
+	span.End()
+
+	// emitBatch of agent with service name and span name
+	assert.Nil(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	raw := make([]byte, 65535)
+	n, err := conn.Read(raw)
+	assert.Nil(t, err)
+	assert.Contains(t, string(raw[:n]), "emitBatch")
+	assert.Contains(t, string(raw[:n]), "ut-service")
+	assert.Contains(t, string(raw[:n]), "ut-span")
+
+	assert.Nil(t, provider.Shutdown(context.TODO()))
+}
@@ -11,11 +11,51 @@ import (
 	"github.com/rookie-ninja/rk-entry/v2/middleware"
 	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
 	"github.com/rookie-ninja/rk-gin/v2/middleware/context"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/log"
+	"net/http"
 )
 
+// Config config of tracing middleware besides options of rkmidtrace.
+//
+// Body records redacted request and response bodies as http.request.body and http.response.body events of span,
+// with the same rules of redaction as body of logging middleware.
+//
+// TraceIdHeader is name of response header with trace id, which is X-Trace-Id by default,
+// and the header is not written if DisableTraceIdHeader is true.
+type Config struct {
+	Body                 rkginlog.BodyConfig `yaml:"body" json:"body"`
+	TraceIdHeader        string              `yaml:"traceIdHeader" json:"traceIdHeader"`
+	DisableTraceIdHeader bool                `yaml:"disableTraceIdHeader" json:"disableTraceIdHeader"`
+}
+
 // Middleware create a interceptor with opentelemetry.
 func Middleware(opts ...rkmidtrace.Option) gin.HandlerFunc {
+	return MiddlewareWithConfig(nil, opts...)
+}
+
+// MiddlewareWithConfig create a interceptor with opentelemetry, bodies are recorded as span events if enabled in config.
+//
+// Server spans are named with method and route template like GET /v1/user/:id, with attributes of HTTP semantic conventions.
+// Spans are marked as error if response is 5xx, errors are added into gin context, or handler panics,
+// and errors are recorded as exception events.
+// Spans batched by tracer provider could be flushed with ShutdownExporters of entry.
+func MiddlewareWithConfig(config *Config, opts ...rkmidtrace.Option) gin.HandlerFunc {
+	if config == nil {
+		config = &Config{}
+	}
+
+	traceIdHeader := config.TraceIdHeader
+	if len(traceIdHeader) < 1 {
+		traceIdHeader = rkmid.HeaderTraceId
+	}
+
 	set := rkmidtrace.NewOptionSet(opts...)
+	providers.register(set.GetEntryName(), set.GetProvider())
+
+	var redactor *rkginlog.BodyRedactor
+	if config.Body.Enabled {
+		redactor = rkginlog.NewBodyRedactor(&config.Body)
+	}
 
 	return func(ctx *gin.Context) {
 		ctx.Set(rkmid.EntryNameKey.String(), set.GetEntryName())
@@ -24,22 +64,49 @@ func Middleware(opts ...rkmidtrace.Option) gin.HandlerFunc {
 		ctx.Set(rkmid.PropagatorKey.String(), set.GetPropagator())
 
 		beforeCtx := set.BeforeCtx(ctx.Request, false)
+		withServerSemconv(beforeCtx, ctx)
 		set.Before(beforeCtx)
 
 		// create request with new context
 		ctx.Request = ctx.Request.WithContext(beforeCtx.Output.NewCtx)
 
 		// add to context
-		if beforeCtx.Output.Span != nil {
-			traceId := beforeCtx.Output.Span.SpanContext().TraceID().String()
+		var writer *bodyWriter
+		if span := beforeCtx.Output.Span; span != nil {
+			traceId := span.SpanContext().TraceID().String()
 			rkginctx.GetEvent(ctx).SetTraceId(traceId)
 			ctx.Set(rkmid.HeaderTraceId, traceId)
-			ctx.Header(rkmid.HeaderTraceId, traceId)
-			ctx.Set(rkmid.SpanKey.String(), beforeCtx.Output.Span)
+			if !config.DisableTraceIdHeader {
+				ctx.Header(traceIdHeader, traceId)
+			}
+			ctx.Set(rkmid.SpanKey.String(), span)
+
+			// bodies are not read for spans which are not sampled
+			if redactor != nil && span.IsRecording() {
+				writer = recordRequestBody(ctx, redactor, span)
+			}
 		}
 
+		// span is ended before panic is recovered by panic middleware in front of this one
+		defer func() {
+			if recv := recover(); recv != nil {
+				if writer != nil && ctx.Writer == gin.ResponseWriter(writer) {
+					ctx.Writer = writer.ResponseWriter
+				}
+
+				recordPanic(beforeCtx, recv)
+				set.After(beforeCtx, set.AfterCtx(http.StatusInternalServerError, ""))
+				panic(recv)
+			}
+		}()
+
 		ctx.Next()
 
+		if writer != nil {
+			recordResponseBody(ctx, redactor, beforeCtx.Output.Span, writer)
+		}
+
+		recordErrors(ctx, beforeCtx)
 		afterCtx := set.AfterCtx(ctx.Writer.Status(), "")
 		set.After(beforeCtx, afterCtx)
 	}
@@ -10,7 +10,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/rookie-ninja/rk-entry/v2/middleware"
 	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/context"
 	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 	"net/http"
 	"net/http/httptest"
@@ -60,6 +62,57 @@ func assertNotPanic(t *testing.T) {
 	}
 }
 
+func TestMiddlewareWithConfig_TraceIdHeader(t *testing.T) {
+	serve := func(config *Config) http.Header {
+		router := gin.New()
+		router.Use(MiddlewareWithConfig(config, rkmidtrace.WithTracerProvider(sdktrace.NewTracerProvider())))
+		router.GET("/ut-path", func(ctx *gin.Context) {
+			assert.NotEmpty(t, rkginctx.GetTraceId(ctx))
+		})
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+		return resp.Header()
+	}
+
+	// default
+	assert.NotEmpty(t, serve(nil).Get(rkmid.HeaderTraceId))
+
+	// renamed
+	header := serve(&Config{TraceIdHeader: "X-Correlation-Id"})
+	assert.NotEmpty(t, header.Get("X-Correlation-Id"))
+	assert.Empty(t, header.Get(rkmid.HeaderTraceId))
+
+	// disabled
+	header = serve(&Config{TraceIdHeader: "X-Correlation-Id", DisableTraceIdHeader: true})
+	assert.Empty(t, header.Get("X-Correlation-Id"))
+	assert.Empty(t, header.Get(rkmid.HeaderTraceId))
+}
+
+func TestMiddleware_TraceState(t *testing.T) {
+	handler := Middleware(rkmidtrace.WithTracerProvider(sdktrace.NewTracerProvider()))
+
+	outgoing := http.Header{}
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/ut-path", func(ctx *gin.Context) {
+		// entry of upstream is kept in server span
+		assert.Equal(t, "ut-value", rkginctx.GetTraceStateValue(ctx, "upstream"))
+		assert.Nil(t, rkginctx.SetTraceStateValue(ctx, "mesh", "ut-route"))
+
+		req := httptest.NewRequest(http.MethodGet, "/ut-downstream", nil)
+		rkginctx.InjectSpanToHttpRequest(ctx, req)
+		outgoing = req.Header
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ut-path", nil)
+	req.Header.Set("traceparent", "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01")
+	req.Header.Set("tracestate", "upstream=ut-value")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "mesh=ut-route,upstream=ut-value", outgoing.Get("tracestate"))
+	assert.Contains(t, outgoing.Get("traceparent"), "0102030405060708090a0b0c0d0e0f10")
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.ReleaseMode)
 	os.Exit(m.Run())
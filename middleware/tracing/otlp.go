@@ -0,0 +1,138 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+	"strings"
+	"time"
+)
+
+const (
+	// OtlpProtocolGrpc exports spans with OTLP over gRPC, which is the default protocol.
+	OtlpProtocolGrpc = "grpc"
+	// OtlpProtocolHttp exports spans with OTLP over HTTP with protobuf encoding.
+	OtlpProtocolHttp = "http"
+
+	defaultOtlpTimeoutMs = 10000
+)
+
+// OtlpExporterConfig config of OTLP exporter which sends spans to OpenTelemetry collector, it extends
+// exporter.otlp of rkmidtrace.BootConfig with http protocol, TLS and headers.
+//
+// Endpoint is host:port of collector, localhost:4317 for grpc and localhost:4318 for http by default,
+// UrlPath is used by http only, /v1/traces by default.
+// Plain connection is used as exporter.otlp of rkmidtrace.BootConfig unless Tls is true or CertEntry is set,
+// CertEntry references cert entry whose CA verifies collector, and whose certificate is sent as client
+// certificate if exists, system roots are used if CertEntry is empty.
+type OtlpExporterConfig struct {
+	Enabled   bool              `yaml:"enabled" json:"enabled"`
+	Endpoint  string            `yaml:"endpoint" json:"endpoint"`
+	Protocol  string            `yaml:"protocol" json:"protocol"`
+	UrlPath   string            `yaml:"urlPath" json:"urlPath"`
+	Tls       bool              `yaml:"tls" json:"tls"`
+	CertEntry string            `yaml:"certEntry" json:"certEntry"`
+	Headers   map[string]string `yaml:"headers" json:"headers"`
+	TimeoutMs int               `yaml:"timeoutMs" json:"timeoutMs"`
+}
+
+// CreateOtlpExporter creates OTLP exporter with config, spans could be sent to any OpenTelemetry collector.
+func CreateOtlpExporter(config *OtlpExporterConfig) (sdktrace.SpanExporter, error) {
+	if config == nil {
+		config = &OtlpExporterConfig{}
+	}
+
+	timeout := time.Duration(config.TimeoutMs) * time.Millisecond
+	if config.TimeoutMs < 1 {
+		timeout = defaultOtlpTimeoutMs * time.Millisecond
+	}
+
+	var tlsConfig *tls.Config
+	if config.Tls || len(config.CertEntry) > 0 {
+		var err error
+		if tlsConfig, err = newOtlpTlsConfig(config.CertEntry); err != nil {
+			return nil, err
+		}
+	}
+
+	var client otlptrace.Client
+	switch strings.ToLower(config.Protocol) {
+	case "", OtlpProtocolGrpc:
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithHeaders(config.Headers),
+			otlptracegrpc.WithTimeout(timeout),
+		}
+		if len(config.Endpoint) > 0 {
+			opts = append(opts,
+				otlptracegrpc.WithEndpoint(config.Endpoint),
+				otlptracegrpc.WithReconnectionPeriod(50*time.Millisecond))
+		}
+		if tlsConfig != nil {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		} else {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		client = otlptracegrpc.NewClient(opts...)
+	case OtlpProtocolHttp:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithHeaders(config.Headers),
+			otlptracehttp.WithTimeout(timeout),
+		}
+		if len(config.Endpoint) > 0 {
+			opts = append(opts, otlptracehttp.WithEndpoint(config.Endpoint))
+		}
+		if len(config.UrlPath) > 0 {
+			opts = append(opts, otlptracehttp.WithURLPath("/"+strings.TrimPrefix(config.UrlPath, "/")))
+		}
+		if tlsConfig != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+		} else {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		client = otlptracehttp.NewClient(opts...)
+	default:
+		return nil, fmt.Errorf("unsupported protocol %s of otlp exporter, should be one of grpc or http", config.Protocol)
+	}
+
+	return otlptrace.New(context.Background(), client)
+}
+
+// newOtlpTlsConfig returns TLS config with CA and client certificate of cert entry, system roots are used if empty.
+func newOtlpTlsConfig(certEntryName string) (*tls.Config, error) {
+	res := &tls.Config{}
+	if len(certEntryName) < 1 {
+		return res, nil
+	}
+
+	certEntry := rkentry.GlobalAppCtx.GetCertEntry(certEntryName)
+	if certEntry == nil {
+		return nil, fmt.Errorf("cert entry %s of otlp exporter not found", certEntryName)
+	}
+
+	// certs are loaded only once, so it is safe to bootstrap before cert entry bootstrapped by application
+	certEntry.Bootstrap(context.Background())
+
+	if certEntry.RootCA != nil {
+		res.RootCAs = x509.NewCertPool()
+		res.RootCAs.AddCert(certEntry.RootCA)
+	}
+	if certEntry.Certificate != nil {
+		res.Certificates = []tls.Certificate{*certEntry.Certificate}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateOtlpExporter(t *testing.T) {
+	// grpc by default
+	exporter, err := CreateOtlpExporter(nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, exporter)
+	assert.Nil(t, exporter.Shutdown(context.TODO()))
+
+	// grpc with endpoint and TLS
+	exporter, err = CreateOtlpExporter(&OtlpExporterConfig{Protocol: "GRPC", Endpoint: "localhost:0", Tls: true})
+	assert.Nil(t, err)
+	assert.Nil(t, exporter.Shutdown(context.TODO()))
+
+	// unsupported protocol
+	_, err = CreateOtlpExporter(&OtlpExporterConfig{Protocol: "thrift"})
+	assert.NotNil(t, err)
+
+	// cert entry not found
+	_, err = CreateOtlpExporter(&OtlpExporterConfig{CertEntry: "ut-not-exist"})
+	assert.NotNil(t, err)
+}
+
+func TestCreateOtlpExporter_Http(t *testing.T) {
+	var received *coltracepb.ExportTraceServiceRequest
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ut/traces", r.URL.Path)
+		headers = r.Header
+		body, _ := io.ReadAll(r.Body)
+		received = &coltracepb.ExportTraceServiceRequest{}
+		assert.Nil(t, proto.Unmarshal(body, received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter, err := CreateOtlpExporter(&OtlpExporterConfig{
+		Protocol: OtlpProtocolHttp,
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		UrlPath:  "ut/traces",
+		Headers:  map[string]string{"Authorization": "ut-token"},
+	})
+	assert.Nil(t, err)
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	_, span := provider.Tracer("ut-tracer").Start(context.TODO(), "ut-span")
+	span.End()
+
+	assert.NotNil(t, received)
+	assert.Equal(t, "application/x-protobuf", headers.Get("Content-Type"))
+	assert.Equal(t, "ut-token", headers.Get("Authorization"))
+	assert.Equal(t, "ut-span", received.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans()[0].GetName())
+	assert.Nil(t, provider.Shutdown(context.TODO()))
+
+	// TLS
+	exporter, err = CreateOtlpExporter(&OtlpExporterConfig{Protocol: OtlpProtocolHttp, Tls: true})
+	assert.Nil(t, err)
+	assert.Nil(t, exporter.Shutdown(context.TODO()))
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+)
+
+// Names of propagators, which are the same as values of OTEL_PROPAGATORS.
+const (
+	// PropagatorTraceContext propagates with traceparent and tracestate headers of W3C.
+	PropagatorTraceContext = "tracecontext"
+	// PropagatorBaggage propagates with baggage header of W3C.
+	PropagatorBaggage = "baggage"
+	// PropagatorB3 propagates with single b3 header.
+	PropagatorB3 = "b3"
+	// PropagatorB3Multi propagates with X-B3-* headers.
+	PropagatorB3Multi = "b3multi"
+	// PropagatorJaeger propagates with uber-trace-id header.
+	PropagatorJaeger = "jaeger"
+	// PropagatorXray propagates with X-Amzn-Trace-Id header of AWS X-Ray.
+	PropagatorXray = "xray"
+)
+
+// NewPropagator creates composite propagator of names, which are extracted in order and injected all,
+// tracecontext and baggage are used if names is empty, which is the propagator created by rkmidtrace.
+func NewPropagator(names ...string) (propagation.TextMapPropagator, error) {
+	if len(names) < 1 {
+		names = []string{PropagatorTraceContext, PropagatorBaggage}
+	}
+
+	propagators := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case PropagatorTraceContext:
+			propagators = append(propagators, propagation.TraceContext{})
+		case PropagatorBaggage:
+			propagators = append(propagators, propagation.Baggage{})
+		case PropagatorB3:
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case PropagatorB3Multi:
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case PropagatorJaeger:
+			propagators = append(propagators, jaeger.Jaeger{})
+		case PropagatorXray:
+			propagators = append(propagators, xray.Propagator{})
+		default:
+			return nil, fmt.Errorf("unsupported propagator %s, should be one of tracecontext, baggage, b3, b3multi, jaeger or xray", name)
+		}
+	}
+
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
+
+// NewFallbackPropagator creates propagator which extracts remote span context from legacy headers like X-Request-Id
+// or custom correlation headers, only if no span context was extracted by propagators before it, so it should be
+// the last one of composite propagator. Nothing is injected, downstream receives headers of other propagators.
+//
+// The first non-empty header in order is used, and the first entry is used if value is a comma separated chain.
+// Value of 32 hex digits, like UUID without dashes, is used as trace id, other values are hashed into trace id,
+// so the same value always maps to the same trace. Span id is derived from value as well.
+//
+// Legacy headers carry no sampling decision, so span context is not sampled and marked in trace state, and sampler of
+// tracer provider created by WithProviderConfig samples spans with it as root spans.
+func NewFallbackPropagator(headers ...string) propagation.TextMapPropagator {
+	return fallbackPropagator{headers: headers}
+}
+
+// fallbackTraceStateKey marks span context extracted from legacy headers.
+const fallbackTraceStateKey = "rkfallback"
+
+var fallbackTraceState, _ = trace.ParseTraceState(fallbackTraceStateKey + "=1")
+
+// isFallbackSpanContext returns true if span context is extracted by fallback propagator.
+func isFallbackSpanContext(spanCtx trace.SpanContext) bool {
+	return spanCtx.IsRemote() && len(spanCtx.TraceState().Get(fallbackTraceStateKey)) > 0
+}
+
+// fallbackPropagator extracts remote span context from legacy headers, see NewFallbackPropagator.
+type fallbackPropagator struct {
+	headers []string
+}
+
+// Inject does nothing, since legacy headers are not propagated.
+func (fallbackPropagator) Inject(context.Context, propagation.TextMapCarrier) {}
+
+// Extract returns ctx with remote span context derived from the first non-empty header,
+// ctx is returned as it is if it contains valid span context or no header is present.
+func (p fallbackPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	for _, header := range p.headers {
+		value := strings.TrimSpace(strings.Split(carrier.Get(header), ",")[0])
+		if len(value) < 1 {
+			continue
+		}
+
+		sum := sha256.Sum256([]byte(value))
+		config := trace.SpanContextConfig{TraceState: fallbackTraceState, Remote: true}
+		if id := strings.ReplaceAll(value, "-", ""); len(id) != 32 || !decodeHexId(id, config.TraceID[:]) {
+			copy(config.TraceID[:], sum[:16])
+		}
+		copy(config.SpanID[:], sum[16:24])
+
+		spanCtx := trace.NewSpanContext(config)
+		if !spanCtx.IsValid() {
+			continue
+		}
+
+		return trace.ContextWithRemoteSpanContext(ctx, spanCtx)
+	}
+
+	return ctx
+}
+
+// Fields returns legacy headers of fallback propagator.
+func (p fallbackPropagator) Fields() []string {
+	return p.headers
+}
+
+// decodeHexId decodes hex string into id, string shorter than id is padded with leading zeros.
+func decodeHexId(s string, id []byte) bool {
+	if len(s) < 1 || len(s) > len(id)*2 {
+		return false
+	}
+
+	_, err := hex.Decode(id, []byte(strings.Repeat("0", len(id)*2-len(s))+s))
+	return err == nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"testing"
+)
+
+var utSpanCtx = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    trace.TraceID{0x5b, 0x8e, 0xfd, 0xf5, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12},
+	SpanID:     trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+	TraceFlags: trace.FlagsSampled,
+	Remote:     true,
+})
+
+func TestNewPropagator(t *testing.T) {
+	// tracecontext and baggage by default
+	propagator, err := NewPropagator()
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, propagator.Fields())
+
+	propagator, err = NewPropagator("B3", " b3multi", "jaeger", "xray")
+	assert.Nil(t, err)
+	assert.Contains(t, propagator.Fields(), "b3")
+	assert.Contains(t, propagator.Fields(), "x-b3-traceid")
+	assert.Contains(t, propagator.Fields(), "uber-trace-id")
+	assert.Contains(t, propagator.Fields(), "X-Amzn-Trace-Id")
+
+	// inject all
+	header := http.Header{}
+	propagator.Inject(trace.ContextWithRemoteSpanContext(context.TODO(), utSpanCtx), propagation.HeaderCarrier(header))
+	assert.NotEmpty(t, header.Get("b3"))
+	assert.NotEmpty(t, header.Get("X-B3-TraceId"))
+	assert.Equal(t, "5b8efdf50102030405060708090a0b0c:0102030405060708:0:1", header.Get("uber-trace-id"))
+	assert.Equal(t, "Root=1-5b8efdf5-0102030405060708090a0b0c;Parent=0102030405060708;Sampled=1",
+		header.Get("X-Amzn-Trace-Id"))
+
+	// unsupported propagator
+	_, err = NewPropagator("ot")
+	assert.NotNil(t, err)
+}
+
+func TestFallbackPropagator(t *testing.T) {
+	propagator := propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, NewFallbackPropagator("X-Request-Id", "X-Correlation-Id"))
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "X-Request-Id", "X-Correlation-Id"}, propagator.Fields())
+
+	extract := func(header http.Header) trace.SpanContext {
+		return trace.SpanContextFromContext(propagator.Extract(context.TODO(), propagation.HeaderCarrier(header)))
+	}
+
+	// no header
+	assert.False(t, extract(http.Header{}).IsValid())
+
+	// uuid is used as trace id
+	spanCtx := extract(http.Header{"X-Request-Id": []string{"5b8efdf5-0102-0304-0506-0708090a0b0c, ut-hop"}})
+	assert.True(t, spanCtx.IsValid())
+	assert.True(t, spanCtx.IsRemote())
+	assert.False(t, spanCtx.IsSampled())
+	assert.Equal(t, utSpanCtx.TraceID(), spanCtx.TraceID())
+
+	// other values are hashed, the same value maps to the same trace
+	spanCtx = extract(http.Header{"X-Correlation-Id": []string{"ut-correlation"}})
+	assert.True(t, spanCtx.IsValid())
+	assert.Equal(t, spanCtx, extract(http.Header{"X-Correlation-Id": []string{"ut-correlation"}}))
+	assert.NotEqual(t, spanCtx.TraceID(), extract(http.Header{"X-Correlation-Id": []string{"ut-other"}}).TraceID())
+
+	// traceparent takes precedence
+	header := http.Header{"X-Request-Id": []string{"ut-request"}}
+	propagation.TraceContext{}.Inject(trace.ContextWithRemoteSpanContext(context.TODO(), utSpanCtx), propagation.HeaderCarrier(header))
+	assert.Equal(t, utSpanCtx.SpanID(), extract(header).SpanID())
+
+	// nothing injected
+	header = http.Header{}
+	NewFallbackPropagator("X-Request-Id").Inject(trace.ContextWithRemoteSpanContext(context.TODO(), utSpanCtx), propagation.HeaderCarrier(header))
+	assert.Empty(t, header)
+}
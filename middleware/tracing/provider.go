@@ -0,0 +1,68 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ProviderConfig config of tracer provider created by WithProviderConfig.
+//
+// Spans are sampled with Sampler, always if nil, and then with TailSampling if it is not nil.
+// Resource is the same as the one created by rkmidtrace, with attributes of detectors of Resource.
+type ProviderConfig struct {
+	Sampler      sdktrace.Sampler
+	TailSampling *TailSamplingConfig
+	Resource     *ResourceConfig
+}
+
+// WithProviderConfig provides tracer provider of config which exports spans with each of exporters in its own
+// batch span processor, instead of the exporter provided by rkmidtrace.WithExporter.
+// Error is returned if resource detectors are invalid.
+func WithProviderConfig(config *ProviderConfig, entryName, entryType string, exporters ...sdktrace.SpanExporter) (rkmidtrace.Option, error) {
+	if config == nil {
+		config = &ProviderConfig{}
+	}
+
+	sampler := config.Sampler
+	if sampler == nil {
+		sampler = sdktrace.AlwaysSample()
+	}
+
+	res, err := NewResource(config.Resource, entryName, entryType)
+	if err != nil {
+		return nil, err
+	}
+
+	processors := make([]sdktrace.SpanProcessor, 0, len(exporters))
+	for _, exporter := range exporters {
+		if exporter != nil {
+			processors = append(processors, sdktrace.NewBatchSpanProcessor(exporter))
+		}
+	}
+	if config.TailSampling != nil {
+		processors = []sdktrace.SpanProcessor{NewTailSamplingProcessor(config.TailSampling, processors...)}
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(fallbackSampler{sampler: sampler}),
+		sdktrace.WithResource(res),
+	}
+	for _, processor := range processors {
+		opts = append(opts, sdktrace.WithSpanProcessor(processor))
+	}
+
+	return rkmidtrace.WithTracerProvider(sdktrace.NewTracerProvider(opts...)), nil
+}
+
+// withExporters provides tracer provider which exports spans with each of exporters in its own batch span processor,
+// so slow exporter doesn't delay others. Spans are sampled with sampler, always if nil.
+func withExporters(sampler sdktrace.Sampler, entryName, entryType string, exporters ...sdktrace.SpanExporter) rkmidtrace.Option {
+	// never fails without resource detectors
+	opt, _ := WithProviderConfig(&ProviderConfig{Sampler: sampler}, entryName, entryType, exporters...)
+	return opt
+}
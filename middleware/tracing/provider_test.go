@@ -0,0 +1,60 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+func TestWithExporters(t *testing.T) {
+	collector := tracetest.NewInMemoryExporter()
+	file := tracetest.NewInMemoryExporter()
+
+	set := rkmidtrace.NewOptionSet(
+		rkmidtrace.WithEntryNameAndType("ut-entry", "ut-type"),
+		withExporters(nil, "ut-entry", "ut-type", collector, nil, file))
+	_, span := set.GetTracer().Start(context.TODO(), "ut-span")
+	assert.True(t, span.SpanContext().IsSampled())
+	span.End()
+	assert.Nil(t, set.GetProvider().ForceFlush(context.TODO()))
+
+	// spans are exported with every exporter
+	assert.Len(t, collector.GetSpans(), 1)
+	assert.Len(t, file.GetSpans(), 1)
+
+	// exporters are shutdown with provider
+	assert.Nil(t, set.GetProvider().Shutdown(context.TODO()))
+}
+
+func TestWithProviderConfig(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	opt, err := WithProviderConfig(&ProviderConfig{
+		Sampler:  sdktrace.NeverSample(),
+		Resource: &ResourceConfig{Detectors: []string{"os"}},
+	}, "ut-entry", "ut-type", exporter)
+	assert.Nil(t, err)
+
+	set := rkmidtrace.NewOptionSet(opt)
+	_, span := set.GetTracer().Start(context.TODO(), "ut-span")
+	assert.False(t, span.SpanContext().IsSampled())
+	assert.Nil(t, set.GetProvider().Shutdown(context.TODO()))
+
+	// invalid detector
+	opt, err = WithProviderConfig(&ProviderConfig{Resource: &ResourceConfig{Detectors: []string{"ut-detector"}}}, "ut-entry", "ut-type")
+	assert.NotNil(t, err)
+	assert.Nil(t, opt)
+
+	// nil config
+	opt, err = WithProviderConfig(nil, "ut-entry", "ut-type")
+	assert.Nil(t, err)
+	assert.NotNil(t, opt)
+}
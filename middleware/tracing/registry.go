@@ -0,0 +1,74 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"sync"
+)
+
+// providers keeps tracer providers of middlewares, keyed with entry name.
+var providers = &providerRegistry{
+	providers: make(map[string][]*sdktrace.TracerProvider),
+}
+
+// providerRegistry keeps tracer providers created for each entry, so spans batched by them could be flushed
+// when entry is interrupted.
+type providerRegistry struct {
+	lock      sync.Mutex
+	providers map[string][]*sdktrace.TracerProvider
+}
+
+// register adds provider of entry, provider registered already is ignored.
+func (r *providerRegistry) register(entryName string, provider *sdktrace.TracerProvider) {
+	if provider == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, v := range r.providers[entryName] {
+		if v == provider {
+			return
+		}
+	}
+	r.providers[entryName] = append(r.providers[entryName], provider)
+}
+
+// deregister removes and returns providers of entry.
+func (r *providerRegistry) deregister(entryName string) []*sdktrace.TracerProvider {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	res := r.providers[entryName]
+	delete(r.providers, entryName)
+
+	return res
+}
+
+// Deregister removes and returns tracer providers of middlewares created for entry without shutting them down,
+// which is useful if providers are shared with other entries or shut down by caller.
+func Deregister(entryName string) []*sdktrace.TracerProvider {
+	return providers.deregister(entryName)
+}
+
+// ShutdownExporters flushes spans batched in tracer providers of middlewares created for entry and shuts them down,
+// exporters are shutdown with providers. Providers are removed even if ctx is done before they are flushed,
+// and the first error is returned.
+//
+// Spans are not exported by middlewares of entry after it is called.
+func ShutdownExporters(ctx context.Context, entryName string) error {
+	var res error
+	for _, provider := range providers.deregister(entryName) {
+		if err := provider.Shutdown(ctx); err != nil && res == nil {
+			res = err
+		}
+	}
+
+	return res
+}
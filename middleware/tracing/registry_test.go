@@ -0,0 +1,92 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"sync"
+	"testing"
+)
+
+// countingExporter counts exported spans, which are kept after shutdown.
+type countingExporter struct {
+	count int
+}
+
+func (e *countingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.count += len(spans)
+	return nil
+}
+
+func (e *countingExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestShutdownExporters(t *testing.T) {
+	exporter := &countingExporter{}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+
+	// registered once
+	Middleware(rkmidtrace.WithEntryNameAndType("ut-entry", "ut-type"), rkmidtrace.WithTracerProvider(provider))
+	Middleware(rkmidtrace.WithEntryNameAndType("ut-entry", "ut-type"), rkmidtrace.WithTracerProvider(provider))
+	assert.Len(t, providers.providers["ut-entry"], 1)
+
+	// batched spans are flushed
+	_, span := provider.Tracer("ut-tracer").Start(context.TODO(), "ut-span")
+	span.End()
+	assert.Zero(t, exporter.count)
+
+	assert.Nil(t, ShutdownExporters(context.TODO(), "ut-entry"))
+	assert.Equal(t, 1, exporter.count)
+	assert.NotContains(t, providers.providers, "ut-entry")
+
+	// nothing to shutdown
+	assert.Nil(t, ShutdownExporters(context.TODO(), "ut-entry"))
+
+	// error of context done
+	provider = sdktrace.NewTracerProvider(sdktrace.WithBatcher(tracetest.NewInMemoryExporter()))
+	providers.register("ut-entry", provider)
+	providers.register("ut-entry", nil)
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	assert.NotNil(t, ShutdownExporters(ctx, "ut-entry"))
+	assert.NotContains(t, providers.providers, "ut-entry")
+}
+
+func TestDeregister(t *testing.T) {
+	exporter := &countingExporter{}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+
+	// concurrent bootstrap of entries
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			Middleware(rkmidtrace.WithEntryNameAndType(fmt.Sprintf("ut-entry-%d", i%2), "ut-type"),
+				rkmidtrace.WithTracerProvider(provider))
+		}(i)
+	}
+	wg.Wait()
+	assert.Len(t, providers.providers["ut-entry-0"], 1)
+	assert.Len(t, providers.providers["ut-entry-1"], 1)
+
+	// provider is not shut down
+	assert.Equal(t, []*sdktrace.TracerProvider{provider}, Deregister("ut-entry-0"))
+	assert.NotContains(t, providers.providers, "ut-entry-0")
+	_, span := provider.Tracer("ut-tracer").Start(context.TODO(), "ut-span")
+	span.End()
+	assert.Nil(t, ShutdownExporters(context.TODO(), "ut-entry-1"))
+	assert.Equal(t, 1, exporter.count)
+
+	// nothing to deregister
+	assert.Empty(t, Deregister("ut-entry-0"))
+}
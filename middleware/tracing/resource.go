@@ -0,0 +1,142 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/entry"
+	"go.opentelemetry.io/contrib/detectors/aws/ec2"
+	"go.opentelemetry.io/contrib/detectors/gcp"
+	"go.opentelemetry.io/otel/attribute"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"os"
+	"strings"
+	"time"
+)
+
+// Names of resource detectors.
+const (
+	// DetectorHost detects host.name and host.id.
+	DetectorHost = "host"
+	// DetectorOS detects os.type and os.description.
+	DetectorOS = "os"
+	// DetectorContainer detects container.id from cgroup.
+	DetectorContainer = "container"
+	// DetectorK8s detects k8s.pod.name, k8s.pod.uid, k8s.namespace.name and k8s.node.name,
+	// from downward API environment variables and service account.
+	DetectorK8s = "k8s"
+	// DetectorEC2 detects cloud and host attributes from instance metadata service of AWS EC2.
+	// Detectors of ec2 and gcp are the ones of opentelemetry-go-contrib.
+	DetectorEC2 = "ec2"
+	// DetectorGCP detects cloud, host and GKE attributes from metadata server of GCP.
+	DetectorGCP = "gcp"
+
+	defaultDetectTimeout = time.Second
+	k8sNamespaceFile     = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// ResourceConfig config of resource of tracer provider.
+//
+// Detectors are any of host, os, container, k8s, ec2 or gcp, and detection of all detectors is limited by TimeoutMs.
+// Detectors which are not on the platform, like ec2 outside of AWS, add nothing.
+type ResourceConfig struct {
+	Detectors []string `yaml:"detectors" json:"detectors"`
+	TimeoutMs int      `yaml:"timeoutMs" json:"timeoutMs"`
+}
+
+// NewResource creates resource of tracer provider, which is the same as the one created by rkmidtrace,
+// with attributes of detectors in config.
+func NewResource(config *ResourceConfig, entryName, entryType string) (*sdkresource.Resource, error) {
+	if config == nil {
+		config = &ResourceConfig{}
+	}
+
+	opts := []sdkresource.Option{
+		sdkresource.WithFromEnv(),
+		sdkresource.WithProcess(),
+		sdkresource.WithTelemetrySDK(),
+		sdkresource.WithHost(),
+	}
+	for _, name := range config.Detectors {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case DetectorHost:
+			// host.name is detected by default
+			opts = append(opts, sdkresource.WithHostID())
+		case DetectorOS:
+			opts = append(opts, sdkresource.WithOS())
+		case DetectorContainer:
+			opts = append(opts, sdkresource.WithContainer())
+		case DetectorK8s:
+			opts = append(opts, sdkresource.WithDetectors(newK8sDetectors(k8sNamespaceFile)...))
+		case DetectorEC2:
+			opts = append(opts, sdkresource.WithDetectors(ec2.NewResourceDetector()))
+		case DetectorGCP:
+			opts = append(opts, sdkresource.WithDetectors(gcp.NewDetector()))
+		default:
+			return nil, fmt.Errorf("unsupported resource detector %s, should be one of host, os, container, k8s, ec2 or gcp", name)
+		}
+	}
+	opts = append(opts, sdkresource.WithAttributes(
+		semconv.ServiceNameKey.String(rkentry.GlobalAppCtx.GetAppInfoEntry().AppName),
+		semconv.ServiceVersionKey.String(rkentry.GlobalAppCtx.GetAppInfoEntry().Version),
+		attribute.String("service.entryName", entryName),
+		attribute.String("service.entryType", entryType),
+		semconv.TelemetrySDKLanguageGo,
+	))
+
+	timeout := time.Duration(config.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultDetectTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// detectors which are not on the platform add nothing, so partial resource is used
+	res, _ := sdkresource.New(ctx, opts...)
+
+	return res, nil
+}
+
+// newK8sDetectors returns detectors of pod attributes from environment variables exposed by downward API, which are
+// K8S_POD_NAME, K8S_POD_UID, K8S_NAMESPACE and K8S_NODE_NAME, pod name falls back to HOSTNAME and namespace
+// falls back to namespace of service account. Nothing is detected outside of kubernetes.
+//
+// opentelemetry-go-contrib has no detector of pods outside of cloud platforms, so pod attributes are read with
+// string detectors of SDK.
+func newK8sDetectors(namespaceFile string) []sdkresource.Detector {
+	env := func(names ...string) func() (string, error) {
+		return func() (string, error) {
+			if len(os.Getenv("KUBERNETES_SERVICE_HOST")) < 1 {
+				return "", errors.New("not running in kubernetes")
+			}
+			for _, name := range names {
+				if value := os.Getenv(name); len(value) > 0 {
+					return value, nil
+				}
+			}
+			return "", fmt.Errorf("%s not set", strings.Join(names, ", "))
+		}
+	}
+
+	namespace := func() (string, error) {
+		value, err := env("K8S_NAMESPACE")()
+		if err == nil || len(os.Getenv("KUBERNETES_SERVICE_HOST")) < 1 {
+			return value, err
+		}
+		data, err := os.ReadFile(namespaceFile)
+		return strings.TrimSpace(string(data)), err
+	}
+
+	return []sdkresource.Detector{
+		sdkresource.StringDetector("", semconv.K8SPodNameKey, env("K8S_POD_NAME", "HOSTNAME")),
+		sdkresource.StringDetector("", semconv.K8SPodUIDKey, env("K8S_POD_UID")),
+		sdkresource.StringDetector("", semconv.K8SNamespaceNameKey, namespace),
+		sdkresource.StringDetector("", semconv.K8SNodeNameKey, env("K8S_NODE_NAME")),
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// resourceValues returns attributes of resource as map.
+func resourceValues(res *sdkresource.Resource) map[attribute.Key]string {
+	values := make(map[attribute.Key]string)
+	for _, kv := range res.Attributes() {
+		values[kv.Key] = kv.Value.Emit()
+	}
+
+	return values
+}
+
+func TestNewResource(t *testing.T) {
+	// the same as rkmidtrace by default
+	res, err := NewResource(nil, "ut-entry", "ut-type")
+	assert.Nil(t, err)
+	values := resourceValues(res)
+	assert.Equal(t, "ut-entry", values["service.entryName"])
+	assert.NotEmpty(t, values[semconv.HostNameKey])
+	assert.NotContains(t, values, semconv.OSTypeKey)
+
+	// detectors
+	res, err = NewResource(&ResourceConfig{Detectors: []string{" OS", "host", "container"}}, "ut-entry", "ut-type")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, resourceValues(res)[semconv.OSTypeKey])
+
+	// unsupported detector
+	res, err = NewResource(&ResourceConfig{Detectors: []string{"ut-detector"}}, "ut-entry", "ut-type")
+	assert.NotNil(t, err)
+	assert.Nil(t, res)
+}
+
+func TestNewK8sDetectors(t *testing.T) {
+	namespaceFile := filepath.Join(t.TempDir(), "namespace")
+	assert.Nil(t, os.WriteFile(namespaceFile, []byte("ut-namespace\n"), 0644))
+	detect := func() map[attribute.Key]string {
+		res, _ := sdkresource.New(context.TODO(), sdkresource.WithDetectors(newK8sDetectors(namespaceFile)...))
+		return resourceValues(res)
+	}
+
+	// outside of kubernetes
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("HOSTNAME", "ut-pod")
+	assert.Empty(t, detect())
+
+	// fallback to hostname and service account
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("K8S_NODE_NAME", "ut-node")
+	values := detect()
+	assert.Equal(t, "ut-pod", values[semconv.K8SPodNameKey])
+	assert.Equal(t, "ut-namespace", values[semconv.K8SNamespaceNameKey])
+	assert.Equal(t, "ut-node", values[semconv.K8SNodeNameKey])
+	assert.NotContains(t, values, semconv.K8SPodUIDKey)
+
+	// downward API
+	t.Setenv("K8S_POD_NAME", "ut-pod-downward")
+	t.Setenv("K8S_NAMESPACE", "ut-namespace-downward")
+	t.Setenv("K8S_POD_UID", "ut-uid")
+	values = detect()
+	assert.Equal(t, "ut-pod-downward", values[semconv.K8SPodNameKey])
+	assert.Equal(t, "ut-namespace-downward", values[semconv.K8SNamespaceNameKey])
+	assert.Equal(t, "ut-uid", values[semconv.K8SPodUIDKey])
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"fmt"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+)
+
+const (
+	// SamplerAlways samples every span, which is the sampler of tracer provider created by rkmidtrace.
+	SamplerAlways = "always"
+	// SamplerNever samples no span.
+	SamplerNever = "never"
+	// SamplerTraceIdRatio samples Ratio of traces regardless of parent span.
+	SamplerTraceIdRatio = "traceIdRatio"
+	// SamplerParentBased follows sampling decision of remote parent span, and samples Ratio of traces
+	// started without parent.
+	SamplerParentBased = "parentBased"
+)
+
+// SamplerConfig config of sampler, which is one of always, never, traceIdRatio or parentBased.
+//
+// Ratio is used by traceIdRatio and parentBased, which should be in range [0, 1].
+type SamplerConfig struct {
+	Type  string  `yaml:"type" json:"type"`
+	Ratio float64 `yaml:"ratio" json:"ratio"`
+}
+
+// NewSampler creates sampler with config, always is used if Type is empty.
+func NewSampler(config *SamplerConfig) (sdktrace.Sampler, error) {
+	if config == nil {
+		config = &SamplerConfig{}
+	}
+
+	if config.Ratio < 0 || config.Ratio > 1 {
+		return nil, fmt.Errorf("invalid ratio %v of sampler, should be in range [0, 1]", config.Ratio)
+	}
+
+	switch strings.ToLower(config.Type) {
+	case "", strings.ToLower(SamplerAlways):
+		return sdktrace.AlwaysSample(), nil
+	case strings.ToLower(SamplerNever):
+		return sdktrace.NeverSample(), nil
+	case strings.ToLower(SamplerTraceIdRatio):
+		return sdktrace.TraceIDRatioBased(config.Ratio), nil
+	case strings.ToLower(SamplerParentBased):
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.Ratio)), nil
+	}
+
+	return nil, fmt.Errorf("unsupported sampler %s, should be one of always, never, traceIdRatio or parentBased", config.Type)
+}
+
+// WithSampler provides tracer provider which samples spans with sampler and exports them with exporter,
+// since tracer provider created by rkmidtrace always samples. Always is used if sampler is nil.
+//
+// Use WithProviderConfig instead to export with multiple exporters, tail sampling or resource detectors.
+//
+//	sampler, _ := rkgintrace.NewSampler(&rkgintrace.SamplerConfig{Type: rkgintrace.SamplerParentBased, Ratio: 0.1})
+//	rkgintrace.Middleware(rkgintrace.WithSampler(sampler, exporter, entryName, entryType))
+func WithSampler(sampler sdktrace.Sampler, exporter sdktrace.SpanExporter, entryName, entryType string) rkmidtrace.Option {
+	return withExporters(sampler, entryName, entryType, exporter)
+}
+
+// fallbackSampler samples spans whose remote parent is extracted by fallback propagator as root spans with sampler,
+// so that sampling decision is made by root sampler instead of the parent which carries none.
+type fallbackSampler struct {
+	sampler sdktrace.Sampler
+}
+
+// ShouldSample drops span context of fallback propagator from parameters, trace id of it is kept.
+func (s fallbackSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if isFallbackSpanContext(trace.SpanContextFromContext(p.ParentContext)) {
+		p.ParentContext = trace.ContextWithSpanContext(p.ParentContext, trace.SpanContext{})
+	}
+
+	return s.sampler.ShouldSample(p)
+}
+
+// Description returns description of sampler.
+func (s fallbackSampler) Description() string {
+	return s.sampler.Description()
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewSampler(t *testing.T) {
+	// always by default
+	sampler, err := NewSampler(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, sdktrace.AlwaysSample().Description(), sampler.Description())
+
+	sampler, err = NewSampler(&SamplerConfig{Type: "never"})
+	assert.Nil(t, err)
+	assert.Equal(t, sdktrace.NeverSample().Description(), sampler.Description())
+
+	// case insensitive
+	sampler, err = NewSampler(&SamplerConfig{Type: "traceIDRatio", Ratio: 0.5})
+	assert.Nil(t, err)
+	assert.Equal(t, sdktrace.TraceIDRatioBased(0.5).Description(), sampler.Description())
+
+	sampler, err = NewSampler(&SamplerConfig{Type: SamplerParentBased, Ratio: 0.1})
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(sampler.Description(), "ParentBased"))
+
+	// invalid ratio
+	_, err = NewSampler(&SamplerConfig{Type: SamplerTraceIdRatio, Ratio: 1.5})
+	assert.NotNil(t, err)
+
+	// unsupported type
+	_, err = NewSampler(&SamplerConfig{Type: "ut-sampler"})
+	assert.NotNil(t, err)
+}
+
+func TestWithSampler(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	// never sample
+	set := rkmidtrace.NewOptionSet(
+		rkmidtrace.WithEntryNameAndType("ut-entry", "ut-type"),
+		WithSampler(sdktrace.NeverSample(), exporter, "ut-entry", "ut-type"))
+	_, span := set.GetTracer().Start(context.TODO(), "ut-span")
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+	assert.Nil(t, set.GetProvider().ForceFlush(context.TODO()))
+	assert.Empty(t, exporter.GetSpans())
+
+	// always sample
+	set = rkmidtrace.NewOptionSet(
+		rkmidtrace.WithEntryNameAndType("ut-entry", "ut-type"),
+		WithSampler(nil, exporter, "ut-entry", "ut-type"))
+	_, span = set.GetTracer().Start(context.TODO(), "ut-span")
+	assert.True(t, span.SpanContext().IsSampled())
+	span.End()
+	assert.Nil(t, set.GetProvider().ForceFlush(context.TODO()))
+	assert.Len(t, exporter.GetSpans(), 1)
+
+	attrs := exporter.GetSpans()[0].Resource.Attributes()
+	found := false
+	for _, kv := range attrs {
+		if kv.Key == "service.entryName" {
+			assert.Equal(t, "ut-entry", kv.Value.AsString())
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestFallbackSampler(t *testing.T) {
+	header := http.Header{"X-Request-Id": []string{"ut-request"}}
+	ctx := NewFallbackPropagator("X-Request-Id").Extract(context.TODO(), propagation.HeaderCarrier(header))
+	parent := trace.SpanContextFromContext(ctx)
+
+	// root sampler decides, instead of unsampled parent
+	for _, ratio := range []float64{0, 1} {
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(
+			fallbackSampler{sampler: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))}))
+		_, span := provider.Tracer("ut-tracer").Start(ctx, "ut-span")
+		assert.Equal(t, ratio == 1, span.SpanContext().IsSampled())
+		assert.Equal(t, parent.TraceID(), span.SpanContext().TraceID())
+		assert.Empty(t, span.SpanContext().TraceState().Get(fallbackTraceStateKey))
+		span.End()
+	}
+
+	// unsampled parent of other propagators is followed
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(
+		fallbackSampler{sampler: sdktrace.ParentBased(sdktrace.AlwaysSample())}))
+	_, span := provider.Tracer("ut-tracer").Start(
+		trace.ContextWithRemoteSpanContext(context.TODO(), utSpanCtx.WithTraceFlags(0)), "ut-span")
+	assert.False(t, span.SpanContext().IsSampled())
+}
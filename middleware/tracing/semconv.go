@@ -0,0 +1,51 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+// withServerSemconv names server span with method and route template like GET /v1/user/:id, or method only
+// if no route matched, so names of spans are low cardinality.
+//
+// http.route set by rkmidtrace with raw path is replaced with route template, and removed if no route matched,
+// net.peer.ip is added if missing, and client ip resolved by gin is added as http.client_ip.
+func withServerSemconv(beforeCtx *rkmidtrace.BeforeCtx, ctx *gin.Context) {
+	route := ctx.FullPath()
+
+	beforeCtx.Input.SpanName = ctx.Request.Method
+	if len(route) > 0 {
+		beforeCtx.Input.SpanName = ctx.Request.Method + " " + route
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(beforeCtx.Input.Attributes)+3)
+	hasPeerIp := false
+	for _, kv := range beforeCtx.Input.Attributes {
+		switch kv.Key {
+		case semconv.HTTPRouteKey:
+			continue
+		case semconv.NetPeerIPKey:
+			hasPeerIp = true
+		}
+		attrs = append(attrs, kv)
+	}
+
+	if len(route) > 0 {
+		attrs = append(attrs, semconv.HTTPRouteKey.String(route))
+	}
+	if remoteIp := ctx.RemoteIP(); !hasPeerIp && len(remoteIp) > 0 {
+		attrs = append(attrs, semconv.NetPeerIPKey.String(remoteIp))
+	}
+	if clientIp := ctx.ClientIP(); len(clientIp) > 0 {
+		attrs = append(attrs, semconv.HTTPClientIPKey.String(clientIp))
+	}
+
+	beforeCtx.Input.Attributes = attrs
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware_ServerSemconv(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	router := gin.New()
+	router.Use(Middleware(rkmidtrace.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))))
+	router.GET("/v1/user/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/user/ut-user", nil)
+	req.RemoteAddr = "10.0.0.1:1949"
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	// no route matched
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/unknown", nil))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	attrs := attribute.NewSet(spans[0].Attributes()...)
+	assert.Equal(t, "GET /v1/user/:id", spans[0].Name())
+	for k, v := range map[string]interface{}{
+		"http.method":      "GET",
+		"http.route":       "/v1/user/:id",
+		"http.status_code": int64(http.StatusCreated),
+		"net.peer.ip":      "10.0.0.1",
+		"http.client_ip":   "10.0.0.1",
+	} {
+		value, ok := attrs.Value(attribute.Key(k))
+		assert.True(t, ok, k)
+		assert.Equal(t, v, value.AsInterface(), k)
+	}
+
+	attrs = attribute.NewSet(spans[1].Attributes()...)
+	assert.Equal(t, "GET", spans[1].Name())
+	assert.False(t, attrs.HasValue("http.route"))
+}
+
+func TestWithServerSemconv(t *testing.T) {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/ut", nil)
+	ctx.Request.RemoteAddr = "ut-addr"
+
+	// net.peer.ip is missing if remote address is not ip
+	beforeCtx := rkmidtrace.NewBeforeCtx()
+	beforeCtx.Input.Attributes = append(beforeCtx.Input.Attributes, attribute.String("http.route", "/ut"))
+	withServerSemconv(beforeCtx, ctx)
+	assert.Equal(t, "POST", beforeCtx.Input.SpanName)
+	assert.Empty(t, beforeCtx.Input.Attributes)
+}
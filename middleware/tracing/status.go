@@ -0,0 +1,60 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordErrors adds errors of gin context as exception events of server span, and marks span as error.
+func recordErrors(ctx *gin.Context, beforeCtx *rkmidtrace.BeforeCtx) {
+	if beforeCtx.Output.Span == nil || len(ctx.Errors) < 1 {
+		return
+	}
+
+	for _, err := range ctx.Errors {
+		beforeCtx.Output.Span.RecordError(err.Err)
+	}
+
+	setErrorStatus(beforeCtx, ctx.Errors.Last().Error())
+}
+
+// recordPanic adds value recovered from panic as exception event with stack trace of server span,
+// and marks span as error.
+func recordPanic(beforeCtx *rkmidtrace.BeforeCtx, recv interface{}) {
+	if beforeCtx.Output.Span == nil {
+		return
+	}
+
+	err, ok := recv.(error)
+	if !ok {
+		err = fmt.Errorf("%v", recv)
+	}
+
+	beforeCtx.Output.Span.RecordError(err, trace.WithStackTrace(true))
+	setErrorStatus(beforeCtx, fmt.Sprintf("panic: %v", err))
+}
+
+// setErrorStatus marks server span as error with description, status set by rkmidtrace with status code of response
+// is ignored afterwards, since ok status would override error status.
+func setErrorStatus(beforeCtx *rkmidtrace.BeforeCtx, description string) {
+	beforeCtx.Output.Span.SetStatus(codes.Error, description)
+	if _, ok := beforeCtx.Output.Span.(errorStatusSpan); !ok {
+		beforeCtx.Output.Span = errorStatusSpan{Span: beforeCtx.Output.Span}
+	}
+}
+
+// errorStatusSpan keeps error status of span.
+type errorStatusSpan struct {
+	trace.Span
+}
+
+// SetStatus is ignored since span is marked as error already.
+func (errorStatusSpan) SetStatus(codes.Code, string) {}
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"github.com/rookie-ninja/rk-gin/v2/middleware/panic"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// serveWithStatus serves request with handler behind panic and tracing middleware, and returns ended server span.
+func serveWithStatus(t *testing.T, handler gin.HandlerFunc) (sdktrace.ReadOnlySpan, int) {
+	recorder := tracetest.NewSpanRecorder()
+
+	router := gin.New()
+	router.Use(rkginpanic.Middleware())
+	router.Use(Middleware(rkmidtrace.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))))
+	router.GET("/ut-path", handler)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ut-path", nil))
+
+	ended := recorder.Ended()
+	assert.Len(t, ended, 1)
+
+	return ended[0], resp.Code
+}
+
+// exceptionEvents returns number of exception events of span.
+func exceptionEvents(span sdktrace.ReadOnlySpan) int {
+	res := 0
+	for _, event := range span.Events() {
+		if event.Name == semconv.ExceptionEventName {
+			res++
+		}
+	}
+
+	return res
+}
+
+func TestMiddleware_StatusOk(t *testing.T) {
+	span, code := serveWithStatus(t, func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, codes.Ok, span.Status().Code)
+	assert.Zero(t, exceptionEvents(span))
+}
+
+func TestMiddleware_StatusServerError(t *testing.T) {
+	span, code := serveWithStatus(t, func(ctx *gin.Context) {
+		ctx.Status(http.StatusBadGateway)
+	})
+	assert.Equal(t, http.StatusBadGateway, code)
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Attributes(), semconv.HTTPStatusCodeKey.Int(http.StatusBadGateway))
+}
+
+func TestMiddleware_StatusErrors(t *testing.T) {
+	span, code := serveWithStatus(t, func(ctx *gin.Context) {
+		ctx.Error(errors.New("ut-error-1"))
+		ctx.Error(errors.New("ut-error-2"))
+		ctx.Status(http.StatusOK)
+	})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Equal(t, "ut-error-2", span.Status().Description)
+	assert.Equal(t, 2, exceptionEvents(span))
+}
+
+func TestMiddleware_StatusPanic(t *testing.T) {
+	span, code := serveWithStatus(t, func(ctx *gin.Context) {
+		panic("ut-panic")
+	})
+	// panic is recovered by panic middleware
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Equal(t, "panic: ut-panic", span.Status().Description)
+	assert.Equal(t, 1, exceptionEvents(span))
+	assert.Contains(t, span.Attributes(), semconv.HTTPStatusCodeKey.Int(http.StatusInternalServerError))
+
+	hasStack := false
+	for _, kv := range span.Events()[len(span.Events())-1].Attributes {
+		hasStack = hasStack || kv.Key == semconv.ExceptionStacktraceKey
+	}
+	assert.True(t, hasStack)
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultTailWait is the default duration spans of trace are held before local root span ends.
+	defaultTailWait = 30 * time.Second
+	// defaultTailMaxTraces is the default number of traces held at the same time.
+	defaultTailMaxTraces = 10000
+)
+
+// TailDecisionFunc decides whether spans of trace are exported when local root span of trace ends,
+// the last one of spans is the local root span.
+type TailDecisionFunc func(spans []sdktrace.ReadOnlySpan) bool
+
+// TailSamplingConfig config of tail sampling.
+//
+// Traces with error span are kept, and traces whose local root span lasts LatencyThresholdMs or longer are kept
+// if LatencyThresholdMs is positive, unless Decision is provided which replaces both rules.
+// Spans are held up to WaitMs for local root span, and the oldest trace is dropped if more than MaxTraces are held.
+type TailSamplingConfig struct {
+	Enabled            bool             `yaml:"enabled" json:"enabled"`
+	LatencyThresholdMs int              `yaml:"latencyThresholdMs" json:"latencyThresholdMs"`
+	WaitMs             int              `yaml:"waitMs" json:"waitMs"`
+	MaxTraces          int              `yaml:"maxTraces" json:"maxTraces"`
+	Decision           TailDecisionFunc `yaml:"-" json:"-"`
+}
+
+// NewTailDecision returns decision which keeps traces with error span, or local root span lasts latencyThreshold
+// or longer, latency is not checked if latencyThreshold is not positive.
+func NewTailDecision(latencyThreshold time.Duration) TailDecisionFunc {
+	return func(spans []sdktrace.ReadOnlySpan) bool {
+		for _, span := range spans {
+			if span.Status().Code == codes.Error {
+				return true
+			}
+		}
+
+		root := spans[len(spans)-1]
+		return latencyThreshold > 0 && root.EndTime().Sub(root.StartTime()) >= latencyThreshold
+	}
+}
+
+// NewTailSamplingProcessor creates processor which holds ended spans of sampled traces until local root span,
+// which has no parent or remote parent, ends, and passes spans to next processors only if decision of config
+// keeps the trace. Spans ended after local root span are held until dropped, since trace is decided already.
+func NewTailSamplingProcessor(config *TailSamplingConfig, next ...sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	if config == nil {
+		config = &TailSamplingConfig{}
+	}
+
+	processor := &tailSamplingProcessor{
+		next:      next,
+		decide:    config.Decision,
+		wait:      time.Duration(config.WaitMs) * time.Millisecond,
+		maxTraces: config.MaxTraces,
+		traces:    make(map[trace.TraceID]*tailTrace),
+	}
+
+	if processor.decide == nil {
+		processor.decide = NewTailDecision(time.Duration(config.LatencyThresholdMs) * time.Millisecond)
+	}
+	if processor.wait <= 0 {
+		processor.wait = defaultTailWait
+	}
+	if processor.maxTraces <= 0 {
+		processor.maxTraces = defaultTailMaxTraces
+	}
+
+	return processor
+}
+
+// tailTrace is ended spans of trace held by tailSamplingProcessor.
+type tailTrace struct {
+	traceId trace.TraceID
+	start   time.Time
+	spans   []sdktrace.ReadOnlySpan
+}
+
+// tailSamplingProcessor see NewTailSamplingProcessor.
+type tailSamplingProcessor struct {
+	next      []sdktrace.SpanProcessor
+	decide    TailDecisionFunc
+	wait      time.Duration
+	maxTraces int
+
+	lock   sync.Mutex
+	traces map[trace.TraceID]*tailTrace
+	// order of traces held, which may contain traces decided already
+	order []*tailTrace
+}
+
+// OnStart passes span to next processors.
+func (p *tailSamplingProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	for _, next := range p.next {
+		next.OnStart(parent, s)
+	}
+}
+
+// OnEnd holds span until local root span of trace ends, and passes spans of trace to next processors if kept.
+func (p *tailSamplingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+
+	traceId := s.SpanContext().TraceID()
+	now := time.Now()
+
+	p.lock.Lock()
+	t, ok := p.traces[traceId]
+	if !ok {
+		t = &tailTrace{traceId: traceId, start: now}
+		p.traces[traceId] = t
+		p.order = append(p.order, t)
+	}
+	t.spans = append(t.spans, s)
+
+	// decided trace releases its spans, since it stays in order until popped from head
+	isRoot := !s.Parent().IsValid() || s.Parent().IsRemote()
+	spans := t.spans
+	if isRoot {
+		delete(p.traces, traceId)
+		t.spans = nil
+	}
+	p.evict(now)
+	p.lock.Unlock()
+
+	if !isRoot || !p.decide(spans) {
+		return
+	}
+
+	for _, next := range p.next {
+		for _, span := range spans {
+			next.OnEnd(span)
+		}
+	}
+}
+
+// evict drops traces decided already from order, and traces held longer than wait or more than maxTraces,
+// decided traces in order are counted as well, so that order is bounded by maxTraces.
+func (p *tailSamplingProcessor) evict(now time.Time) {
+	for len(p.order) > 0 {
+		t := p.order[0]
+		held := p.traces[t.traceId] == t
+		if held && now.Sub(t.start) < p.wait && len(p.order) <= p.maxTraces {
+			return
+		}
+
+		if held {
+			delete(p.traces, t.traceId)
+		}
+		p.order[0] = nil
+		p.order = p.order[1:]
+	}
+}
+
+// Shutdown drops spans held and shuts down next processors.
+func (p *tailSamplingProcessor) Shutdown(ctx context.Context) error {
+	p.lock.Lock()
+	p.traces = make(map[trace.TraceID]*tailTrace)
+	p.order = nil
+	p.lock.Unlock()
+
+	var res error
+	for _, next := range p.next {
+		if err := next.Shutdown(ctx); err != nil && res == nil {
+			res = err
+		}
+	}
+
+	return res
+}
+
+// ForceFlush flushes next processors, spans held are not passed since traces are not decided.
+func (p *tailSamplingProcessor) ForceFlush(ctx context.Context) error {
+	for _, next := range p.next {
+		if err := next.ForceFlush(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
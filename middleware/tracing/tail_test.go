@@ -0,0 +1,163 @@
+// Copyright (c) 2021 rookie-ninja
+//
+// Use of this source code is governed by an Apache-style
+// license that can be found in the LICENSE file.
+
+package rkgintrace
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/rookie-ninja/rk-entry/v2/middleware/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTailTracer returns tracer whose spans are processed by tail sampling processor of config, and spans kept.
+func newTailTracer(config *TailSamplingConfig) (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(NewTailSamplingProcessor(config, recorder))), recorder
+}
+
+func TestNewTailSamplingProcessor(t *testing.T) {
+	processor := NewTailSamplingProcessor(nil).(*tailSamplingProcessor)
+	assert.Equal(t, defaultTailWait, processor.wait)
+	assert.Equal(t, defaultTailMaxTraces, processor.maxTraces)
+	assert.NotNil(t, processor.decide)
+}
+
+func TestTailSamplingProcessor_Error(t *testing.T) {
+	provider, recorder := newTailTracer(&TailSamplingConfig{})
+	tracer := provider.Tracer("ut-tracer")
+
+	// trace without error is dropped
+	ctx, root := tracer.Start(context.TODO(), "ut-root")
+	_, child := tracer.Start(ctx, "ut-child")
+	child.End()
+	root.End()
+	assert.Empty(t, recorder.Ended())
+
+	// trace with error child is kept
+	ctx, root = tracer.Start(context.TODO(), "ut-root")
+	_, child = tracer.Start(ctx, "ut-child")
+	child.SetStatus(codes.Error, "ut-error")
+	child.End()
+	assert.Empty(t, recorder.Ended())
+	root.End()
+	assert.Len(t, recorder.Ended(), 2)
+	assert.Empty(t, provider.ForceFlush(context.TODO()))
+	assert.Empty(t, provider.Shutdown(context.TODO()))
+}
+
+func TestTailSamplingProcessor_Latency(t *testing.T) {
+	provider, recorder := newTailTracer(&TailSamplingConfig{LatencyThresholdMs: 100})
+	tracer := provider.Tracer("ut-tracer")
+
+	start := time.Now()
+	_, root := tracer.Start(context.TODO(), "ut-fast", trace.WithTimestamp(start))
+	root.End(trace.WithTimestamp(start.Add(10 * time.Millisecond)))
+	_, root = tracer.Start(context.TODO(), "ut-slow", trace.WithTimestamp(start))
+	root.End(trace.WithTimestamp(start.Add(time.Second)))
+
+	assert.Len(t, recorder.Ended(), 1)
+	assert.Equal(t, "ut-slow", recorder.Ended()[0].Name())
+}
+
+func TestTailSamplingProcessor_Decision(t *testing.T) {
+	provider, recorder := newTailTracer(&TailSamplingConfig{
+		Decision: func(spans []sdktrace.ReadOnlySpan) bool {
+			return len(spans) > 1
+		},
+	})
+	tracer := provider.Tracer("ut-tracer")
+
+	_, root := tracer.Start(context.TODO(), "ut-root")
+	root.End()
+	assert.Empty(t, recorder.Ended())
+
+	ctx, root := tracer.Start(context.TODO(), "ut-root")
+	_, child := tracer.Start(ctx, "ut-child")
+	child.End()
+	root.End()
+	assert.Len(t, recorder.Ended(), 2)
+
+	// spans which are not sampled are ignored
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.NeverSample()),
+		sdktrace.WithSpanProcessor(NewTailSamplingProcessor(&TailSamplingConfig{}, recorder)))
+	_, root = provider.Tracer("ut-tracer").Start(context.TODO(), "ut-root")
+	root.SetStatus(codes.Error, "ut-error")
+	root.End()
+	assert.Len(t, recorder.Ended(), 2)
+}
+
+func TestTailSamplingProcessor_Evict(t *testing.T) {
+	processor := NewTailSamplingProcessor(&TailSamplingConfig{MaxTraces: 2}).(*tailSamplingProcessor)
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+	tracer := provider.Tracer("ut-tracer")
+
+	// children whose root never ends
+	for i := 0; i < 3; i++ {
+		ctx, _ := tracer.Start(context.TODO(), "ut-root")
+		_, child := tracer.Start(ctx, "ut-child")
+		child.End()
+	}
+	assert.Len(t, processor.traces, 2)
+	assert.Len(t, processor.order, 2)
+
+	// expired
+	processor.wait = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	ctx, _ := tracer.Start(context.TODO(), "ut-root")
+	_, child := tracer.Start(ctx, "ut-child")
+	child.End()
+	assert.Len(t, processor.traces, 1)
+
+	// decided trace is not held
+	processor.wait = time.Minute
+	_, root := tracer.Start(context.TODO(), "ut-root")
+	root.End()
+	assert.Len(t, processor.traces, 1)
+
+	assert.Len(t, processor.order, 2)
+	assert.Nil(t, processor.order[1].spans)
+
+	// decided traces queued behind undecided head are counted, so that the head is dropped
+	_, root = tracer.Start(context.TODO(), "ut-root")
+	root.End()
+	assert.Empty(t, processor.traces)
+	assert.Empty(t, processor.order)
+
+	assert.Nil(t, provider.Shutdown(context.TODO()))
+	assert.Empty(t, processor.traces)
+}
+
+func TestWithProviderConfig_TailSampling(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	opt, err := WithProviderConfig(&ProviderConfig{TailSampling: &TailSamplingConfig{}}, "ut-entry-tail", "ut-type", exporter)
+	assert.Nil(t, err)
+	handler := Middleware(rkmidtrace.WithEntryNameAndType("ut-entry-tail", "ut-type"), opt)
+
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/ut-ok", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.GET("/ut-error", func(ctx *gin.Context) {
+		ctx.Status(http.StatusInternalServerError)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-ok", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ut-error", nil))
+
+	assert.Nil(t, providers.providers["ut-entry-tail"][0].ForceFlush(context.TODO()))
+	assert.Len(t, exporter.GetSpans(), 1)
+	assert.Equal(t, "GET /ut-error", exporter.GetSpans()[0].Name)
+	assert.Nil(t, ShutdownExporters(context.TODO(), "ut-entry-tail"))
+}